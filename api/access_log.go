@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type (
+	// AccessLogRecord describes a request to a bucket for server access logs.
+	AccessLogRecord struct {
+		Time       time.Time
+		RemoteIP   string
+		Requester  string
+		RequestID  string
+		Operation  string
+		Bucket     string
+		Key        string
+		RequestURI string
+		Status     int
+		ErrorCode  string
+		BytesSent  int64
+		TotalTime  time.Duration
+		Referer    string
+		UserAgent  string
+		VersionID  string
+		Host       string
+	}
+
+	// AccessLogger collects records of the requests to buckets.
+	// Log is called on the request path, so it mustn't block.
+	AccessLogger interface {
+		Log(rec *AccessLogRecord)
+	}
+
+	accessLogResponseWriter struct {
+		http.ResponseWriter
+
+		statusCode int
+		bytesSent  int64
+	}
+)
+
+func (w *accessLogResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytesSent += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to stream responses through the access logger.
+func (w *accessLogResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// accessLog passes a record of every request to a bucket to the access logger.
+func accessLog(l AccessLogger) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			lw := &accessLogResponseWriter{ResponseWriter: w}
+
+			h.ServeHTTP(lw, r)
+
+			reqInfo := GetReqInfo(r.Context())
+			if reqInfo.BucketName == "" {
+				return
+			}
+
+			status := lw.statusCode
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			l.Log(&AccessLogRecord{
+				Time:       start,
+				RemoteIP:   reqInfo.RemoteHost,
+				Requester:  reqInfo.User,
+				RequestID:  reqInfo.RequestID,
+				Operation:  mux.CurrentRoute(r).GetName(),
+				Bucket:     reqInfo.BucketName,
+				Key:        reqInfo.ObjectName,
+				RequestURI: r.Method + " " + r.RequestURI + " " + r.Proto,
+				Status:     status,
+				ErrorCode:  reqInfo.ErrorCode,
+				BytesSent:  lw.bytesSent,
+				TotalTime:  time.Since(start),
+				Referer:    r.Referer(),
+				UserAgent:  r.UserAgent(),
+				VersionID:  r.URL.Query().Get("versionId"),
+				Host:       r.Host,
+			})
+		})
+	}
+}
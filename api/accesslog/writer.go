@@ -0,0 +1,364 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"go.uber.org/zap"
+)
+
+// Default values of the access log writer parameters.
+const (
+	DefaultFlushInterval   = 5 * time.Minute
+	DefaultRefreshInterval = 5 * time.Minute
+	DefaultBufferSize      = 10000
+	DefaultMaxRecords      = 1000
+)
+
+// logTimeFormat is a time format of records, e.g. [06/Feb/2019:00:00:38 +0000].
+const logTimeFormat = "[02/Jan/2006:15:04:05 -0700]"
+
+type (
+	// Config contains parameters of the access log writer.
+	Config struct {
+		// FlushInterval is a maximum time records are buffered before they're written to the target bucket.
+		FlushInterval time.Duration
+		// RefreshInterval is an interval to reload logging configurations of the buckets.
+		RefreshInterval time.Duration
+		// BufferSize is a number of records waiting for processing,
+		// new records are dropped if the buffer is full.
+		BufferSize int
+		// MaxRecords is a maximum number of records in a log object.
+		MaxRecords int
+		// AccessKeys are access key IDs which credentials are used to read logging
+		// configurations of the buckets of their owners and to write logs.
+		AccessKeys []string
+		// Registry is an optional access key registry to resolve AccessKeys
+		// which aren't access box addresses.
+		Registry *tokens.Registry
+	}
+
+	// Writer batches access log records of the buckets with enabled logging
+	// and periodically writes them as objects to the target buckets.
+	Writer struct {
+		log             *zap.Logger
+		obj             layer.Client
+		creds           tokens.Credentials
+		flushInterval   time.Duration
+		refreshInterval time.Duration
+		maxRecords      int
+		accessKeys      []string
+		registry        *tokens.Registry
+		records         chan *api.AccessLogRecord
+
+		// targets and batches are accessed by the Run goroutine only.
+		targets map[string]*target
+		batches map[string]*batch
+	}
+
+	// target is a destination of access logs of the source bucket.
+	target struct {
+		ctx     context.Context
+		owner   string
+		bktInfo *data.BucketInfo
+		prefix  string
+	}
+
+	batch struct {
+		buf     bytes.Buffer
+		records int
+	}
+)
+
+var _ api.AccessLogger = (*Writer)(nil)
+
+// NewWriter creates an access log writer. Logging configurations are read and
+// logs are written on behalf of owners of the configured access keys.
+func NewWriter(log *zap.Logger, obj layer.Client, creds tokens.Credentials, cfg *Config) (*Writer, error) {
+	w := &Writer{
+		log:             log,
+		obj:             obj,
+		creds:           creds,
+		flushInterval:   cfg.FlushInterval,
+		refreshInterval: cfg.RefreshInterval,
+		maxRecords:      cfg.MaxRecords,
+		accessKeys:      cfg.AccessKeys,
+		registry:        cfg.Registry,
+		targets:         make(map[string]*target),
+		batches:         make(map[string]*batch),
+	}
+
+	if w.flushInterval <= 0 {
+		w.flushInterval = DefaultFlushInterval
+	}
+	if w.refreshInterval <= 0 {
+		w.refreshInterval = DefaultRefreshInterval
+	}
+	if w.maxRecords <= 0 {
+		w.maxRecords = DefaultMaxRecords
+	}
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	w.records = make(chan *api.AccessLogRecord, bufferSize)
+
+	for _, accessKeyID := range cfg.AccessKeys {
+		if err := tokens.CheckAccessKey(cfg.Registry, accessKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// Log implements api.AccessLogger. The record is dropped if the buffer is full.
+func (w *Writer) Log(rec *api.AccessLogRecord) {
+	select {
+	case w.records <- rec:
+	default:
+		w.log.Warn("access log buffer is full, record is dropped",
+			zap.String("bucket", rec.Bucket), zap.String("request_id", rec.RequestID))
+	}
+}
+
+// Run processes records until the context is done, buffered records are written before exit.
+func (w *Writer) Run(ctx context.Context) {
+	w.log.Info("access log writer started",
+		zap.Duration("flush_interval", w.flushInterval),
+		zap.Duration("refresh_interval", w.refreshInterval))
+
+	w.refresh(ctx)
+
+	flush := time.NewTicker(w.flushInterval)
+	defer flush.Stop()
+	refresh := time.NewTicker(w.refreshInterval)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.drain()
+			w.flushAll()
+			w.log.Info("access log writer stopped")
+			return
+		case rec := <-w.records:
+			w.add(rec)
+		case <-flush.C:
+			w.flushAll()
+		case <-refresh.C:
+			w.flushAll()
+			w.refresh(ctx)
+		}
+	}
+}
+
+// refresh reloads logging configurations of the buckets of the access keys owners.
+func (w *Writer) refresh(ctx context.Context) {
+	targets := make(map[string]*target)
+
+	for _, accessKeyID := range w.accessKeys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		addr, err := tokens.ResolveAccessKey(ctx, w.registry, accessKeyID)
+		if err != nil {
+			w.log.Error("couldn't resolve access key id", zap.String("access_key_id", accessKeyID), zap.Error(err))
+			continue
+		}
+
+		box, err := w.creds.GetBox(ctx, addr)
+		if err != nil {
+			w.log.Error("couldn't get access box", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		// logs are written after the context of the writer is done
+		boxCtx := context.WithValue(context.Background(), api.BoxData, box)
+
+		buckets, err := w.obj.ListBuckets(boxCtx)
+		if err != nil {
+			w.log.Error("couldn't list buckets", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		for _, bktInfo := range buckets {
+			t, err := w.loadTarget(boxCtx, bktInfo)
+			if err != nil {
+				w.log.Error("couldn't load bucket logging configuration", zap.String("bucket", bktInfo.Name), zap.Error(err))
+				continue
+			}
+			if t != nil {
+				targets[bktInfo.Name] = t
+			}
+		}
+	}
+
+	w.targets = targets
+}
+
+func (w *Writer) loadTarget(ctx context.Context, bktInfo *data.BucketInfo) (*target, error) {
+	conf, err := w.obj.GetBucketLoggingConfiguration(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+	if conf.LoggingEnabled == nil {
+		return nil, nil
+	}
+
+	targetInfo, err := w.obj.GetBucketInfo(ctx, conf.LoggingEnabled.TargetBucket)
+	if err != nil {
+		return nil, fmt.Errorf("get target bucket: %w", err)
+	}
+
+	return &target{
+		ctx:     ctx,
+		owner:   bktInfo.Owner.EncodeToString(),
+		bktInfo: targetInfo,
+		prefix:  conf.LoggingEnabled.TargetPrefix,
+	}, nil
+}
+
+func (w *Writer) add(rec *api.AccessLogRecord) {
+	t, ok := w.targets[rec.Bucket]
+	if !ok {
+		return
+	}
+
+	b := w.batches[rec.Bucket]
+	if b == nil {
+		b = new(batch)
+		w.batches[rec.Bucket] = b
+	}
+
+	writeRecord(&b.buf, t.owner, rec)
+	b.records++
+
+	if b.records >= w.maxRecords {
+		w.flush(rec.Bucket, b)
+	}
+}
+
+// drain processes records which are already in the buffer.
+func (w *Writer) drain() {
+	for {
+		select {
+		case rec := <-w.records:
+			w.add(rec)
+		default:
+			return
+		}
+	}
+}
+
+func (w *Writer) flushAll() {
+	for bucket, b := range w.batches {
+		w.flush(bucket, b)
+	}
+}
+
+// flush writes the batch to the target bucket as a single object, records are lost if it fails.
+func (w *Writer) flush(bucket string, b *batch) {
+	delete(w.batches, bucket)
+
+	t, ok := w.targets[bucket]
+	if !ok || b.records == 0 {
+		return
+	}
+
+	key := logObjectKey(t.prefix, time.Now())
+	_, err := w.obj.PutObject(t.ctx, &layer.PutObjectParams{
+		BktInfo: t.bktInfo,
+		Object:  key,
+		Size:    int64(b.buf.Len()),
+		Reader:  &b.buf,
+		Header:  map[string]string{api.ContentType: "text/plain"},
+	})
+	if err != nil {
+		w.log.Error("couldn't write access logs", zap.String("bucket", bucket),
+			zap.String("target", t.bktInfo.Name), zap.Int("records", b.records), zap.Error(err))
+		return
+	}
+
+	w.log.Debug("access logs are written", zap.String("bucket", bucket),
+		zap.String("target", t.bktInfo.Name), zap.String("key", key), zap.Int("records", b.records))
+}
+
+// logObjectKey returns a key of the log object in the standard format:
+// TargetPrefixYYYY-mm-DD-HH-MM-SS-UniqueString.
+func logObjectKey(prefix string, now time.Time) string {
+	unique := make([]byte, 8)
+	_, _ = rand.Read(unique)
+
+	return prefix + now.UTC().Format("2006-01-02-15-04-05") + "-" + strings.ToUpper(hex.EncodeToString(unique))
+}
+
+// writeRecord writes the record in the format of S3 server access logs:
+//
+//	owner bucket [time] remote-ip requester request-id operation key "request-uri" status error-code
+//	bytes-sent object-size total-time turn-around-time "referer" "user-agent" version-id host-id
+//	signature-version cipher-suite authentication-type host-header tls-version
+//
+// Fields which aren't known are written as "-".
+func writeRecord(buf *bytes.Buffer, owner string, rec *api.AccessLogRecord) {
+	fields := []string{
+		owner,
+		rec.Bucket,
+		rec.Time.UTC().Format(logTimeFormat),
+		orDash(rec.RemoteIP),
+		orDash(rec.Requester),
+		orDash(rec.RequestID),
+		orDash(rec.Operation),
+		orDash(escapeKey(rec.Key)),
+		strconv.Quote(rec.RequestURI),
+		strconv.Itoa(rec.Status),
+		orDash(rec.ErrorCode),
+		strconv.FormatInt(rec.BytesSent, 10),
+		"-",
+		strconv.FormatInt(rec.TotalTime.Milliseconds(), 10),
+		"-",
+		quoteOrDash(rec.Referer),
+		quoteOrDash(rec.UserAgent),
+		orDash(rec.VersionID),
+		"-",
+		"-",
+		"-",
+		"-",
+		orDash(rec.Host),
+		"-",
+	}
+
+	buf.WriteString(strings.Join(fields, " "))
+	buf.WriteByte('\n')
+}
+
+// escapeKey encodes the object key like in the request path.
+func escapeKey(key string) string {
+	return strings.ReplaceAll(url.PathEscape(key), "%2F", "/")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func quoteOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,62 @@
+package accesslog
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLogObjectKey(t *testing.T) {
+	now := time.Date(2022, 7, 1, 13, 5, 9, 0, time.UTC)
+
+	key := logObjectKey("logs/", now)
+	require.Regexp(t, regexp.MustCompile(`^logs/2022-07-01-13-05-09-[0-9A-F]{16}$`), key)
+	require.NotEqual(t, key, logObjectKey("logs/", now))
+}
+
+func TestWriteRecord(t *testing.T) {
+	rec := &api.AccessLogRecord{
+		Time:       time.Date(2022, 7, 1, 13, 5, 9, 0, time.UTC),
+		RemoteIP:   "192.0.2.3",
+		Requester:  "NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM",
+		RequestID:  "3E57427F33A59F07",
+		Operation:  "GetObject",
+		Bucket:     "bucket",
+		Key:        "dir/my photo.png",
+		RequestURI: "GET /bucket/dir/my%20photo.png HTTP/1.1",
+		Status:     404,
+		ErrorCode:  "NoSuchKey",
+		TotalTime:  12 * time.Millisecond,
+		UserAgent:  "aws-cli/2.7.0",
+		Host:       "s3.neofs.devenv",
+	}
+
+	buf := new(bytes.Buffer)
+	writeRecord(buf, "owner", rec)
+	writeRecord(buf, "owner", &api.AccessLogRecord{Time: rec.Time, Bucket: "bucket", Status: 200})
+
+	require.Equal(t, `owner bucket [01/Jul/2022:13:05:09 +0000] 192.0.2.3 NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM `+
+		`3E57427F33A59F07 GetObject dir/my%20photo.png "GET /bucket/dir/my%20photo.png HTTP/1.1" 404 NoSuchKey 0 - 12 - `+
+		`- "aws-cli/2.7.0" - - - - - s3.neofs.devenv -`+"\n"+
+		`owner bucket [01/Jul/2022:13:05:09 +0000] - - - - - "" 200 - 0 - 0 - - - - - - - - - -`+"\n", buf.String())
+}
+
+func TestWriterLog(t *testing.T) {
+	w, err := NewWriter(zap.NewNop(), nil, nil, &Config{BufferSize: 1})
+	require.NoError(t, err)
+
+	// the second record is dropped, Log mustn't block
+	w.Log(&api.AccessLogRecord{Bucket: "bucket", RequestID: "1"})
+	w.Log(&api.AccessLogRecord{Bucket: "bucket", RequestID: "2"})
+
+	require.Len(t, w.records, 1)
+	require.Equal(t, "1", (<-w.records).RequestID)
+
+	_, err = NewWriter(zap.NewNop(), nil, nil, &Config{AccessKeys: []string{"invalid"}})
+	require.Error(t, err)
+}
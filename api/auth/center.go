@@ -37,9 +37,12 @@ type (
 	}
 
 	center struct {
-		reg     *regexpSubmatcher
-		postReg *regexpSubmatcher
-		cli     tokens.Credentials
+		reg         *regexpSubmatcher
+		regV2       *regexpSubmatcher
+		postReg     *regexpSubmatcher
+		cli         tokens.Credentials
+		registry    *tokens.Registry
+		signatureV2 bool
 	}
 
 	prs int
@@ -69,6 +72,12 @@ const (
 	AmzDate          = "X-Amz-Date"
 	AuthorizationHdr = "Authorization"
 	ContentTypeHdr   = "Content-Type"
+
+	AmzContentSHA256        = "X-Amz-Content-Sha256"
+	UnsignedPayload         = "UNSIGNED-PAYLOAD"
+	AmzDecodedContentLength = "X-Amz-Decoded-Content-Length"
+	AmzTrailer              = "X-Amz-Trailer"
+	ContentEncodingHdr      = "Content-Encoding"
 )
 
 // ErrNoAuthorizationHeader is returned for unauthenticated requests.
@@ -84,12 +93,17 @@ func (p prs) Seek(_ int64, _ int) (int64, error) {
 
 var _ io.ReadSeeker = prs(0)
 
-// New creates an instance of AuthCenter.
-func New(neoFS tokens.NeoFS, key *keys.PrivateKey, config *cache.Config) Center {
+// New creates an instance of AuthCenter. Registry is used to resolve access key
+// IDs which aren't access box addresses, it can be nil if they aren't used.
+// Requests signed with AWS Signature Version 2 are accepted if signatureV2 is true.
+func New(neoFS tokens.NeoFS, key *keys.PrivateKey, config *cache.Config, registry *tokens.Registry, signatureV2 bool) Center {
 	return &center{
-		cli:     tokens.New(neoFS, key, config),
-		reg:     &regexpSubmatcher{re: authorizationFieldRegexp},
-		postReg: &regexpSubmatcher{re: postPolicyCredentialRegexp},
+		cli:         tokens.New(neoFS, key, config),
+		reg:         &regexpSubmatcher{re: authorizationFieldRegexp},
+		regV2:       &regexpSubmatcher{re: authorizationV2Regexp},
+		postReg:     &regexpSubmatcher{re: postPolicyCredentialRegexp},
+		registry:    registry,
+		signatureV2: signatureV2,
 	}
 }
 
@@ -100,7 +114,7 @@ func (c *center) parseAuthHeader(header string) (*authHeader, error) {
 	}
 
 	accessKey := strings.Split(submatches["access_key_id"], "0")
-	if len(accessKey) != accessKeyPartsNum {
+	if len(accessKey) != accessKeyPartsNum && !tokens.IsAccessKeyAlias(submatches["access_key_id"]) {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
 	}
 
@@ -124,6 +138,36 @@ func (a *authHeader) getAddress() (oid.Address, error) {
 	return addr, nil
 }
 
+// getBox returns the access box of the access key ID, the access key ID is
+// either the access box address or the alias from the registry.
+func (c *center) getBox(ctx context.Context, authHdr *authHeader) (*accessbox.Box, error) {
+	var (
+		addr oid.Address
+		err  error
+	)
+
+	if c.registry != nil && tokens.IsAccessKeyAlias(authHdr.AccessKeyID) {
+		if addr, err = c.registry.Resolve(ctx, authHdr.AccessKeyID); err != nil {
+			if errors.Is(err, tokens.ErrAccessKeyNotFound) {
+				return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
+			}
+			return nil, fmt.Errorf("resolve access key id: %w", err)
+		}
+	} else if addr, err = authHdr.getAddress(); err != nil {
+		return nil, err
+	}
+
+	box, err := c.cli.GetBox(ctx, addr)
+	if err != nil {
+		if errors.Is(err, tokens.ErrAccessBoxRevoked) {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
+		}
+		return nil, fmt.Errorf("get box: %w", err)
+	}
+
+	return box, nil
+}
+
 func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 	var (
 		err                  error
@@ -132,6 +176,13 @@ func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 	)
 
 	queryValues := r.URL.Query()
+	if queryValues.Get(AmzAlgorithm) != "AWS4-HMAC-SHA256" && isSignatureV2(r) {
+		if !c.signatureV2 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureVersionNotSupported)
+		}
+		return c.authenticateV2(r)
+	}
+
 	if queryValues.Get(AmzAlgorithm) == "AWS4-HMAC-SHA256" {
 		creds := strings.Split(queryValues.Get(AmzCredential), "/")
 		if len(creds) != 5 || creds[4] != "aws4_request" {
@@ -157,6 +208,9 @@ func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 			if strings.HasPrefix(r.Header.Get(ContentTypeHdr), "multipart/form-data") {
 				return c.checkFormData(r)
 			}
+			if err = prepareStreamingRequest(r, nil); err != nil {
+				return nil, err
+			}
 			return nil, ErrNoAuthorizationHeader
 		}
 		authHdr, err = c.parseAuthHeader(authHeaderField[0])
@@ -171,21 +225,21 @@ func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 		return nil, fmt.Errorf("failed to parse x-amz-date header field: %w", err)
 	}
 
-	addr, err := authHdr.getAddress()
+	box, err := c.getBox(r.Context(), authHdr)
 	if err != nil {
 		return nil, err
 	}
 
-	box, err := c.cli.GetBox(r.Context(), addr)
-	if err != nil {
-		return nil, fmt.Errorf("get box: %w", err)
-	}
-
 	clonedRequest := cloneRequest(r, authHdr)
 	if err = c.checkSign(authHdr, box, clonedRequest, signatureDateTime); err != nil {
 		return nil, err
 	}
 
+	signer := newChunkSigner(box.Gate.AccessKey, authHdr.Service, authHdr.Region, signatureDateTime, authHdr.SignatureV4)
+	if err = prepareStreamingRequest(r, signer); err != nil {
+		return nil, err
+	}
+
 	return box, nil
 }
 
@@ -203,6 +257,13 @@ func (c *center) checkFormData(r *http.Request) (*accessbox.Box, error) {
 		return nil, ErrNoAuthorizationHeader
 	}
 
+	if MultipartFormValue(r, "awsaccesskeyid") != "" {
+		if !c.signatureV2 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureVersionNotSupported)
+		}
+		return c.checkFormDataV2(r, policy)
+	}
+
 	submatches := c.postReg.getSubmatches(MultipartFormValue(r, "x-amz-credential"))
 	if len(submatches) != 4 {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrAuthorizationHeaderMalformed)
@@ -213,14 +274,9 @@ func (c *center) checkFormData(r *http.Request) (*accessbox.Box, error) {
 		return nil, fmt.Errorf("failed to parse x-amz-date field: %w", err)
 	}
 
-	var addr oid.Address
-	if err = addr.DecodeString(strings.ReplaceAll(submatches["access_key_id"], "0", "/")); err != nil {
-		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
-	}
-
-	box, err := c.cli.GetBox(r.Context(), addr)
+	box, err := c.getBox(r.Context(), &authHeader{AccessKeyID: submatches["access_key_id"]})
 	if err != nil {
-		return nil, fmt.Errorf("get box: %w", err)
+		return nil, err
 	}
 
 	secret := box.Gate.AccessKey
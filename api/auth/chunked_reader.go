@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+const (
+	// StreamingContentSHA256 is a value of x-amz-content-sha256 header for aws-chunked payload with signed chunks.
+	StreamingContentSHA256 = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	// StreamingContentSHA256Trailer is a value of x-amz-content-sha256 header for aws-chunked payload
+	// with signed chunks and signed trailing headers.
+	StreamingContentSHA256Trailer = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	// StreamingUnsignedPayloadTrailer is a value of x-amz-content-sha256 header for aws-chunked payload
+	// with unsigned chunks and trailing headers.
+	StreamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+	awsChunkedEncoding = "aws-chunked"
+
+	chunkSignatureKey     = "chunk-signature"
+	trailerSignatureKey   = "x-amz-trailer-signature"
+	checksumTrailerPrefix = "x-amz-checksum-"
+
+	// maxChunkSize limits the size of a single chunk which is kept in memory until its signature is verified.
+	maxChunkSize = 16 << 20 // 16 MB
+	// maxChunkLineSize limits the size of chunk header and trailer lines.
+	maxChunkLineSize = 4096
+
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var errMalformedChunk = errors.New("malformed aws-chunked payload")
+
+type (
+	// chunkSigner calculates signatures of aws-chunked payload chunks.
+	// Every signature depends on the previous one, the first chunk is chained to the seed signature of the request.
+	chunkSigner struct {
+		key      []byte
+		dateTime string
+		scope    string
+		prevSig  string
+	}
+
+	// chunkedReader decodes aws-chunked payload, verifies chunk signatures and trailing checksums.
+	// Chunk data is returned only after its signature is verified.
+	chunkedReader struct {
+		reader *bufio.Reader
+		closer io.Closer
+
+		// signer is nil for unsigned payload.
+		signer *chunkSigner
+
+		trailer  string
+		checksum hash.Hash
+
+		decodedLength int64
+		read          int64
+
+		chunk []byte
+		err   error
+	}
+)
+
+func newChunkSigner(secret, service, region string, t time.Time, seedSignature string) *chunkSigner {
+	return &chunkSigner{
+		key:      deriveKey(secret, service, region, t),
+		dateTime: t.UTC().Format("20060102T150405Z"),
+		scope:    strings.Join([]string{t.UTC().Format("20060102"), region, service, "aws4_request"}, "/"),
+		prevSig:  seedSignature,
+	}
+}
+
+func (s *chunkSigner) verify(algorithm, payloadHash, signature string) bool {
+	strToSign := []string{algorithm, s.dateTime, s.scope, s.prevSig}
+	if algorithm == "AWS4-HMAC-SHA256-PAYLOAD" {
+		strToSign = append(strToSign, emptySHA256)
+	}
+	strToSign = append(strToSign, payloadHash)
+
+	expected := hex.EncodeToString(hmacSHA256(s.key, []byte(strings.Join(strToSign, "\n"))))
+	s.prevSig = expected
+
+	return expected == signature
+}
+
+func (s *chunkSigner) verifyChunk(data []byte, signature string) bool {
+	hash := sha256.Sum256(data)
+	return s.verify("AWS4-HMAC-SHA256-PAYLOAD", hex.EncodeToString(hash[:]), signature)
+}
+
+func (s *chunkSigner) verifyTrailer(trailer []byte, signature string) bool {
+	hash := sha256.Sum256(trailer)
+	return s.verify("AWS4-HMAC-SHA256-TRAILER", hex.EncodeToString(hash[:]), signature)
+}
+
+// prepareStreamingRequest replaces the body of aws-chunked request with a reader which decodes the payload.
+// Content length of the request is set to x-amz-decoded-content-length. Signer is required for
+// signed payload and is ignored for unsigned one. Requests with other payload types are not modified.
+func prepareStreamingRequest(r *http.Request, signer *chunkSigner) error {
+	reader := &chunkedReader{
+		closer: r.Body,
+	}
+
+	var signed bool
+	switch r.Header.Get(AmzContentSHA256) {
+	case StreamingContentSHA256:
+		signed = true
+	case StreamingContentSHA256Trailer:
+		signed = true
+		reader.trailer = strings.ToLower(strings.TrimSpace(r.Header.Get(AmzTrailer)))
+	case StreamingUnsignedPayloadTrailer:
+		reader.trailer = strings.ToLower(strings.TrimSpace(r.Header.Get(AmzTrailer)))
+	default:
+		return nil
+	}
+
+	if signed {
+		if signer == nil {
+			return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+		}
+		reader.signer = signer
+	}
+
+	decodedLength, err := strconv.ParseInt(r.Header.Get(AmzDecodedContentLength), 10, 64)
+	if err != nil || decodedLength < 0 {
+		return apiErrors.GetAPIError(apiErrors.ErrMissingContentLength)
+	}
+	reader.decodedLength = decodedLength
+
+	if reader.trailer != "" {
+		if reader.checksum = newChecksumHash(reader.trailer); reader.checksum == nil {
+			return apiErrors.GetAPIErrorWithError(apiErrors.ErrInvalidRequest, fmt.Errorf("unsupported trailer '%s'", reader.trailer))
+		}
+	}
+
+	reader.reader = bufio.NewReaderSize(r.Body, maxChunkLineSize)
+
+	r.Body = reader
+	r.ContentLength = decodedLength
+	removeAWSChunkedEncoding(r.Header)
+
+	return nil
+}
+
+// removeAWSChunkedEncoding removes aws-chunked from Content-Encoding header because it describes
+// transfer of the payload rather than the payload itself.
+func removeAWSChunkedEncoding(header http.Header) {
+	encodings := strings.Split(header.Get(ContentEncodingHdr), ",")
+	result := encodings[:0]
+	for _, encoding := range encodings {
+		if encoding = strings.TrimSpace(encoding); encoding != "" && encoding != awsChunkedEncoding {
+			result = append(result, encoding)
+		}
+	}
+
+	if len(result) == 0 {
+		header.Del(ContentEncodingHdr)
+		return
+	}
+	header.Set(ContentEncodingHdr, strings.Join(result, ","))
+}
+
+// newChecksumHash returns a hash which is used to calculate the checksum for the trailing header.
+func newChecksumHash(trailer string) hash.Hash {
+	if !strings.HasPrefix(trailer, checksumTrailerPrefix) {
+		return nil
+	}
+	return data.NewChecksumHash(strings.ToUpper(strings.TrimPrefix(trailer, checksumTrailerPrefix)))
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		c.chunk, c.err = c.readChunk()
+	}
+
+	n := copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+
+	return n, nil
+}
+
+func (c *chunkedReader) Close() error {
+	return c.closer.Close()
+}
+
+func (c *chunkedReader) readChunk() ([]byte, error) {
+	line, err := c.readLine()
+	if err == io.EOF {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrIncompleteBody)
+	} else if err != nil {
+		return nil, err
+	}
+
+	size, signature, err := c.parseChunkHeader(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.read+size > c.decodedLength {
+		return nil, apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody,
+			fmt.Errorf("payload is longer than x-amz-decoded-content-length"))
+	}
+
+	data := make([]byte, size)
+	if _, err = io.ReadFull(c.reader, data); err != nil {
+		return nil, apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, err)
+	}
+
+	if size > 0 {
+		if line, err = c.readLine(); err != nil || len(line) != 0 {
+			return nil, apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, errMalformedChunk)
+		}
+	}
+
+	if c.signer != nil && !c.signer.verifyChunk(data, signature) {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
+	}
+
+	if size > 0 {
+		c.read += size
+		if c.checksum != nil {
+			c.checksum.Write(data)
+		}
+		return data, nil
+	}
+
+	if c.read != c.decodedLength {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrIncompleteBody)
+	}
+
+	if err = c.readTrailers(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func (c *chunkedReader) parseChunkHeader(line string) (int64, string, error) {
+	parts := strings.SplitN(line, ";", 2)
+
+	size, err := strconv.ParseInt(parts[0], 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return 0, "", apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, errMalformedChunk)
+	}
+
+	if c.signer == nil {
+		return size, "", nil
+	}
+
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], chunkSignatureKey+"=") {
+		return 0, "", apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
+	}
+
+	return size, strings.TrimPrefix(parts[1], chunkSignatureKey+"="), nil
+}
+
+func (c *chunkedReader) readTrailers() error {
+	var (
+		trailers         = make(map[string]string)
+		signedTrailers   bytes.Buffer
+		trailerSignature string
+	)
+
+	for {
+		line, err := c.readLine()
+		if err == io.EOF || err == nil && len(line) == 0 {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			return apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, errMalformedChunk)
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		value := strings.TrimSpace(kv[1])
+
+		if key == trailerSignatureKey {
+			trailerSignature = value
+			continue
+		}
+
+		trailers[key] = value
+		signedTrailers.WriteString(key + ":" + value + "\n")
+	}
+
+	if c.signer != nil && c.trailer != "" && !c.signer.verifyTrailer(signedTrailers.Bytes(), trailerSignature) {
+		return apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
+	}
+
+	if c.checksum == nil {
+		return nil
+	}
+
+	expected, ok := trailers[c.trailer]
+	if !ok {
+		return apiErrors.GetAPIErrorWithError(apiErrors.ErrInvalidRequest, fmt.Errorf("missing trailer '%s'", c.trailer))
+	}
+
+	if actual := base64.StdEncoding.EncodeToString(c.checksum.Sum(nil)); actual != expected {
+		return apiErrors.GetAPIErrorWithError(apiErrors.ErrBadDigest, fmt.Errorf("'%s' trailer does not match calculated checksum", c.trailer))
+	}
+
+	return nil
+}
+
+// readLine reads a line terminated by CRLF and returns it without the terminator.
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.reader.ReadSlice('\n')
+	if err != nil {
+		if err == io.EOF && len(line) == 0 {
+			return "", io.EOF
+		}
+		return "", apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, errMalformedChunk)
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return "", apiErrors.GetAPIErrorWithError(apiErrors.ErrIncompleteBody, errMalformedChunk)
+	}
+
+	return string(line[:len(line)-2]), nil
+}
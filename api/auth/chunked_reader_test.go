@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// Example from https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html
+const (
+	exampleSecretKey     = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	exampleSeedSignature = "4f232c4386841ef735655705268965c44a0e4690baa4adea153f7db9fa80a0a9"
+)
+
+func exampleSigner(t *testing.T) *chunkSigner {
+	signTime, err := time.Parse("20060102T150405Z", "20130524T000000Z")
+	require.NoError(t, err)
+
+	return newChunkSigner(exampleSecretKey, "s3", "us-east-1", signTime, exampleSeedSignature)
+}
+
+func examplePayload() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("10000;chunk-signature=ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648\r\n")
+	buf.Write(bytes.Repeat([]byte{'a'}, 65536))
+	buf.WriteString("\r\n")
+	buf.WriteString("400;chunk-signature=0055627c9e194cb4542bae2aa5492e3c1575bbb81b612b7d234b86a503ef5497\r\n")
+	buf.Write(bytes.Repeat([]byte{'a'}, 1024))
+	buf.WriteString("\r\n")
+	buf.WriteString("0;chunk-signature=b6c6ea8a5354eaf15b3cb7646744f4275b71ea724fed81ceb9323e279d449df9\r\n\r\n")
+	return buf.Bytes()
+}
+
+func prepareChunkedRequest(payload []byte, contentSHA256 string, decodedLength int) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, "/bucket/object", bytes.NewReader(payload))
+	r.Header.Set(AmzContentSHA256, contentSHA256)
+	r.Header.Set(AmzDecodedContentLength, strconv.Itoa(decodedLength))
+	r.Header.Set(ContentEncodingHdr, awsChunkedEncoding)
+	return r
+}
+
+func TestChunkedReaderSigned(t *testing.T) {
+	expected := bytes.Repeat([]byte{'a'}, 66560)
+
+	t.Run("valid", func(t *testing.T) {
+		r := prepareChunkedRequest(examplePayload(), StreamingContentSHA256, len(expected))
+		require.NoError(t, prepareStreamingRequest(r, exampleSigner(t)))
+		require.EqualValues(t, len(expected), r.ContentLength)
+		require.Empty(t, r.Header.Get(ContentEncodingHdr))
+
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+
+	t.Run("tampered chunk", func(t *testing.T) {
+		payload := examplePayload()
+		payload[100] = 'b'
+
+		r := prepareChunkedRequest(payload, StreamingContentSHA256, len(expected))
+		require.NoError(t, prepareStreamingRequest(r, exampleSigner(t)))
+
+		_, err := io.ReadAll(r.Body)
+		require.Equal(t, errors.GetAPIError(errors.ErrSignatureDoesNotMatch), err)
+	})
+
+	t.Run("decoded length mismatch", func(t *testing.T) {
+		r := prepareChunkedRequest(examplePayload(), StreamingContentSHA256, len(expected)+1)
+		require.NoError(t, prepareStreamingRequest(r, exampleSigner(t)))
+
+		_, err := io.ReadAll(r.Body)
+		require.Equal(t, errors.GetAPIError(errors.ErrIncompleteBody), err)
+	})
+
+	t.Run("no signer", func(t *testing.T) {
+		r := prepareChunkedRequest(examplePayload(), StreamingContentSHA256, len(expected))
+		err := prepareStreamingRequest(r, nil)
+		require.Equal(t, errors.GetAPIError(errors.ErrAccessDenied), err)
+	})
+
+	t.Run("missing decoded length", func(t *testing.T) {
+		r := prepareChunkedRequest(examplePayload(), StreamingContentSHA256, 0)
+		r.Header.Del(AmzDecodedContentLength)
+		err := prepareStreamingRequest(r, exampleSigner(t))
+		require.Equal(t, errors.GetAPIError(errors.ErrMissingContentLength), err)
+	})
+}
+
+func TestChunkedReaderUnsignedTrailer(t *testing.T) {
+	content := []byte("hello world")
+	checksum := crc32.ChecksumIEEE(content)
+	sum := base64.StdEncoding.EncodeToString([]byte{byte(checksum >> 24), byte(checksum >> 16), byte(checksum >> 8), byte(checksum)})
+
+	payload := func(trailer string) []byte {
+		return []byte("b\r\n" + string(content) + "\r\n0\r\n" + trailer + "\r\n\r\n")
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		r := prepareChunkedRequest(payload("x-amz-checksum-crc32:"+sum), StreamingUnsignedPayloadTrailer, len(content))
+		r.Header.Set(AmzTrailer, "x-amz-checksum-crc32")
+		require.NoError(t, prepareStreamingRequest(r, nil))
+
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, content, data)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		r := prepareChunkedRequest(payload("x-amz-checksum-crc32:AAAAAA=="), StreamingUnsignedPayloadTrailer, len(content))
+		r.Header.Set(AmzTrailer, "x-amz-checksum-crc32")
+		require.NoError(t, prepareStreamingRequest(r, nil))
+
+		_, err := io.ReadAll(r.Body)
+		require.Error(t, err)
+		require.Equal(t, errors.ErrBadDigest, err.(errors.Error).ErrCode)
+	})
+
+	t.Run("unsupported trailer", func(t *testing.T) {
+		r := prepareChunkedRequest(payload("x-amz-checksum-md5:"+sum), StreamingUnsignedPayloadTrailer, len(content))
+		r.Header.Set(AmzTrailer, "x-amz-checksum-md5")
+		err := prepareStreamingRequest(r, nil)
+		require.Error(t, err)
+		require.Equal(t, errors.ErrInvalidRequest, err.(errors.Error).ErrCode)
+	})
+}
+
+func TestRemoveAWSChunkedEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		header   string
+		expected string
+	}{
+		{header: "aws-chunked", expected: ""},
+		{header: "aws-chunked,gzip", expected: "gzip"},
+		{header: "gzip, aws-chunked", expected: "gzip"},
+		{header: "gzip", expected: "gzip"},
+	} {
+		header := make(http.Header)
+		header.Set(ContentEncodingHdr, tc.header)
+		removeAWSChunkedEncoding(header)
+		require.Equal(t, tc.expected, header.Get(ContentEncodingHdr))
+	}
+}
@@ -0,0 +1,377 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+)
+
+// authorizationV2Regexp -- is regexp for credentials of AWS Signature Version 2.
+var authorizationV2Regexp = regexp.MustCompile(`^AWS (?P<access_key_id>[^:\s]+):(?P<v2_signature>\S+)$`)
+
+// Query parameters of presigned URLs of AWS Signature Version 2.
+const (
+	AmzAccessKeyIDV2 = "AWSAccessKeyId"
+	AmzSignatureV2   = "Signature"
+	AmzExpiresV2     = "Expires"
+)
+
+// maxRequestTimeSkewV2 is the maximum difference between the request date and the server time.
+const maxRequestTimeSkewV2 = 15 * time.Minute
+
+// dateFormatsV2 are formats of Date and x-amz-date headers: RFC1123, RFC1123Z, RFC850, ANSI C and ISO8601.
+var dateFormatsV2 = []string{
+	http.TimeFormat,
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.ANSIC,
+	"20060102T150405Z",
+}
+
+// subResourcesV2 are query parameters included in the canonicalized resource of AWS Signature Version 2.
+var subResourcesV2 = map[string]struct{}{
+	"accelerate":                   {},
+	"acl":                          {},
+	"attributes":                   {},
+	"cors":                         {},
+	"delete":                       {},
+	"encryption":                   {},
+	"legal-hold":                   {},
+	"lifecycle":                    {},
+	"location":                     {},
+	"logging":                      {},
+	"notification":                 {},
+	"object-lock":                  {},
+	"partNumber":                   {},
+	"policy":                       {},
+	"replication":                  {},
+	"requestPayment":               {},
+	"response-cache-control":       {},
+	"response-content-disposition": {},
+	"response-content-encoding":    {},
+	"response-content-language":    {},
+	"response-content-type":        {},
+	"response-expires":             {},
+	"restore":                      {},
+	"retention":                    {},
+	"select":                       {},
+	"select-type":                  {},
+	"tagging":                      {},
+	"torrent":                      {},
+	"uploadId":                     {},
+	"uploads":                      {},
+	"versionId":                    {},
+	"versioning":                   {},
+	"versions":                     {},
+	"website":                      {},
+}
+
+// isSignatureV2 checks if the request is signed with AWS Signature Version 2.
+func isSignatureV2(r *http.Request) bool {
+	query := r.URL.Query()
+	if query.Get(AmzAccessKeyIDV2) != "" && query.Get(AmzSignatureV2) != "" {
+		return true
+	}
+	return strings.HasPrefix(r.Header.Get(AuthorizationHdr), "AWS ")
+}
+
+// authenticateV2 checks AWS Signature Version 2 of the request signed in
+// the Authorization header or in the query of the presigned URL.
+func (c *center) authenticateV2(r *http.Request) (*accessbox.Box, error) {
+	var (
+		authHdr     authHeader
+		signature   string
+		date        string
+		requestTime time.Time
+	)
+
+	query := r.URL.Query()
+	if query.Get(AmzAccessKeyIDV2) != "" {
+		expires, err := strconv.ParseInt(query.Get(AmzExpiresV2), 10, 64)
+		if err != nil {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrMalformedExpires)
+		}
+		if time.Now().Unix() > expires {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrExpiredPresignRequest)
+		}
+
+		authHdr.AccessKeyID = query.Get(AmzAccessKeyIDV2)
+		authHdr.IsPresigned = true
+		signature = query.Get(AmzSignatureV2)
+		date = query.Get(AmzExpiresV2)
+	} else {
+		submatches := c.regV2.getSubmatches(r.Header.Get(AuthorizationHdr))
+		if len(submatches) != 2 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrAuthorizationHeaderMalformed)
+		}
+
+		authHdr.AccessKeyID = submatches["access_key_id"]
+		signature = submatches["v2_signature"]
+
+		// Date is replaced by x-amz-date which is a part of canonicalized amz headers
+		requestDate := r.Header.Get(AmzDate)
+		if requestDate == "" {
+			if date = r.Header.Get("Date"); date == "" {
+				return nil, apiErrors.GetAPIError(apiErrors.ErrMissingDateHeader)
+			}
+			requestDate = date
+		}
+
+		var err error
+		if requestTime, err = parseDateV2(requestDate); err != nil {
+			return nil, err
+		}
+	}
+
+	box, err := c.getBox(r.Context(), &authHdr)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched bool
+	for _, resource := range canonicalResourcesV2(r) {
+		expected := signV2(box.Gate.AccessKey, stringToSignV2(r, date, resource))
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
+	}
+
+	// presigned URLs are limited by the expiration time instead
+	if !authHdr.IsPresigned {
+		if skew := time.Since(requestTime); skew > maxRequestTimeSkewV2 || skew < -maxRequestTimeSkewV2 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrRequestTimeTooSkewed)
+		}
+	}
+
+	if err = prepareStreamingRequest(r, nil); err != nil {
+		return nil, err
+	}
+
+	return box, nil
+}
+
+// parseDateV2 parses the request date of AWS Signature Version 2.
+func parseDateV2(value string) (time.Time, error) {
+	for _, format := range dateFormatsV2 {
+		if t, err := time.Parse(format, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, apiErrors.GetAPIError(apiErrors.ErrMalformedDate)
+}
+
+// checkFormDataV2 checks AWS Signature Version 2 of the POST policy.
+func (c *center) checkFormDataV2(r *http.Request, policy string) (*accessbox.Box, error) {
+	box, err := c.getBox(r.Context(), &authHeader{AccessKeyID: MultipartFormValue(r, "awsaccesskeyid")})
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal([]byte(signV2(box.Gate.AccessKey, policy)), []byte(MultipartFormValue(r, "signature"))) {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
+	}
+
+	if err = checkPostPolicyV2(r, policy); err != nil {
+		return nil, err
+	}
+
+	return box, nil
+}
+
+// postPolicyV2 is the POST policy document of the form signed with AWS Signature Version 2.
+type postPolicyV2 struct {
+	Expiration string            `json:"expiration"`
+	Conditions []json.RawMessage `json:"conditions"`
+}
+
+// checkPostPolicyV2 checks the expiration of the POST policy and its conditions on the bucket,
+// the object key and the content length. The signature covers the policy only, so the request
+// is denied if the form doesn't match it.
+func checkPostPolicyV2(r *http.Request, policy string) error {
+	document, err := base64.StdEncoding.DecodeString(policy)
+	if err != nil {
+		return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+	}
+
+	var p postPolicyV2
+	if err = json.Unmarshal(document, &p); err != nil {
+		return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+	}
+
+	expiration, err := time.Parse(time.RFC3339, p.Expiration)
+	if err != nil {
+		return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+	}
+	if time.Now().After(expiration) {
+		return apiErrors.GetAPIError(apiErrors.ErrExpiredPresignRequest)
+	}
+
+	for _, raw := range p.Conditions {
+		if !postConditionV2Matches(r, raw) {
+			return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+		}
+	}
+
+	return nil
+}
+
+// postConditionV2Matches checks the condition of the POST policy, conditions on the fields
+// other than the bucket, the key and the content length are checked by the handler.
+func postConditionV2Matches(r *http.Request, raw json.RawMessage) bool {
+	var exact map[string]string
+	if err := json.Unmarshal(raw, &exact); err == nil {
+		for field, value := range exact {
+			if actual, ok := postFieldV2(r, field); ok && actual != value {
+				return false
+			}
+		}
+		return true
+	}
+
+	var condition []interface{}
+	if err := json.Unmarshal(raw, &condition); err != nil || len(condition) != 3 {
+		return false
+	}
+
+	matching, _ := condition[0].(string)
+	if strings.EqualFold(matching, "content-length-range") {
+		minSize, ok := condition[1].(float64)
+		maxSize, ok2 := condition[2].(float64)
+		if !ok || !ok2 {
+			return false
+		}
+		files := r.MultipartForm.File["file"]
+		if len(files) == 0 {
+			return true
+		}
+		return float64(files[0].Size) >= minSize && float64(files[0].Size) <= maxSize
+	}
+
+	field, ok := condition[1].(string)
+	value, ok2 := condition[2].(string)
+	if !ok || !ok2 || !strings.HasPrefix(field, "$") {
+		return false
+	}
+
+	actual, ok := postFieldV2(r, strings.TrimPrefix(field, "$"))
+	if !ok {
+		return true
+	}
+
+	switch strings.ToLower(matching) {
+	case "eq":
+		return actual == value
+	case "starts-with":
+		return strings.HasPrefix(actual, value)
+	default:
+		return false
+	}
+}
+
+// postFieldV2 returns the value of the form field checked by the gateway, the second
+// result is false for the fields checked by the handler.
+func postFieldV2(r *http.Request, field string) (string, bool) {
+	switch strings.ToLower(field) {
+	case "bucket":
+		return mux.Vars(r)["bucket"], true
+	case "key":
+		return MultipartFormValue(r, "key"), true
+	default:
+		return "", false
+	}
+}
+
+// stringToSignV2 returns the string to sign of AWS Signature Version 2, date is
+// the Date header or Expires query parameter of the presigned URL.
+func stringToSignV2(r *http.Request, date, resource string) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method + "\n")
+	sb.WriteString(r.Header.Get("Content-MD5") + "\n")
+	sb.WriteString(r.Header.Get(ContentTypeHdr) + "\n")
+	sb.WriteString(date + "\n")
+
+	var amzHeaders []string
+	for key := range r.Header {
+		if lowerKey := strings.ToLower(key); strings.HasPrefix(lowerKey, "x-amz-") {
+			amzHeaders = append(amzHeaders, lowerKey)
+		}
+	}
+	sort.Strings(amzHeaders)
+
+	for _, key := range amzHeaders {
+		var values []string
+		for _, value := range r.Header.Values(key) {
+			values = append(values, strings.TrimSpace(value))
+		}
+		sb.WriteString(key + ":" + strings.Join(values, ",") + "\n")
+	}
+
+	sb.WriteString(resource)
+
+	return sb.String()
+}
+
+// canonicalResourcesV2 returns possible canonicalized resources of the request.
+// The bucket of virtual-hosted-style requests isn't a part of the path, so
+// the resource with the bucket is tried too.
+func canonicalResourcesV2(r *http.Request) []string {
+	query := r.URL.Query()
+
+	var keys []string
+	for key := range query {
+		if _, ok := subResourcesV2[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	subResources := make([]string, len(keys))
+	for i, key := range keys {
+		subResources[i] = key
+		if value := query.Get(key); value != "" {
+			subResources[i] += "=" + value
+		}
+	}
+
+	var rawQuery string
+	if len(subResources) != 0 {
+		rawQuery = "?" + strings.Join(subResources, "&")
+	}
+
+	path := r.URL.EscapedPath()
+	resources := []string{path + rawQuery}
+
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if bucket := mux.Vars(r)["bucket"]; bucket != "" && strings.HasPrefix(host, bucket+".") {
+		resources = append(resources, fmt.Sprintf("/%s%s%s", bucket, path, rawQuery))
+	}
+
+	return resources
+}
+
+func signV2(secret, stringToSign string) string {
+	hash := hmac.New(sha1.New, []byte(secret))
+	hash.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
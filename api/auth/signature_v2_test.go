@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+// secret access key of the examples from AWS Signature Version 2 documentation.
+const exampleSecretV2 = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+
+type secretCredentials struct {
+	tokens.Credentials
+	secret string
+}
+
+func (c *secretCredentials) GetBox(context.Context, oid.Address) (*accessbox.Box, error) {
+	return &accessbox.Box{Gate: &accessbox.GateData{AccessKey: c.secret}}, nil
+}
+
+func TestStringToSignV2(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		method    string
+		url       string
+		host      string
+		bucket    string
+		headers   map[string]string
+		date      string
+		signature string
+		expected  string
+	}{
+		{
+			name:      "path style",
+			method:    http.MethodGet,
+			url:       "/johnsmith/photos/puppy.jpg",
+			bucket:    "johnsmith",
+			date:      "Tue, 27 Mar 2007 19:36:42 +0000",
+			signature: "bWq2s1WEIj+Ydj0vQ697zp+IXMU=",
+			expected:  "GET\n\n\nTue, 27 Mar 2007 19:36:42 +0000\n/johnsmith/photos/puppy.jpg",
+		},
+		{
+			name:      "virtual hosted style",
+			method:    http.MethodGet,
+			url:       "/?prefix=photos&max-keys=50&marker=puppy",
+			host:      "johnsmith.s3.amazonaws.com",
+			bucket:    "johnsmith",
+			date:      "Tue, 27 Mar 2007 19:42:41 +0000",
+			signature: "htDYFYduRNen8P9ZfE/s9SuKy0U=",
+			expected:  "GET\n\n\nTue, 27 Mar 2007 19:42:41 +0000\n/johnsmith/",
+		},
+		{
+			name:   "sub-resources and amz headers",
+			method: http.MethodPut,
+			url:    "/bucket/key?uploadId=abc&partNumber=2&foo=bar&acl",
+			bucket: "bucket",
+			headers: map[string]string{
+				"Content-MD5":  "c8fdb181845a4ca6b8fec737b3581d76",
+				"Content-Type": "text/plain",
+				"X-Amz-Meta-B": " second ",
+				"X-Amz-Meta-A": "first",
+			},
+			date:     "Thu, 17 Nov 2005 18:49:58 GMT",
+			expected: "PUT\nc8fdb181845a4ca6b8fec737b3581d76\ntext/plain\nThu, 17 Nov 2005 18:49:58 GMT\nx-amz-meta-a:first\nx-amz-meta-b:second\n/bucket/key?acl&partNumber=2&uploadId=abc",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.url, nil)
+			if tc.host != "" {
+				r.Host = tc.host
+			}
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+			r = mux.SetURLVars(r, map[string]string{"bucket": tc.bucket})
+
+			resources := canonicalResourcesV2(r)
+			stringToSign := stringToSignV2(r, tc.date, resources[len(resources)-1])
+			require.Equal(t, tc.expected, stringToSign)
+
+			if tc.signature != "" {
+				require.Equal(t, tc.signature, signV2(exampleSecretV2, stringToSign))
+			}
+		})
+	}
+}
+
+func TestAuthenticateV2(t *testing.T) {
+	const accessKeyID = "vWqF8cMDRbJcvnPLALoQGnABPPhw8NyYMcGsfDPfZJM0HrgjonN8CgFvCZ3kh9BUXw4W2tJ5E7EAGhueSF122HB"
+
+	c := &center{
+		regV2:       &regexpSubmatcher{re: authorizationV2Regexp},
+		cli:         &secretCredentials{secret: exampleSecretV2},
+		signatureV2: true,
+	}
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/johnsmith/photos/puppy.jpg", nil)
+		return mux.SetURLVars(r, map[string]string{"bucket": "johnsmith"})
+	}
+
+	r := newRequest()
+	r.Header.Set("Date", "Tue, 27 Mar 2007 19:36:42 +0000")
+	r.Header.Set(AuthorizationHdr, "AWS "+accessKeyID+":bWq2s1WEIj+Ydj0vQ697zp+IXMU=")
+	_, err := c.Authenticate(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrRequestTimeTooSkewed), err)
+
+	r.Header.Set("Date", "Tue, 27 Mar 2007 19:36:43 +0000")
+	_, err = c.Authenticate(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrSignatureDoesNotMatch), err)
+
+	date := time.Now().UTC().Format(http.TimeFormat)
+	r = newRequest()
+	r.Header.Set("Date", date)
+	r.Header.Set(AuthorizationHdr, "AWS "+accessKeyID+":"+signV2(exampleSecretV2, "GET\n\n\n"+date+"\n/johnsmith/photos/puppy.jpg"))
+	box, err := c.Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, exampleSecretV2, box.Gate.AccessKey)
+
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	r = newRequest()
+	query := r.URL.Query()
+	query.Set(AmzAccessKeyIDV2, accessKeyID)
+	query.Set(AmzExpiresV2, expires)
+	query.Set(AmzSignatureV2, signV2(exampleSecretV2, "GET\n\n\n"+expires+"\n/johnsmith/photos/puppy.jpg"))
+	r.URL.RawQuery = query.Encode()
+	_, err = c.Authenticate(r)
+	require.NoError(t, err)
+
+	query.Set(AmzExpiresV2, "1175139620")
+	query.Set(AmzSignatureV2, "NpgCjnDzrM+WFzoENXmpNDUsSn8=")
+	r.URL.RawQuery = query.Encode()
+	_, err = c.Authenticate(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrExpiredPresignRequest), err)
+
+	c.signatureV2 = false
+	_, err = c.Authenticate(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrSignatureVersionNotSupported), err)
+}
+
+func TestAuthenticateV2RequestTime(t *testing.T) {
+	const accessKeyID = "vWqF8cMDRbJcvnPLALoQGnABPPhw8NyYMcGsfDPfZJM0HrgjonN8CgFvCZ3kh9BUXw4W2tJ5E7EAGhueSF122HB"
+
+	c := &center{
+		regV2:       &regexpSubmatcher{re: authorizationV2Regexp},
+		cli:         &secretCredentials{secret: exampleSecretV2},
+		signatureV2: true,
+	}
+
+	now := time.Now().UTC()
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		date   string
+		err    error
+	}{
+		{name: "date", header: "Date", date: now.Format(http.TimeFormat)},
+		{name: "rfc1123z date", header: "Date", date: now.Add(-10 * time.Minute).Format(time.RFC1123Z)},
+		{name: "x-amz-date", header: AmzDate, date: now.Add(10 * time.Minute).Format(http.TimeFormat)},
+		{name: "iso8601 x-amz-date", header: AmzDate, date: now.Format("20060102T150405Z")},
+		{name: "past", header: "Date", date: now.Add(-16 * time.Minute).Format(http.TimeFormat),
+			err: errors.GetAPIError(errors.ErrRequestTimeTooSkewed)},
+		{name: "future", header: AmzDate, date: now.Add(16 * time.Minute).Format(http.TimeFormat),
+			err: errors.GetAPIError(errors.ErrRequestTimeTooSkewed)},
+		{name: "malformed", header: "Date", date: "yesterday", err: errors.GetAPIError(errors.ErrMalformedDate)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/johnsmith/photos/puppy.jpg", nil)
+			r = mux.SetURLVars(r, map[string]string{"bucket": "johnsmith"})
+			r.Header.Set(tc.header, tc.date)
+
+			var date string
+			if tc.header == "Date" {
+				date = tc.date
+			}
+			stringToSign := stringToSignV2(r, date, "/johnsmith/photos/puppy.jpg")
+			r.Header.Set(AuthorizationHdr, "AWS "+accessKeyID+":"+signV2(exampleSecretV2, stringToSign))
+
+			_, err := c.Authenticate(r)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestCheckFormDataV2(t *testing.T) {
+	const accessKeyID = "vWqF8cMDRbJcvnPLALoQGnABPPhw8NyYMcGsfDPfZJM0HrgjonN8CgFvCZ3kh9BUXw4W2tJ5E7EAGhueSF122HB"
+
+	c := &center{
+		cli:         &secretCredentials{secret: exampleSecretV2},
+		signatureV2: true,
+	}
+
+	newRequest := func(key, document string) *http.Request {
+		policy := base64.StdEncoding.EncodeToString([]byte(document))
+
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		require.NoError(t, w.WriteField("key", key))
+		require.NoError(t, w.WriteField("AWSAccessKeyId", accessKeyID))
+		require.NoError(t, w.WriteField("policy", policy))
+		require.NoError(t, w.WriteField("signature", signV2(exampleSecretV2, policy)))
+		file, err := w.CreateFormFile("file", "puppy.jpg")
+		require.NoError(t, err)
+		_, err = file.Write([]byte("content"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r := httptest.NewRequest(http.MethodPost, "/johnsmith", body)
+		r.Header.Set(ContentTypeHdr, w.FormDataContentType())
+		return mux.SetURLVars(r, map[string]string{"bucket": "johnsmith"})
+	}
+
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	document := `{"expiration": "` + expiration + `", "conditions": [{"bucket": "johnsmith"},
+  ["starts-with", "$key", "user/eric/"], ["content-length-range", 1, 10], {"acl": "public-read"}]}`
+
+	for _, tc := range []struct {
+		name     string
+		key      string
+		document string
+		err      error
+	}{
+		{name: "valid", key: "user/eric/puppy.jpg", document: document},
+		{name: "expired", key: "user/eric/puppy.jpg",
+			document: `{"expiration": "2007-12-01T12:00:00.000Z", "conditions": [{"bucket": "johnsmith"}]}`,
+			err:      errors.GetAPIError(errors.ErrExpiredPresignRequest)},
+		{name: "no expiration", key: "user/eric/puppy.jpg", document: `{"conditions": [{"bucket": "johnsmith"}]}`,
+			err: errors.GetAPIError(errors.ErrAccessDenied)},
+		{name: "key", key: "user/john/puppy.jpg", document: document, err: errors.GetAPIError(errors.ErrAccessDenied)},
+		{name: "bucket", key: "user/eric/puppy.jpg",
+			document: `{"expiration": "` + expiration + `", "conditions": [["eq", "$bucket", "other"]]}`,
+			err:      errors.GetAPIError(errors.ErrAccessDenied)},
+		{name: "content length", key: "user/eric/puppy.jpg",
+			document: `{"expiration": "` + expiration + `", "conditions": [["content-length-range", 100, 1000]]}`,
+			err:      errors.GetAPIError(errors.ErrAccessDenied)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			box, err := c.Authenticate(newRequest(tc.key, tc.document))
+			require.Equal(t, tc.err, err)
+			if tc.err == nil {
+				require.Equal(t, exampleSecretV2, box.Gate.AccessKey)
+			}
+		})
+	}
+}
@@ -13,15 +13,24 @@ import (
 type (
 	// AccessBoxCache stores an access box by its address.
 	AccessBoxCache struct {
-		logger *zap.Logger
-		cache  gcache.Cache
+		logger        *zap.Logger
+		cache         gcache.Cache
+		checkInterval time.Duration
 	}
 
 	// Config stores expiration params for cache.
 	Config struct {
 		Size     int
 		Lifetime time.Duration
-		Logger   *zap.Logger
+		// RevocationCheckInterval is used by the access box cache only, cached boxes
+		// are checked for revocation after this interval. Zero disables checks.
+		RevocationCheckInterval time.Duration
+		Logger                  *zap.Logger
+	}
+
+	accessBoxCacheEntry struct {
+		box     *accessbox.Box
+		checked time.Time
 	}
 )
 
@@ -30,14 +39,17 @@ const (
 	DefaultAccessBoxCacheSize = 100
 	// DefaultAccessBoxCacheLifetime is a default lifetime of entries in cache.
 	DefaultAccessBoxCacheLifetime = 10 * time.Minute
+	// DefaultAccessBoxRevocationCheckInterval is a default interval to check cached entries for revocation.
+	DefaultAccessBoxRevocationCheckInterval = time.Minute
 )
 
 // DefaultAccessBoxConfig returns new default cache expiration values.
 func DefaultAccessBoxConfig(logger *zap.Logger) *Config {
 	return &Config{
-		Size:     DefaultAccessBoxCacheSize,
-		Lifetime: DefaultAccessBoxCacheLifetime,
-		Logger:   logger,
+		Size:                    DefaultAccessBoxCacheSize,
+		Lifetime:                DefaultAccessBoxCacheLifetime,
+		RevocationCheckInterval: DefaultAccessBoxRevocationCheckInterval,
+		Logger:                  logger,
 	}
 }
 
@@ -45,27 +57,56 @@ func DefaultAccessBoxConfig(logger *zap.Logger) *Config {
 func NewAccessBoxCache(config *Config) *AccessBoxCache {
 	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
 
-	return &AccessBoxCache{cache: gc, logger: config.Logger}
+	return &AccessBoxCache{cache: gc, logger: config.Logger, checkInterval: config.RevocationCheckInterval}
 }
 
 // Get returns a cached object.
 func (o *AccessBoxCache) Get(address oid.Address) *accessbox.Box {
+	box, _ := o.GetWithCheck(address)
+	return box
+}
+
+// GetWithCheck returns a cached object and a flag which is set if the object
+// must be checked for revocation before use.
+func (o *AccessBoxCache) GetWithCheck(address oid.Address) (*accessbox.Box, bool) {
 	entry, err := o.cache.Get(address)
 	if err != nil {
-		return nil
+		return nil, false
 	}
 
-	result, ok := entry.(*accessbox.Box)
+	result, ok := entry.(*accessBoxCacheEntry)
 	if !ok {
 		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
 			zap.String("expected", fmt.Sprintf("%T", result)))
-		return nil
+		return nil, false
 	}
 
-	return result
+	return result.box, o.checkInterval > 0 && time.Since(result.checked) >= o.checkInterval
 }
 
-// Put stores an object to cache.
+// CheckOverdue checks if the last successful revocation check of the cached object
+// is older than two check intervals, so the object mustn't be used until the check
+// succeeds. It gives the failed check a grace period of one more interval.
+func (o *AccessBoxCache) CheckOverdue(address oid.Address) bool {
+	entry, err := o.cache.Get(address)
+	if err != nil {
+		return true
+	}
+
+	result, ok := entry.(*accessBoxCacheEntry)
+	if !ok {
+		return true
+	}
+
+	return o.checkInterval > 0 && time.Since(result.checked) >= 2*o.checkInterval
+}
+
+// Put stores an object to cache, the object is considered checked for revocation.
 func (o *AccessBoxCache) Put(address oid.Address, box *accessbox.Box) error {
-	return o.cache.Set(address, box)
+	return o.cache.Set(address, &accessBoxCacheEntry{box: box, checked: time.Now()})
+}
+
+// Delete removes an object from cache.
+func (o *AccessBoxCache) Delete(address oid.Address) {
+	o.cache.Remove(address)
 }
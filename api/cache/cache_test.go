@@ -2,6 +2,7 @@ package cache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
@@ -31,6 +32,33 @@ func TestAccessBoxCacheType(t *testing.T) {
 	assertInvalidCacheEntry(t, cache.Get(addr), observedLog)
 }
 
+func TestAccessBoxCacheRevocationCheck(t *testing.T) {
+	config := DefaultAccessBoxConfig(zap.NewNop())
+	config.RevocationCheckInterval = 50 * time.Millisecond
+	cache := NewAccessBoxCache(config)
+
+	addr := oidtest.Address()
+	box := &accessbox.Box{}
+
+	require.NoError(t, cache.Put(addr, box))
+	val, check := cache.GetWithCheck(addr)
+	require.Equal(t, box, val)
+	require.False(t, check)
+
+	time.Sleep(config.RevocationCheckInterval)
+	val, check = cache.GetWithCheck(addr)
+	require.Equal(t, box, val)
+	require.True(t, check)
+	require.False(t, cache.CheckOverdue(addr))
+
+	time.Sleep(config.RevocationCheckInterval)
+	require.True(t, cache.CheckOverdue(addr))
+
+	cache.Delete(addr)
+	require.True(t, cache.CheckOverdue(addr))
+	require.Nil(t, cache.Get(addr))
+}
+
 func TestBucketsCacheType(t *testing.T) {
 	logger, observedLog := getObservedLogger()
 	cache := NewBucketCache(DefaultBucketConfig(logger))
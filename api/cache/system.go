@@ -119,6 +119,70 @@ func (o *SystemCache) GetNotificationConfiguration(key string) *data.Notificatio
 	return result
 }
 
+func (o *SystemCache) GetLifecycleConfiguration(key string) *data.LifecycleConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.LifecycleConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetReplicationConfiguration(key string) *data.ReplicationConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.ReplicationConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetWebsiteConfiguration(key string) *data.WebsiteConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.WebsiteConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetLoggingConfiguration(key string) *data.BucketLoggingStatus {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.BucketLoggingStatus)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
 // GetTagging returns tags of a bucket or an object.
 func (o *SystemCache) GetTagging(key string) map[string]string {
 	entry, err := o.cache.Get(key)
@@ -134,6 +198,21 @@ func (o *SystemCache) GetTagging(key string) map[string]string {
 	return result
 }
 
+// GetPolicy returns bucket policy document. Empty non-nil document means that bucket has no policy.
+func (o *SystemCache) GetPolicy(key string) []byte {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return result
+}
+
 // PutObject puts an object to cache.
 func (o *SystemCache) PutObject(key string, obj *data.ObjectInfo) error {
 	return o.cache.Set(key, obj)
@@ -156,11 +235,32 @@ func (o *SystemCache) PutNotificationConfiguration(key string, obj *data.Notific
 	return o.cache.Set(key, obj)
 }
 
+func (o *SystemCache) PutLifecycleConfiguration(key string, obj *data.LifecycleConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutReplicationConfiguration(key string, obj *data.ReplicationConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutWebsiteConfiguration(key string, obj *data.WebsiteConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutLoggingConfiguration(key string, obj *data.BucketLoggingStatus) error {
+	return o.cache.Set(key, obj)
+}
+
 // PutTagging puts tags of a bucket or an object.
 func (o *SystemCache) PutTagging(key string, tagSet map[string]string) error {
 	return o.cache.Set(key, tagSet)
 }
 
+// PutPolicy puts bucket policy document.
+func (o *SystemCache) PutPolicy(key string, policy []byte) error {
+	return o.cache.Set(key, policy)
+}
+
 // Delete deletes an object from cache.
 func (o *SystemCache) Delete(key string) bool {
 	return o.cache.Remove(key)
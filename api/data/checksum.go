@@ -0,0 +1,102 @@
+package data
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	"strings"
+)
+
+// Algorithms of additional checksums.
+const (
+	ChecksumCRC32  = "CRC32"
+	ChecksumCRC32C = "CRC32C"
+	ChecksumSHA1   = "SHA1"
+	ChecksumSHA256 = "SHA256"
+)
+
+type (
+	// Checksum is an additional checksum of the object or part payload.
+	Checksum struct {
+		Algorithm string
+		// Value is base64 encoded checksum. Checksum of the object uploaded via
+		// multipart upload is a checksum of part checksums with "-<parts count>" suffix.
+		Value string
+	}
+
+	// ChecksumFields are checksum elements of S3 XML requests and responses.
+	ChecksumFields struct {
+		ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+		ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+		ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+	}
+)
+
+// ChecksumAlgorithms is a list of supported checksum algorithms.
+var ChecksumAlgorithms = []string{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256}
+
+// NewChecksumHash returns hash which calculates checksum of the algorithm
+// or nil if algorithm is not supported.
+func NewChecksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case ChecksumCRC32:
+		return crc32.NewIEEE()
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case ChecksumSHA1:
+		return sha1.New()
+	case ChecksumSHA256:
+		return sha256.New()
+	default:
+		return nil
+	}
+}
+
+// ChecksumHeader returns the name of the header with the checksum of the algorithm.
+func ChecksumHeader(algorithm string) string {
+	return "X-Amz-Checksum-" + strings.ToLower(algorithm)
+}
+
+// IsComposite checks if the checksum is a checksum of part checksums.
+func (c *Checksum) IsComposite() bool {
+	return strings.Contains(c.Value, "-")
+}
+
+// NewChecksumFields forms checksum elements of S3 XML, c can be nil.
+func NewChecksumFields(c *Checksum) ChecksumFields {
+	var res ChecksumFields
+	if c == nil {
+		return res
+	}
+
+	switch c.Algorithm {
+	case ChecksumCRC32:
+		res.ChecksumCRC32 = c.Value
+	case ChecksumCRC32C:
+		res.ChecksumCRC32C = c.Value
+	case ChecksumSHA1:
+		res.ChecksumSHA1 = c.Value
+	case ChecksumSHA256:
+		res.ChecksumSHA256 = c.Value
+	}
+
+	return res
+}
+
+// Checksum returns the checksum set in the fields or nil if there is no one.
+func (f ChecksumFields) Checksum() *Checksum {
+	switch {
+	case f.ChecksumCRC32 != "":
+		return &Checksum{Algorithm: ChecksumCRC32, Value: f.ChecksumCRC32}
+	case f.ChecksumCRC32C != "":
+		return &Checksum{Algorithm: ChecksumCRC32C, Value: f.ChecksumCRC32C}
+	case f.ChecksumSHA1 != "":
+		return &Checksum{Algorithm: ChecksumSHA1, Value: f.ChecksumSHA1}
+	case f.ChecksumSHA256 != "":
+		return &Checksum{Algorithm: ChecksumSHA256, Value: f.ChecksumSHA256}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package data
+
+import "encoding/xml"
+
+type (
+	// ServerSideEncryptionConfiguration stores default encryption configuration of a bucket.
+	ServerSideEncryptionConfiguration struct {
+		XMLName xml.Name                   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ServerSideEncryptionConfiguration" json:"-"`
+		Rules   []ServerSideEncryptionRule `xml:"Rule" json:"Rules"`
+	}
+
+	// ServerSideEncryptionRule is a default encryption rule of a bucket.
+	ServerSideEncryptionRule struct {
+		ApplyServerSideEncryptionByDefault *ServerSideEncryptionByDefault `xml:"ApplyServerSideEncryptionByDefault" json:"ApplyServerSideEncryptionByDefault"`
+		BucketKeyEnabled                   bool                           `xml:"BucketKeyEnabled,omitempty" json:"BucketKeyEnabled,omitempty"`
+	}
+
+	// ServerSideEncryptionByDefault contains encryption algorithm applied to new objects.
+	ServerSideEncryptionByDefault struct {
+		SSEAlgorithm   string `xml:"SSEAlgorithm" json:"SSEAlgorithm"`
+		KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty" json:"KMSMasterKeyID,omitempty"`
+	}
+)
+
+// NewServerSideEncryptionConfiguration creates configuration with the single rule of the algorithm.
+func NewServerSideEncryptionConfiguration(algorithm string) *ServerSideEncryptionConfiguration {
+	return &ServerSideEncryptionConfiguration{
+		Rules: []ServerSideEncryptionRule{{
+			ApplyServerSideEncryptionByDefault: &ServerSideEncryptionByDefault{SSEAlgorithm: algorithm},
+		}},
+	}
+}
+
+// Algorithm returns default encryption algorithm of the configuration, c can be nil.
+func (c *ServerSideEncryptionConfiguration) Algorithm() string {
+	if c == nil || len(c.Rules) == 0 || c.Rules[0].ApplyServerSideEncryptionByDefault == nil {
+		return ""
+	}
+	return c.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm
+}
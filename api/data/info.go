@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
@@ -13,6 +14,10 @@ const (
 	bktSettingsObject                  = ".s3-settings"
 	bktCORSConfigurationObject         = ".s3-cors"
 	bktNotificationConfigurationObject = ".s3-notifications"
+	bktLifecycleConfigurationObject    = ".s3-lifecycle"
+	bktReplicationConfigurationObject  = ".s3-replication"
+	bktWebsiteConfigurationObject      = ".s3-website"
+	bktLoggingConfigurationObject      = ".s3-logging"
 
 	VersioningUnversioned = "Unversioned"
 	VersioningEnabled     = "Enabled"
@@ -43,8 +48,14 @@ type (
 		ContentType string
 		Created     time.Time
 		HashSum     string
+		Checksum    *Checksum
 		Owner       user.ID
 		Headers     map[string]string
+
+		EncryptionInfo encryption.ObjectEncryption
+
+		// Parts are set if the object is completed from the multipart upload without copying of the payload.
+		Parts []ManifestPart
 	}
 
 	// NotificationInfo store info to send s3 notification.
@@ -57,8 +68,10 @@ type (
 
 	// BucketSettings stores settings such as versioning.
 	BucketSettings struct {
-		Versioning        string                   `json:"versioning"`
-		LockConfiguration *ObjectLockConfiguration `json:"lock_configuration"`
+		Versioning              string                             `json:"versioning"`
+		LockConfiguration       *ObjectLockConfiguration           `json:"lock_configuration"`
+		EncryptionConfiguration *ServerSideEncryptionConfiguration `json:"encryption_configuration"`
+		Quota                   *BucketQuota                       `json:"quota"`
 	}
 
 	// CORSConfiguration stores CORS configuration of a request.
@@ -98,6 +111,26 @@ func (b *BucketInfo) NotificationConfigurationObjectName() string {
 	return bktNotificationConfigurationObject
 }
 
+// LifecycleConfigurationObjectName returns a system name for a bucket lifecycle configuration file.
+func (b *BucketInfo) LifecycleConfigurationObjectName() string {
+	return bktLifecycleConfigurationObject
+}
+
+// ReplicationConfigurationObjectName returns a system name for a bucket replication configuration file.
+func (b *BucketInfo) ReplicationConfigurationObjectName() string {
+	return bktReplicationConfigurationObject
+}
+
+// WebsiteConfigurationObjectName returns a system name for a bucket website configuration file.
+func (b *BucketInfo) WebsiteConfigurationObjectName() string {
+	return bktWebsiteConfigurationObject
+}
+
+// LoggingConfigurationObjectName returns a system name for a bucket logging configuration file.
+func (b *BucketInfo) LoggingConfigurationObjectName() string {
+	return bktLoggingConfigurationObject
+}
+
 // Version returns object version from ObjectInfo.
 func (o *ObjectInfo) Version() string { return o.ID.EncodeToString() }
 
@@ -0,0 +1,114 @@
+package data
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	LifecycleStatusEnabled  = "Enabled"
+	LifecycleStatusDisabled = "Disabled"
+)
+
+type (
+	// LifecycleConfiguration stores lifecycle configuration of a bucket.
+	LifecycleConfiguration struct {
+		XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LifecycleConfiguration" json:"-"`
+		Rules   []LifecycleRule `xml:"Rule" json:"Rules"`
+	}
+
+	// LifecycleRule is a single lifecycle rule of a bucket.
+	LifecycleRule struct {
+		ID     string               `xml:"ID,omitempty" json:"ID,omitempty"`
+		Status string               `xml:"Status" json:"Status"`
+		Filter *LifecycleRuleFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		// Prefix is a legacy way to filter objects, Filter should be used instead.
+		Prefix                         string                          `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Expiration                     *LifecycleExpiration            `xml:"Expiration,omitempty" json:"Expiration,omitempty"`
+		NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty" json:"NoncurrentVersionExpiration,omitempty"`
+		AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty" json:"AbortIncompleteMultipartUpload,omitempty"`
+	}
+
+	// LifecycleRuleFilter describes objects the rule is applied to.
+	LifecycleRuleFilter struct {
+		Prefix string                    `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag    *Tag                      `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		And    *LifecycleRuleAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// LifecycleRuleAndOperator combines prefix and several tags in a filter.
+	LifecycleRuleAndOperator struct {
+		Prefix string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags   []Tag  `xml:"Tag" json:"Tags"`
+	}
+
+	// Tag is a key-value pair used in lifecycle filters.
+	Tag struct {
+		Key   string `xml:"Key" json:"Key"`
+		Value string `xml:"Value" json:"Value"`
+	}
+
+	// LifecycleExpiration describes when the current object version expires.
+	LifecycleExpiration struct {
+		Days                      *int   `xml:"Days,omitempty" json:"Days,omitempty"`
+		Date                      string `xml:"Date,omitempty" json:"Date,omitempty"`
+		ExpiredObjectDeleteMarker *bool  `xml:"ExpiredObjectDeleteMarker,omitempty" json:"ExpiredObjectDeleteMarker,omitempty"`
+	}
+
+	// NoncurrentVersionExpiration describes when noncurrent object versions expire.
+	NoncurrentVersionExpiration struct {
+		NoncurrentDays          *int `xml:"NoncurrentDays,omitempty" json:"NoncurrentDays,omitempty"`
+		NewerNoncurrentVersions *int `xml:"NewerNoncurrentVersions,omitempty" json:"NewerNoncurrentVersions,omitempty"`
+	}
+
+	// AbortIncompleteMultipartUpload describes when incomplete multipart uploads are aborted.
+	AbortIncompleteMultipartUpload struct {
+		DaysAfterInitiation *int `xml:"DaysAfterInitiation,omitempty" json:"DaysAfterInitiation,omitempty"`
+	}
+)
+
+// Enabled checks if the rule must be applied.
+func (r LifecycleRule) Enabled() bool {
+	return r.Status == LifecycleStatusEnabled
+}
+
+// RulePrefix returns the object name prefix the rule is applied to.
+func (r LifecycleRule) RulePrefix() string {
+	if r.Filter == nil {
+		return r.Prefix
+	}
+	if r.Filter.And != nil {
+		return r.Filter.And.Prefix
+	}
+	return r.Filter.Prefix
+}
+
+// RuleTags returns tags which an object must have for the rule to be applied.
+func (r LifecycleRule) RuleTags() []Tag {
+	if r.Filter == nil {
+		return nil
+	}
+	if r.Filter.And != nil {
+		return r.Filter.And.Tags
+	}
+	if r.Filter.Tag != nil {
+		return []Tag{*r.Filter.Tag}
+	}
+	return nil
+}
+
+// MatchTags checks if the tag set contains all the rule tags.
+func (r LifecycleRule) MatchTags(tagSet map[string]string) bool {
+	for _, tag := range r.RuleTags() {
+		if val, ok := tagSet[tag.Key]; !ok || val != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// ExpirationDate returns the moment since which an object created at the given time is expired.
+// Expiration time is rounded up to the next midnight UTC as AWS S3 does.
+func ExpirationDate(created time.Time, days int) time.Time {
+	return created.UTC().Truncate(24*time.Hour).AddDate(0, 0, days+1)
+}
@@ -0,0 +1,18 @@
+package data
+
+import "encoding/xml"
+
+type (
+	// BucketLoggingStatus stores server access logging configuration of a bucket.
+	// Logging is disabled if LoggingEnabled is nil.
+	BucketLoggingStatus struct {
+		XMLName        xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ BucketLoggingStatus" json:"-"`
+		LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty" json:"LoggingEnabled,omitempty"`
+	}
+
+	// LoggingEnabled describes where access logs of the bucket are stored.
+	LoggingEnabled struct {
+		TargetBucket string `xml:"TargetBucket" json:"TargetBucket"`
+		TargetPrefix string `xml:"TargetPrefix" json:"TargetPrefix"`
+	}
+)
@@ -2,8 +2,7 @@ package data
 
 type (
 	NotificationConfiguration struct {
-		QueueConfigurations []QueueConfiguration `xml:"QueueConfiguration" json:"QueueConfigurations"`
-		// Not supported topics
+		QueueConfigurations          []QueueConfiguration          `xml:"QueueConfiguration" json:"QueueConfigurations"`
 		TopicConfigurations          []TopicConfiguration          `xml:"TopicConfiguration" json:"TopicConfigurations"`
 		LambdaFunctionConfigurations []LambdaFunctionConfiguration `xml:"CloudFunctionConfiguration" json:"CloudFunctionConfigurations"`
 	}
@@ -15,6 +14,24 @@ type (
 		Filter   Filter   `xml:"Filter" json:"Filter"`
 	}
 
+	// TopicConfiguration is handled the same way as QueueConfiguration,
+	// TopicArn addresses one of the notification targets of the gateway.
+	TopicConfiguration struct {
+		ID       string   `xml:"Id" json:"Id"`
+		TopicArn string   `xml:"Topic" json:"Topic"`
+		Events   []string `xml:"Event" json:"Events"`
+		Filter   Filter   `xml:"Filter" json:"Filter"`
+	}
+
+	// LambdaFunctionConfiguration is handled the same way as QueueConfiguration,
+	// LambdaFunctionArn addresses one of the notification targets of the gateway.
+	LambdaFunctionConfiguration struct {
+		ID                string   `xml:"Id" json:"Id"`
+		LambdaFunctionArn string   `xml:"CloudFunction" json:"CloudFunction"`
+		Events            []string `xml:"Event" json:"Events"`
+		Filter            Filter   `xml:"Filter" json:"Filter"`
+	}
+
 	Filter struct {
 		Key Key `xml:"S3Key" json:"S3Key"`
 	}
@@ -28,12 +45,31 @@ type (
 		Value string `xml:"Value" json:"Value"`
 	}
 
-	// TopicConfiguration and LambdaFunctionConfiguration -- we don't support these configurations,
-	// but we need them to detect in notification configurations in requests.
-	TopicConfiguration          struct{}
-	LambdaFunctionConfiguration struct{}
+	// NotificationDestination is a common view of queue, topic and lambda function configurations.
+	NotificationDestination struct {
+		ID     *string
+		Arn    string
+		Events []string
+		Filter Filter
+	}
 )
 
 func (n NotificationConfiguration) IsEmpty() bool {
 	return len(n.QueueConfigurations) == 0 && len(n.TopicConfigurations) == 0 && len(n.LambdaFunctionConfigurations) == 0
 }
+
+// Destinations returns all queue, topic and lambda function configurations.
+// ID of the destination points to the ID of the original configuration, so it can be updated.
+func (n *NotificationConfiguration) Destinations() []NotificationDestination {
+	res := make([]NotificationDestination, 0, len(n.QueueConfigurations)+len(n.TopicConfigurations)+len(n.LambdaFunctionConfigurations))
+	for i, q := range n.QueueConfigurations {
+		res = append(res, NotificationDestination{ID: &n.QueueConfigurations[i].ID, Arn: q.QueueArn, Events: q.Events, Filter: q.Filter})
+	}
+	for i, t := range n.TopicConfigurations {
+		res = append(res, NotificationDestination{ID: &n.TopicConfigurations[i].ID, Arn: t.TopicArn, Events: t.Events, Filter: t.Filter})
+	}
+	for i, l := range n.LambdaFunctionConfigurations {
+		res = append(res, NotificationDestination{ID: &n.LambdaFunctionConfigurations[i].ID, Arn: l.LambdaFunctionArn, Events: l.Events, Filter: l.Filter})
+	}
+	return res
+}
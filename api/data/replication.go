@@ -0,0 +1,157 @@
+package data
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const (
+	ReplicationRuleEnabled  = "Enabled"
+	ReplicationRuleDisabled = "Disabled"
+
+	// Replication statuses of object versions.
+	ReplicationStatusPending   = "PENDING"
+	ReplicationStatusCompleted = "COMPLETED"
+	ReplicationStatusFailed    = "FAILED"
+	ReplicationStatusReplica   = "REPLICA"
+
+	// ReplicationBucketARNPrefix is a prefix of the destination bucket ARN.
+	ReplicationBucketARNPrefix = "arn:aws:s3:::"
+)
+
+type (
+	// ReplicationConfiguration stores replication configuration of a bucket.
+	ReplicationConfiguration struct {
+		XMLName xml.Name          `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ReplicationConfiguration" json:"-"`
+		Role    string            `xml:"Role,omitempty" json:"Role,omitempty"`
+		Rules   []ReplicationRule `xml:"Rule" json:"Rules"`
+	}
+
+	// ReplicationRule is a single replication rule of a bucket.
+	ReplicationRule struct {
+		ID       string                 `xml:"ID,omitempty" json:"ID,omitempty"`
+		Priority int                    `xml:"Priority,omitempty" json:"Priority,omitempty"`
+		Status   string                 `xml:"Status" json:"Status"`
+		Filter   *ReplicationRuleFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		// Prefix is a legacy way to filter objects, Filter should be used instead.
+		Prefix                  string                   `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Destination             ReplicationDestination   `xml:"Destination" json:"Destination"`
+		DeleteMarkerReplication *DeleteMarkerReplication `xml:"DeleteMarkerReplication,omitempty" json:"DeleteMarkerReplication,omitempty"`
+	}
+
+	// ReplicationRuleFilter describes objects the rule is applied to.
+	ReplicationRuleFilter struct {
+		Prefix string                      `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag    *Tag                        `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		And    *ReplicationRuleAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// ReplicationRuleAndOperator combines prefix and several tags in a filter.
+	ReplicationRuleAndOperator struct {
+		Prefix string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags   []Tag  `xml:"Tag" json:"Tags"`
+	}
+
+	// ReplicationDestination describes where objects are replicated to.
+	// Account is a name of the replication target configured in the gateway,
+	// if it's empty, objects are replicated to the bucket served by this gateway.
+	ReplicationDestination struct {
+		Bucket       string `xml:"Bucket" json:"Bucket"`
+		Account      string `xml:"Account,omitempty" json:"Account,omitempty"`
+		StorageClass string `xml:"StorageClass,omitempty" json:"StorageClass,omitempty"`
+	}
+
+	// DeleteMarkerReplication describes if delete markers are replicated.
+	DeleteMarkerReplication struct {
+		Status string `xml:"Status" json:"Status"`
+	}
+)
+
+// Enabled checks if the rule must be applied.
+func (r ReplicationRule) Enabled() bool {
+	return r.Status == ReplicationRuleEnabled
+}
+
+// RulePrefix returns the object name prefix the rule is applied to.
+func (r ReplicationRule) RulePrefix() string {
+	if r.Filter == nil {
+		return r.Prefix
+	}
+	if r.Filter.And != nil {
+		return r.Filter.And.Prefix
+	}
+	return r.Filter.Prefix
+}
+
+// RuleTags returns tags which an object must have for the rule to be applied.
+func (r ReplicationRule) RuleTags() []Tag {
+	if r.Filter == nil {
+		return nil
+	}
+	if r.Filter.And != nil {
+		return r.Filter.And.Tags
+	}
+	if r.Filter.Tag != nil {
+		return []Tag{*r.Filter.Tag}
+	}
+	return nil
+}
+
+// MatchTags checks if the tag set contains all the rule tags.
+func (r ReplicationRule) MatchTags(tagSet map[string]string) bool {
+	for _, tag := range r.RuleTags() {
+		if val, ok := tagSet[tag.Key]; !ok || val != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplicateDeleteMarkers checks if delete markers are replicated by the rule.
+func (r ReplicationRule) ReplicateDeleteMarkers() bool {
+	return r.DeleteMarkerReplication != nil && r.DeleteMarkerReplication.Status == ReplicationRuleEnabled
+}
+
+// BucketName returns the name of the destination bucket from its ARN.
+func (d ReplicationDestination) BucketName() string {
+	return strings.TrimPrefix(d.Bucket, ReplicationBucketARNPrefix)
+}
+
+// MatchRule returns an enabled rule with the highest priority applied to the object,
+// tags are requested only if some rule filters objects by tags.
+// Nil is returned if no rule matches the object.
+func (c *ReplicationConfiguration) MatchRule(objName string, getTags func() (map[string]string, error)) (*ReplicationRule, error) {
+	var (
+		res     *ReplicationRule
+		tagSet  map[string]string
+		tagsErr error
+		gotTags bool
+	)
+
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if !rule.Enabled() || !strings.HasPrefix(objName, rule.RulePrefix()) {
+			continue
+		}
+		if res != nil && res.Priority >= rule.Priority {
+			continue
+		}
+
+		if len(rule.RuleTags()) != 0 {
+			if !gotTags {
+				tagSet, tagsErr = getTags()
+				gotTags = true
+			}
+			if tagsErr != nil {
+				return nil, tagsErr
+			}
+			if !rule.MatchTags(tagSet) {
+				continue
+			}
+		}
+
+		res = rule
+	}
+
+	return res, nil
+}
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
@@ -14,6 +16,61 @@ type NodeVersion struct {
 	BaseNodeVersion
 	DeleteMarker  *DeleteMarkerInfo
 	IsUnversioned bool
+	// ReplicationStatus is a status of the version replication, empty if the version isn't replicated.
+	ReplicationStatus string
+	// Parts is a manifest of the object completed from the multipart upload without copying
+	// of the payload, it's empty for regular objects.
+	Parts []ManifestPart
+}
+
+// ManifestPart is a part of the object completed from the multipart upload. Payload of such object
+// is a concatenation of payloads of its parts which are stored as separate NeoFS objects.
+type ManifestPart struct {
+	OID  oid.ID
+	Size int64
+	ETag string
+}
+
+// EncodeManifest encodes parts of the completed object as a comma separated
+// list of <oid>-<size>-<etag> items.
+func EncodeManifest(parts []ManifestPart) string {
+	var sb strings.Builder
+	for i, part := range parts {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(part.OID.EncodeToString())
+		sb.WriteByte('-')
+		sb.WriteString(strconv.FormatInt(part.Size, 10))
+		sb.WriteByte('-')
+		sb.WriteString(part.ETag)
+	}
+
+	return sb.String()
+}
+
+// DecodeManifest decodes parts of the completed object encoded by EncodeManifest.
+func DecodeManifest(value string) ([]ManifestPart, error) {
+	items := strings.Split(value, ",")
+	parts := make([]ManifestPart, len(items))
+	for i, item := range items {
+		fields := strings.Split(item, "-")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid part '%s'", item)
+		}
+		if err := parts[i].OID.DecodeString(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid part oid '%s': %w", fields[0], err)
+		}
+
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid part size '%s': %w", fields[1], err)
+		}
+		parts[i].Size = size
+		parts[i].ETag = fields[2]
+	}
+
+	return parts, nil
 }
 
 // DeleteMarkerInfo is used to save object info if node in the tree service is delete marker.
@@ -38,6 +95,7 @@ type BaseNodeVersion struct {
 	Timestamp uint64
 	Size      int64
 	ETag      string
+	Checksum  *Checksum
 	FilePath  string
 }
 
@@ -57,6 +115,8 @@ type MultipartInfo struct {
 	Owner    user.ID
 	Created  time.Time
 	Meta     map[string]string
+	// ChecksumAlgorithm is an algorithm of additional checksums of parts, optional.
+	ChecksumAlgorithm string
 }
 
 // PartInfo is upload information about part.
@@ -67,12 +127,18 @@ type PartInfo struct {
 	OID      oid.ID
 	Size     int64
 	ETag     string
+	Checksum *Checksum
 	Created  time.Time
 }
 
 // ToHeaderString form short part representation to use in S3-Completed-Parts header.
+// Additional checksum of the part is appended if it is set.
 func (p *PartInfo) ToHeaderString() string {
-	return strconv.Itoa(p.Number) + "-" + strconv.FormatInt(p.Size, 10) + "-" + p.ETag
+	res := strconv.Itoa(p.Number) + "-" + strconv.FormatInt(p.Size, 10) + "-" + p.ETag
+	if p.Checksum != nil {
+		res += "-" + p.Checksum.Value
+	}
+	return res
 }
 
 // LockInfo is lock information to create appropriate tree node.
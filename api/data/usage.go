@@ -0,0 +1,48 @@
+package data
+
+import "encoding/xml"
+
+type (
+	// BucketUsage contains usage counters of a bucket.
+	BucketUsage struct {
+		// Objects is a number of objects whose latest version isn't a delete marker.
+		Objects int64 `json:"objects"`
+		// Bytes is a total size of object versions.
+		Bytes int64 `json:"bytes"`
+		// Versions is a number of object versions, delete markers aren't counted.
+		Versions int64 `json:"versions"`
+		// MultipartBytes is a total size of uploaded parts of incomplete multipart uploads.
+		MultipartBytes int64 `json:"multipart_bytes"`
+	}
+
+	// BucketQuota limits the size of a bucket, zero limits aren't checked.
+	// Writes exceeding the hard limit fail, exceeding the soft limit is reported only.
+	BucketQuota struct {
+		XMLName   xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ BucketQuota" json:"-"`
+		HardLimit uint64   `xml:"HardLimit,omitempty" json:"hard_limit,omitempty"`
+		SoftLimit uint64   `xml:"SoftLimit,omitempty" json:"soft_limit,omitempty"`
+	}
+)
+
+// Add adds counters of another usage.
+func (u *BucketUsage) Add(other BucketUsage) {
+	u.Objects += other.Objects
+	u.Bytes += other.Bytes
+	u.Versions += other.Versions
+	u.MultipartBytes += other.MultipartBytes
+}
+
+// Sub returns the difference between the usage and another one.
+func (u BucketUsage) Sub(other BucketUsage) BucketUsage {
+	return BucketUsage{
+		Objects:        u.Objects - other.Objects,
+		Bytes:          u.Bytes - other.Bytes,
+		Versions:       u.Versions - other.Versions,
+		MultipartBytes: u.MultipartBytes - other.MultipartBytes,
+	}
+}
+
+// Size returns the number of bytes the quota is checked against.
+func (u BucketUsage) Size() int64 {
+	return u.Bytes + u.MultipartBytes
+}
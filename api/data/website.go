@@ -0,0 +1,100 @@
+package data
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+type (
+	// WebsiteConfiguration stores static website configuration of a bucket.
+	WebsiteConfiguration struct {
+		XMLName               xml.Name               `xml:"http://s3.amazonaws.com/doc/2006-03-01/ WebsiteConfiguration" json:"-"`
+		RedirectAllRequestsTo *RedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty" json:"RedirectAllRequestsTo,omitempty"`
+		IndexDocument         *IndexDocument         `xml:"IndexDocument,omitempty" json:"IndexDocument,omitempty"`
+		ErrorDocument         *ErrorDocument         `xml:"ErrorDocument,omitempty" json:"ErrorDocument,omitempty"`
+		RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty" json:"RoutingRules,omitempty"`
+	}
+
+	// RedirectAllRequestsTo redirects all requests to the website endpoint of the bucket to another host.
+	RedirectAllRequestsTo struct {
+		HostName string `xml:"HostName" json:"HostName"`
+		Protocol string `xml:"Protocol,omitempty" json:"Protocol,omitempty"`
+	}
+
+	// IndexDocument is appended to the requests for directories (keys ending with a slash).
+	IndexDocument struct {
+		Suffix string `xml:"Suffix" json:"Suffix"`
+	}
+
+	// ErrorDocument is an object returned when an error occurs.
+	ErrorDocument struct {
+		Key string `xml:"Key" json:"Key"`
+	}
+
+	// RoutingRule redirects requests matching the condition.
+	RoutingRule struct {
+		Condition *RoutingRuleCondition `xml:"Condition,omitempty" json:"Condition,omitempty"`
+		Redirect  RoutingRuleRedirect   `xml:"Redirect" json:"Redirect"`
+	}
+
+	// RoutingRuleCondition describes requests the rule is applied to.
+	RoutingRuleCondition struct {
+		HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty" json:"HttpErrorCodeReturnedEquals,omitempty"`
+		KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty" json:"KeyPrefixEquals,omitempty"`
+	}
+
+	// RoutingRuleRedirect describes where the request is redirected to.
+	RoutingRuleRedirect struct {
+		HostName             string `xml:"HostName,omitempty" json:"HostName,omitempty"`
+		HTTPRedirectCode     string `xml:"HttpRedirectCode,omitempty" json:"HttpRedirectCode,omitempty"`
+		Protocol             string `xml:"Protocol,omitempty" json:"Protocol,omitempty"`
+		ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty" json:"ReplaceKeyPrefixWith,omitempty"`
+		ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty" json:"ReplaceKeyWith,omitempty"`
+	}
+)
+
+// MatchRoutingRule returns the first routing rule matching the key and the error code.
+// Zero error code is used before the object is requested, rules with an error code
+// condition don't match it.
+func (c *WebsiteConfiguration) MatchRoutingRule(key string, errorCode int) *RoutingRule {
+	for i := range c.RoutingRules {
+		if c.RoutingRules[i].Matches(key, errorCode) {
+			return &c.RoutingRules[i]
+		}
+	}
+	return nil
+}
+
+// Matches checks if the rule is applied to the key and the error code.
+func (r RoutingRule) Matches(key string, errorCode int) bool {
+	if r.Condition == nil {
+		return errorCode == 0
+	}
+
+	if r.Condition.HTTPErrorCodeReturnedEquals != "" {
+		if r.Condition.HTTPErrorCodeReturnedEquals != strconv.Itoa(errorCode) {
+			return false
+		}
+	} else if errorCode != 0 {
+		return false
+	}
+
+	return strings.HasPrefix(key, r.Condition.KeyPrefixEquals)
+}
+
+// RedirectKey returns the key the request for the key is redirected to.
+func (r RoutingRule) RedirectKey(key string) string {
+	switch {
+	case r.Redirect.ReplaceKeyWith != "":
+		return r.Redirect.ReplaceKeyWith
+	case r.Redirect.ReplaceKeyPrefixWith != "":
+		var prefix string
+		if r.Condition != nil {
+			prefix = r.Condition.KeyPrefixEquals
+		}
+		return r.Redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	default:
+		return key
+	}
+}
@@ -34,6 +34,9 @@ const (
 	ErrInvalidAccessKeyID
 	ErrInvalidBucketName
 	ErrInvalidDigest
+	ErrInvalidChecksum
+	ErrBadChecksum
+	ErrInvalidChecksumAlgorithm
 	ErrInvalidRange
 	ErrInvalidCopyPartRange
 	ErrInvalidCopyPartRangeSource
@@ -166,6 +169,12 @@ const (
 	ErrOverlappingConfigs
 	ErrNotificationTopicNotSupported
 
+	// Bucket replication related errors.
+	ErrReplicationNotEnabled
+	ErrReplicationVersioningRequired
+	ErrInvalidReplicationDestination
+	ErrInvalidTargetBucketForLogging
+
 	// S3 extended errors.
 	ErrContentSHA256Mismatch
 
@@ -175,6 +184,7 @@ const (
 	ErrOperationMaxedOut
 	ErrInvalidRequest
 	ErrInvalidStorageClass
+	ErrQuotaExceeded
 
 	ErrMalformedJSON
 	ErrInsecureClientRequest
@@ -265,6 +275,8 @@ const (
 	ErrEvaluatorBindingDoesNotExist
 	ErrMissingHeaders
 	ErrInvalidColumnIndex
+	ErrCSVParsingError
+	ErrJSONParsingError
 
 	ErrPostPolicyConditionInvalidFormat
 
@@ -414,6 +426,24 @@ var errorCodes = errorCodeMap{
 		Description:    "The Content-Md5 you specified is not valid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidChecksum: {
+		ErrCode:        ErrInvalidChecksum,
+		Code:           "InvalidRequest",
+		Description:    "Value for x-amz-checksum header is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBadChecksum: {
+		ErrCode:        ErrBadChecksum,
+		Code:           "BadDigest",
+		Description:    "The checksum you specified did not match the calculated checksum.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidChecksumAlgorithm: {
+		ErrCode:        ErrInvalidChecksumAlgorithm,
+		Code:           "InvalidRequest",
+		Description:    "Checksum algorithm provided is unsupported. Please try again with any of the valid types: [CRC32, CRC32C, SHA1, SHA256]",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidRange: {
 		ErrCode:        ErrInvalidRange,
 		Code:           "InvalidRange",
@@ -945,6 +975,31 @@ var errorCodes = errorCodeMap{
 		Description:    "Configurations overlap. Configurations on the same bucket cannot share a common event type.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	// Bucket replication related errors.
+	ErrReplicationNotEnabled: {
+		ErrCode:        ErrReplicationNotEnabled,
+		Code:           "InvalidRequest",
+		Description:    "Replication is not enabled in the gateway. Please connect to the other gateway",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrReplicationVersioningRequired: {
+		ErrCode:        ErrReplicationVersioningRequired,
+		Code:           "InvalidRequest",
+		Description:    "Versioning must be 'Enabled' on the bucket to apply a replication configuration",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidReplicationDestination: {
+		ErrCode:        ErrInvalidReplicationDestination,
+		Code:           "InvalidRequest",
+		Description:    "Destination bucket of the replication rule is not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTargetBucketForLogging: {
+		ErrCode:        ErrInvalidTargetBucketForLogging,
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist or is not owned by you",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidCopyPartRange: {
 		ErrCode:        ErrInvalidCopyPartRange,
 		Code:           "InvalidArgument",
@@ -1085,6 +1140,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Object name contains unsupported characters.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrQuotaExceeded: {
+		ErrCode:        ErrQuotaExceeded,
+		Code:           "QuotaExceeded",
+		Description:    "The bucket quota is exceeded.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrMalformedJSON: {
 		ErrCode:        ErrMalformedJSON,
 		Code:           "MalformedJSON",
@@ -1196,7 +1257,7 @@ var errorCodes = errorCodeMap{
 	ErrInvalidCompressionFormat: {
 		ErrCode:        ErrInvalidCompressionFormat,
 		Code:           "InvalidCompressionFormat",
-		Description:    "The file is not in a supported compression format. Only GZIP is supported at this time.",
+		Description:    "The file is not in a supported compression format. Only GZIP and BZIP2 are supported at this time.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
 	ErrInvalidFileHeaderInfo: {
@@ -1643,6 +1704,18 @@ var errorCodes = errorCodeMap{
 		Description:    "The column index is invalid. Please check the service documentation and try again.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrCSVParsingError: {
+		ErrCode:        ErrCSVParsingError,
+		Code:           "CSVParsingError",
+		Description:    "Encountered an error parsing the CSV file. Check the file and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrJSONParsingError: {
+		ErrCode:        ErrJSONParsingError,
+		Code:           "JSONParsingError",
+		Description:    "Encountered an error parsing the JSON file. Check the file and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrPostPolicyConditionInvalidFormat: {
 		ErrCode:        ErrPostPolicyConditionInvalidFormat,
 		Code:           "PostPolicyInvalidKeyName",
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+)
+
+// Event types produced by background processes of the gateway.
+const (
+	LifecycleExpirationDelete              = "s3:LifecycleExpiration:Delete"
+	LifecycleExpirationDeleteMarkerCreated = "s3:LifecycleExpiration:DeleteMarkerCreated"
+	ReplicationOperationFailedReplication  = "s3:Replication:OperationFailedReplication"
+)
+
+type (
+	// Event is an event of the object produced by a background process of the gateway.
+	Event struct {
+		Name    string
+		BktInfo *data.BucketInfo
+		Object  *data.NotificationInfo
+	}
+
+	// Handler processes events published to the bus. The context of the event
+	// contains credentials of the bucket owner.
+	Handler func(ctx context.Context, e *Event)
+
+	// Bus delivers events of background processes (lifecycle expiration, replication etc.)
+	// to the subscribers. Producers declare event types they publish, so subscriptions
+	// to events which are never produced can be rejected.
+	Bus struct {
+		mu       sync.RWMutex
+		handlers []Handler
+		produced map[string]struct{}
+	}
+)
+
+// NewBus creates an event bus without producers and subscribers.
+func NewBus() *Bus {
+	return &Bus{produced: make(map[string]struct{})}
+}
+
+// RegisterProducer declares event types published to the bus.
+func (b *Bus) RegisterProducer(eventTypes ...string) {
+	b.mu.Lock()
+	for _, e := range eventTypes {
+		b.produced[e] = struct{}{}
+	}
+	b.mu.Unlock()
+}
+
+// Produces checks if the event type is published to the bus. Event types
+// ending with * match all produced events with the same prefix.
+func (b *Bus) Produces(eventType string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !strings.HasSuffix(eventType, "*") {
+		_, ok := b.produced[eventType]
+		return ok
+	}
+
+	prefix := eventType[:len(eventType)-1]
+	for e := range b.produced {
+		if strings.HasPrefix(e, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe adds the handler of all events published to the bus.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.mu.Unlock()
+}
+
+// Publish passes the event to the subscribers. Handlers are called synchronously,
+// so producers are slowed down rather than lose events.
+func (b *Bus) Publish(ctx context.Context, e *Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, e)
+	}
+}
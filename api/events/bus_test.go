@@ -0,0 +1,32 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	b := NewBus()
+	require.False(t, b.Produces(LifecycleExpirationDelete))
+
+	b.RegisterProducer(LifecycleExpirationDelete, LifecycleExpirationDeleteMarkerCreated)
+	require.True(t, b.Produces(LifecycleExpirationDelete))
+	require.True(t, b.Produces("s3:LifecycleExpiration:*"))
+	require.False(t, b.Produces("s3:Replication:*"))
+	require.False(t, b.Produces(ReplicationOperationFailedReplication))
+
+	var first, second []string
+	b.Subscribe(func(_ context.Context, e *Event) { first = append(first, e.Name) })
+	b.Subscribe(func(_ context.Context, e *Event) { second = append(second, e.Object.Name) })
+
+	b.Publish(context.Background(), &Event{
+		Name:    LifecycleExpirationDelete,
+		BktInfo: &data.BucketInfo{Name: "bucket"},
+		Object:  &data.NotificationInfo{Name: "object"},
+	})
+	require.Equal(t, []string{LifecycleExpirationDelete}, first)
+	require.Equal(t, []string{"object"}, second)
+}
@@ -5,10 +5,10 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/hex"
-	"encoding/json"
 	"encoding/xml"
 	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
@@ -20,6 +20,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
@@ -42,12 +43,19 @@ var actionToOpMap = map[string][]eacl.Operation{
 	s3ListBucket:   readOps,
 }
 
+// nativeActions are the actions of actionToOpMap in a stable order.
+var nativeActions = []string{s3DeleteObject, s3GetObject, s3ListBucket, s3PutObject}
+
 const (
 	arnAwsPrefix     = "arn:aws:s3:::"
 	allUsersWildcard = "*"
 	allUsersGroup    = "http://acs.amazonaws.com/groups/global/AllUsers"
 
+	// maxBucketPolicySize is the limit of bucket policy document size set by AWS.
+	maxBucketPolicySize = 20 * 1024
+
 	s3DeleteObject               = "s3:DeleteObject"
+	s3DeleteObjectVersion        = "s3:DeleteObjectVersion"
 	s3GetObject                  = "s3:GetObject"
 	s3PutObject                  = "s3:PutObject"
 	s3ListBucket                 = "s3:ListBucket"
@@ -382,7 +390,8 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 			ReqInfo:          reqInfo,
 		}
 		if err = h.sendNotifications(r.Context(), s); err != nil {
-			h.log.Error("couldn't send notification: %w", zap.Error(err))
+			h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+			return
 		}
 	}
 	w.WriteHeader(http.StatusOK)
@@ -397,18 +406,16 @@ func (h *handler) GetBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bucketACL, err := h.obj.GetBucketACL(r.Context(), bktInfo)
+	bktPolicy, err := h.obj.GetBucketPolicy(r.Context(), bktInfo)
 	if err != nil {
-		h.logAndSendError(w, "could not fetch bucket acl", reqInfo, err)
+		h.logAndSendError(w, "could not get bucket policy", reqInfo, err)
 		return
 	}
 
-	ast := tableToAst(bucketACL.EACL, reqInfo.BucketName)
-	bktPolicy := astToPolicy(ast)
-
+	w.Header().Set(api.ContentType, "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	if err = json.NewEncoder(w).Encode(bktPolicy); err != nil {
+	if _, err = w.Write(bktPolicy); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -441,13 +448,23 @@ func (h *handler) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bktPolicy := &bucketPolicy{Bucket: reqInfo.BucketName}
-	if err = json.NewDecoder(r.Body).Decode(bktPolicy); err != nil {
-		h.logAndSendError(w, "could not parse bucket policy", reqInfo, err)
+	document, err := io.ReadAll(io.LimitReader(r.Body, maxBucketPolicySize+1))
+	if err != nil {
+		h.logAndSendError(w, "could not read bucket policy", reqInfo, err)
+		return
+	}
+	if len(document) > maxBucketPolicySize {
+		h.logAndSendError(w, "bucket policy is too large", reqInfo, errors.GetAPIError(errors.ErrPolicyTooLarge))
+		return
+	}
+
+	bktPolicy, err := policy.Parse(document, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not parse bucket policy", reqInfo, errors.GetAPIError(errors.ErrMalformedPolicy), zap.Error(err))
 		return
 	}
 
-	astPolicy, err := policyToAst(bktPolicy)
+	astPolicy, err := policyToAst(nativePolicy(bktPolicy, reqInfo.BucketName))
 	if err != nil {
 		h.logAndSendError(w, "could not translate policy to ast", reqInfo, err)
 		return
@@ -457,6 +474,13 @@ func (h *handler) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
 	}
+
+	if err = h.obj.PutBucketPolicy(r.Context(), bktInfo, document); err != nil {
+		h.logAndSendError(w, "could not put bucket policy", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func parseACLHeaders(header http.Header, key *keys.PublicKey) (*AccessControlPolicy, error) {
@@ -1042,76 +1066,6 @@ func resourceInfoFromName(name, bucketName string) resourceInfo {
 	return resInfo
 }
 
-func astToPolicy(ast *ast) *bucketPolicy {
-	bktPolicy := &bucketPolicy{}
-
-	for _, resource := range ast.Resources {
-		if len(resource.Version) == 0 {
-			continue
-		}
-		allowed, denied := triageOperations(resource.Operations)
-		handleResourceOperations(bktPolicy, allowed, eacl.ActionAllow, resource.Name())
-		handleResourceOperations(bktPolicy, denied, eacl.ActionDeny, resource.Name())
-	}
-
-	return bktPolicy
-}
-
-func handleResourceOperations(bktPolicy *bucketPolicy, list []*astOperation, eaclAction eacl.Action, resourceName string) {
-	userOpsMap := make(map[string][]eacl.Operation)
-
-	for _, op := range list {
-		if !op.IsGroupGrantee() {
-			for _, user := range op.Users {
-				userOps := userOpsMap[user]
-				userOps = append(userOps, op.Op)
-				userOpsMap[user] = userOps
-			}
-		} else {
-			userOps := userOpsMap[allUsersGroup]
-			userOps = append(userOps, op.Op)
-			userOpsMap[allUsersGroup] = userOps
-		}
-	}
-
-	for user, userOps := range userOpsMap {
-		var actions []string
-	LOOP:
-		for action, ops := range actionToOpMap {
-			for _, op := range ops {
-				if !contains(userOps, op) {
-					break LOOP
-				}
-			}
-			actions = append(actions, action)
-		}
-		if len(actions) != 0 {
-			state := statement{
-				Effect:    actionToEffect(eaclAction),
-				Principal: principal{CanonicalUser: user},
-				Action:    actions,
-				Resource:  []string{arnAwsPrefix + resourceName},
-			}
-			if user == allUsersGroup {
-				state.Principal = principal{AWS: allUsersWildcard}
-			}
-			bktPolicy.Statement = append(bktPolicy.Statement, state)
-		}
-	}
-}
-
-func triageOperations(operations []*astOperation) ([]*astOperation, []*astOperation) {
-	var allowed, denied []*astOperation
-	for _, op := range operations {
-		if op.Action == eacl.ActionAllow {
-			allowed = append(allowed, op)
-		} else {
-			denied = append(denied, op)
-		}
-	}
-	return allowed, denied
-}
-
 func addTo(list []*astOperation, userID string, op eacl.Operation, groupGrantee bool, action eacl.Action) []*astOperation {
 	var found *astOperation
 	for _, astop := range list {
@@ -1298,17 +1252,6 @@ func effectToAction(effect string) eacl.Action {
 	return eacl.ActionUnknown
 }
 
-func actionToEffect(action eacl.Action) string {
-	switch action {
-	case eacl.ActionAllow:
-		return "Allow"
-	case eacl.ActionDeny:
-		return "Deny"
-	default:
-		return ""
-	}
-}
-
 func permissionToOperations(permission AWSACL) []eacl.Operation {
 	switch permission {
 	case aclFullControl:
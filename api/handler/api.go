@@ -4,8 +4,10 @@ import (
 	"errors"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
@@ -14,6 +16,8 @@ type (
 		log         *zap.Logger
 		obj         layer.Client
 		notificator Notificator
+		replicator  Replicator
+		listener    Listener
 		cfg         *Config
 	}
 
@@ -27,6 +31,12 @@ type (
 		DefaultPolicy      netmap.PlacementPolicy
 		DefaultMaxAge      int
 		NotificatorEnabled bool
+		// QuotaAdmins are users allowed to set quotas of the buckets, quotas can't be changed
+		// by the bucket owners themselves.
+		QuotaAdmins []user.ID
+		// Events is an optional bus of events produced by background processes,
+		// they are sent to notification targets and listeners like events of requests.
+		Events *events.Bus
 	}
 )
 
@@ -36,7 +46,9 @@ const DefaultPolicy = "REP 3"
 var _ api.Handler = (*handler)(nil)
 
 // New creates new api.Handler using given logger and client.
-func New(log *zap.Logger, obj layer.Client, notificator Notificator, cfg *Config) (api.Handler, error) {
+// Replicator is optional, bucket replication is disabled if it's nil.
+// Listener is optional, ListenBucketNotification isn't supported if it's nil.
+func New(log *zap.Logger, obj layer.Client, notificator Notificator, replicator Replicator, listener Listener, cfg *Config) (api.Handler, error) {
 	switch {
 	case obj == nil:
 		return nil, errors.New("empty NeoFS Object Layer")
@@ -50,10 +62,18 @@ func New(log *zap.Logger, obj layer.Client, notificator Notificator, cfg *Config
 		return nil, errors.New("empty notificator")
 	}
 
-	return &handler{
+	h := &handler{
 		log:         log,
 		obj:         obj,
 		cfg:         cfg,
 		notificator: notificator,
-	}, nil
+		replicator:  replicator,
+		listener:    listener,
+	}
+
+	if cfg.Events != nil {
+		cfg.Events.Subscribe(h.handleEvent)
+	}
+
+	return h, nil
 }
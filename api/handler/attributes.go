@@ -22,7 +22,7 @@ type (
 	}
 
 	Checksum struct {
-		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+		data.ChecksumFields
 	}
 
 	ObjectParts struct {
@@ -35,9 +35,9 @@ type (
 	}
 
 	Part struct {
-		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
-		PartNumber     int    `xml:"PartNumber,omitempty"`
-		Size           int    `xml:"Size,omitempty"`
+		data.ChecksumFields
+		PartNumber int `xml:"PartNumber,omitempty"`
+		Size       int `xml:"Size,omitempty"`
 	}
 
 	GetObjectAttributesArgs struct {
@@ -93,6 +93,16 @@ func (h *handler) GetObjectAttributesHandler(w http.ResponseWriter, r *http.Requ
 	}
 	info := extendedInfo.ObjectInfo
 
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+	if err = encryptionParams.MatchObjectEncryption(info.EncryptionInfo); err != nil {
+		h.logAndSendError(w, "encryption doesn't match object", reqInfo, err)
+		return
+	}
+
 	if err = checkPreconditions(info, params.Conditional); err != nil {
 		h.logAndSendError(w, "precondition failed", reqInfo, err)
 		return
@@ -172,7 +182,11 @@ func encodeToObjectAttributesResponse(info *data.ObjectInfo, p *GetObjectAttribu
 		case objectSize:
 			resp.ObjectSize = info.Size
 		case checksum:
-			resp.Checksum = &Checksum{ChecksumSHA256: info.HashSum}
+			if info.Checksum != nil {
+				resp.Checksum = &Checksum{ChecksumFields: data.NewChecksumFields(info.Checksum)}
+			} else {
+				resp.Checksum = &Checksum{ChecksumFields: data.ChecksumFields{ChecksumSHA256: info.HashSum}}
+			}
 		case objectParts:
 			parts, err := formUploadAttributes(info, p.MaxParts, p.PartNumberMarker)
 			if err != nil {
@@ -196,9 +210,10 @@ func formUploadAttributes(info *data.ObjectInfo, maxParts, marker int) (*ObjectP
 	partInfos := strings.Split(completedParts, ",")
 	parts := make([]Part, len(partInfos))
 	for i, p := range partInfos {
-		// partInfo[0] -- part number, partInfo[1] -- part size, partInfo[2] -- checksum
+		// partInfo[0] -- part number, partInfo[1] -- part size, partInfo[2] -- checksum,
+		// partInfo[3] -- additional checksum of the algorithm of object checksum (optional)
 		partInfo := strings.Split(p, "-")
-		if len(partInfo) != 3 {
+		if len(partInfo) != 3 && len(partInfo) != 4 {
 			return nil, fmt.Errorf("invalid completed parts header")
 		}
 		num, err := strconv.Atoi(partInfo[0])
@@ -210,9 +225,13 @@ func formUploadAttributes(info *data.ObjectInfo, maxParts, marker int) (*ObjectP
 			return nil, err
 		}
 		parts[i] = Part{
-			PartNumber:     num,
-			Size:           size,
-			ChecksumSHA256: partInfo[2],
+			PartNumber: num,
+			Size:       size,
+		}
+		if len(partInfo) == 4 && info.Checksum != nil {
+			parts[i].ChecksumFields = data.NewChecksumFields(&data.Checksum{Algorithm: info.Checksum.Algorithm, Value: partInfo[3]})
+		} else {
+			parts[i].ChecksumSHA256 = partInfo[2]
 		}
 	}
 
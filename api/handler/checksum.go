@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+const checksumModeEnabled = "ENABLED"
+
+// parseChecksumAlgorithm returns checksum algorithm from x-amz-checksum-algorithm or
+// x-amz-sdk-checksum-algorithm header, empty string is returned if the headers are not set.
+func parseChecksumAlgorithm(header http.Header) (string, error) {
+	algorithm := header.Get(api.AmzChecksumAlgorithm)
+	if algorithm == "" {
+		algorithm = header.Get(api.AmzSdkChecksumAlgorithm)
+	}
+	if algorithm == "" {
+		return "", nil
+	}
+
+	algorithm = strings.ToUpper(algorithm)
+	if data.NewChecksumHash(algorithm) == nil {
+		return "", errors.GetAPIError(errors.ErrInvalidChecksumAlgorithm)
+	}
+
+	return algorithm, nil
+}
+
+// parseChecksum returns additional checksum of the payload provided by the client.
+// Value of the checksum is empty if only algorithm is set or the checksum is sent in
+// the trailer of aws-chunked payload (such checksum is verified on payload reading).
+func parseChecksum(header http.Header) (*data.Checksum, error) {
+	algorithm, err := parseChecksumAlgorithm(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var checksum *data.Checksum
+	for _, alg := range data.ChecksumAlgorithms {
+		value := header.Get(data.ChecksumHeader(alg))
+		if value == "" {
+			continue
+		}
+		if checksum != nil {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, fmt.Errorf("expecting a single x-amz-checksum- header"))
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(decoded) != data.NewChecksumHash(alg).Size() {
+			return nil, errors.GetAPIError(errors.ErrInvalidChecksum)
+		}
+		checksum = &data.Checksum{Algorithm: alg, Value: value}
+	}
+
+	if checksum == nil {
+		if trailer := header.Get(api.AmzTrailer); strings.HasPrefix(strings.ToLower(trailer), "x-amz-checksum-") {
+			checksum = &data.Checksum{Algorithm: strings.ToUpper(strings.TrimPrefix(strings.ToLower(trailer), "x-amz-checksum-"))}
+			if data.NewChecksumHash(checksum.Algorithm) == nil {
+				return nil, errors.GetAPIError(errors.ErrInvalidChecksumAlgorithm)
+			}
+		}
+	}
+
+	switch {
+	case checksum == nil && algorithm != "":
+		checksum = &data.Checksum{Algorithm: algorithm}
+	case checksum != nil && algorithm != "" && checksum.Algorithm != algorithm:
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+			fmt.Errorf("value for %s header is invalid", api.AmzSdkChecksumAlgorithm))
+	}
+
+	return checksum, nil
+}
+
+// writeChecksumHeaders sets the header with the additional checksum of the object
+// if checksum mode is enabled in the request.
+func writeChecksumHeaders(h http.Header, reqHeader http.Header, checksum *data.Checksum) {
+	if checksum != nil && strings.EqualFold(reqHeader.Get(api.AmzChecksumMode), checksumModeEnabled) {
+		h.Set(data.ChecksumHeader(checksum.Algorithm), checksum.Value)
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutObjectChecksum(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-checksum", "object"
+	createTestBucket(ctx, t, hc, bktName)
+
+	content := []byte("content")
+	crc := crc32.ChecksumIEEE(content)
+	expected := base64.StdEncoding.EncodeToString([]byte{byte(crc >> 24), byte(crc >> 16), byte(crc >> 8), byte(crc)})
+	crcHeader := data.ChecksumHeader(data.ChecksumCRC32)
+
+	for _, tc := range []struct {
+		name   string
+		header map[string]string
+		err    errors.ErrorCode
+	}{
+		{name: "invalid value", header: map[string]string{crcHeader: "invalid"}, err: errors.ErrInvalidChecksum},
+		{name: "mismatch", header: map[string]string{crcHeader: "AAAAAA=="}, err: errors.ErrBadChecksum},
+		{name: "unsupported algorithm", header: map[string]string{api.AmzSdkChecksumAlgorithm: "MD5"}, err: errors.ErrInvalidChecksumAlgorithm},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+			hc.Handler().PutObjectHandler(w, r)
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+		})
+	}
+
+	w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+	r.Header.Set(crcHeader, expected)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, expected, w.Header().Get(crcHeader))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(crcHeader))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	r.Header.Set(api.AmzChecksumMode, checksumModeEnabled)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, expected, w.Header().Get(crcHeader))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	r.Header.Set(api.AmzObjectAttributes, checksum)
+	hc.Handler().GetObjectAttributesHandler(w, r)
+	result := &GetObjectAttributesResponse{}
+	parseTestResponse(t, w, result)
+	require.Equal(t, expected, result.Checksum.ChecksumCRC32)
+}
+
+func TestMultipartUploadChecksum(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-checksum", "object-multipart"
+	createTestBucket(ctx, t, hc, bktName)
+
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	r.Header.Set(api.AmzChecksumAlgorithm, data.ChecksumSHA256)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	multipartUpload := &InitiateMultipartUploadResponse{}
+	parseTestResponse(t, w, multipartUpload)
+	require.Equal(t, data.ChecksumSHA256, w.Header().Get(api.AmzChecksumAlgorithm))
+
+	content := []byte("content")
+	partSum := sha256.Sum256(content)
+	partChecksum := base64.StdEncoding.EncodeToString(partSum[:])
+
+	uploadPart := func(header map[string]string) *httptest.ResponseRecorder {
+		w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+		query := make(url.Values)
+		query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+		query.Add(partNumberHeaderName, "1")
+		r.URL.RawQuery = query.Encode()
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		hc.Handler().UploadPartHandler(w, r)
+		return w
+	}
+
+	w = uploadPart(map[string]string{data.ChecksumHeader(data.ChecksumCRC32): "AAAAAA=="})
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w = uploadPart(nil)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, partChecksum, w.Header().Get(data.ChecksumHeader(data.ChecksumSHA256)))
+	etag := w.Header().Get(api.ETag)
+
+	completeUpload := &CompleteMultipartUpload{
+		Parts: []*layer.CompletedPart{{
+			ETag:           etag,
+			PartNumber:     1,
+			ChecksumFields: data.ChecksumFields{ChecksumSHA256: partChecksum},
+		}},
+	}
+	w, r = prepareTestRequest(t, bktName, objName, completeUpload)
+	query := make(url.Values)
+	query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+	r.URL.RawQuery = query.Encode()
+	hc.Handler().CompleteMultipartUploadHandler(w, r)
+	completeResult := &CompleteMultipartUploadResponse{}
+	parseTestResponse(t, w, completeResult)
+
+	compositeSum := sha256.Sum256(partSum[:])
+	expected := base64.StdEncoding.EncodeToString(compositeSum[:]) + "-1"
+	require.Equal(t, expected, completeResult.ChecksumSHA256)
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	r.Header.Set(api.AmzObjectAttributes, checksum+","+objectParts)
+	hc.Handler().GetObjectAttributesHandler(w, r)
+	result := &GetObjectAttributesResponse{}
+	parseTestResponse(t, w, result)
+	require.Equal(t, expected, result.Checksum.ChecksumSHA256)
+	require.Len(t, result.ObjectParts.Parts, 1)
+	require.Equal(t, partChecksum, result.ObjectParts.Parts[0].ChecksumSHA256)
+}
@@ -65,9 +65,20 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		VersionID: versionID,
 	}
 
+	srcEncryptionParams, err := formCopySourceEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid copy source sse headers", reqInfo, err)
+		return
+	}
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
 	if args.MetadataDirective == replaceMetadataDirective {
 		metadata = parseMetadata(r)
-	} else if srcBucket == reqInfo.BucketName && srcObject == reqInfo.ObjectName {
+	} else if srcBucket == reqInfo.BucketName && srcObject == reqInfo.ObjectName && !encryptionParams.Enabled() {
 		h.logAndSendError(w, "could not copy to itself", reqInfo, errors.GetAPIError(errors.ErrInvalidRequest))
 		return
 	}
@@ -77,6 +88,11 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkCopySourcePolicy(r, p.BktInfo, srcObject, versionID); err != nil {
+		h.logAndSendError(w, "copy source access denied by bucket policy", reqInfo, err)
+		return
+	}
+
 	dstBktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
 	if err != nil {
 		h.logAndSendError(w, "couldn't get target bucket", reqInfo, err)
@@ -97,6 +113,16 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	info := extendedInfo.ObjectInfo
 
+	if err = api.CheckObjectSize(r.Context(), uint64(info.Size)); err != nil {
+		h.logAndSendError(w, "copy source size exceeds access box restrictions", reqInfo, err)
+		return
+	}
+
+	if err = srcEncryptionParams.MatchObjectEncryption(info.EncryptionInfo); err != nil {
+		h.logAndSendError(w, "encryption doesn't match source object", reqInfo, err)
+		return
+	}
+
 	if err = checkPreconditions(info, args.Conditional); err != nil {
 		h.logAndSendError(w, "precondition failed", reqInfo, errors.GetAPIError(errors.ErrPreconditionFailed))
 		return
@@ -118,6 +144,9 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		DstObject:  reqInfo.ObjectName,
 		SrcSize:    info.Size,
 		Header:     metadata,
+
+		SrcEncryption: srcEncryptionParams,
+		Encryption:    encryptionParams,
 	}
 
 	settings, err := h.obj.GetBucketSettings(r.Context(), dstBktInfo)
@@ -136,7 +165,21 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	if info, err = h.obj.CopyObject(r.Context(), params); err != nil {
 		h.logAndSendError(w, "couldn't copy object", reqInfo, err, additional...)
 		return
-	} else if err = api.EncodeToResponse(w, &CopyObjectResponse{LastModified: info.Created.UTC().Format(time.RFC3339), ETag: info.HashSum}); err != nil {
+	}
+
+	s := &SendNotificationParams{
+		Event:            EventObjectCreatedCopy,
+		NotificationInfo: data.NotificationInfoFromObject(info),
+		BktInfo:          dstBktInfo,
+		ReqInfo:          reqInfo,
+	}
+	if err = h.sendNotifications(r.Context(), s); err != nil {
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
+	}
+
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
+	if err = api.EncodeToResponse(w, &CopyObjectResponse{LastModified: info.Created.UTC().Format(time.RFC3339), ETag: info.HashSum}); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err, additional...)
 		return
 	}
@@ -160,20 +203,16 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.replicate(r.Context(), &ReplicationParams{
+		BktInfo:    dstBktInfo,
+		ObjectName: info.Name,
+		VersionID:  info.Version(),
+	})
+
 	h.log.Info("object is copied",
 		zap.String("bucket", info.Bucket),
 		zap.String("object", info.Name),
 		zap.Stringer("object_id", info.ID))
-
-	s := &SendNotificationParams{
-		Event:            EventObjectCreatedCopy,
-		NotificationInfo: data.NotificationInfoFromObject(info),
-		BktInfo:          dstBktInfo,
-		ReqInfo:          reqInfo,
-	}
-	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
-	}
 }
 
 func parseCopyObjectArgs(headers http.Header) (*copyObjectArgs, error) {
@@ -105,6 +105,13 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 			BktInfo: bktInfo,
 			ReqInfo: reqInfo,
 		}
+
+		h.replicate(r.Context(), &ReplicationParams{
+			BktInfo:      bktInfo,
+			ObjectName:   reqInfo.ObjectName,
+			VersionID:    deletedObject.DeleteMarkVersion,
+			DeleteMarker: true,
+		})
 	} else {
 		var objID oid.ID
 		if len(versionID) != 0 {
@@ -125,7 +132,8 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err = h.sendNotifications(r.Context(), m); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	if deletedObject.VersionID != "" {
@@ -204,6 +212,29 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	allowed := toRemove[:0]
+	for _, obj := range toRemove {
+		action := s3DeleteObject
+		if obj.VersionID != "" {
+			action = s3DeleteObjectVersion
+		}
+		if err = h.checkBucketPolicy(r, bktInfo, action, obj.Name); err != nil {
+			code := "BadRequest"
+			if s3err, ok := err.(errors.Error); ok {
+				code = s3err.Code
+			}
+			response.Errors = append(response.Errors, DeleteError{
+				Code:      code,
+				Message:   err.Error(),
+				Key:       obj.Name,
+				VersionID: obj.VersionID,
+			})
+			continue
+		}
+		allowed = append(allowed, obj)
+	}
+	toRemove = allowed
+
 	marshaler := zapcore.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
 		for _, obj := range toRemove {
 			encoder.AppendString(obj.String())
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+)
+
+func (h *handler) PutBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	configuration := new(data.ServerSideEncryptionConfiguration)
+	if err := xml.NewDecoder(r.Body).Decode(configuration); err != nil {
+		h.logAndSendError(w, "couldn't decode encryption configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if len(configuration.Rules) != 1 {
+		h.logAndSendError(w, "invalid encryption configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+	if configuration.Algorithm() != encryption.AESEncryptionAlgorithm {
+		h.logAndSendError(w, "unsupported encryption algorithm", reqInfo, errors.GetAPIError(errors.ErrInvalidEncryptionMethod))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	newSettings := *settings
+	newSettings.EncryptionConfiguration = data.NewServerSideEncryptionConfiguration(encryption.AESEncryptionAlgorithm)
+
+	p := &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	}
+
+	if err = h.obj.PutBucketSettings(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put encryption settings", reqInfo, err)
+	}
+}
+
+func (h *handler) GetBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	if settings.EncryptionConfiguration == nil {
+		h.logAndSendError(w, "encryption configuration not found", reqInfo, errors.GetAPIError(errors.ErrNoSuchBucketSSEConfig))
+		return
+	}
+
+	if err = api.EncodeToResponse(w, settings.EncryptionConfiguration); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) DeleteBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	newSettings := *settings
+	newSettings.EncryptionConfiguration = nil
+
+	p := &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	}
+
+	if err = h.obj.PutBucketSettings(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't delete encryption settings", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// formEncryptionParams parses SSE-C headers of the request, nil params are returned if there are no such headers.
+func formEncryptionParams(r *http.Request) (*encryption.Params, error) {
+	return formEncryptionParamsBase(r, api.AmzServerSideEncryptionCustomerAlgorithm,
+		api.AmzServerSideEncryptionCustomerKey, api.AmzServerSideEncryptionCustomerKeyMD5)
+}
+
+// formCopySourceEncryptionParams parses SSE-C headers of the copy source, nil params are returned if there are no such headers.
+func formCopySourceEncryptionParams(r *http.Request) (*encryption.Params, error) {
+	return formEncryptionParamsBase(r, api.AmzCopySourceServerSideEncryptionCustomerAlgorithm,
+		api.AmzCopySourceServerSideEncryptionCustomerKey, api.AmzCopySourceServerSideEncryptionCustomerKeyMD5)
+}
+
+func formEncryptionParamsBase(r *http.Request, algorithmHeader, keyHeader, keyMD5Header string) (*encryption.Params, error) {
+	algorithm := r.Header.Get(algorithmHeader)
+	key := r.Header.Get(keyHeader)
+	keyMD5 := r.Header.Get(keyMD5Header)
+
+	if len(algorithm)+len(key)+len(keyMD5) == 0 {
+		return nil, nil
+	}
+
+	if r.TLS == nil {
+		return nil, errors.GetAPIError(errors.ErrInsecureSSECustomerRequest)
+	}
+
+	if algorithm != encryption.AESEncryptionAlgorithm {
+		return nil, errors.GetAPIError(errors.ErrInvalidSSECustomerAlgorithm)
+	}
+	if len(key) == 0 {
+		return nil, errors.GetAPIError(errors.ErrMissingSSECustomerKey)
+	}
+	if len(keyMD5) == 0 {
+		return nil, errors.GetAPIError(errors.ErrMissingSSECustomerKeyMD5)
+	}
+
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(keyBytes) != encryption.AESKeySize {
+		return nil, errors.GetAPIError(errors.ErrInvalidSSECustomerKey)
+	}
+
+	keySum := md5.Sum(keyBytes)
+	if base64.StdEncoding.EncodeToString(keySum[:]) != keyMD5 {
+		return nil, errors.GetAPIError(errors.ErrSSECustomerKeyMD5Mismatch)
+	}
+
+	return encryption.NewSSECParams(keyBytes)
+}
+
+// writeEncryptionHeaders writes SSE-C or SSE-S3 headers of the object if it's encrypted.
+func writeEncryptionHeaders(h http.Header, info encryption.ObjectEncryption) {
+	if !info.Enabled() {
+		return
+	}
+
+	if info.Managed() {
+		h.Set(api.AmzServerSideEncryption, info.Algorithm)
+		return
+	}
+
+	h.Set(api.AmzServerSideEncryptionCustomerAlgorithm, info.Algorithm)
+	h.Set(api.AmzServerSideEncryptionCustomerKeyMD5, info.CustomerKeyMD5)
+}
@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	aes256Key      = "1234567890qwertyuiopasdfghjklzxc"
+	aes256KeyAlt   = "zxcvbnmasdfghjklqwertyuiop098765"
+	testManagedKey = "managedkeymanagedkeymanagedkey12"
+)
+
+func setEncryptHeaders(r *http.Request, key string) {
+	keyMD5 := md5.Sum([]byte(key))
+	r.TLS = &tls.ConnectionState{}
+	r.Header.Set(api.AmzServerSideEncryptionCustomerAlgorithm, encryption.AESEncryptionAlgorithm)
+	r.Header.Set(api.AmzServerSideEncryptionCustomerKey, base64.StdEncoding.EncodeToString([]byte(key)))
+	r.Header.Set(api.AmzServerSideEncryptionCustomerKeyMD5, base64.StdEncoding.EncodeToString(keyMD5[:]))
+}
+
+func setCopySourceEncryptHeaders(r *http.Request, key string) {
+	keyMD5 := md5.Sum([]byte(key))
+	r.TLS = &tls.ConnectionState{}
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerAlgorithm, encryption.AESEncryptionAlgorithm)
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKey, base64.StdEncoding.EncodeToString([]byte(key)))
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKeyMD5, base64.StdEncoding.EncodeToString(keyMD5[:]))
+}
+
+func putEncryptedObject(t *testing.T, hc *handlerContext, bktName, objName string, content []byte) {
+	w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+	setEncryptHeaders(r, aes256Key)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryptionCustomerAlgorithm))
+}
+
+func getObject(t *testing.T, hc *handlerContext, bktName, objName, key, rng string) *bytes.Buffer {
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	if key != "" {
+		setEncryptHeaders(r, key)
+	}
+	if rng != "" {
+		r.Header.Set("Range", rng)
+	}
+	hc.Handler().GetObjectHandler(w, r)
+	if w.Code != http.StatusOK && w.Code != http.StatusPartialContent {
+		return nil
+	}
+	return w.Body
+}
+
+func TestSSECPutGetObject(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-c", "object-to-encrypt"
+	createTestBucket(ctx, t, hc, bktName)
+
+	content := bytes.Repeat([]byte("content"), 20000)
+	putEncryptedObject(t, hc, bktName, objName, content)
+
+	objects := hc.MockedPool().Objects()
+	require.Len(t, objects, 1)
+	require.NotContains(t, string(objects[0].Payload()), "content")
+	require.EqualValues(t, encryption.EncryptedSize(uint64(len(content))), len(objects[0].Payload()))
+
+	require.Equal(t, content, getObject(t, hc, bktName, objName, aes256Key, "").Bytes())
+	require.Equal(t, content[100:70000], getObject(t, hc, bktName, objName, aes256Key, "bytes=100-69999").Bytes())
+	require.Equal(t, content[len(content)-10:], getObject(t, hc, bktName, objName, aes256Key, "bytes=-10").Bytes())
+
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrSSEEncryptedObject))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	setEncryptHeaders(r, aes256KeyAlt)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidSSECustomerParameters))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	setEncryptHeaders(r, aes256Key)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, strconv.Itoa(len(content)), w.Header().Get(api.ContentLength))
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryptionCustomerAlgorithm))
+}
+
+func TestSSECInvalidHeaders(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-c-headers", "object"
+	createTestBucket(ctx, t, hc, bktName)
+
+	for _, tc := range []struct {
+		name  string
+		setup func(r *http.Request)
+		err   errors.ErrorCode
+	}{
+		{
+			name: "insecure request",
+			setup: func(r *http.Request) {
+				setEncryptHeaders(r, aes256Key)
+				r.TLS = nil
+			},
+			err: errors.ErrInsecureSSECustomerRequest,
+		},
+		{
+			name: "invalid algorithm",
+			setup: func(r *http.Request) {
+				setEncryptHeaders(r, aes256Key)
+				r.Header.Set(api.AmzServerSideEncryptionCustomerAlgorithm, "AES128")
+			},
+			err: errors.ErrInvalidSSECustomerAlgorithm,
+		},
+		{
+			name: "invalid key",
+			setup: func(r *http.Request) {
+				setEncryptHeaders(r, "short key")
+			},
+			err: errors.ErrInvalidSSECustomerKey,
+		},
+		{
+			name: "key md5 mismatch",
+			setup: func(r *http.Request) {
+				setEncryptHeaders(r, aes256Key)
+				r.Header.Set(api.AmzServerSideEncryptionCustomerKeyMD5, base64.StdEncoding.EncodeToString(make([]byte, md5.Size)))
+			},
+			err: errors.ErrSSECustomerKeyMD5Mismatch,
+		},
+		{
+			name: "missing key md5",
+			setup: func(r *http.Request) {
+				setEncryptHeaders(r, aes256Key)
+				r.Header.Del(api.AmzServerSideEncryptionCustomerKeyMD5)
+			},
+			err: errors.ErrMissingSSECustomerKeyMD5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader([]byte("content")))
+			tc.setup(r)
+			hc.Handler().PutObjectHandler(w, r)
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+		})
+	}
+}
+
+func TestSSECCopyObject(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName, copyName := "bucket-for-sse-c-copy", "object", "object-copy"
+	createTestBucket(ctx, t, hc, bktName)
+
+	content := []byte("content")
+	putEncryptedObject(t, hc, bktName, objName, content)
+
+	w, r := prepareTestRequest(t, bktName, copyName, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrSSEEncryptedObject))
+
+	w, r = prepareTestRequest(t, bktName, copyName, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	setCopySourceEncryptHeaders(r, aes256Key)
+	setEncryptHeaders(r, aes256KeyAlt)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	require.Nil(t, getObject(t, hc, bktName, copyName, aes256Key, ""))
+	require.Equal(t, content, getObject(t, hc, bktName, copyName, aes256KeyAlt, "").Bytes())
+
+	w, r = prepareTestRequest(t, bktName, copyName, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	setCopySourceEncryptHeaders(r, aes256Key)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	require.Equal(t, content, getObject(t, hc, bktName, copyName, "", "").Bytes())
+}
+
+func TestSSECMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-c-multipart", "object-multipart"
+	createTestBucket(ctx, t, hc, bktName)
+
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	setEncryptHeaders(r, aes256Key)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	multipartUpload := &InitiateMultipartUploadResponse{}
+	parseTestResponse(t, w, multipartUpload)
+
+	uploadPart := func(number int, key string, content []byte) string {
+		w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+		query := make(url.Values)
+		query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+		query.Add(partNumberHeaderName, strconv.Itoa(number))
+		r.URL.RawQuery = query.Encode()
+		if key != "" {
+			setEncryptHeaders(r, key)
+		}
+		hc.Handler().UploadPartHandler(w, r)
+		if w.Code != http.StatusOK {
+			return ""
+		}
+		return w.Header().Get(api.ETag)
+	}
+
+	partSize := 5 * 1048576
+	part1 := bytes.Repeat([]byte("a"), partSize)
+	part2 := []byte("part two")
+
+	require.Empty(t, uploadPart(1, "", part1))
+	require.Empty(t, uploadPart(1, aes256KeyAlt, part1))
+	etag1 := uploadPart(1, aes256Key, part1)
+	etag2 := uploadPart(2, aes256Key, part2)
+	require.NotEmpty(t, etag1)
+	require.NotEmpty(t, etag2)
+
+	completeUpload := &CompleteMultipartUpload{
+		Parts: []*layer.CompletedPart{{ETag: etag1, PartNumber: 1}, {ETag: etag2, PartNumber: 2}},
+	}
+	w, r = prepareTestRequest(t, bktName, objName, completeUpload)
+	query := make(url.Values)
+	query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+	r.URL.RawQuery = query.Encode()
+	setEncryptHeaders(r, aes256Key)
+	hc.Handler().CompleteMultipartUploadHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	expected := append(append([]byte{}, part1...), part2...)
+	require.Equal(t, expected, getObject(t, hc, bktName, objName, aes256Key, "").Bytes())
+	require.Equal(t, expected[partSize-5:], getObject(t, hc, bktName, objName, aes256Key, "bytes="+strconv.Itoa(partSize-5)+"-").Bytes())
+}
+
+func putBucketEncryption(t *testing.T, hc *handlerContext, bktName, algorithm string) *httptest.ResponseRecorder {
+	w, r := prepareTestRequest(t, bktName, "", data.NewServerSideEncryptionConfiguration(algorithm))
+	hc.Handler().PutBucketEncryptionHandler(w, r)
+	return w
+}
+
+func TestBucketDefaultEncryption(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-s3", "object"
+	createTestBucket(ctx, t, hc, bktName)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketEncryptionHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchBucketSSEConfig))
+
+	w = putBucketEncryption(t, hc, bktName, "aws:kms")
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidEncryptionMethod))
+
+	w = putBucketEncryption(t, hc, bktName, encryption.AESEncryptionAlgorithm)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketEncryptionHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	configuration := &data.ServerSideEncryptionConfiguration{}
+	parseTestResponse(t, w, configuration)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, configuration.Algorithm())
+
+	content := bytes.Repeat([]byte("content"), 20000)
+	w, r = prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+
+	objects := hc.MockedPool().Objects()
+	require.Len(t, objects, 1)
+	require.NotContains(t, string(objects[0].Payload()), "content")
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, content, w.Body.Bytes())
+	require.Equal(t, content[100:70000], getObject(t, hc, bktName, objName, "", "bytes=100-69999").Bytes())
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, strconv.Itoa(len(content)), w.Header().Get(api.ContentLength))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	setEncryptHeaders(r, aes256Key)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidEncryptionParameters))
+
+	w, r = prepareTestRequest(t, bktName, "object-copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, content, getObject(t, hc, bktName, "object-copy", "", "").Bytes())
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().DeleteBucketEncryptionHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestPayloadRequest(bktName, "plain-object", bytes.NewReader(content))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, content, getObject(t, hc, bktName, objName, "", "").Bytes())
+}
+
+func TestBucketDefaultEncryptionMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-s3-multipart", "object-multipart"
+	createTestBucket(ctx, t, hc, bktName)
+	assertStatus(t, putBucketEncryption(t, hc, bktName, encryption.AESEncryptionAlgorithm), http.StatusOK)
+
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+	multipartUpload := &InitiateMultipartUploadResponse{}
+	parseTestResponse(t, w, multipartUpload)
+
+	partSize := 5 * 1048576
+	parts := [][]byte{bytes.Repeat([]byte("a"), partSize), []byte("part two")}
+	completeUpload := &CompleteMultipartUpload{}
+	for i, part := range parts {
+		w, r = prepareTestPayloadRequest(bktName, objName, bytes.NewReader(part))
+		query := make(url.Values)
+		query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+		query.Add(partNumberHeaderName, strconv.Itoa(i+1))
+		r.URL.RawQuery = query.Encode()
+		hc.Handler().UploadPartHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+		require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+
+		completeUpload.Parts = append(completeUpload.Parts, &layer.CompletedPart{ETag: w.Header().Get(api.ETag), PartNumber: i + 1})
+	}
+
+	for _, obj := range hc.MockedPool().Objects() {
+		require.NotContains(t, string(obj.Payload()), "part two")
+	}
+
+	w, r = prepareTestRequest(t, bktName, objName, completeUpload)
+	query := make(url.Values)
+	query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+	r.URL.RawQuery = query.Encode()
+	hc.Handler().CompleteMultipartUploadHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, encryption.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+
+	expected := append(append([]byte{}, parts[0]...), parts[1]...)
+	require.Equal(t, expected, getObject(t, hc, bktName, objName, "", "").Bytes())
+}
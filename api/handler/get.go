@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,7 +25,10 @@ type conditionalArgs struct {
 	IfNoneMatch       string
 }
 
-func fetchRangeHeader(headers http.Header, fullSize uint64) (*layer.RangeParams, error) {
+// fetchRangeHeader parses the Range header according to RFC 7233. Ranges which
+// start beyond the object are skipped and suffix ranges are clamped to the object size.
+// Overlapping and adjacent ranges are coalesced, so the result is sorted by offset.
+func fetchRangeHeader(headers http.Header, fullSize uint64) ([]*layer.RangeParams, error) {
 	const prefix = "bytes="
 	rangeHeader := headers.Get("Range")
 	if len(rangeHeader) == 0 {
@@ -33,36 +40,114 @@ func fetchRangeHeader(headers http.Header, fullSize uint64) (*layer.RangeParams,
 	if !strings.HasPrefix(rangeHeader, prefix) {
 		return nil, fmt.Errorf("unknown unit in range header")
 	}
-	arr := strings.Split(strings.TrimPrefix(rangeHeader, prefix), "-")
-	if len(arr) != 2 || (len(arr[0]) == 0 && len(arr[1]) == 0) {
+
+	var ranges []*layer.RangeParams
+	for _, spec := range strings.Split(strings.TrimPrefix(rangeHeader, prefix), ",") {
+		spec = strings.TrimSpace(spec)
+		if len(spec) == 0 {
+			continue
+		}
+
+		params, err := parseByteRangeSpec(spec, fullSize)
+		if err != nil {
+			return nil, err
+		}
+		if params != nil {
+			ranges = append(ranges, params)
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, errors.GetAPIError(errors.ErrInvalidRange)
+	}
+
+	return coalesceRanges(ranges), nil
+}
+
+// parseByteRangeSpec parses a single byte-range-spec or suffix-byte-range-spec.
+// It returns nil if the range isn't satisfiable for the object of the specified size.
+func parseByteRangeSpec(spec string, fullSize uint64) (*layer.RangeParams, error) {
+	index := strings.Index(spec, "-")
+	if index < 0 || len(spec) == 1 {
 		return nil, fmt.Errorf("unknown byte-range-set")
 	}
 
-	var end, start uint64
-	var err0, err1 error
+	first, last := spec[:index], spec[index+1:]
 	base, bitSize := 10, 64
 
-	if len(arr[0]) == 0 {
-		end, err1 = strconv.ParseUint(arr[1], base, bitSize)
-		start = fullSize - end
-		end = fullSize - 1
-	} else if len(arr[1]) == 0 {
-		start, err0 = strconv.ParseUint(arr[0], base, bitSize)
-		end = fullSize - 1
-	} else {
-		start, err0 = strconv.ParseUint(arr[0], base, bitSize)
-		end, err1 = strconv.ParseUint(arr[1], base, bitSize)
+	if len(first) == 0 {
+		suffix, err := strconv.ParseUint(last, base, bitSize)
+		if err != nil {
+			return nil, errors.GetAPIError(errors.ErrInvalidRange)
+		}
+		if suffix == 0 {
+			return nil, nil
+		}
+		if suffix > fullSize {
+			suffix = fullSize
+		}
+		return &layer.RangeParams{Start: fullSize - suffix, End: fullSize - 1}, nil
+	}
+
+	start, err := strconv.ParseUint(first, base, bitSize)
+	if err != nil {
+		return nil, errors.GetAPIError(errors.ErrInvalidRange)
+	}
+
+	end := fullSize - 1
+	if len(last) != 0 {
+		if end, err = strconv.ParseUint(last, base, bitSize); err != nil || start > end {
+			return nil, errors.GetAPIError(errors.ErrInvalidRange)
+		}
 		if end > fullSize-1 {
 			end = fullSize - 1
 		}
 	}
 
-	if err0 != nil || err1 != nil || start > end || start > fullSize {
-		return nil, errors.GetAPIError(errors.ErrInvalidRange)
+	if start > end {
+		return nil, nil
 	}
 	return &layer.RangeParams{Start: start, End: end}, nil
 }
 
+// coalesceRanges sorts the ranges and merges overlapping and adjacent ones.
+func coalesceRanges(ranges []*layer.RangeParams) []*layer.RangeParams {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	res := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := res[len(res)-1]
+		if r.Start > last.End+1 {
+			res = append(res, r)
+			continue
+		}
+		if r.End > last.End {
+			last.End = r.End
+		}
+	}
+
+	return res
+}
+
+// checkIfRange checks if the Range header must be applied according to the If-Range header:
+// the header contains either the current ETag or the exact Last-Modified date of the object.
+func checkIfRange(headers http.Header, info *data.ObjectInfo) bool {
+	ifRange := headers.Get(api.IfRange)
+	if len(ifRange) == 0 {
+		return true
+	}
+
+	if date, err := time.Parse(http.TimeFormat, ifRange); err == nil {
+		return info.Created.UTC().Truncate(time.Second).Equal(date)
+	}
+
+	// weak entity tags can't be used for range requests
+	if strings.HasPrefix(ifRange, "W/") {
+		return false
+	}
+	return strings.Trim(ifRange, "\"") == info.HashSum
+}
+
 func overrideResponseHeaders(h http.Header, query url.Values) {
 	for key, value := range query {
 		if hdr, ok := api.ResponseModifiers[strings.ToLower(key)]; ok {
@@ -98,7 +183,7 @@ func writeHeaders(h http.Header, info *data.ObjectInfo, tagSetLength int) {
 
 func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 	var (
-		params *layer.RangeParams
+		ranges []*layer.RangeParams
 
 		reqInfo = api.GetReqInfo(r.Context())
 	)
@@ -121,6 +206,12 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 		VersionID: reqInfo.URL.Query().Get(api.QueryVersionID),
 	}
 
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
 	extendedInfo, err := h.obj.GetObjectInfo(r.Context(), p)
 	if err != nil {
 		h.logAndSendError(w, "could not find object", reqInfo, err)
@@ -128,14 +219,21 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	info := extendedInfo.ObjectInfo
 
+	if err = encryptionParams.MatchObjectEncryption(info.EncryptionInfo); err != nil {
+		h.logAndSendError(w, "encryption doesn't match object", reqInfo, err)
+		return
+	}
+
 	if err = checkPreconditions(info, conditional); err != nil {
 		h.logAndSendError(w, "precondition failed", reqInfo, err)
 		return
 	}
 
-	if params, err = fetchRangeHeader(r.Header, uint64(info.Size)); err != nil {
-		h.logAndSendError(w, "could not parse range header", reqInfo, err)
-		return
+	if checkIfRange(r.Header, info) {
+		if ranges, err = fetchRangeHeader(r.Header, uint64(info.Size)); err != nil {
+			h.logAndSendError(w, "could not parse range header", reqInfo, err)
+			return
+		}
 	}
 
 	t := &layer.ObjectVersion{
@@ -159,24 +257,104 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.writeReplicationStatus(r.Context(), w.Header(), t, extendedInfo.NodeVersion)
 	writeHeaders(w.Header(), info, len(tagSet))
-	if params != nil {
-		writeRangeHeaders(w, params, info.Size)
-	} else {
-		w.WriteHeader(http.StatusOK)
-	}
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
 
 	getParams := &layer.GetObjectParams{
 		ObjectInfo: info,
 		Writer:     w,
-		Range:      params,
 		BucketInfo: bktInfo,
+		Encryption: encryptionParams,
 	}
-	if err = h.obj.GetObject(r.Context(), getParams); err != nil {
+
+	switch len(ranges) {
+	case 0:
+		writeChecksumHeaders(w.Header(), r.Header, info.Checksum)
+		w.WriteHeader(http.StatusOK)
+		err = h.obj.GetObject(r.Context(), getParams)
+	case 1:
+		writeRangeHeaders(w, ranges[0], info.Size)
+		getParams.Range = ranges[0]
+		err = h.obj.GetObject(r.Context(), getParams)
+	default:
+		err = h.getObjectRanges(r.Context(), w, getParams, ranges)
+	}
+	if err != nil {
 		h.logAndSendError(w, "could not get object", reqInfo, err)
 	}
 }
 
+// getObjectRanges writes multipart/byteranges response. Every range is read from NeoFS separately.
+func (h *handler) getObjectRanges(ctx context.Context, w http.ResponseWriter, p *layer.GetObjectParams, ranges []*layer.RangeParams) error {
+	contentType := w.Header().Get(api.ContentType)
+	mw := multipart.NewWriter(w)
+
+	partHeaders := make([]textproto.MIMEHeader, len(ranges))
+	for i, rng := range ranges {
+		partHeaders[i] = make(textproto.MIMEHeader)
+		if len(contentType) > 0 {
+			partHeaders[i].Set(api.ContentType, contentType)
+		}
+		partHeaders[i].Set(api.ContentRange, fmt.Sprintf("bytes %d-%d/%d", rng.Start, rng.End, p.ObjectInfo.Size))
+	}
+
+	size, err := multipartRangesSize(mw.Boundary(), partHeaders, ranges)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(api.AcceptRanges, "bytes")
+	w.Header().Set(api.ContentType, "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set(api.ContentLength, strconv.FormatUint(size, 10))
+	w.WriteHeader(http.StatusPartialContent)
+
+	for i, rng := range ranges {
+		part, err := mw.CreatePart(partHeaders[i])
+		if err != nil {
+			return err
+		}
+
+		prm := *p
+		prm.Writer = part
+		prm.Range = rng
+		if err = h.obj.GetObject(ctx, &prm); err != nil {
+			return fmt.Errorf("get range %d-%d: %w", rng.Start, rng.End, err)
+		}
+	}
+
+	return mw.Close()
+}
+
+// multipartRangesSize calculates the length of multipart/byteranges body.
+func multipartRangesSize(boundary string, partHeaders []textproto.MIMEHeader, ranges []*layer.RangeParams) (uint64, error) {
+	var counter byteCounter
+	mw := multipart.NewWriter(&counter)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return 0, err
+	}
+
+	var size uint64
+	for i, rng := range ranges {
+		if _, err := mw.CreatePart(partHeaders[i]); err != nil {
+			return 0, err
+		}
+		size += rng.End - rng.Start + 1
+	}
+	if err := mw.Close(); err != nil {
+		return 0, err
+	}
+
+	return size + uint64(counter), nil
+}
+
+type byteCounter uint64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
 func checkPreconditions(info *data.ObjectInfo, args *conditionalArgs) error {
 	if len(args.IfMatch) > 0 && args.IfMatch != info.HashSum {
 		return errors.GetAPIError(errors.ErrPreconditionFailed)
@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
@@ -14,15 +21,20 @@ import (
 func TestFetchRangeHeader(t *testing.T) {
 	for _, tc := range []struct {
 		header   string
-		expected *layer.RangeParams
+		expected []*layer.RangeParams
 		fullSize uint64
 		err      bool
 	}{
-		{header: "bytes=0-256", expected: &layer.RangeParams{Start: 0, End: 256}, fullSize: 257, err: false},
-		{header: "bytes=0-0", expected: &layer.RangeParams{Start: 0, End: 0}, fullSize: 1, err: false},
-		{header: "bytes=0-256", expected: &layer.RangeParams{Start: 0, End: 255}, fullSize: 256, err: false},
-		{header: "bytes=0-", expected: &layer.RangeParams{Start: 0, End: 99}, fullSize: 100, err: false},
-		{header: "bytes=-10", expected: &layer.RangeParams{Start: 90, End: 99}, fullSize: 100, err: false},
+		{header: "bytes=0-256", expected: []*layer.RangeParams{{Start: 0, End: 256}}, fullSize: 257, err: false},
+		{header: "bytes=0-0", expected: []*layer.RangeParams{{Start: 0, End: 0}}, fullSize: 1, err: false},
+		{header: "bytes=0-256", expected: []*layer.RangeParams{{Start: 0, End: 255}}, fullSize: 256, err: false},
+		{header: "bytes=0-", expected: []*layer.RangeParams{{Start: 0, End: 99}}, fullSize: 100, err: false},
+		{header: "bytes=-10", expected: []*layer.RangeParams{{Start: 90, End: 99}}, fullSize: 100, err: false},
+		{header: "bytes=-200", expected: []*layer.RangeParams{{Start: 0, End: 99}}, fullSize: 100, err: false},
+		{header: "bytes=0-9, 20-29,-10", expected: []*layer.RangeParams{{Start: 0, End: 9}, {Start: 20, End: 29}, {Start: 90, End: 99}}, fullSize: 100, err: false},
+		{header: "bytes=50-59,0-9", expected: []*layer.RangeParams{{Start: 0, End: 9}, {Start: 50, End: 59}}, fullSize: 100, err: false},
+		{header: "bytes=0-9,5-19,20-29", expected: []*layer.RangeParams{{Start: 0, End: 29}}, fullSize: 100, err: false},
+		{header: "bytes=0-9,200-300", expected: []*layer.RangeParams{{Start: 0, End: 9}}, fullSize: 100, err: false},
 		{header: "", err: false},
 		{header: "bytes=-1-256", err: true},
 		{header: "bytes=256-0", err: true},
@@ -30,6 +42,9 @@ func TestFetchRangeHeader(t *testing.T) {
 		{header: "bytes=0-string", err: true},
 		{header: "bytes:0-256", err: true},
 		{header: "bytes:-", err: true},
+		{header: "bytes=-", fullSize: 100, err: true},
+		{header: "bytes=0-9,string", fullSize: 100, err: true},
+		{header: "bytes=-0", fullSize: 100, err: true},
 		{header: "bytes=0-0", fullSize: 0, err: true},
 		{header: "bytes=10-20", fullSize: 5, err: true},
 	} {
@@ -37,13 +52,110 @@ func TestFetchRangeHeader(t *testing.T) {
 		h.Add("Range", tc.header)
 		params, err := fetchRangeHeader(h, tc.fullSize)
 		if tc.err {
-			require.Error(t, err)
+			require.Error(t, err, tc.header)
 			continue
 		}
 
+		require.NoError(t, err, tc.header)
+		require.Equal(t, tc.expected, params, tc.header)
+	}
+}
+
+func TestCheckIfRange(t *testing.T) {
+	modified := time.Date(2022, time.March, 1, 10, 20, 30, 500, time.UTC)
+	info := newInfo("etag", modified)
+
+	for _, tc := range []struct {
+		ifRange  string
+		expected bool
+	}{
+		{ifRange: "", expected: true},
+		{ifRange: "etag", expected: true},
+		{ifRange: `"etag"`, expected: true},
+		{ifRange: `W/"etag"`, expected: false},
+		{ifRange: `"etag2"`, expected: false},
+		{ifRange: modified.Format(http.TimeFormat), expected: true},
+		{ifRange: modified.Add(time.Second).Format(http.TimeFormat), expected: false},
+		{ifRange: modified.Add(-time.Second).Format(http.TimeFormat), expected: false},
+	} {
+		h := make(http.Header)
+		h.Set(api.IfRange, tc.ifRange)
+		require.Equal(t, tc.expected, checkIfRange(h, info), tc.ifRange)
+	}
+}
+
+func TestGetObjectRanges(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-ranges", "object"
+	createTestBucket(hc.Context(), t, hc, bktName)
+
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+	r.Header.Set(api.ContentType, "text/plain")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	etag := w.Header().Get(api.ETag)
+
+	t.Run("single range", func(t *testing.T) {
+		w := getObjectRange(t, hc, bktName, objName, "bytes=-100", "")
+		assertStatus(t, w, http.StatusPartialContent)
+		require.Equal(t, "bytes 0-35/36", w.Header().Get(api.ContentRange))
+		require.Equal(t, content, w.Body.Bytes())
+	})
+
+	t.Run("multiple ranges", func(t *testing.T) {
+		w := getObjectRange(t, hc, bktName, objName, "bytes=10-15,0-2,-3", etag)
+		assertStatus(t, w, http.StatusPartialContent)
+		require.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get(api.ContentLength))
+
+		mediaType, params, err := mime.ParseMediaType(w.Header().Get(api.ContentType))
 		require.NoError(t, err)
-		require.Equal(t, tc.expected, params)
+		require.Equal(t, "multipart/byteranges", mediaType)
+
+		expected := []struct {
+			contentRange string
+			payload      []byte
+		}{
+			{contentRange: "bytes 0-2/36", payload: content[:3]},
+			{contentRange: "bytes 10-15/36", payload: content[10:16]},
+			{contentRange: "bytes 33-35/36", payload: content[33:]},
+		}
+
+		mr := multipart.NewReader(w.Body, params["boundary"])
+		for _, exp := range expected {
+			part, err := mr.NextPart()
+			require.NoError(t, err)
+			require.Equal(t, "text/plain", part.Header.Get(api.ContentType))
+			require.Equal(t, exp.contentRange, part.Header.Get(api.ContentRange))
+			payload, err := io.ReadAll(part)
+			require.NoError(t, err)
+			require.Equal(t, exp.payload, payload)
+		}
+		_, err = mr.NextPart()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("if-range doesn't match", func(t *testing.T) {
+		w := getObjectRange(t, hc, bktName, objName, "bytes=0-2,5-7", `"outdated"`)
+		assertStatus(t, w, http.StatusOK)
+		require.Equal(t, content, w.Body.Bytes())
+	})
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		w := getObjectRange(t, hc, bktName, objName, "bytes=100-200,300-", "")
+		assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidRange))
+	})
+}
+
+func getObjectRange(t *testing.T, hc *handlerContext, bktName, objName, rng, ifRange string) *httptest.ResponseRecorder {
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	r.Header.Set("Range", rng)
+	if ifRange != "" {
+		r.Header.Set(api.IfRange, ifRange)
 	}
+	hc.Handler().GetObjectHandler(w, r)
+	return w
 }
 
 func newInfo(etag string, created time.Time) *data.ObjectInfo {
@@ -17,6 +17,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
@@ -61,11 +62,16 @@ func prepareHandlerContext(t *testing.T) *handlerContext {
 		return tp.ContainerID(name)
 	})
 
+	keyRing, err := encryption.NewKeyRing("test-key", map[string][]byte{"test-key": []byte(testManagedKey)})
+	require.NoError(t, err)
+
 	layerCfg := &layer.Config{
 		Caches:      layer.DefaultCachesConfigs(zap.NewExample()),
 		AnonKey:     layer.AnonymousKey{Key: key},
 		Resolver:    testResolver,
 		TreeService: layer.NewTreeService(),
+		KeyRing:     keyRing,
+		Usage:       layer.NewUsageTracker(0, true),
 	}
 
 	h := &handler{
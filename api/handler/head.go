@@ -46,6 +46,12 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 		VersionID: reqInfo.URL.Query().Get(api.QueryVersionID),
 	}
 
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
 	extendedInfo, err := h.obj.GetObjectInfo(r.Context(), p)
 	if err != nil {
 		h.logAndSendError(w, "could not find object", reqInfo, err)
@@ -53,6 +59,11 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	info := extendedInfo.ObjectInfo
 
+	if err = encryptionParams.MatchObjectEncryption(info.EncryptionInfo); err != nil {
+		h.logAndSendError(w, "encryption doesn't match object", reqInfo, err)
+		return
+	}
+
 	if err = checkPreconditions(info, conditional); err != nil {
 		h.logAndSendError(w, "precondition failed", reqInfo, err)
 		return
@@ -78,6 +89,7 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 				Writer:     buffer,
 				Range:      getRangeToDetectContentType(info.Size),
 				BucketInfo: bktInfo,
+				Encryption: encryptionParams,
 			}
 			if err = h.obj.GetObject(r.Context(), getParams); err != nil {
 				h.logAndSendError(w, "could not get object", reqInfo, err, zap.Stringer("oid", info.ID))
@@ -92,7 +104,10 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.writeReplicationStatus(r.Context(), w.Header(), t, extendedInfo.NodeVersion)
 	writeHeaders(w.Header(), info, len(tagSet))
+	writeChecksumHeaders(w.Header(), r.Header, info.Checksum)
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
 	w.WriteHeader(http.StatusOK)
 }
 
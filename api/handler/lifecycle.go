@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+const (
+	maxLifecycleRules         = 1000
+	maxLifecycleRuleIDLength  = 255
+	maxNewerNoncurrentVersion = 100
+)
+
+func (h *handler) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketLifecycleConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode bucket lifecycle configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &data.LifecycleConfiguration{}
+	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't decode lifecycle configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if err = checkLifecycleConfiguration(conf); err != nil {
+		h.logAndSendError(w, "invalid lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	p := &layer.PutBucketLifecycleParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+	}
+
+	if err = h.obj.PutBucketLifecycleConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put bucket lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketLifecycleConfiguration(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "couldn't delete bucket lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkLifecycleConfiguration checks lifecycle rules and generates IDs for rules with empty ones.
+func checkLifecycleConfiguration(conf *data.LifecycleConfiguration) error {
+	if len(conf.Rules) == 0 || len(conf.Rules) > maxLifecycleRules {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	ids := make(map[string]struct{}, len(conf.Rules))
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+
+		if rule.ID == "" {
+			rule.ID = uuid.NewString()
+		}
+		if len(rule.ID) > maxLifecycleRuleIDLength {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("rule ID is too long"))
+		}
+		if _, ok := ids[rule.ID]; ok {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("rule ID must be unique: %s", rule.ID))
+		}
+		ids[rule.ID] = struct{}{}
+
+		if err := checkLifecycleRule(rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkLifecycleRule(rule *data.LifecycleRule) error {
+	if rule.Status != data.LifecycleStatusEnabled && rule.Status != data.LifecycleStatusDisabled {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if rule.Expiration == nil && rule.NoncurrentVersionExpiration == nil && rule.AbortIncompleteMultipartUpload == nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("at least one action must be specified in a rule"))
+	}
+
+	if err := checkLifecycleFilter(rule); err != nil {
+		return err
+	}
+
+	hasTags := len(rule.RuleTags()) != 0
+
+	if exp := rule.Expiration; exp != nil {
+		var set int
+		if exp.Days != nil {
+			if *exp.Days <= 0 {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("'Days' for Expiration action must be a positive integer"))
+			}
+			set++
+		}
+		if exp.Date != "" {
+			date, err := time.Parse(time.RFC3339, exp.Date)
+			if err != nil || !date.Equal(date.UTC().Truncate(24*time.Hour)) {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("'Date' must be at midnight GMT"))
+			}
+			set++
+		}
+		if exp.ExpiredObjectDeleteMarker != nil {
+			if hasTags {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("ExpiredObjectDeleteMarker cannot be specified with tags"))
+			}
+			set++
+		}
+		if set != 1 {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+	}
+
+	if exp := rule.NoncurrentVersionExpiration; exp != nil {
+		if exp.NoncurrentDays == nil || *exp.NoncurrentDays <= 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("'NoncurrentDays' for NoncurrentVersionExpiration action must be a positive integer"))
+		}
+		if exp.NewerNoncurrentVersions != nil && (*exp.NewerNoncurrentVersions <= 0 || *exp.NewerNoncurrentVersions > maxNewerNoncurrentVersion) {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("'NewerNoncurrentVersions' must be between 1 and %d", maxNewerNoncurrentVersion))
+		}
+	}
+
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil {
+		if abort.DaysAfterInitiation == nil || *abort.DaysAfterInitiation <= 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("'DaysAfterInitiation' for AbortIncompleteMultipartUpload action must be a positive integer"))
+		}
+		if hasTags {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("AbortIncompleteMultipartUpload cannot be specified with tags"))
+		}
+	}
+
+	return nil
+}
+
+func checkLifecycleFilter(rule *data.LifecycleRule) error {
+	filter := rule.Filter
+	if filter == nil {
+		return nil
+	}
+
+	if rule.Prefix != "" {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	var set int
+	if filter.Prefix != "" {
+		set++
+	}
+	if filter.Tag != nil {
+		set++
+	}
+	if filter.And != nil {
+		set++
+	}
+	if set > 1 {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	tags := rule.RuleTags()
+	keys := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if err := checkTag(Tag{Key: tag.Key, Value: tag.Value}); err != nil {
+			return err
+		}
+		if _, ok := keys[tag.Key]; ok {
+			return errors.GetAPIError(errors.ErrInvalidTagKey)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
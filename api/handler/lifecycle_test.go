@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLifecycleConfiguration(t *testing.T) {
+	days := func(d int) *int { return &d }
+	boolPtr := func(b bool) *bool { return &b }
+
+	for _, tc := range []struct {
+		name  string
+		rules []data.LifecycleRule
+		valid bool
+	}{
+		{
+			name:  "no rules",
+			valid: false,
+		},
+		{
+			name: "expiration days",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Filter:     &data.LifecycleRuleFilter{Prefix: "logs/"},
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: true,
+		},
+		{
+			name: "expiration date",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusDisabled,
+				Expiration: &data.LifecycleExpiration{Date: "2030-01-01T00:00:00Z"},
+			}},
+			valid: true,
+		},
+		{
+			name: "expiration date not at midnight",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Expiration: &data.LifecycleExpiration{Date: "2030-01-01T10:00:00Z"},
+			}},
+			valid: false,
+		},
+		{
+			name: "zero days",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Expiration: &data.LifecycleExpiration{Days: days(0)},
+			}},
+			valid: false,
+		},
+		{
+			name: "days and date",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Expiration: &data.LifecycleExpiration{Days: days(1), Date: "2030-01-01T00:00:00Z"},
+			}},
+			valid: false,
+		},
+		{
+			name: "invalid status",
+			rules: []data.LifecycleRule{{
+				Status:     "enabled",
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: false,
+		},
+		{
+			name:  "no actions",
+			rules: []data.LifecycleRule{{Status: data.LifecycleStatusEnabled}},
+			valid: false,
+		},
+		{
+			name: "same id",
+			rules: []data.LifecycleRule{
+				{ID: "rule", Status: data.LifecycleStatusEnabled, Expiration: &data.LifecycleExpiration{Days: days(1)}},
+				{ID: "rule", Status: data.LifecycleStatusEnabled, Expiration: &data.LifecycleExpiration{Days: days(2)}},
+			},
+			valid: false,
+		},
+		{
+			name: "too long id",
+			rules: []data.LifecycleRule{{
+				ID:         strings.Repeat("a", maxLifecycleRuleIDLength+1),
+				Status:     data.LifecycleStatusEnabled,
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: false,
+		},
+		{
+			name: "noncurrent expiration",
+			rules: []data.LifecycleRule{{
+				Status: data.LifecycleStatusEnabled,
+				NoncurrentVersionExpiration: &data.NoncurrentVersionExpiration{
+					NoncurrentDays:          days(2),
+					NewerNoncurrentVersions: days(3),
+				},
+			}},
+			valid: true,
+		},
+		{
+			name: "noncurrent expiration without days",
+			rules: []data.LifecycleRule{{
+				Status:                      data.LifecycleStatusEnabled,
+				NoncurrentVersionExpiration: &data.NoncurrentVersionExpiration{},
+			}},
+			valid: false,
+		},
+		{
+			name: "expired delete marker with tags",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Filter:     &data.LifecycleRuleFilter{Tag: &data.Tag{Key: "key", Value: "val"}},
+				Expiration: &data.LifecycleExpiration{ExpiredObjectDeleteMarker: boolPtr(true)},
+			}},
+			valid: false,
+		},
+		{
+			name: "abort multipart upload with tags",
+			rules: []data.LifecycleRule{{
+				Status: data.LifecycleStatusEnabled,
+				Filter: &data.LifecycleRuleFilter{And: &data.LifecycleRuleAndOperator{
+					Prefix: "dir/",
+					Tags:   []data.Tag{{Key: "key", Value: "val"}},
+				}},
+				AbortIncompleteMultipartUpload: &data.AbortIncompleteMultipartUpload{DaysAfterInitiation: days(1)},
+			}},
+			valid: false,
+		},
+		{
+			name: "filter with prefix and tag",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Filter:     &data.LifecycleRuleFilter{Prefix: "dir/", Tag: &data.Tag{Key: "key", Value: "val"}},
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: false,
+		},
+		{
+			name: "duplicated tag keys",
+			rules: []data.LifecycleRule{{
+				Status: data.LifecycleStatusEnabled,
+				Filter: &data.LifecycleRuleFilter{And: &data.LifecycleRuleAndOperator{
+					Tags: []data.Tag{{Key: "key", Value: "val"}, {Key: "key", Value: "val2"}},
+				}},
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: false,
+		},
+		{
+			name: "legacy prefix with filter",
+			rules: []data.LifecycleRule{{
+				Status:     data.LifecycleStatusEnabled,
+				Prefix:     "dir/",
+				Filter:     &data.LifecycleRuleFilter{},
+				Expiration: &data.LifecycleExpiration{Days: days(1)},
+			}},
+			valid: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkLifecycleConfiguration(&data.LifecycleConfiguration{Rules: tc.rules})
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBucketLifecycleConfiguration(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-lifecycle"
+	createTestBucket(ctx, t, hc, bktName)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNoSuchLifecycleConfiguration))
+
+	days := 10
+	conf := &data.LifecycleConfiguration{
+		Rules: []data.LifecycleRule{{
+			Status:     data.LifecycleStatusEnabled,
+			Filter:     &data.LifecycleRuleFilter{Prefix: "logs/"},
+			Expiration: &data.LifecycleExpiration{Days: &days},
+		}},
+	}
+
+	w, r = prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	actualConf := &data.LifecycleConfiguration{}
+	parseTestResponse(t, w, actualConf)
+	require.Len(t, actualConf.Rules, 1)
+	require.NotEmpty(t, actualConf.Rules[0].ID)
+	require.Equal(t, "logs/", actualConf.Rules[0].RulePrefix())
+	require.Equal(t, days, *actualConf.Rules[0].Expiration.Days)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().DeleteBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNoSuchLifecycleConfiguration))
+}
+
+func TestPutBucketLifecycleMalformedXML(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-lifecycle"
+	createTestBucket(ctx, t, hc, bktName)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, defaultURL, bytes.NewReader([]byte("<LifecycleConfiguration>")))
+	r = r.WithContext(api.SetReqInfo(r.Context(), api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName})))
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrMalformedXML))
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+type (
+	// Listener fans out notification events to ListenBucketNotification clients.
+	Listener interface {
+		// Listen subscribes to events of the bucket matching the filter. Events are encoded
+		// notification records, the subscription is canceled by the returned function.
+		Listen(bucket string, filter *ListenFilter) (events <-chan []byte, cancel func())
+		// Publish sends the event to the listeners of the bucket, it mustn't block.
+		Publish(p *SendNotificationParams)
+	}
+
+	// ListenFilter selects events streamed to a ListenBucketNotification client.
+	ListenFilter struct {
+		Prefix string
+		Suffix string
+		Events []string
+	}
+)
+
+// listenKeepAliveInterval is a period of blank lines sent to keep idle listen connections open.
+var listenKeepAliveInterval = 500 * time.Millisecond
+
+var listenNewLine = []byte{'\n'}
+
+// Match checks if the event of the object passes the filter.
+func (f *ListenFilter) Match(eventType, objName string) bool {
+	return matchEvent(f.Events, eventType) &&
+		strings.HasPrefix(objName, f.Prefix) && strings.HasSuffix(objName, f.Suffix)
+}
+
+func (h *handler) ListenBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if h.listener == nil {
+		h.logAndSendError(w, "listening to notifications is disabled", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &ListenFilter{
+		Prefix: query.Get(filterRulePrefixName),
+		Suffix: query.Get(filterRuleSuffixName),
+	}
+	for _, events := range query["events"] {
+		for _, e := range strings.Split(events, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				filter.Events = append(filter.Events, e)
+			}
+		}
+	}
+	if len(filter.Events) == 0 {
+		h.logAndSendError(w, "no events to listen", reqInfo, errors.GetAPIError(errors.ErrEventNotification))
+		return
+	}
+	if err = h.checkEvents(filter.Events); err != nil {
+		h.logAndSendError(w, "invalid events to listen", reqInfo, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.logAndSendError(w, "response can't be streamed", reqInfo, errors.GetAPIError(errors.ErrInternalError))
+		return
+	}
+
+	events, cancel := h.listener.Listen(bktInfo.Name, filter)
+	defer cancel()
+
+	w.Header().Set(api.ContentType, "text/event-stream")
+	w.Header().Set(api.CacheControl, "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(listenKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		var msg []byte
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+		case msg, ok = <-events:
+			if !ok {
+				return
+			}
+		}
+
+		// the message is shared between listeners, so it's written as is
+		if _, err = w.Write(msg); err == nil {
+			_, err = w.Write(listenNewLine)
+		}
+		if err != nil {
+			h.log.Debug("listen connection is closed", zap.String("bucket", bktInfo.Name), zap.Error(err))
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testListener struct {
+	mu        sync.Mutex
+	ch        chan []byte
+	filter    *ListenFilter
+	published []*SendNotificationParams
+}
+
+func (l *testListener) Listen(_ string, filter *ListenFilter) (<-chan []byte, func()) {
+	l.mu.Lock()
+	l.filter = filter
+	l.mu.Unlock()
+	return l.ch, func() {}
+}
+
+func (l *testListener) Publish(p *SendNotificationParams) {
+	l.mu.Lock()
+	l.published = append(l.published, p)
+	l.mu.Unlock()
+}
+
+func (l *testListener) listening() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.filter != nil
+}
+
+func TestListenFilter(t *testing.T) {
+	filter := &ListenFilter{Prefix: "dir/", Suffix: ".png", Events: []string{EventObjectCreated, EventObjectRemovedDelete}}
+
+	require.True(t, filter.Match(EventObjectCreatedPut, "dir/cat.png"))
+	require.True(t, filter.Match(EventObjectRemovedDelete, "dir/cat.png"))
+	require.False(t, filter.Match(EventObjectRemovedDeleteMarkerCreated, "dir/cat.png"))
+	require.False(t, filter.Match(EventObjectCreatedPut, "cat.png"))
+	require.False(t, filter.Match(EventObjectCreatedPut, "dir/cat.jpg"))
+}
+
+func TestListenBucketNotification(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	lst := &testListener{ch: make(chan []byte, 1)}
+	hc.h.listener = lst
+
+	bktName := "bucket-for-listen"
+	createTestBucket(hc.Context(), t, hc, bktName)
+
+	t.Run("events are published without notificator", func(t *testing.T) {
+		putObject(t, hc, bktName, "object")
+		require.Len(t, lst.published, 1)
+		require.Equal(t, EventObjectCreatedPut, lst.published[0].Event)
+		require.Equal(t, "object", lst.published[0].NotificationInfo.Name)
+	})
+
+	t.Run("invalid events", func(t *testing.T) {
+		for _, events := range []string{"", "s3:Unknown"} {
+			query := url.Values{"events": []string{events}}
+			w, r := prepareTestFullRequest(t, bktName, "", query, nil)
+			hc.Handler().ListenBucketNotificationHandler(w, r)
+			assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrEventNotification))
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		interval := listenKeepAliveInterval
+		listenKeepAliveInterval = 10 * time.Millisecond
+		defer func() { listenKeepAliveInterval = interval }()
+
+		query := url.Values{
+			"events": []string{EventObjectCreated + "," + EventObjectRemoved, EventObjectTaggingPut},
+			"prefix": []string{"dir/"},
+		}
+		w, r := prepareTestFullRequest(t, bktName, "", query, nil)
+		ctx, cancel := context.WithCancel(r.Context())
+		r = r.WithContext(ctx)
+
+		lst.ch <- []byte(`{"Records":[]}`)
+
+		done := make(chan struct{})
+		go func() {
+			hc.Handler().ListenBucketNotificationHandler(w, r)
+			close(done)
+		}()
+
+		require.Eventually(t, lst.listening, time.Second, 5*time.Millisecond)
+		time.Sleep(5 * listenKeepAliveInterval)
+		cancel()
+		<-done
+
+		require.Equal(t, &ListenFilter{
+			Prefix: "dir/",
+			Events: []string{EventObjectCreated, EventObjectRemoved, EventObjectTaggingPut},
+		}, lst.filter)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "text/event-stream", w.Header().Get(api.ContentType))
+		require.True(t, w.Flushed)
+
+		body := w.Body.String()
+		require.True(t, strings.HasPrefix(body, `{"Records":[]}`+"\n\n"), body)
+		require.Empty(t, strings.Trim(strings.TrimPrefix(body, `{"Records":[]}`), "\n"))
+	})
+}
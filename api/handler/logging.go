@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+func (h *handler) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketLoggingConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket logging configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode bucket logging configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &data.BucketLoggingStatus{}
+	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't decode logging configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	// empty status disables logging
+	if conf.LoggingEnabled == nil {
+		if err = h.obj.DeleteBucketLoggingConfiguration(r.Context(), bktInfo); err != nil {
+			h.logAndSendError(w, "couldn't delete bucket logging configuration", reqInfo, err)
+		}
+		return
+	}
+
+	if err = h.checkLoggingTarget(r, bktInfo, conf.LoggingEnabled); err != nil {
+		h.logAndSendError(w, "invalid logging target", reqInfo, err)
+		return
+	}
+
+	p := &layer.PutBucketLoggingParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+	}
+
+	if err = h.obj.PutBucketLoggingConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put bucket logging configuration", reqInfo, err)
+		return
+	}
+}
+
+// checkLoggingTarget checks that the target bucket exists and has the same owner as the source bucket.
+func (h *handler) checkLoggingTarget(r *http.Request, bktInfo *data.BucketInfo, target *data.LoggingEnabled) error {
+	if target.TargetBucket == "" {
+		return errors.GetAPIError(errors.ErrInvalidTargetBucketForLogging)
+	}
+
+	targetInfo, err := h.obj.GetBucketInfo(r.Context(), target.TargetBucket)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchBucket) {
+			return errors.GetAPIError(errors.ErrInvalidTargetBucketForLogging)
+		}
+		return err
+	}
+
+	if !targetInfo.Owner.Equals(bktInfo.Owner) {
+		return errors.GetAPIError(errors.ErrInvalidTargetBucketForLogging)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	usertest "github.com/nspcc-dev/neofs-sdk-go/user/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketLoggingConfiguration(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	owner := *usertest.ID()
+	bktName, targetName, foreignName := "bucket-for-logging", "bucket-for-logs", "bucket-of-another-owner"
+	for _, name := range []string{bktName, targetName} {
+		_, err := hc.MockedPool().CreateContainer(hc.Context(), layer.PrmContainerCreate{
+			Creator: owner,
+			Name:    name,
+		})
+		require.NoError(t, err)
+	}
+	createTestBucket(hc.Context(), t, hc, foreignName)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLoggingHandler(w, r)
+	status := &data.BucketLoggingStatus{}
+	parseTestResponse(t, w, status)
+	require.Nil(t, status.LoggingEnabled)
+
+	t.Run("invalid target", func(t *testing.T) {
+		for _, target := range []string{"", "unknown-bucket", foreignName} {
+			conf := &data.BucketLoggingStatus{LoggingEnabled: &data.LoggingEnabled{TargetBucket: target}}
+			w, r := prepareTestRequest(t, bktName, "", conf)
+			hc.Handler().PutBucketLoggingHandler(w, r)
+			assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrInvalidTargetBucketForLogging))
+		}
+	})
+
+	conf := &data.BucketLoggingStatus{LoggingEnabled: &data.LoggingEnabled{TargetBucket: targetName, TargetPrefix: "logs/"}}
+	w, r = prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketLoggingHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLoggingHandler(w, r)
+	status = &data.BucketLoggingStatus{}
+	parseTestResponse(t, w, status)
+	require.Equal(t, conf.LoggingEnabled, status.LoggingEnabled)
+
+	// empty status disables logging
+	w, r = prepareTestRequest(t, bktName, "", &data.BucketLoggingStatus{})
+	hc.Handler().PutBucketLoggingHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketLoggingHandler(w, r)
+	status = &data.BucketLoggingStatus{}
+	parseTestResponse(t, w, status)
+	require.Nil(t, status.LoggingEnabled)
+}
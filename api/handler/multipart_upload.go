@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"go.uber.org/zap"
 )
@@ -29,6 +30,7 @@ type (
 		Bucket  string   `xml:"Bucket"`
 		Key     string   `xml:"Key"`
 		ETag    string   `xml:"ETag"`
+		data.ChecksumFields
 	}
 
 	ListMultipartUploadsResponse struct {
@@ -142,6 +144,22 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 		p.Header[api.ContentType] = contentType
 	}
 
+	if p.ChecksumAlgorithm, err = parseChecksumAlgorithm(r.Header); err != nil {
+		h.logAndSendError(w, "invalid checksum algorithm", reqInfo, err, additional...)
+		return
+	}
+
+	if p.Info.Encryption, err = formEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err, additional...)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err, additional...)
+		return
+	}
+
 	if err = h.obj.CreateMultipartUpload(r.Context(), p); err != nil {
 		h.logAndSendError(w, "could not upload a part", reqInfo, err, additional...)
 		return
@@ -153,6 +171,16 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 		UploadID: uploadID.String(),
 	}
 
+	if p.ChecksumAlgorithm != "" {
+		w.Header().Set(api.AmzChecksumAlgorithm, p.ChecksumAlgorithm)
+	}
+	if p.Info.Encryption.Enabled() {
+		w.Header().Set(api.AmzServerSideEncryptionCustomerAlgorithm, encryption.AESEncryptionAlgorithm)
+		w.Header().Set(api.AmzServerSideEncryptionCustomerKeyMD5, p.Info.Encryption.CustomerKeyMD5())
+	} else if settings.EncryptionConfiguration != nil {
+		w.Header().Set(api.AmzServerSideEncryption, encryption.AESEncryptionAlgorithm)
+	}
+
 	if err = api.EncodeToResponse(w, resp); err != nil {
 		h.logAndSendError(w, "could not encode InitiateMultipartUploadResponse to response", reqInfo, err, additional...)
 		return
@@ -210,13 +238,34 @@ func (h *handler) UploadPartHandler(w http.ResponseWriter, r *http.Request) {
 		Reader:     r.Body,
 	}
 
-	hash, err := h.obj.UploadPart(r.Context(), p)
+	if p.ContentMD5, err = parseContentMD5(r.Header); err != nil {
+		h.logAndSendError(w, "invalid Content-MD5", reqInfo, err, additional...)
+		return
+	}
+	if p.ContentSHA256Hash, err = parseContentSHA256(r.Header); err != nil {
+		h.logAndSendError(w, "invalid x-amz-content-sha256", reqInfo, err, additional...)
+		return
+	}
+	if p.Checksum, err = parseChecksum(r.Header); err != nil {
+		h.logAndSendError(w, "invalid checksum", reqInfo, err, additional...)
+		return
+	}
+	if p.Info.Encryption, err = formEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err, additional...)
+		return
+	}
+
+	info, err := h.obj.UploadPart(r.Context(), p)
 	if err != nil {
-		h.logAndSendError(w, "could not upload a part", reqInfo, err, additional...)
+		h.logAndSendError(w, "could not upload a part", reqInfo, bodyReadError(err), additional...)
 		return
 	}
 
-	w.Header().Set(api.ETag, hash)
+	w.Header().Set(api.ETag, info.HashSum)
+	if info.Checksum != nil {
+		w.Header().Set(data.ChecksumHeader(info.Checksum.Algorithm), info.Checksum.Value)
+	}
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
@@ -255,6 +304,11 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkCopySourcePolicy(r, srcBktInfo, srcObject, versionID); err != nil {
+		h.logAndSendError(w, "copy source access denied by bucket policy", reqInfo, err, additional...)
+		return
+	}
+
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
 	if err != nil {
 		h.logAndSendError(w, "could not get target bucket info", reqInfo, err)
@@ -301,6 +355,15 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 		Range:      srcRange,
 	}
 
+	if p.SrcEncryption, err = formCopySourceEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid copy source sse headers", reqInfo, err, additional...)
+		return
+	}
+	if p.Info.Encryption, err = formEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err, additional...)
+		return
+	}
+
 	info, err := h.obj.UploadPartCopy(r.Context(), p)
 	if err != nil {
 		h.logAndSendError(w, "could not upload part copy", reqInfo, err, additional...)
@@ -312,6 +375,8 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 		LastModified: info.Created.UTC().Format(time.RFC3339),
 	}
 
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
+
 	if err = api.EncodeToResponse(w, response); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
@@ -349,6 +414,11 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	if uploadInfo.Encryption, err = formEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err, additional...)
+		return
+	}
+
 	c := &layer.CompleteMultipartParams{
 		Info:  uploadInfo,
 		Parts: reqBody.Parts,
@@ -398,6 +468,12 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 		}
 	}
 
+	h.replicate(r.Context(), &ReplicationParams{
+		BktInfo:    bktInfo,
+		ObjectName: objInfo.Name,
+		VersionID:  objInfo.Version(),
+	})
+
 	s := &SendNotificationParams{
 		Event:            EventObjectCreatedCompleteMultipartUpload,
 		NotificationInfo: data.NotificationInfoFromObject(objInfo),
@@ -405,7 +481,8 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 		ReqInfo:          reqInfo,
 	}
 	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	bktSettings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
@@ -414,14 +491,16 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 	}
 
 	response := CompleteMultipartUploadResponse{
-		Bucket: objInfo.Bucket,
-		ETag:   objInfo.HashSum,
-		Key:    objInfo.Name,
+		Bucket:         objInfo.Bucket,
+		ETag:           objInfo.HashSum,
+		Key:            objInfo.Name,
+		ChecksumFields: data.NewChecksumFields(objInfo.Checksum),
 	}
 
 	if bktSettings.VersioningEnabled() {
 		w.Header().Set(api.AmzVersionID, objInfo.Version())
 	}
+	writeEncryptionHeaders(w.Header(), objInfo.EncryptionInfo)
 
 	if err = api.EncodeToResponse(w, response); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
@@ -11,8 +11,10 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"go.uber.org/zap"
 )
 
 type (
@@ -47,7 +49,7 @@ const (
 	EventObjectRestorePost                            = "s3:ObjectRestore:Post"
 	EventObjectRestoreCompleted                       = "s3:ObjectRestore:Completed"
 	EventReplication                                  = "s3:Replication:*"
-	EventReplicationOperationFailedReplication        = "s3:Replication:OperationFailedReplication"
+	EventReplicationOperationFailedReplication        = events.ReplicationOperationFailedReplication
 	EventReplicationOperationNotTracked               = "s3:Replication:OperationNotTracked"
 	EventReplicationOperationMissedThreshold          = "s3:Replication:OperationMissedThreshold"
 	EventReplicationOperationReplicatedAfterThreshold = "s3:Replication:OperationReplicatedAfterThreshold"
@@ -56,8 +58,8 @@ const (
 	EventIntelligentTiering                           = "s3:IntelligentTiering"
 	EventObjectACLPut                                 = "s3:ObjectAcl:Put"
 	EventLifecycleExpiration                          = "s3:LifecycleExpiration:*"
-	EventLifecycleExpirationDelete                    = "s3:LifecycleExpiration:Delete"
-	EventLifecycleExpirationDeleteMarkerCreated       = "s3:LifecycleExpiration:DeleteMarkerCreated"
+	EventLifecycleExpirationDelete                    = events.LifecycleExpirationDelete
+	EventLifecycleExpirationDeleteMarkerCreated       = events.LifecycleExpirationDeleteMarkerCreated
 	EventObjectTagging                                = "s3:ObjectTagging:*"
 	EventObjectTaggingPut                             = "s3:ObjectTagging:Put"
 	EventObjectTaggingDelete                          = "s3:ObjectTagging:Delete"
@@ -93,6 +95,20 @@ var validEvents = map[string]struct{}{
 	EventObjectTaggingDelete:                          {},
 }
 
+// requestEvents are event types produced by requests to the gateway,
+// events of background processes are declared by producers of the event bus.
+var requestEvents = []string{
+	EventObjectCreatedPut,
+	EventObjectCreatedPost,
+	EventObjectCreatedCopy,
+	EventObjectCreatedCompleteMultipartUpload,
+	EventObjectRemovedDelete,
+	EventObjectRemovedDeleteMarkerCreated,
+	EventObjectACLPut,
+	EventObjectTaggingPut,
+	EventObjectTaggingDelete,
+}
+
 func (h *handler) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
@@ -145,7 +161,22 @@ func (h *handler) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// sendNotifications publishes the event to the listener and sends it to the notification targets
+// of the bucket. An error means the event may be lost (e.g. it isn't persisted in the outbox),
+// so the request must fail to be retried by the client.
 func (h *handler) sendNotifications(ctx context.Context, p *SendNotificationParams) error {
+	if !h.cfg.NotificatorEnabled && h.listener == nil {
+		return nil
+	}
+
+	box, err := layer.GetBoxData(ctx)
+	if err == nil && box.Gate.BearerToken != nil {
+		p.User = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
+	}
+
+	if h.listener != nil {
+		h.listener.Publish(p)
+	}
 	if !h.cfg.NotificatorEnabled {
 		return nil
 	}
@@ -158,11 +189,6 @@ func (h *handler) sendNotifications(ctx context.Context, p *SendNotificationPara
 		return nil
 	}
 
-	box, err := layer.GetBoxData(ctx)
-	if err == nil && box.Gate.BearerToken != nil {
-		p.User = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
-	}
-
 	topics := filterSubjects(conf, p.Event, p.NotificationInfo.Name)
 
 	return h.notificator.SendNotifications(topics, p)
@@ -174,30 +200,26 @@ func (h *handler) checkBucketConfiguration(conf *data.NotificationConfiguration,
 		return
 	}
 
-	if conf.TopicConfigurations != nil || conf.LambdaFunctionConfigurations != nil {
-		return completed, errors.GetAPIError(errors.ErrNotificationTopicNotSupported)
-	}
-
-	for i, q := range conf.QueueConfigurations {
-		if err = checkEvents(q.Events); err != nil {
+	for _, d := range conf.Destinations() {
+		if err = h.checkEvents(d.Events); err != nil {
 			return
 		}
 
-		if err = checkRules(q.Filter.Key.FilterRules); err != nil {
+		if err = checkRules(d.Filter.Key.FilterRules); err != nil {
 			return
 		}
 
 		if h.cfg.NotificatorEnabled {
-			if err = h.notificator.SendTestNotification(q.QueueArn, r.BucketName, r.RequestID, r.Host); err != nil {
+			if err = h.notificator.SendTestNotification(d.Arn, r.BucketName, r.RequestID, r.Host); err != nil {
 				return
 			}
 		} else {
 			h.log.Warn("failed to send test event because notifications is disabled")
 		}
 
-		if q.ID == "" {
+		if *d.ID == "" {
 			completed = true
-			conf.QueueConfigurations[i].ID = uuid.NewString()
+			*d.ID = uuid.NewString()
 		}
 	}
 
@@ -224,36 +246,56 @@ func checkRules(rules []data.FilterRule) error {
 	return nil
 }
 
-func checkEvents(events []string) error {
-	for _, e := range events {
+// checkEvents checks if the event types are valid and produced by the gateway,
+// so subscriptions don't silently do nothing.
+func (h *handler) checkEvents(eventTypes []string) error {
+	for _, e := range eventTypes {
 		if _, ok := validEvents[e]; !ok {
 			return errors.GetAPIError(errors.ErrEventNotification)
 		}
+		if !h.isProduced(e) {
+			return errors.GetAPIErrorWithError(errors.ErrEventNotification, fmt.Errorf("event '%s' is never produced by the gateway", e))
+		}
 	}
 
 	return nil
 }
 
+func (h *handler) isProduced(eventType string) bool {
+	for _, e := range requestEvents {
+		if matchEvent([]string{eventType}, e) {
+			return true
+		}
+	}
+
+	return h.cfg.Events != nil && h.cfg.Events.Produces(eventType)
+}
+
+// handleEvent sends notifications about the event of a background process.
+func (h *handler) handleEvent(ctx context.Context, e *events.Event) {
+	p := &SendNotificationParams{
+		Event:            e.Name,
+		NotificationInfo: e.Object,
+		BktInfo:          e.BktInfo,
+		ReqInfo:          &api.ReqInfo{BucketName: e.BktInfo.Name, ObjectName: e.Object.Name},
+	}
+
+	if err := h.sendNotifications(ctx, p); err != nil {
+		h.log.Error("couldn't send notification", zap.String("event", e.Name),
+			zap.String("bucket", e.BktInfo.Name), zap.String("object", e.Object.Name), zap.Error(err))
+	}
+}
+
 func filterSubjects(conf *data.NotificationConfiguration, eventType, objName string) map[string]string {
 	topics := make(map[string]string)
 
-	for _, t := range conf.QueueConfigurations {
-		event := false
-		for _, e := range t.Events {
-			// the second condition is comparison with the events ending with *:
-			// s3:ObjectCreated:*, s3:ObjectRemoved:* etc without the last char
-			if eventType == e || strings.HasPrefix(eventType, e[:len(e)-1]) {
-				event = true
-				break
-			}
-		}
-
-		if !event {
+	for _, d := range conf.Destinations() {
+		if !matchEvent(d.Events, eventType) {
 			continue
 		}
 
 		filter := true
-		for _, f := range t.Filter.Key.FilterRules {
+		for _, f := range d.Filter.Key.FilterRules {
 			if f.Name == filterRulePrefixName && !strings.HasPrefix(objName, f.Value) ||
 				f.Name == filterRuleSuffixName && !strings.HasSuffix(objName, f.Value) {
 				filter = false
@@ -261,9 +303,21 @@ func filterSubjects(conf *data.NotificationConfiguration, eventType, objName str
 			}
 		}
 		if filter {
-			topics[t.ID] = t.QueueArn
+			topics[*d.ID] = d.Arn
 		}
 	}
 
 	return topics
 }
+
+func matchEvent(patterns []string, eventType string) bool {
+	for _, e := range patterns {
+		// the second condition is comparison with the events ending with *:
+		// s3:ObjectCreated:*, s3:ObjectRemoved:* etc without the last char
+		if eventType == e || strings.HasSuffix(e, "*") && strings.HasPrefix(eventType, e[:len(e)-1]) {
+			return true
+		}
+	}
+
+	return false
+}
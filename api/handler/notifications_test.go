@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"bytes"
+	stderrors "errors"
+	"net/http"
 	"testing"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,6 +32,20 @@ func TestFilterSubjects(t *testing.T) {
 				}}},
 			},
 		},
+		TopicConfigurations: []data.TopicConfiguration{
+			{
+				ID:       "test3",
+				TopicArn: "test3",
+				Events:   []string{EventObjectRemoved},
+			},
+		},
+		LambdaFunctionConfigurations: []data.LambdaFunctionConfiguration{
+			{
+				ID:                "test4",
+				LambdaFunctionArn: "test4",
+				Events:            []string{EventObjectCreatedCopy},
+			},
+		},
 	}
 
 	t.Run("no topics because suitable events not found", func(t *testing.T) {
@@ -56,6 +76,37 @@ func TestFilterSubjects(t *testing.T) {
 		require.Len(t, topics, 1)
 		require.Equal(t, topics["test2"], "test2")
 	})
+
+	t.Run("filter topics from topic and lambda function configs", func(t *testing.T) {
+		topics := filterSubjects(config, EventObjectRemovedDelete, "dir/a.png")
+		require.Equal(t, map[string]string{"test1": "test1", "test3": "test3"}, topics)
+
+		topics = filterSubjects(config, EventObjectCreatedCopy, "dir/a.png")
+		require.Equal(t, map[string]string{"test1": "test1", "test4": "test4"}, topics)
+	})
+}
+
+func TestCheckBucketConfiguration(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	conf := &data.NotificationConfiguration{
+		QueueConfigurations: []data.QueueConfiguration{{QueueArn: "queue", Events: []string{EventObjectCreated}}},
+		TopicConfigurations: []data.TopicConfiguration{{ID: "topic", TopicArn: "topic", Events: []string{EventObjectRemoved}}},
+		LambdaFunctionConfigurations: []data.LambdaFunctionConfiguration{
+			{LambdaFunctionArn: "lambda", Events: []string{EventObjectTagging}},
+		},
+	}
+
+	completed, err := hc.Handler().checkBucketConfiguration(conf, &api.ReqInfo{})
+	require.NoError(t, err)
+	require.True(t, completed)
+	require.NotEmpty(t, conf.QueueConfigurations[0].ID)
+	require.Equal(t, "topic", conf.TopicConfigurations[0].ID)
+	require.NotEmpty(t, conf.LambdaFunctionConfigurations[0].ID)
+
+	conf.TopicConfigurations[0].Events = []string{"s3:Unknown"}
+	_, err = hc.Handler().checkBucketConfiguration(conf, &api.ReqInfo{})
+	require.True(t, errors.IsS3Error(err, errors.ErrEventNotification))
 }
 
 func TestCheckRules(t *testing.T) {
@@ -113,3 +164,105 @@ func TestCheckRules(t *testing.T) {
 		require.ErrorIs(t, err, errors.GetAPIError(errors.ErrFilterNamePrefix))
 	})
 }
+
+type testNotificator struct {
+	topics []map[string]string
+	events []*SendNotificationParams
+	err    error
+}
+
+func (n *testNotificator) SendNotifications(topics map[string]string, p *SendNotificationParams) error {
+	n.topics = append(n.topics, topics)
+	n.events = append(n.events, p)
+	return n.err
+}
+
+func (n *testNotificator) SendTestNotification(string, string, string, string) error {
+	return nil
+}
+
+func TestCheckEventsProduced(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	hc.h.cfg.Events = events.NewBus()
+
+	for _, e := range []string{EventObjectCreated, EventObjectCreatedPut, EventObjectRemoved, EventObjectTaggingDelete} {
+		require.NoError(t, hc.h.checkEvents([]string{e}), e)
+	}
+
+	for _, e := range []string{EventObjectRestore, EventReducedRedundancyLostObject, EventLifecycleExpiration, EventReplication} {
+		err := hc.h.checkEvents([]string{EventObjectCreated, e})
+		require.True(t, errors.IsS3Error(err, errors.ErrEventNotification), e)
+	}
+
+	hc.h.cfg.Events.RegisterProducer(events.LifecycleExpirationDelete)
+	require.NoError(t, hc.h.checkEvents([]string{EventLifecycleExpiration, EventLifecycleExpirationDelete}))
+	require.Error(t, hc.h.checkEvents([]string{EventLifecycleExpirationDeleteMarkerCreated}))
+}
+
+func TestBackgroundEvents(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	notificator := &testNotificator{}
+	bus := events.NewBus()
+	bus.RegisterProducer(events.LifecycleExpirationDelete)
+
+	h, err := New(hc.h.log, hc.h.obj, notificator, nil, nil, &Config{NotificatorEnabled: true, Events: bus})
+	require.NoError(t, err)
+	hc.h = h.(*handler)
+
+	bktName := "bucket-for-background-events"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+
+	conf := &data.NotificationConfiguration{
+		QueueConfigurations: []data.QueueConfiguration{
+			{ID: "expiration", QueueArn: "queue", Events: []string{EventLifecycleExpiration}},
+			{ID: "created", QueueArn: "queue", Events: []string{EventObjectCreated}},
+		},
+	}
+	err = hc.Layer().PutBucketNotificationConfiguration(hc.Context(), &layer.PutBucketNotificationConfigurationParams{
+		RequestInfo:   &api.ReqInfo{},
+		BktInfo:       bktInfo,
+		Configuration: conf,
+	})
+	require.NoError(t, err)
+
+	bus.Publish(hc.Context(), &events.Event{
+		Name:    events.LifecycleExpirationDelete,
+		BktInfo: bktInfo,
+		Object:  &data.NotificationInfo{Name: "object"},
+	})
+
+	require.Len(t, notificator.events, 1)
+	require.Equal(t, map[string]string{"expiration": "queue"}, notificator.topics[0])
+	require.Equal(t, EventLifecycleExpirationDelete, notificator.events[0].Event)
+	require.Equal(t, "object", notificator.events[0].NotificationInfo.Name)
+}
+
+func TestNotificationFailure(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	notificator := &testNotificator{err: stderrors.New("outbox is unavailable")}
+
+	h, err := New(hc.h.log, hc.h.obj, notificator, nil, nil, &Config{NotificatorEnabled: true})
+	require.NoError(t, err)
+	hc.h = h.(*handler)
+
+	bktName, objName := "bucket-for-notification-failure", "object"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+
+	err = hc.Layer().PutBucketNotificationConfiguration(hc.Context(), &layer.PutBucketNotificationConfigurationParams{
+		RequestInfo: &api.ReqInfo{},
+		BktInfo:     bktInfo,
+		Configuration: &data.NotificationConfiguration{
+			QueueConfigurations: []data.QueueConfiguration{{ID: "created", QueueArn: "queue", Events: []string{EventObjectCreated}}},
+		},
+	})
+	require.NoError(t, err)
+
+	w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader([]byte("content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusInternalServerError)
+	require.Len(t, notificator.events, 1)
+}
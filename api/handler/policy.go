@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+)
+
+const (
+	existingObjectTagPrefix = "s3:ExistingObjectTag/"
+	requestObjectTagPrefix  = "s3:RequestObjectTag/"
+)
+
+// policyAction describes an S3 action of the route.
+type policyAction struct {
+	// action is the S3 action name.
+	action string
+	// versionAction is used instead of action if the request has versionId.
+	versionAction string
+	// object is true if the resource of the action is an object.
+	object bool
+}
+
+// routeActions maps router route names to the S3 actions used in bucket policies.
+var routeActions = map[string]policyAction{
+	"HeadObject":          {action: s3GetObject, versionAction: s3GetObjectVersion, object: true},
+	"GetObject":           {action: s3GetObject, versionAction: s3GetObjectVersion, object: true},
+	"GetObjectAttributes": {action: "s3:GetObjectAttributes", versionAction: "s3:GetObjectVersionAttributes", object: true},
+	"SelectObjectContent": {action: s3GetObject, object: true},
+
+	"PutObject":               {action: s3PutObject, object: true},
+	"CopyObject":              {action: s3PutObject, object: true},
+	"UploadPart":              {action: s3PutObject, object: true},
+	"UploadPartCopy":          {action: s3PutObject, object: true},
+	"CreateMultipartUpload":   {action: s3PutObject, object: true},
+	"CompleteMultipartUpload": {action: s3PutObject, object: true},
+	"ListObjectParts":         {action: "s3:ListMultipartUploadParts", object: true},
+	"AbortMultipartUpload":    {action: "s3:AbortMultipartUpload", object: true},
+	"DeleteObject":            {action: s3DeleteObject, versionAction: s3DeleteObjectVersion, object: true},
+
+	"GetObjectACL":        {action: "s3:GetObjectAcl", versionAction: "s3:GetObjectVersionAcl", object: true},
+	"PutObjectACL":        {action: "s3:PutObjectAcl", versionAction: "s3:PutObjectVersionAcl", object: true},
+	"GetObjectTagging":    {action: "s3:GetObjectTagging", versionAction: "s3:GetObjectVersionTagging", object: true},
+	"PutObjectTagging":    {action: "s3:PutObjectTagging", versionAction: "s3:PutObjectVersionTagging", object: true},
+	"DeleteObjectTagging": {action: "s3:DeleteObjectTagging", versionAction: "s3:DeleteObjectVersionTagging", object: true},
+	"GetObjectRetention":  {action: "s3:GetObjectRetention", object: true},
+	"PutObjectRetention":  {action: "s3:PutObjectRetention", object: true},
+	"GetObjectLegalHold":  {action: "s3:GetObjectLegalHold", object: true},
+	"PutObjectLegalHold":  {action: "s3:PutObjectLegalHold", object: true},
+
+	"HeadBucket":               {action: s3ListBucket},
+	"ListObjectsV1":            {action: s3ListBucket},
+	"ListObjectsV2":            {action: s3ListBucket},
+	"ListObjectsV2M":           {action: s3ListBucket},
+	"ListBucketVersions":       {action: s3ListBucketVersions},
+	"ListMultipartUploads":     {action: s3ListBucketMultipartUploads},
+	"ListenBucketNotification": {action: "s3:ListenBucketNotification"},
+	"DeleteBucket":             {action: "s3:DeleteBucket"},
+
+	"GetBucketLocation":         {action: "s3:GetBucketLocation"},
+	"GetBucketPolicy":           {action: "s3:GetBucketPolicy"},
+	"PutBucketPolicy":           {action: "s3:PutBucketPolicy"},
+	"DeleteBucketPolicy":        {action: "s3:DeleteBucketPolicy"},
+	"GetBucketACL":              {action: "s3:GetBucketAcl"},
+	"PutBucketACL":              {action: "s3:PutBucketAcl"},
+	"GetBucketCors":             {action: "s3:GetBucketCORS"},
+	"PutBucketCors":             {action: "s3:PutBucketCORS"},
+	"DeleteBucketCors":          {action: "s3:PutBucketCORS"},
+	"GetBucketLifecycle":        {action: "s3:GetLifecycleConfiguration"},
+	"PutBucketLifecycle":        {action: "s3:PutLifecycleConfiguration"},
+	"DeleteBucketLifecycle":     {action: "s3:PutLifecycleConfiguration"},
+	"GetBucketEncryption":       {action: "s3:GetEncryptionConfiguration"},
+	"PutBucketEncryption":       {action: "s3:PutEncryptionConfiguration"},
+	"DeleteBucketEncryption":    {action: "s3:PutEncryptionConfiguration"},
+	"GetBucketTagging":          {action: "s3:GetBucketTagging"},
+	"PutBucketTagging":          {action: "s3:PutBucketTagging"},
+	"DeleteBucketTagging":       {action: "s3:PutBucketTagging"},
+	"GetBucketVersioning":       {action: "s3:GetBucketVersioning"},
+	"PutBucketVersioning":       {action: "s3:PutBucketVersioning"},
+	"GetBucketObjectLockConfig": {action: "s3:GetBucketObjectLockConfiguration"},
+	"PutBucketObjectLockConfig": {action: "s3:PutBucketObjectLockConfiguration"},
+	"GetBucketNotification":     {action: "s3:GetBucketNotification"},
+	"PutBucketNotification":     {action: "s3:PutBucketNotification"},
+	"GetBucketWebsite":          {action: "s3:GetBucketWebsite"},
+	"PutBucketWebsite":          {action: "s3:PutBucketWebsite"},
+	"DeleteBucketWebsite":       {action: "s3:DeleteBucketWebsite"},
+	"GetBucketReplication":      {action: "s3:GetReplicationConfiguration"},
+	"PutBucketReplication":      {action: "s3:PutReplicationConfiguration"},
+	"DeleteBucketReplication":   {action: "s3:PutReplicationConfiguration"},
+	"GetBucketLogging":          {action: "s3:GetBucketLogging"},
+	"PutBucketLogging":          {action: "s3:PutBucketLogging"},
+	"GetBucketQuota":            {action: "s3:GetBucketQuota"},
+	"PutBucketQuota":            {action: "s3:PutBucketQuota"},
+	"DeleteBucketQuota":         {action: "s3:PutBucketQuota"},
+	"GetBucketAccelerate":       {action: "s3:GetAccelerateConfiguration"},
+	"GetBucketRequestPayment":   {action: "s3:GetBucketRequestPayment"},
+}
+
+// policyManagementRoutes can't be denied to the bucket owner, so the owner can't lock themself out.
+var policyManagementRoutes = map[string]struct{}{
+	"GetBucketPolicy":    {},
+	"PutBucketPolicy":    {},
+	"DeleteBucketPolicy": {},
+}
+
+// checkRoutePolicy evaluates the bucket policy for the action of the current route.
+// Routes without an S3 action (e.g. in tests) are not checked.
+func (h *handler) checkRoutePolicy(r *http.Request, bktInfo *data.BucketInfo) error {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	route, ok := routeActions[reqInfo.API]
+	if !ok {
+		return nil
+	}
+
+	action := route.action
+	if route.versionAction != "" && r.URL.Query().Get(api.QueryVersionID) != "" {
+		action = route.versionAction
+	}
+
+	var object string
+	if route.object {
+		object = reqInfo.ObjectName
+	}
+
+	return h.checkBucketPolicy(r, bktInfo, action, object)
+}
+
+// checkBucketPolicy evaluates the bucket policy for the action on the bucket or the object (if it's not empty).
+// AccessDenied is returned if the policy denies the request explicitly. Allowed requests are still
+// checked by NeoFS against the container eACL.
+func (h *handler) checkBucketPolicy(r *http.Request, bktInfo *data.BucketInfo, action, object string) error {
+	bktPolicy, err := h.bucketPolicy(r, bktInfo)
+	if err != nil || bktPolicy == nil {
+		return err
+	}
+
+	var (
+		principals []string
+		owner      bool
+	)
+	if key, err := h.bearerTokenIssuerKey(r.Context()); err == nil {
+		var userID user.ID
+		user.IDFromKey(&userID, (ecdsa.PublicKey)(*key))
+
+		if owner = bktInfo.Owner.Equals(userID); owner {
+			if _, ok := policyManagementRoutes[api.GetReqInfo(r.Context()).API]; ok {
+				return nil
+			}
+		}
+
+		principals = []string{hex.EncodeToString(key.Bytes()), userID.EncodeToString()}
+	}
+
+	return h.evaluateBucketPolicy(r, bktInfo, bktPolicy, action, object, principals, !owner)
+}
+
+// checkWebsitePolicy evaluates the bucket policy for the anonymous s3:GetObject of the object
+// served by the website endpoint, website requests are never authenticated.
+func (h *handler) checkWebsitePolicy(r *http.Request, bktInfo *data.BucketInfo, object string) error {
+	bktPolicy, err := h.bucketPolicy(r, bktInfo)
+	if err != nil || bktPolicy == nil {
+		return err
+	}
+
+	return h.evaluateBucketPolicy(r, bktInfo, bktPolicy, s3GetObject, object, nil, true)
+}
+
+// bucketPolicy returns the parsed bucket policy or nil if the bucket has no policy.
+func (h *handler) bucketPolicy(r *http.Request, bktInfo *data.BucketInfo) (*policy.Policy, error) {
+	document, err := h.obj.GetBucketPolicy(r.Context(), bktInfo)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchBucketPolicy) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not get bucket policy: %w", err)
+	}
+
+	bktPolicy, err := policy.Parse(document, bktInfo.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse bucket policy: %w", err)
+	}
+
+	return bktPolicy, nil
+}
+
+// evaluateBucketPolicy evaluates the bucket policy for the request of the principals,
+// the request is anonymous if there are no principals. If narrow is set, requests in the scope
+// of Allow statements with conditions or negations are denied unless the policy allows them,
+// the bucket owner's access doesn't depend on eACL grants, so it's not narrowed.
+func (h *handler) evaluateBucketPolicy(r *http.Request, bktInfo *data.BucketInfo, bktPolicy *policy.Policy, action, object string, principals []string, narrow bool) error {
+	req := policy.Request{
+		Action:     action,
+		Resource:   policy.BucketResource(bktInfo.Name),
+		Principals: principals,
+		Conditions: requestConditions(r),
+	}
+
+	if object != "" {
+		req.Resource = policy.ObjectResource(bktInfo.Name, object)
+		if bktPolicy.UsesConditionKey(existingObjectTagPrefix) {
+			if err := h.addExistingObjectTags(r, bktInfo, object, req.Conditions); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch bktPolicy.Evaluate(req) {
+	case policy.ResultDeny:
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	case policy.ResultNone:
+		if narrow && bktPolicy.Restricts(req) {
+			return errors.GetAPIError(errors.ErrAccessDenied)
+		}
+	}
+
+	return nil
+}
+
+// checkCopySourcePolicy evaluates the policy of the source bucket for reading the copied object.
+func (h *handler) checkCopySourcePolicy(r *http.Request, bktInfo *data.BucketInfo, object, versionID string) error {
+	action := s3GetObject
+	if versionID != "" {
+		action = s3GetObjectVersion
+	}
+
+	return h.checkBucketPolicy(r, bktInfo, action, object)
+}
+
+func (h *handler) addExistingObjectTags(r *http.Request, bktInfo *data.BucketInfo, object string, conditions map[string][]string) error {
+	p := &layer.ObjectVersion{
+		BktInfo:    bktInfo,
+		ObjectName: object,
+		VersionID:  r.URL.Query().Get(api.QueryVersionID),
+	}
+
+	_, tagSet, err := h.obj.GetObjectTagging(r.Context(), p)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchKey) || stderrors.Is(err, layer.ErrNodeNotFound) {
+			return nil
+		}
+		return fmt.Errorf("could not get object tagging: %w", err)
+	}
+
+	for key, val := range tagSet {
+		conditions[existingObjectTagPrefix+key] = []string{val}
+	}
+
+	return nil
+}
+
+// requestConditions collects values of the condition keys available in the request.
+func requestConditions(r *http.Request) map[string][]string {
+	now := time.Now().UTC()
+	res := map[string][]string{
+		"aws:SecureTransport": {strconv.FormatBool(r.TLS != nil)},
+		"aws:CurrentTime":     {now.Format(time.RFC3339)},
+		"aws:EpochTime":       {strconv.FormatInt(now.Unix(), 10)},
+	}
+
+	addValue := func(key, val string) {
+		if val != "" {
+			res[key] = []string{val}
+		}
+	}
+
+	addValue("aws:SourceIp", api.GetReqInfo(r.Context()).ClientIP)
+	addValue("aws:UserAgent", r.UserAgent())
+	addValue("aws:Referer", r.Referer())
+
+	query := r.URL.Query()
+	for _, key := range []string{"prefix", "delimiter", "max-keys"} {
+		if query.Has(key) {
+			res["s3:"+key] = []string{query.Get(key)}
+		}
+	}
+	addValue("s3:VersionId", query.Get(api.QueryVersionID))
+
+	addValue("s3:x-amz-acl", r.Header.Get(api.AmzACL))
+	addValue("s3:x-amz-copy-source", r.Header.Get(api.AmzCopySource))
+	addValue("s3:x-amz-metadata-directive", r.Header.Get(api.AmzMetadataDirective))
+	addValue("s3:x-amz-server-side-encryption", r.Header.Get(api.AmzServerSideEncryption))
+
+	if tagSet, err := parseTaggingHeader(r.Header); err == nil {
+		keys := make([]string, 0, len(tagSet))
+		for key, val := range tagSet {
+			res[requestObjectTagPrefix+key] = []string{val}
+			keys = append(keys, key)
+		}
+		if len(keys) != 0 {
+			res["s3:RequestObjectTagKeys"] = keys
+		}
+	}
+
+	return res
+}
+
+// nativePolicy translates statements which can be enforced by NeoFS eACL to the legacy
+// bucket policy. Deny statements with conditions or negations and statements with wildcards
+// in resources or principals other than users' keys are evaluated by the gateway only.
+// Allow statements with conditions or negations are translated without them: NotPrincipal
+// grants access to all users, NotAction to all the actions it doesn't exclude and NotResource
+// to the whole bucket. The gateway narrows such access to the original statements, see Policy.Restricts.
+func nativePolicy(bktPolicy *policy.Policy, bucket string) *bucketPolicy {
+	res := &bucketPolicy{
+		Version: bktPolicy.Version,
+		ID:      bktPolicy.ID,
+		Bucket:  bucket,
+	}
+
+	for _, st := range bktPolicy.Statement {
+		if st.Restricted() && st.Effect != policy.EffectAllow {
+			continue
+		}
+
+		resources := []string{policy.BucketResource(bucket)}
+		if len(st.NotResource) == 0 {
+			var ok bool
+			if resources, ok = nativeResources(st.Resource, bucket); !ok {
+				continue
+			}
+		}
+
+		var actions []string
+		if len(st.NotAction) != 0 {
+			for _, action := range nativeActions {
+				if st.MatchesAction(action) {
+					actions = append(actions, action)
+				}
+			}
+		} else {
+			for _, action := range st.Action {
+				if _, ok := actionToOpMap[action]; ok {
+					actions = append(actions, action)
+				}
+			}
+		}
+		if len(actions) == 0 {
+			continue
+		}
+
+		var principals []principal
+		if st.NotPrincipal != nil || st.Principal.Wildcard {
+			principals = append(principals, principal{AWS: allUsersWildcard})
+		} else {
+			if len(st.Principal.AWS) != 0 {
+				continue
+			}
+			for _, id := range st.Principal.CanonicalUser {
+				principals = append(principals, principal{CanonicalUser: id})
+			}
+		}
+
+		for _, p := range principals {
+			res.Statement = append(res.Statement, statement{
+				Sid:       st.Sid,
+				Effect:    st.Effect,
+				Principal: p,
+				Action:    actions,
+				Resource:  resources,
+			})
+		}
+	}
+
+	return res
+}
+
+// nativeResources converts policy resources to the names eACL rules can be applied to.
+// All objects of the bucket ("bucket/*") are covered by the bucket container itself.
+func nativeResources(resources policy.Values, bucket string) ([]string, bool) {
+	res := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if resource == policy.ObjectResource(bucket, policy.Wildcard) {
+			resource = policy.BucketResource(bucket)
+		}
+		if strings.ContainsAny(resource, "*?") {
+			return nil, false
+		}
+		res = append(res, resource)
+	}
+
+	return res, true
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBucketPolicyReturnsStoredDocument(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-policy"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketPolicyHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchBucketPolicy))
+
+	document := []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket-for-policy/*",
+    "Condition": {"Bool": {"aws:SecureTransport": false}}}]
+}`)
+	require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, document))
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketPolicyHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	require.Equal(t, document, body)
+}
+
+func TestBucketPolicyDeny(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-policy", "object"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+	createTestObject(hc.Context(), t, hc, bktInfo, objName)
+
+	require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, []byte(`{
+  "Statement": [{
+    "Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket-for-policy/*",
+    "Condition": {"Bool": {"aws:SecureTransport": "false"}}
+  }, {
+    "Effect": "Deny", "Principal": "*", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::bucket-for-policy",
+    "Condition": {"NotIpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+  }]
+}`)))
+
+	getObject := func(secure bool) *httptest.ResponseRecorder {
+		w, r := prepareTestPayloadRequest(bktName, objName, nil)
+		api.GetReqInfo(r.Context()).API = "HeadObject"
+		if secure {
+			r.TLS = &tls.ConnectionState{}
+		}
+		hc.Handler().HeadObjectHandler(w, r)
+		return w
+	}
+
+	assertStatus(t, getObject(true), http.StatusOK)
+	require.Equal(t, http.StatusForbidden, getObject(false).Code)
+
+	listObjects := func(remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, defaultURL, nil)
+		r.RemoteAddr = remoteAddr
+		reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Method: "ListObjectsV2"})
+		r = r.WithContext(api.SetReqInfo(r.Context(), reqInfo))
+		hc.Handler().ListObjectsV2Handler(w, r)
+		return w
+	}
+
+	assertStatus(t, listObjects("10.1.2.3:1234"), http.StatusOK)
+	assertS3Error(t, listObjects("192.168.1.1:1234"), errors.GetAPIError(errors.ErrAccessDenied))
+}
+
+func TestBucketPolicyConditionalAllow(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-policy", "object"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+	createTestObject(hc.Context(), t, hc, bktInfo, objName)
+
+	require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, []byte(`{
+  "Statement": [{
+    "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket-for-policy/*",
+    "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+  }, {
+    "Effect": "Allow", "Principal": "*", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::bucket-for-policy",
+    "Condition": {"StringLike": {"s3:prefix": "public/*"}}
+  }]
+}`)))
+
+	getObject := func(remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodHead, defaultURL+objName, nil)
+		r.RemoteAddr = remoteAddr
+		reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Object: objName, Method: "HeadObject"})
+		r = r.WithContext(api.SetReqInfo(r.Context(), reqInfo))
+		hc.Handler().HeadObjectHandler(w, r)
+		return w
+	}
+
+	assertStatus(t, getObject("10.1.2.3:1234"), http.StatusOK)
+	assertS3Error(t, getObject("192.168.1.1:1234"), errors.GetAPIError(errors.ErrAccessDenied))
+
+	listObjects := func(prefix string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, defaultURL+"?prefix="+prefix, nil)
+		reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Method: "ListObjectsV2"})
+		r = r.WithContext(api.SetReqInfo(r.Context(), reqInfo))
+		hc.Handler().ListObjectsV2Handler(w, r)
+		return w
+	}
+
+	assertStatus(t, listObjects("public/docs/"), http.StatusOK)
+	assertS3Error(t, listObjects("private/"), errors.GetAPIError(errors.ErrAccessDenied))
+	assertS3Error(t, listObjects(""), errors.GetAPIError(errors.ErrAccessDenied))
+}
+
+func TestNativePolicy(t *testing.T) {
+	bktName := "bucket"
+
+	bktPolicy, err := policy.Parse([]byte(`{
+  "Statement": [{
+    "Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject", "s3:GetObjectTagging"], "Resource": "arn:aws:s3:::bucket/*"
+  }, {
+    "Effect": "Deny", "Principal": {"CanonicalUser": ["user1", "user2"]}, "Action": "s3:PutObject", "Resource": "arn:aws:s3:::bucket/obj"
+  }, {
+    "Effect": "Deny", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/private/*"
+  }, {
+    "Effect": "Deny", "Principal": "*", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::bucket/*",
+    "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+  }, {
+    "Effect": "Allow", "NotPrincipal": {"CanonicalUser": "user1"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket"
+  }, {
+    "Effect": "Allow", "Principal": {"CanonicalUser": "user3"}, "NotAction": "s3:Delete*", "NotResource": "arn:aws:s3:::bucket/private/*",
+    "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+  }]
+}`), bktName)
+	require.NoError(t, err)
+
+	expected := &bucketPolicy{
+		Bucket: bktName,
+		Statement: []statement{{
+			Effect:    "Allow",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{s3GetObject},
+			Resource:  []string{arnAwsPrefix + bktName},
+		}, {
+			Effect:    "Deny",
+			Principal: principal{CanonicalUser: "user1"},
+			Action:    []string{s3PutObject},
+			Resource:  []string{arnAwsPrefix + bktName + "/obj"},
+		}, {
+			Effect:    "Deny",
+			Principal: principal{CanonicalUser: "user2"},
+			Action:    []string{s3PutObject},
+			Resource:  []string{arnAwsPrefix + bktName + "/obj"},
+		}, {
+			Effect:    "Allow",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{s3GetObject},
+			Resource:  []string{arnAwsPrefix + bktName},
+		}, {
+			Effect:    "Allow",
+			Principal: principal{CanonicalUser: "user3"},
+			Action:    []string{s3GetObject, s3ListBucket, s3PutObject},
+			Resource:  []string{arnAwsPrefix + bktName},
+		}},
+	}
+
+	require.Equal(t, expected, nativePolicy(bktPolicy, bktName))
+}
+
+func TestRequestConditions(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, defaultURL+"?prefix=dir/&versionId=v1", bytes.NewReader(nil))
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set(api.AmzTagging, "key=val")
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set("X-Forwarded-For", "192.168.0.1")
+	r = r.WithContext(api.SetReqInfo(r.Context(), api.NewReqInfo(httptest.NewRecorder(), r, api.ObjectRequest{})))
+
+	conditions := requestConditions(r)
+	require.Equal(t, []string{"10.0.0.1"}, conditions["aws:SourceIp"])
+	require.Equal(t, []string{"false"}, conditions["aws:SecureTransport"])
+	require.Equal(t, []string{"test-agent"}, conditions["aws:UserAgent"])
+	require.Equal(t, []string{"dir/"}, conditions["s3:prefix"])
+	require.Equal(t, []string{"v1"}, conditions["s3:VersionId"])
+	require.Equal(t, []string{"val"}, conditions["s3:RequestObjectTag/key"])
+	require.NotContains(t, conditions, "s3:delimiter")
+}
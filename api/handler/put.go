@@ -217,6 +217,23 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Header:  metadata,
 	}
 
+	if params.ContentMD5, err = parseContentMD5(r.Header); err != nil {
+		h.logAndSendError(w, "invalid Content-MD5", reqInfo, err)
+		return
+	}
+	if params.ContentSHA256Hash, err = parseContentSHA256(r.Header); err != nil {
+		h.logAndSendError(w, "invalid x-amz-content-sha256", reqInfo, err)
+		return
+	}
+	if params.Checksum, err = parseChecksum(r.Header); err != nil {
+		h.logAndSendError(w, "invalid checksum", reqInfo, err)
+		return
+	}
+	if params.Encryption, err = formEncryptionParams(r); err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
 	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
@@ -231,7 +248,7 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 
 	info, err := h.obj.PutObject(r.Context(), params)
 	if err != nil {
-		h.logAndSendError(w, "could not upload object", reqInfo, err)
+		h.logAndSendError(w, "could not upload object", reqInfo, bodyReadError(err))
 		return
 	}
 
@@ -242,7 +259,8 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		ReqInfo:          reqInfo,
 	}
 	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	if containsACL {
@@ -264,6 +282,12 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.replicate(r.Context(), &ReplicationParams{
+		BktInfo:    bktInfo,
+		ObjectName: info.Name,
+		VersionID:  info.Version(),
+	})
+
 	if newEaclTable != nil {
 		p := &layer.PutBucketACLParams{
 			BktInfo:      bktInfo,
@@ -282,6 +306,10 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set(api.ETag, info.HashSum)
+	if info.Checksum != nil {
+		w.Header().Set(data.ChecksumHeader(info.Checksum.Algorithm), info.Checksum.Value)
+	}
+	writeEncryptionHeaders(w.Header(), info.EncryptionInfo)
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
@@ -343,6 +371,11 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkBucketPolicy(r, bktInfo, s3PutObject, reqInfo.ObjectName); err != nil {
+		h.logAndSendError(w, "access denied by bucket policy", reqInfo, err)
+		return
+	}
+
 	params := &layer.PutObjectParams{
 		BktInfo: bktInfo,
 		Object:  reqInfo.ObjectName,
@@ -351,6 +384,13 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		Header:  metadata,
 	}
 
+	if contentMD5 := auth.MultipartFormValue(r, "content-md5"); contentMD5 != "" {
+		if params.ContentMD5, err = decodeContentMD5(contentMD5); err != nil {
+			h.logAndSendError(w, "invalid Content-MD5", reqInfo, err)
+			return
+		}
+	}
+
 	info, err := h.obj.PutObject(r.Context(), params)
 	if err != nil {
 		h.logAndSendError(w, "could not upload object", reqInfo, err)
@@ -364,7 +404,8 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		ReqInfo:          reqInfo,
 	}
 	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	if acl := auth.MultipartFormValue(r, "acl"); acl != "" {
@@ -392,6 +433,12 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.replicate(r.Context(), &ReplicationParams{
+		BktInfo:    bktInfo,
+		ObjectName: info.Name,
+		VersionID:  info.Version(),
+	})
+
 	if newEaclTable != nil {
 		p := &layer.PutBucketACLParams{
 			BktInfo:      bktInfo,
@@ -457,7 +504,8 @@ func checkPostPolicy(r *http.Request, reqInfo *api.ReqInfo, metadata map[string]
 
 	for key, v := range r.MultipartForm.Value {
 		value := v[0]
-		if key == "file" || key == "policy" || key == "x-amz-signature" || strings.HasPrefix(key, "x-ignore-") {
+		if key == "file" || key == "policy" || key == "x-amz-signature" || strings.HasPrefix(key, "x-ignore-") ||
+			key == "awsaccesskeyid" || key == "signature" {
 			continue
 		}
 		if err := policy.CheckField(key, value); err != nil {
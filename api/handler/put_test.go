@@ -1,13 +1,24 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/stretchr/testify/require"
 )
 
@@ -104,3 +115,125 @@ func TestEmptyPostPolicy(t *testing.T) {
 	_, err := checkPostPolicy(r, reqInfo, metadata)
 	require.NoError(t, err)
 }
+
+func TestPostPolicyV2Fields(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	policy := base64.StdEncoding.EncodeToString([]byte(`{"expiration": "` + expiration + `",
+  "conditions": [["starts-with", "$key", "user/"]]}`))
+
+	r := &http.Request{
+		MultipartForm: &multipart.Form{
+			Value: map[string][]string{
+				"key":            {"user/object"},
+				"policy":         {policy},
+				"awsaccesskeyid": {"access-key-id"},
+				"signature":      {"signature"},
+			},
+		},
+	}
+
+	_, err := checkPostPolicy(r, &api.ReqInfo{}, make(map[string]string))
+	require.NoError(t, err)
+}
+
+func TestPutObjectPayloadHashes(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-digest", "object"
+	createTestBucket(ctx, t, hc, bktName)
+
+	content := []byte("content")
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+	validMD5 := base64.StdEncoding.EncodeToString(md5Sum[:])
+	validSHA256 := hex.EncodeToString(sha256Sum[:])
+	otherMD5 := md5.Sum([]byte("other"))
+	otherSHA256 := sha256.Sum256([]byte("other"))
+
+	for _, tc := range []struct {
+		name    string
+		md5     string
+		sha256  string
+		err     errors.ErrorCode
+		success bool
+	}{
+		{name: "no hashes", success: true},
+		{name: "valid hashes", md5: validMD5, sha256: validSHA256, success: true},
+		{name: "unsigned payload", md5: validMD5, sha256: auth.UnsignedPayload, success: true},
+		{name: "invalid md5", md5: "invalid", err: errors.ErrInvalidDigest},
+		{name: "md5 mismatch", md5: base64.StdEncoding.EncodeToString(otherMD5[:]), err: errors.ErrBadDigest},
+		{name: "invalid sha256", sha256: "invalid", err: errors.ErrContentSHA256Mismatch},
+		{name: "sha256 mismatch", sha256: hex.EncodeToString(otherSHA256[:]), err: errors.ErrContentSHA256Mismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := len(hc.MockedPool().Objects())
+
+			w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader(content))
+			if tc.md5 != "" {
+				r.Header.Set(api.ContentMD5, tc.md5)
+			}
+			if tc.sha256 != "" {
+				r.Header.Set(auth.AmzContentSHA256, tc.sha256)
+			}
+			hc.Handler().PutObjectHandler(w, r)
+
+			if tc.success {
+				assertStatus(t, w, http.StatusOK)
+				require.Len(t, hc.MockedPool().Objects(), before+1)
+				return
+			}
+
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+			require.Len(t, hc.MockedPool().Objects(), before, "orphaned object must be removed")
+		})
+	}
+}
+
+func TestRestrictedObjectSize(t *testing.T) {
+	ctx := context.Background()
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-restrictions", "object"
+	createTestBucket(ctx, t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(ctx, bktName)
+	require.NoError(t, err)
+	createTestObject(ctx, t, hc, bktInfo, objName)
+
+	restricted := func(r *http.Request) *http.Request {
+		box := &accessbox.Box{Restrictions: &accessbox.Restrictions{MaxObjectSize: 5}}
+		return r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	}
+
+	w, r := prepareTestRequest(t, bktName, "object-copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	hc.Handler().CopyObjectHandler(w, restricted(r))
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrAccessDenied))
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	multipartUpload := &InitiateMultipartUploadResponse{}
+	parseTestResponse(t, w, multipartUpload)
+
+	query := make(url.Values)
+	query.Add(uploadIDHeaderName, multipartUpload.UploadID)
+	query.Add(partNumberHeaderName, "1")
+
+	w, r = prepareTestFullRequest(t, bktName, objName, query, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	hc.Handler().UploadPartCopy(w, restricted(r))
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrAccessDenied))
+
+	w, r = prepareTestPayloadRequest(bktName, objName, bytes.NewReader([]byte("content")))
+	r.URL.RawQuery = query.Encode()
+	hc.Handler().UploadPartHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	completeUpload := &CompleteMultipartUpload{
+		Parts: []*layer.CompletedPart{{ETag: w.Header().Get(api.ETag), PartNumber: 1}},
+	}
+	query.Del(partNumberHeaderName)
+	w, r = prepareTestFullRequest(t, bktName, objName, query, completeUpload)
+	hc.Handler().CompleteMultipartUploadHandler(w, restricted(r))
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrAccessDenied))
+}
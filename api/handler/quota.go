@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+)
+
+// GetBucketQuotaHandler returns the quota of the bucket, it's a gateway extension of S3 API.
+func (h *handler) GetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	quota := settings.Quota
+	if quota == nil {
+		quota = &data.BucketQuota{}
+	}
+
+	if err = api.EncodeToResponse(w, quota); err != nil {
+		h.logAndSendError(w, "could not encode bucket quota to response", reqInfo, err)
+	}
+}
+
+// PutBucketQuotaHandler sets the quota of the bucket, it's a gateway extension of S3 API.
+func (h *handler) PutBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	quota := new(data.BucketQuota)
+	if err := xml.NewDecoder(r.Body).Decode(quota); err != nil {
+		h.logAndSendError(w, "couldn't decode bucket quota", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if quota.HardLimit != 0 && quota.SoftLimit > quota.HardLimit {
+		h.logAndSendError(w, "invalid bucket quota", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			fmt.Errorf("soft limit %d exceeds hard limit %d", quota.SoftLimit, quota.HardLimit)))
+		return
+	}
+
+	if quota.HardLimit == 0 && quota.SoftLimit == 0 {
+		quota = nil
+	}
+
+	h.updateBucketQuota(w, r, quota)
+}
+
+// DeleteBucketQuotaHandler removes the quota of the bucket, it's a gateway extension of S3 API.
+func (h *handler) DeleteBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	if h.updateBucketQuota(w, r, nil) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// updateBucketQuota stores the quota in the bucket settings, the error response is sent if it fails.
+// Quotas are changed by the gateway administrators only, not by the bucket owners.
+func (h *handler) updateBucketQuota(w http.ResponseWriter, r *http.Request, quota *data.BucketQuota) bool {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if quota != nil && !h.obj.IsUsageEnabled() {
+		h.logAndSendError(w, "couldn't set bucket quota", reqInfo,
+			errors.GetAPIErrorWithError(errors.ErrNotImplemented, fmt.Errorf("usage tracking is disabled")))
+		return false
+	}
+
+	if !h.isQuotaAdmin(r.Context()) {
+		h.logAndSendError(w, "couldn't set bucket quota", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return false
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return false
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return false
+	}
+
+	newSettings := *settings
+	newSettings.Quota = quota
+
+	p := &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	}
+
+	if err = h.obj.PutBucketSettings(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put bucket settings", reqInfo, err)
+		return false
+	}
+
+	return true
+}
+
+// isQuotaAdmin checks if the request is signed with the credentials of the quota administrator.
+func (h *handler) isQuotaAdmin(ctx context.Context) bool {
+	box, err := layer.GetBoxData(ctx)
+	if err != nil || box.Gate.BearerToken == nil {
+		return false
+	}
+
+	issuer := bearer.ResolveIssuer(*box.Gate.BearerToken)
+	for i := range h.cfg.QuotaAdmins {
+		if h.cfg.QuotaAdmins[i].Equals(issuer) {
+			return true
+		}
+	}
+
+	return false
+}
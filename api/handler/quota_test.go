@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	bearertest "github.com/nspcc-dev/neofs-sdk-go/bearer/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketQuota(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-quota"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	asAdmin := quotaAdmin(t, hc)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketQuotaHandler(w, r)
+	quota := &data.BucketQuota{}
+	parseTestResponse(t, w, quota)
+	require.Zero(t, quota.HardLimit)
+
+	t.Run("invalid quota", func(t *testing.T) {
+		w, r := prepareTestRequest(t, bktName, "", &data.BucketQuota{HardLimit: 10, SoftLimit: 20})
+		hc.Handler().PutBucketQuotaHandler(w, asAdmin(r))
+		assertS3Error(t, w, apiErrors.GetAPIErrorWithError(apiErrors.ErrInvalidArgument, fmt.Errorf("soft limit 20 exceeds hard limit 10")))
+	})
+
+	t.Run("not admin", func(t *testing.T) {
+		w, r := prepareTestRequest(t, bktName, "", &data.BucketQuota{HardLimit: 10})
+		hc.Handler().PutBucketQuotaHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrAccessDenied))
+
+		w, r = prepareTestRequest(t, bktName, "", nil)
+		hc.Handler().DeleteBucketQuotaHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrAccessDenied))
+	})
+
+	w, r = prepareTestRequest(t, bktName, "", &data.BucketQuota{HardLimit: 10})
+	hc.Handler().PutBucketQuotaHandler(w, asAdmin(r))
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketQuotaHandler(w, r)
+	parseTestResponse(t, w, quota)
+	require.EqualValues(t, 10, quota.HardLimit)
+
+	putObject(t, hc, bktName, "object")
+
+	w, r = prepareTestPayloadRequest(bktName, "large-object", bytes.NewReader([]byte("large content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrQuotaExceeded))
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().DeleteBucketQuotaHandler(w, asAdmin(r))
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestPayloadRequest(bktName, "large-object", bytes.NewReader([]byte("large content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+}
+
+// quotaAdmin registers a quota administrator and returns a function to sign requests on its behalf.
+func quotaAdmin(t *testing.T, hc *handlerContext) func(r *http.Request) *http.Request {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	token := bearertest.Token()
+	require.NoError(t, token.Sign(key.PrivateKey))
+	hc.h.cfg.QuotaAdmins = append(hc.h.cfg.QuotaAdmins, bearer.ResolveIssuer(token))
+
+	box := &accessbox.Box{Gate: &accessbox.GateData{BearerToken: &token, GateKey: key.PublicKey()}}
+	return func(r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	}
+}
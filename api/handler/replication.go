@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+const (
+	maxReplicationRules        = 1000
+	maxReplicationRuleIDLength = 255
+)
+
+type (
+	// Replicator replicates new object versions according to bucket replication configurations.
+	Replicator interface {
+		// CheckDestination checks if objects can be replicated to the destination.
+		CheckDestination(ctx context.Context, dst data.ReplicationDestination) error
+		// Replicate schedules replication of the object version, it mustn't block.
+		Replicate(ctx context.Context, p *ReplicationParams)
+	}
+
+	// ReplicationParams describes a new object version or a delete marker to replicate.
+	ReplicationParams struct {
+		BktInfo      *data.BucketInfo
+		ObjectName   string
+		VersionID    string
+		DeleteMarker bool
+	}
+)
+
+func (h *handler) GetBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketReplicationConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket replication configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode bucket replication configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if h.replicator == nil {
+		h.logAndSendError(w, "replication is disabled", reqInfo, errors.GetAPIError(errors.ErrReplicationNotEnabled))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+	if !settings.VersioningEnabled() {
+		h.logAndSendError(w, "versioning is not enabled", reqInfo, errors.GetAPIError(errors.ErrReplicationVersioningRequired))
+		return
+	}
+
+	conf := &data.ReplicationConfiguration{}
+	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't decode replication configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if err = checkReplicationConfiguration(conf); err != nil {
+		h.logAndSendError(w, "invalid replication configuration", reqInfo, err)
+		return
+	}
+
+	for _, rule := range conf.Rules {
+		if rule.Destination.Account == "" && rule.Destination.BucketName() == bktInfo.Name {
+			h.logAndSendError(w, "invalid replication destination", reqInfo,
+				errors.GetAPIErrorWithError(errors.ErrInvalidReplicationDestination, fmt.Errorf("destination bucket must differ from the source one")))
+			return
+		}
+		if err = h.replicator.CheckDestination(r.Context(), rule.Destination); err != nil {
+			h.logAndSendError(w, "invalid replication destination", reqInfo, err)
+			return
+		}
+	}
+
+	p := &layer.PutBucketReplicationParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+	}
+
+	if err = h.obj.PutBucketReplicationConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put bucket replication configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketReplicationConfiguration(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "couldn't delete bucket replication configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// replicate schedules replication of the new object version if replication is enabled in the gateway.
+func (h *handler) replicate(ctx context.Context, p *ReplicationParams) {
+	if h.replicator != nil {
+		h.replicator.Replicate(ctx, p)
+	}
+}
+
+// checkReplicationConfiguration checks replication rules and generates IDs for rules with empty ones.
+func checkReplicationConfiguration(conf *data.ReplicationConfiguration) error {
+	if len(conf.Rules) == 0 || len(conf.Rules) > maxReplicationRules {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	ids := make(map[string]struct{}, len(conf.Rules))
+	priorities := make(map[int]struct{}, len(conf.Rules))
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+
+		if rule.ID == "" {
+			rule.ID = uuid.NewString()
+		}
+		if len(rule.ID) > maxReplicationRuleIDLength {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("rule ID is too long"))
+		}
+		if _, ok := ids[rule.ID]; ok {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("rule ID must be unique: %s", rule.ID))
+		}
+		ids[rule.ID] = struct{}{}
+
+		if err := checkReplicationRule(rule); err != nil {
+			return err
+		}
+
+		if rule.Filter != nil {
+			if _, ok := priorities[rule.Priority]; ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("priority must be unique: %d", rule.Priority))
+			}
+			priorities[rule.Priority] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func checkReplicationRule(rule *data.ReplicationRule) error {
+	if rule.Status != data.ReplicationRuleEnabled && rule.Status != data.ReplicationRuleDisabled {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if !strings.HasPrefix(rule.Destination.Bucket, data.ReplicationBucketARNPrefix) || rule.Destination.BucketName() == "" {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidReplicationDestination, fmt.Errorf("bucket must be specified as ARN: %s", rule.Destination.Bucket))
+	}
+
+	if dmr := rule.DeleteMarkerReplication; dmr != nil && dmr.Status != data.ReplicationRuleEnabled && dmr.Status != data.ReplicationRuleDisabled {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	filter := rule.Filter
+	if filter == nil {
+		return nil
+	}
+
+	if rule.Prefix != "" {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+	if rule.DeleteMarkerReplication == nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("DeleteMarkerReplication must be specified for a rule with Filter"))
+	}
+
+	var set int
+	if filter.Prefix != "" {
+		set++
+	}
+	if filter.Tag != nil {
+		set++
+	}
+	if filter.And != nil {
+		set++
+	}
+	if set > 1 {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	tags := rule.RuleTags()
+	if len(tags) != 0 && rule.ReplicateDeleteMarkers() {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("delete marker replication is not supported if any Tag filter is specified"))
+	}
+
+	keys := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if err := checkTag(Tag{Key: tag.Key, Value: tag.Value}); err != nil {
+			return err
+		}
+		if _, ok := keys[tag.Key]; ok {
+			return errors.GetAPIError(errors.ErrInvalidTagKey)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// writeReplicationStatus sets x-amz-replication-status header if the object version is a subject of replication.
+func (h *handler) writeReplicationStatus(ctx context.Context, header http.Header, p *layer.ObjectVersion, nodeVersion *data.NodeVersion) {
+	status, err := h.obj.GetObjectReplicationStatus(ctx, p, nodeVersion)
+	if err != nil {
+		h.log.Warn("couldn't get replication status", zap.String("bucket", p.BktInfo.Name),
+			zap.String("object", p.ObjectName), zap.Error(err))
+		return
+	}
+
+	if status != "" {
+		header.Set(api.AmzReplicationStatus, status)
+	}
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+type testReplicator struct {
+	params []*ReplicationParams
+}
+
+func (r *testReplicator) CheckDestination(_ context.Context, dst data.ReplicationDestination) error {
+	if dst.Account == "unknown" {
+		return apiErrors.GetAPIError(apiErrors.ErrInvalidReplicationDestination)
+	}
+	return nil
+}
+
+func (r *testReplicator) Replicate(_ context.Context, p *ReplicationParams) {
+	r.params = append(r.params, p)
+}
+
+func TestCheckReplicationConfiguration(t *testing.T) {
+	dst := data.ReplicationDestination{Bucket: data.ReplicationBucketARNPrefix + "destination"}
+	enabled := &data.DeleteMarkerReplication{Status: data.ReplicationRuleEnabled}
+	disabled := &data.DeleteMarkerReplication{Status: data.ReplicationRuleDisabled}
+
+	for _, tc := range []struct {
+		name  string
+		rules []data.ReplicationRule
+		valid bool
+	}{
+		{
+			name:  "no rules",
+			valid: false,
+		},
+		{
+			name: "legacy prefix",
+			rules: []data.ReplicationRule{{
+				Status:      data.ReplicationRuleEnabled,
+				Prefix:      "logs/",
+				Destination: dst,
+			}},
+			valid: true,
+		},
+		{
+			name: "filter with prefix",
+			rules: []data.ReplicationRule{{
+				Status:                  data.ReplicationRuleEnabled,
+				Filter:                  &data.ReplicationRuleFilter{Prefix: "logs/"},
+				Destination:             dst,
+				DeleteMarkerReplication: enabled,
+			}},
+			valid: true,
+		},
+		{
+			name: "filter without delete marker replication",
+			rules: []data.ReplicationRule{{
+				Status:      data.ReplicationRuleEnabled,
+				Filter:      &data.ReplicationRuleFilter{Prefix: "logs/"},
+				Destination: dst,
+			}},
+			valid: false,
+		},
+		{
+			name: "invalid status",
+			rules: []data.ReplicationRule{{
+				Status:      "enabled",
+				Destination: dst,
+			}},
+			valid: false,
+		},
+		{
+			name: "destination isn't arn",
+			rules: []data.ReplicationRule{{
+				Status:      data.ReplicationRuleEnabled,
+				Destination: data.ReplicationDestination{Bucket: "destination"},
+			}},
+			valid: false,
+		},
+		{
+			name: "same id",
+			rules: []data.ReplicationRule{
+				{ID: "rule", Status: data.ReplicationRuleEnabled, Destination: dst},
+				{ID: "rule", Status: data.ReplicationRuleEnabled, Destination: dst},
+			},
+			valid: false,
+		},
+		{
+			name: "too long id",
+			rules: []data.ReplicationRule{{
+				ID:          strings.Repeat("a", maxReplicationRuleIDLength+1),
+				Status:      data.ReplicationRuleEnabled,
+				Destination: dst,
+			}},
+			valid: false,
+		},
+		{
+			name: "same priority",
+			rules: []data.ReplicationRule{
+				{Status: data.ReplicationRuleEnabled, Priority: 1, Filter: &data.ReplicationRuleFilter{}, Destination: dst, DeleteMarkerReplication: disabled},
+				{Status: data.ReplicationRuleEnabled, Priority: 1, Filter: &data.ReplicationRuleFilter{}, Destination: dst, DeleteMarkerReplication: disabled},
+			},
+			valid: false,
+		},
+		{
+			name: "delete marker replication with tags",
+			rules: []data.ReplicationRule{{
+				Status:                  data.ReplicationRuleEnabled,
+				Filter:                  &data.ReplicationRuleFilter{Tag: &data.Tag{Key: "key", Value: "val"}},
+				Destination:             dst,
+				DeleteMarkerReplication: enabled,
+			}},
+			valid: false,
+		},
+		{
+			name: "filter with prefix and tag",
+			rules: []data.ReplicationRule{{
+				Status:                  data.ReplicationRuleEnabled,
+				Filter:                  &data.ReplicationRuleFilter{Prefix: "dir/", Tag: &data.Tag{Key: "key", Value: "val"}},
+				Destination:             dst,
+				DeleteMarkerReplication: disabled,
+			}},
+			valid: false,
+		},
+		{
+			name: "duplicated tag keys",
+			rules: []data.ReplicationRule{{
+				Status: data.ReplicationRuleEnabled,
+				Filter: &data.ReplicationRuleFilter{And: &data.ReplicationRuleAndOperator{
+					Tags: []data.Tag{{Key: "key", Value: "val"}, {Key: "key", Value: "val2"}},
+				}},
+				Destination:             dst,
+				DeleteMarkerReplication: disabled,
+			}},
+			valid: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkReplicationConfiguration(&data.ReplicationConfiguration{Rules: tc.rules})
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBucketReplicationConfiguration(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-replication"
+	createTestBucket(hc.Context(), t, hc, bktName)
+
+	conf := &data.ReplicationConfiguration{
+		Rules: []data.ReplicationRule{{
+			Status:      data.ReplicationRuleEnabled,
+			Prefix:      "logs/",
+			Destination: data.ReplicationDestination{Bucket: data.ReplicationBucketARNPrefix + "destination"},
+		}},
+	}
+
+	w, r := prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrReplicationNotEnabled))
+
+	hc.Handler().replicator = &testReplicator{}
+
+	w, r = prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrReplicationVersioningRequired))
+
+	putBucketVersioning(t, hc, bktName, true)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrReplicationConfigurationNotFoundError))
+
+	unknown := &data.ReplicationConfiguration{
+		Rules: []data.ReplicationRule{{
+			Status:      data.ReplicationRuleEnabled,
+			Destination: data.ReplicationDestination{Bucket: data.ReplicationBucketARNPrefix + "destination", Account: "unknown"},
+		}},
+	}
+	w, r = prepareTestRequest(t, bktName, "", unknown)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrInvalidReplicationDestination))
+
+	w, r = prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	actualConf := &data.ReplicationConfiguration{}
+	parseTestResponse(t, w, actualConf)
+	require.Len(t, actualConf.Rules, 1)
+	require.NotEmpty(t, actualConf.Rules[0].ID)
+	require.Equal(t, "logs/", actualConf.Rules[0].RulePrefix())
+	require.Equal(t, "destination", actualConf.Rules[0].Destination.BucketName())
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().DeleteBucketReplicationHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrReplicationConfigurationNotFoundError))
+}
+
+func TestObjectReplicationStatus(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	replicator := &testReplicator{}
+	hc.Handler().replicator = replicator
+
+	bktName, objName := "bucket-for-replication", "object"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+	putBucketVersioning(t, hc, bktName, true)
+
+	putObject(t, hc, bktName, objName)
+	require.Len(t, replicator.params, 1)
+	p := replicator.params[0]
+	require.Equal(t, objName, p.ObjectName)
+	require.NotEmpty(t, p.VersionID)
+	require.False(t, p.DeleteMarker)
+
+	w, r := prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzReplicationStatus))
+
+	objVersion := &layer.ObjectVersion{BktInfo: bktInfo, ObjectName: objName, VersionID: p.VersionID}
+	err = hc.Layer().PutObjectReplicationStatus(hc.Context(), objVersion, data.ReplicationStatusCompleted)
+	require.NoError(t, err)
+
+	w, r = prepareTestRequest(t, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, data.ReplicationStatusCompleted, w.Header().Get(api.AmzReplicationStatus))
+
+	_, isDeleteMarker := deleteObject(t, hc, bktName, objName, "")
+	require.True(t, isDeleteMarker)
+	require.Len(t, replicator.params, 2)
+	require.True(t, replicator.params[1].DeleteMarker)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/s3select"
+	"go.uber.org/zap"
+)
+
+func (h *handler) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	request := &s3select.Request{}
+	if err = xml.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logAndSendError(w, "couldn't decode body", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	query, err := s3select.NewQuery(request)
+	if err != nil {
+		h.logAndSendError(w, "invalid select request", reqInfo, err)
+		return
+	}
+
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
+	p := &layer.HeadObjectParams{
+		BktInfo:   bktInfo,
+		Object:    reqInfo.ObjectName,
+		VersionID: reqInfo.URL.Query().Get(api.QueryVersionID),
+	}
+
+	extendedInfo, err := h.obj.GetObjectInfo(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "could not find object", reqInfo, err)
+		return
+	}
+	info := extendedInfo.ObjectInfo
+
+	if err = encryptionParams.MatchObjectEncryption(info.EncryptionInfo); err != nil {
+		h.logAndSendError(w, "encryption doesn't match object", reqInfo, err)
+		return
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		err := h.obj.GetObject(r.Context(), &layer.GetObjectParams{
+			ObjectInfo: info,
+			Writer:     pw,
+			BucketInfo: bktInfo,
+			Encryption: encryptionParams,
+		})
+		pw.CloseWithError(err)
+	}()
+
+	// errors are sent in the event stream since the status is already written
+	w.Header().Set(api.ContentType, "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	if err = query.Run(pr, w); err != nil {
+		h.log.Error("could not select object content",
+			zap.String("request_id", reqInfo.RequestID),
+			zap.String("bucket_name", reqInfo.BucketName),
+			zap.String("object_name", reqInfo.ObjectName),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/s3select"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectObjectContent(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-select", "data.csv"
+	createTestBucket(hc.Context(), t, hc, bktName)
+
+	w, r := prepareTestPayloadRequest(bktName, objName, bytes.NewReader([]byte("name,city\nAlice,Berlin\nBob,Paris\n")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	request := &s3select.Request{
+		Expression:     "SELECT name FROM S3Object WHERE city = 'Paris'",
+		ExpressionType: s3select.ExpressionTypeSQL,
+		InputSerialization: s3select.InputSerialization{
+			CSV: &s3select.CSVInput{FileHeaderInfo: s3select.FileHeaderUse},
+		},
+		OutputSerialization: s3select.OutputSerialization{
+			CSV: &s3select.CSVOutput{},
+		},
+	}
+
+	w, r = prepareTestRequest(t, bktName, objName, request)
+	hc.Handler().SelectObjectContentHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Contains(t, w.Body.String(), "Bob\n")
+	require.NotContains(t, w.Body.String(), "Alice")
+	require.Contains(t, w.Body.String(), "End")
+
+	request.ExpressionType = "XPATH"
+	w, r = prepareTestRequest(t, bktName, objName, request)
+	hc.Handler().SelectObjectContentHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidExpressionType))
+
+	request.ExpressionType = s3select.ExpressionTypeSQL
+	w, r = prepareTestRequest(t, bktName, "missing.csv", request)
+	hc.Handler().SelectObjectContentHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchKey))
+}
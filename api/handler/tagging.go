@@ -12,7 +12,6 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
-	"go.uber.org/zap"
 )
 
 const (
@@ -62,7 +61,8 @@ func (h *handler) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request
 		ReqInfo: reqInfo,
 	}
 	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -136,7 +136,8 @@ func (h *handler) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Requ
 		ReqInfo: reqInfo,
 	}
 	if err = h.sendNotifications(r.Context(), s); err != nil {
-		h.log.Error("couldn't send notification: %w", zap.Error(err))
+		h.logAndSendError(w, "couldn't send notification", reqInfo, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
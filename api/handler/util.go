@@ -2,11 +2,17 @@ package handler
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
@@ -26,6 +32,51 @@ func (h *handler) logAndSendError(w http.ResponseWriter, logText string, reqInfo
 	api.WriteErrorResponse(w, reqInfo, err)
 }
 
+// bodyReadError returns an S3 error if err was caused by the request body reader
+// (e.g. chunk signature mismatch of aws-chunked payload), otherwise err is returned as is.
+func bodyReadError(err error) error {
+	var s3Err errors.Error
+	if stderrors.As(err, &s3Err) {
+		return s3Err
+	}
+	return err
+}
+
+// parseContentMD5 decodes the value of Content-MD5 header, returns nil if the header is not set.
+func parseContentMD5(header http.Header) ([]byte, error) {
+	values, ok := header[api.ContentMD5]
+	if !ok {
+		return nil, nil
+	}
+
+	return decodeContentMD5(values[0])
+}
+
+func decodeContentMD5(value string) ([]byte, error) {
+	md5Sum, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(md5Sum) != md5.Size {
+		return nil, errors.GetAPIError(errors.ErrInvalidDigest)
+	}
+
+	return md5Sum, nil
+}
+
+// parseContentSHA256 decodes the value of x-amz-content-sha256 header, returns nil
+// if the header is not set or payload is unsigned or streamed in chunks.
+func parseContentSHA256(header http.Header) ([]byte, error) {
+	value := header.Get(auth.AmzContentSHA256)
+	if value == "" || value == auth.UnsignedPayload || strings.HasPrefix(value, "STREAMING-") {
+		return nil, nil
+	}
+
+	sha256Sum, err := hex.DecodeString(value)
+	if err != nil || len(sha256Sum) != sha256.Size {
+		return nil, errors.GetAPIError(errors.ErrContentSHA256Mismatch)
+	}
+
+	return sha256Sum, nil
+}
+
 func (h *handler) getBucketAndCheckOwner(r *http.Request, bucket string, header ...string) (*data.BucketInfo, error) {
 	bktInfo, err := h.obj.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
@@ -39,11 +90,17 @@ func (h *handler) getBucketAndCheckOwner(r *http.Request, bucket string, header
 		expected = r.Header.Get(header[0])
 	}
 
-	if len(expected) == 0 {
-		return bktInfo, nil
+	if err = checkOwner(bktInfo, expected); err != nil {
+		return nil, err
+	}
+
+	if len(header) == 0 && bucket == api.GetReqInfo(r.Context()).BucketName {
+		if err = h.checkRoutePolicy(r, bktInfo); err != nil {
+			return nil, err
+		}
 	}
 
-	return bktInfo, checkOwner(bktInfo, expected)
+	return bktInfo, nil
 }
 
 func parseRange(s string) (*layer.RangeParams, error) {
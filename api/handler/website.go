@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+const maxWebsiteRoutingRules = 50
+
+// websiteErrorPage is a page returned by the website endpoint if the bucket has no error document.
+const websiteErrorPage = `<html>
+<head><title>%[1]d %[2]s</title></head>
+<body>
+<h1>%[1]d %[2]s</h1>
+<ul>
+<li>Code: %[3]s</li>
+<li>Message: %[4]s</li>
+<li>RequestId: %[5]s</li>
+</ul>
+<hr/>
+</body>
+</html>
+`
+
+func (h *handler) GetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketWebsiteConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket website configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode bucket website configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &data.WebsiteConfiguration{}
+	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't decode website configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if err = checkWebsiteConfiguration(conf); err != nil {
+		h.logAndSendError(w, "invalid website configuration", reqInfo, err)
+		return
+	}
+
+	p := &layer.PutBucketWebsiteParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+	}
+
+	if err = h.obj.PutBucketWebsiteConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "couldn't put bucket website configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketWebsiteConfiguration(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "couldn't delete bucket website configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func checkWebsiteConfiguration(conf *data.WebsiteConfiguration) error {
+	if redirect := conf.RedirectAllRequestsTo; redirect != nil {
+		if conf.IndexDocument != nil || conf.ErrorDocument != nil || len(conf.RoutingRules) != 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("RedirectAllRequestsTo can't be combined with other elements"))
+		}
+		if redirect.HostName == "" {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+		return checkWebsiteProtocol(redirect.Protocol)
+	}
+
+	if conf.IndexDocument == nil || conf.IndexDocument.Suffix == "" {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("IndexDocument must be specified"))
+	}
+	if strings.Contains(conf.IndexDocument.Suffix, "/") {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("IndexDocument suffix must not contain '/'"))
+	}
+
+	if conf.ErrorDocument != nil && conf.ErrorDocument.Key == "" {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if len(conf.RoutingRules) > maxWebsiteRoutingRules {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("too many routing rules"))
+	}
+
+	for _, rule := range conf.RoutingRules {
+		if err := checkRoutingRule(rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkRoutingRule(rule data.RoutingRule) error {
+	if cond := rule.Condition; cond != nil {
+		if cond.HTTPErrorCodeReturnedEquals == "" && cond.KeyPrefixEquals == "" {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+		if cond.HTTPErrorCodeReturnedEquals != "" {
+			code, err := strconv.Atoi(cond.HTTPErrorCodeReturnedEquals)
+			if err != nil || code < 400 || code > 599 {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+					fmt.Errorf("invalid HttpErrorCodeReturnedEquals: %s", cond.HTTPErrorCodeReturnedEquals))
+			}
+		}
+	}
+
+	redirect := rule.Redirect
+	if redirect == (data.RoutingRuleRedirect{}) {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+	if redirect.ReplaceKeyWith != "" && redirect.ReplaceKeyPrefixWith != "" {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("ReplaceKeyWith and ReplaceKeyPrefixWith can't be used together"))
+	}
+	if redirect.HTTPRedirectCode != "" {
+		code, err := strconv.Atoi(redirect.HTTPRedirectCode)
+		if err != nil || code < 300 || code > 399 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid HttpRedirectCode: %s", redirect.HTTPRedirectCode))
+		}
+	}
+
+	return checkWebsiteProtocol(redirect.Protocol)
+}
+
+func checkWebsiteProtocol(protocol string) error {
+	switch protocol {
+	case "", "http", "https":
+		return nil
+	default:
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid protocol: %s", protocol))
+	}
+}
+
+// WebsiteHandler serves GET and HEAD requests to the website endpoint of the bucket:
+// resolves index documents, applies redirects and returns the error document on failure.
+func (h *handler) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		h.websiteError(w, r, nil, nil, reqInfo.ObjectName, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketWebsiteConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.websiteError(w, r, bktInfo, nil, reqInfo.ObjectName, err)
+		return
+	}
+
+	if redirect := conf.RedirectAllRequestsTo; redirect != nil {
+		http.Redirect(w, r, websiteURL(r, redirect.Protocol, redirect.HostName, r.URL.Path), http.StatusMovedPermanently)
+		return
+	}
+
+	if rule := conf.MatchRoutingRule(reqInfo.ObjectName, 0); rule != nil {
+		websiteRedirect(w, r, rule, reqInfo.ObjectName)
+		return
+	}
+
+	key := reqInfo.ObjectName
+	isDir := key == "" || strings.HasSuffix(key, api.SlashSeparator)
+	if isDir {
+		key += conf.IndexDocument.Suffix
+	}
+
+	// the policy is evaluated for the resolved key, so the index document can be denied too
+	info, err := h.getWebsiteObject(r, bktInfo, key)
+	if err != nil && !isDir && errors.IsS3Error(err, errors.ErrNoSuchKey) {
+		// the key can be a directory requested without a trailing slash
+		if _, dirErr := h.getWebsiteObject(r, bktInfo, key+api.SlashSeparator+conf.IndexDocument.Suffix); dirErr == nil {
+			http.Redirect(w, r, api.SlashSeparator+key+api.SlashSeparator, http.StatusFound)
+			return
+		}
+	}
+	if err != nil {
+		h.websiteError(w, r, bktInfo, conf, reqInfo.ObjectName, err)
+		return
+	}
+
+	h.serveWebsiteObject(w, r, bktInfo, info, http.StatusOK)
+}
+
+// getWebsiteObject returns info of the latest version of the object if the bucket policy
+// allows anonymous s3:GetObject for the key. The policy is evaluated before the object is
+// looked up, so existence of denied objects isn't disclosed.
+func (h *handler) getWebsiteObject(r *http.Request, bktInfo *data.BucketInfo, key string) (*data.ObjectInfo, error) {
+	if err := h.checkWebsitePolicy(r, bktInfo, key); err != nil {
+		return nil, err
+	}
+
+	extendedInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{
+		BktInfo: bktInfo,
+		Object:  key,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := extendedInfo.ObjectInfo
+	if info.EncryptionInfo.Enabled() && !info.EncryptionInfo.Managed() {
+		// objects encrypted with customer-provided keys can't be served without the key
+		return nil, errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	return info, nil
+}
+
+func (h *handler) serveWebsiteObject(w http.ResponseWriter, r *http.Request, bktInfo *data.BucketInfo, info *data.ObjectInfo, status int) {
+	var params *layer.RangeParams
+
+	if status == http.StatusOK {
+		conditional, err := parseConditionalHeaders(r.Header)
+		if err == nil {
+			err = checkPreconditions(info, conditional)
+		}
+		if err == nil && checkIfRange(r.Header, info) {
+			var ranges []*layer.RangeParams
+			// multiple ranges aren't served by the website endpoint, the whole object is returned
+			if ranges, err = fetchRangeHeader(r.Header, uint64(info.Size)); len(ranges) == 1 {
+				params = ranges[0]
+			}
+		}
+		if err != nil {
+			if errors.IsS3Error(err, errors.ErrNotModified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			h.websiteError(w, r, bktInfo, nil, info.Name, err)
+			return
+		}
+	}
+
+	header := w.Header()
+	if info.ContentType != "" {
+		header.Set(api.ContentType, info.ContentType)
+	} else {
+		header.Set(api.ContentType, layer.MimeByFileName(info.Name))
+	}
+	header.Set(api.LastModified, info.Created.UTC().Format(http.TimeFormat))
+	header.Set(api.ContentLength, strconv.FormatInt(info.Size, 10))
+	header.Set(api.ETag, info.HashSum)
+	if cacheControl := info.Headers[api.CacheControl]; cacheControl != "" {
+		header.Set(api.CacheControl, cacheControl)
+	}
+	if expires := info.Headers[api.Expires]; expires != "" {
+		header.Set(api.Expires, expires)
+	}
+
+	if params != nil {
+		writeRangeHeaders(w, params, info.Size)
+	} else {
+		w.WriteHeader(status)
+	}
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	getParams := &layer.GetObjectParams{
+		ObjectInfo: info,
+		Writer:     w,
+		Range:      params,
+		BucketInfo: bktInfo,
+	}
+	if err := h.obj.GetObject(r.Context(), getParams); err != nil {
+		h.log.Error("could not get website object", zap.String("bucket", bktInfo.Name),
+			zap.String("object", info.Name), zap.Error(err))
+	}
+}
+
+// websiteError applies routing rules for the error code and returns the error document of the bucket
+// for client errors. The default error page is returned if there is no error document.
+func (h *handler) websiteError(w http.ResponseWriter, r *http.Request, bktInfo *data.BucketInfo, conf *data.WebsiteConfiguration, key string, err error) {
+	reqInfo := api.GetReqInfo(r.Context())
+	s3Err := websiteS3Error(err)
+
+	h.log.Error("website request failed", zap.String("request_id", reqInfo.RequestID),
+		zap.String("bucket_name", reqInfo.BucketName), zap.String("object_name", key), zap.Error(err))
+
+	if conf != nil {
+		if rule := conf.MatchRoutingRule(key, s3Err.HTTPStatusCode); rule != nil {
+			websiteRedirect(w, r, rule, key)
+			return
+		}
+
+		if conf.ErrorDocument != nil && s3Err.HTTPStatusCode >= 400 && s3Err.HTTPStatusCode < 500 {
+			info, docErr := h.getWebsiteObject(r, bktInfo, conf.ErrorDocument.Key)
+			if docErr == nil {
+				h.serveWebsiteObject(w, r, bktInfo, info, s3Err.HTTPStatusCode)
+				return
+			}
+			h.log.Warn("couldn't get error document", zap.String("bucket_name", bktInfo.Name),
+				zap.String("object_name", conf.ErrorDocument.Key), zap.Error(docErr))
+		}
+	}
+
+	w.Header().Set(api.ContentType, "text/html; charset=utf-8")
+	w.WriteHeader(s3Err.HTTPStatusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	statusText := http.StatusText(s3Err.HTTPStatusCode)
+	if _, err = fmt.Fprintf(w, websiteErrorPage, s3Err.HTTPStatusCode, statusText, html.EscapeString(s3Err.Code),
+		html.EscapeString(s3Err.Description), html.EscapeString(reqInfo.RequestID)); err != nil {
+		h.log.Error("couldn't write website error page", zap.Error(err))
+	}
+}
+
+func websiteS3Error(err error) errors.Error {
+	var s3Err errors.Error
+	switch {
+	case stderrors.As(err, &s3Err):
+		return s3Err
+	case stderrors.Is(err, layer.ErrAccessDenied):
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	default:
+		return errors.GetAPIError(errors.ErrInternalError)
+	}
+}
+
+func websiteRedirect(w http.ResponseWriter, r *http.Request, rule *data.RoutingRule, key string) {
+	code := http.StatusMovedPermanently
+	if rule.Redirect.HTTPRedirectCode != "" {
+		code, _ = strconv.Atoi(rule.Redirect.HTTPRedirectCode)
+	}
+
+	location := websiteURL(r, rule.Redirect.Protocol, rule.Redirect.HostName, api.SlashSeparator+rule.RedirectKey(key))
+	http.Redirect(w, r, location, code)
+}
+
+// websiteURL forms an absolute URL of the redirect, protocol and host of the request are used if they're empty.
+func websiteURL(r *http.Request, protocol, host, path string) string {
+	if protocol == "" {
+		protocol = "http"
+		if r.TLS != nil || strings.EqualFold(r.Header.Get(api.XForwardedProto), "https") {
+			protocol = "https"
+		}
+	}
+	if host == "" {
+		host = r.Host
+	}
+
+	u := &url.URL{Scheme: protocol, Host: host, Path: path}
+	return u.String()
+}
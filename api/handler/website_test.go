@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWebsiteConfiguration(t *testing.T) {
+	index := &data.IndexDocument{Suffix: "index.html"}
+
+	for _, tc := range []struct {
+		name  string
+		conf  data.WebsiteConfiguration
+		valid bool
+	}{
+		{
+			name:  "index document",
+			conf:  data.WebsiteConfiguration{IndexDocument: index, ErrorDocument: &data.ErrorDocument{Key: "404.html"}},
+			valid: true,
+		},
+		{
+			name:  "no index document",
+			conf:  data.WebsiteConfiguration{ErrorDocument: &data.ErrorDocument{Key: "404.html"}},
+			valid: false,
+		},
+		{
+			name:  "index document with slash",
+			conf:  data.WebsiteConfiguration{IndexDocument: &data.IndexDocument{Suffix: "dir/index.html"}},
+			valid: false,
+		},
+		{
+			name:  "redirect all requests",
+			conf:  data.WebsiteConfiguration{RedirectAllRequestsTo: &data.RedirectAllRequestsTo{HostName: "example.com", Protocol: "https"}},
+			valid: true,
+		},
+		{
+			name: "redirect all requests with index document",
+			conf: data.WebsiteConfiguration{
+				RedirectAllRequestsTo: &data.RedirectAllRequestsTo{HostName: "example.com"},
+				IndexDocument:         index,
+			},
+			valid: false,
+		},
+		{
+			name:  "invalid protocol",
+			conf:  data.WebsiteConfiguration{RedirectAllRequestsTo: &data.RedirectAllRequestsTo{HostName: "example.com", Protocol: "ftp"}},
+			valid: false,
+		},
+		{
+			name: "routing rule",
+			conf: data.WebsiteConfiguration{
+				IndexDocument: index,
+				RoutingRules: []data.RoutingRule{{
+					Condition: &data.RoutingRuleCondition{KeyPrefixEquals: "docs/", HTTPErrorCodeReturnedEquals: "404"},
+					Redirect:  data.RoutingRuleRedirect{ReplaceKeyPrefixWith: "documents/", HTTPRedirectCode: "302"},
+				}},
+			},
+			valid: true,
+		},
+		{
+			name: "empty redirect",
+			conf: data.WebsiteConfiguration{
+				IndexDocument: index,
+				RoutingRules:  []data.RoutingRule{{Condition: &data.RoutingRuleCondition{KeyPrefixEquals: "docs/"}}},
+			},
+			valid: false,
+		},
+		{
+			name: "replace key and prefix",
+			conf: data.WebsiteConfiguration{
+				IndexDocument: index,
+				RoutingRules: []data.RoutingRule{{
+					Redirect: data.RoutingRuleRedirect{ReplaceKeyWith: "index.html", ReplaceKeyPrefixWith: "docs/"},
+				}},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid redirect code",
+			conf: data.WebsiteConfiguration{
+				IndexDocument: index,
+				RoutingRules:  []data.RoutingRule{{Redirect: data.RoutingRuleRedirect{HostName: "example.com", HTTPRedirectCode: "200"}}},
+			},
+			valid: false,
+		},
+		{
+			name: "invalid error code",
+			conf: data.WebsiteConfiguration{
+				IndexDocument: index,
+				RoutingRules: []data.RoutingRule{{
+					Condition: &data.RoutingRuleCondition{HTTPErrorCodeReturnedEquals: "200"},
+					Redirect:  data.RoutingRuleRedirect{HostName: "example.com"},
+				}},
+			},
+			valid: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkWebsiteConfiguration(&tc.conf)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBucketWebsiteConfiguration(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-website"
+	createTestBucket(hc.Context(), t, hc, bktName)
+
+	w, r := prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketWebsiteHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNoSuchWebsiteConfiguration))
+
+	conf := &data.WebsiteConfiguration{
+		IndexDocument: &data.IndexDocument{Suffix: "index.html"},
+		ErrorDocument: &data.ErrorDocument{Key: "error.html"},
+	}
+
+	w, r = prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketWebsiteHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketWebsiteHandler(w, r)
+	actualConf := &data.WebsiteConfiguration{}
+	parseTestResponse(t, w, actualConf)
+	require.Equal(t, "index.html", actualConf.IndexDocument.Suffix)
+	require.Equal(t, "error.html", actualConf.ErrorDocument.Key)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().DeleteBucketWebsiteHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(t, bktName, "", nil)
+	hc.Handler().GetBucketWebsiteHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNoSuchWebsiteConfiguration))
+}
+
+func TestWebsiteHandler(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-website"
+	createTestBucket(hc.Context(), t, hc, bktName)
+	bktInfo, err := hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+
+	w := websiteRequest(hc, http.MethodGet, bktName, "index.html")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), "NoSuchWebsiteConfiguration")
+
+	putWebsiteObject(t, hc, bktInfo, "index.html", "root index")
+	putWebsiteObject(t, hc, bktInfo, "docs/index.html", "docs index")
+	putWebsiteObject(t, hc, bktInfo, "page.html", "page")
+	putWebsiteObject(t, hc, bktInfo, "error.html", "custom error")
+
+	conf := &data.WebsiteConfiguration{
+		IndexDocument: &data.IndexDocument{Suffix: "index.html"},
+		ErrorDocument: &data.ErrorDocument{Key: "error.html"},
+		RoutingRules: []data.RoutingRule{
+			{
+				Condition: &data.RoutingRuleCondition{KeyPrefixEquals: "old/"},
+				Redirect:  data.RoutingRuleRedirect{ReplaceKeyPrefixWith: "docs/"},
+			},
+			{
+				Condition: &data.RoutingRuleCondition{KeyPrefixEquals: "missing/", HTTPErrorCodeReturnedEquals: "404"},
+				Redirect:  data.RoutingRuleRedirect{HostName: "example.com", Protocol: "https", HTTPRedirectCode: "302"},
+			},
+		},
+	}
+	w, r := prepareTestRequest(t, bktName, "", conf)
+	hc.Handler().PutBucketWebsiteHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	t.Run("object", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "page.html")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "page", w.Body.String())
+		require.Equal(t, "text/html", w.Header().Get(api.ContentType))
+	})
+
+	t.Run("head", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodHead, bktName, "page.html")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Body.String())
+		require.Equal(t, "4", w.Header().Get(api.ContentLength))
+	})
+
+	t.Run("root index", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "root index", w.Body.String())
+	})
+
+	t.Run("directory index", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "docs/")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "docs index", w.Body.String())
+	})
+
+	t.Run("directory without slash", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "docs")
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, "/docs/", w.Header().Get(api.Location))
+	})
+
+	t.Run("error document", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "unknown.html")
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, "custom error", w.Body.String())
+	})
+
+	t.Run("routing rule", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "old/page.html")
+		require.Equal(t, http.StatusMovedPermanently, w.Code)
+		require.Equal(t, "http://"+bktName+".website.test/docs/page.html", w.Header().Get(api.Location))
+	})
+
+	t.Run("routing rule on error", func(t *testing.T) {
+		w := websiteRequest(hc, http.MethodGet, bktName, "missing/page.html")
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, "https://example.com/missing/page.html", w.Header().Get(api.Location))
+	})
+
+	t.Run("bucket policy", func(t *testing.T) {
+		document := []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {"Effect": "Deny", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket-for-website/docs/*"},
+    {"Effect": "Deny", "Principal": {"AWS": "031a6c6fbbdf02ca351745fa86b9ba5a9452d785ac4f7fc2b7548ca2a46c4fcf4a"},
+      "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket-for-website/page.html"}
+  ]
+}`)
+		require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, document))
+
+		w := websiteRequest(hc, http.MethodGet, bktName, "docs/")
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, "custom error", w.Body.String())
+
+		w = websiteRequest(hc, http.MethodGet, bktName, "docs/index.html")
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		w = websiteRequest(hc, http.MethodGet, bktName, "docs")
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Empty(t, w.Header().Get(api.Location))
+
+		w = websiteRequest(hc, http.MethodGet, bktName, "page.html")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "page", w.Body.String())
+
+		document = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Deny", "Principal": "*", "Action": "s3:GetObject",
+    "Resource": ["arn:aws:s3:::bucket-for-website/docs/*", "arn:aws:s3:::bucket-for-website/error.html"]}]
+}`)
+		require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, document))
+
+		w = websiteRequest(hc, http.MethodGet, bktName, "docs/")
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Contains(t, w.Body.String(), "AccessDenied")
+		require.NotContains(t, w.Body.String(), "custom error")
+
+		require.NoError(t, hc.Layer().PutBucketPolicy(hc.Context(), bktInfo, []byte(`{"Version": "2012-10-17", "Statement": []}`)))
+	})
+
+	t.Run("redirect all requests", func(t *testing.T) {
+		conf := &data.WebsiteConfiguration{
+			RedirectAllRequestsTo: &data.RedirectAllRequestsTo{HostName: "example.com", Protocol: "https"},
+		}
+		w, r := prepareTestRequest(t, bktName, "", conf)
+		hc.Handler().PutBucketWebsiteHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+
+		w = websiteRequest(hc, http.MethodGet, bktName, "page.html")
+		require.Equal(t, http.StatusMovedPermanently, w.Code)
+		require.Equal(t, "https://example.com/page.html", w.Header().Get(api.Location))
+	})
+}
+
+func putWebsiteObject(t *testing.T, hc *handlerContext, bktInfo *data.BucketInfo, key, content string) {
+	_, err := hc.Layer().PutObject(hc.Context(), &layer.PutObjectParams{
+		BktInfo: bktInfo,
+		Object:  key,
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader([]byte(content)),
+		Header:  map[string]string{api.ContentType: "text/html"},
+	})
+	require.NoError(t, err)
+}
+
+func websiteRequest(hc *handlerContext, method, bktName, key string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, "http://"+bktName+".website.test/"+key, nil)
+
+	reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Object: key})
+	r = r.WithContext(api.SetReqInfo(r.Context(), reqInfo))
+
+	hc.Handler().WebsiteHandler(w, r)
+	return w
+}
@@ -9,6 +9,7 @@ const (
 	AmzTaggingCount           = "X-Amz-Tagging-Count"
 	AmzTagging                = "X-Amz-Tagging"
 	AmzDeleteMarker           = "X-Amz-Delete-Marker"
+	AmzReplicationStatus      = "X-Amz-Replication-Status"
 	AmzCopySource             = "X-Amz-Copy-Source"
 	AmzCopySourceRange        = "X-Amz-Copy-Source-Range"
 	AmzDate                   = "X-Amz-Date"
@@ -37,6 +38,8 @@ const (
 	IfUnmodifiedSince  = "If-Unmodified-Since"
 	IfMatch            = "If-Match"
 	IfNoneMatch        = "If-None-Match"
+	IfRange            = "If-Range"
+	XForwardedProto    = "X-Forwarded-Proto"
 
 	AmzCopyIfModifiedSince       = "X-Amz-Copy-Source-If-Modified-Since"
 	AmzCopyIfUnmodifiedSince     = "X-Amz-Copy-Source-If-Unmodified-Since"
@@ -56,6 +59,18 @@ const (
 	AmzObjectAttributes          = "X-Amz-Object-Attributes"
 	AmzMaxParts                  = "X-Amz-Max-Parts"
 	AmzPartNumberMarker          = "X-Amz-Part-Number-Marker"
+	AmzChecksumMode              = "X-Amz-Checksum-Mode"
+	AmzChecksumAlgorithm         = "X-Amz-Checksum-Algorithm"
+	AmzSdkChecksumAlgorithm      = "X-Amz-Sdk-Checksum-Algorithm"
+	AmzTrailer                   = "X-Amz-Trailer"
+
+	AmzServerSideEncryption                            = "X-Amz-Server-Side-Encryption"
+	AmzServerSideEncryptionCustomerAlgorithm           = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	AmzServerSideEncryptionCustomerKey                 = "X-Amz-Server-Side-Encryption-Customer-Key"
+	AmzServerSideEncryptionCustomerKeyMD5              = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+	AmzCopySourceServerSideEncryptionCustomerAlgorithm = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Algorithm"
+	AmzCopySourceServerSideEncryptionCustomerKey       = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key"
+	AmzCopySourceServerSideEncryptionCustomerKeyMD5    = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5"
 
 	ContainerID = "X-Container-Id"
 
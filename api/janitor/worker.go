@@ -0,0 +1,214 @@
+package janitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Default values of the multipart janitor parameters.
+const (
+	DefaultInterval = time.Hour
+	DefaultTTL      = 7 * 24 * time.Hour
+)
+
+type (
+	// Config contains parameters of the multipart janitor.
+	Config struct {
+		// Interval between two sweeps.
+		Interval time.Duration
+		// TTL is a time after initiation the multipart upload is aborted.
+		TTL time.Duration
+		// BucketTTL overrides TTL for the buckets by their names,
+		// uploads of the bucket with zero TTL aren't aborted.
+		BucketTTL map[string]time.Duration
+		// AccessKeys are access key IDs which credentials are used to process
+		// buckets of their owners.
+		AccessKeys []string
+		// Registry is an optional access key registry to resolve AccessKeys
+		// which aren't access box addresses.
+		Registry *tokens.Registry
+	}
+
+	// Worker periodically aborts multipart uploads which were initiated more than
+	// TTL ago and deletes their parts.
+	Worker struct {
+		log        *zap.Logger
+		obj        layer.Client
+		creds      tokens.Credentials
+		interval   time.Duration
+		ttl        time.Duration
+		bucketTTL  map[string]time.Duration
+		accessKeys []string
+		registry   *tokens.Registry
+
+		uploadsDesc *prometheus.Desc
+		bytesDesc   *prometheus.Desc
+
+		mu    sync.Mutex
+		stats map[string]layer.AbortedUploads
+	}
+)
+
+var _ prometheus.Collector = (*Worker)(nil)
+
+// NewWorker creates a multipart janitor. Buckets are listed and processed
+// on behalf of owners of the configured access keys.
+func NewWorker(log *zap.Logger, obj layer.Client, creds tokens.Credentials, cfg *Config) (*Worker, error) {
+	w := &Worker{
+		log:        log,
+		obj:        obj,
+		creds:      creds,
+		interval:   cfg.Interval,
+		ttl:        cfg.TTL,
+		bucketTTL:  cfg.BucketTTL,
+		accessKeys: cfg.AccessKeys,
+		registry:   cfg.Registry,
+		stats:      make(map[string]layer.AbortedUploads),
+
+		uploadsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "multipart_janitor", "aborted_uploads_total"),
+			"Number of stale multipart uploads aborted by the janitor",
+			[]string{"bucket"}, nil),
+		bytesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "multipart_janitor", "reclaimed_bytes_total"),
+			"Size of parts of stale multipart uploads deleted by the janitor",
+			[]string{"bucket"}, nil),
+	}
+
+	if w.interval <= 0 {
+		w.interval = DefaultInterval
+	}
+	if w.ttl <= 0 {
+		w.ttl = DefaultTTL
+	}
+
+	for _, accessKeyID := range cfg.AccessKeys {
+		if err := tokens.CheckAccessKey(cfg.Registry, accessKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// Run starts sweeping buckets every interval until the context is done.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("multipart janitor started", zap.Duration("interval", w.interval), zap.Duration("ttl", w.ttl))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.sweep(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			w.log.Info("multipart janitor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) sweep(ctx context.Context, now time.Time) {
+	var total layer.AbortedUploads
+
+	for _, accessKeyID := range w.accessKeys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		addr, err := tokens.ResolveAccessKey(ctx, w.registry, accessKeyID)
+		if err != nil {
+			w.log.Error("couldn't resolve access key id", zap.String("access_key_id", accessKeyID), zap.Error(err))
+			continue
+		}
+
+		box, err := w.creds.GetBox(ctx, addr)
+		if err != nil {
+			w.log.Error("couldn't get access box", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		boxCtx := context.WithValue(ctx, api.BoxData, box)
+
+		buckets, err := w.obj.ListBuckets(boxCtx)
+		if err != nil {
+			w.log.Error("couldn't list buckets", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		for _, bktInfo := range buckets {
+			if ctx.Err() != nil {
+				return
+			}
+
+			res := w.sweepBucket(boxCtx, bktInfo, now)
+			total.Uploads += res.Uploads
+			total.Bytes += res.Bytes
+		}
+	}
+
+	if total.Uploads != 0 {
+		w.log.Info("stale multipart uploads are aborted",
+			zap.Int("uploads", total.Uploads), zap.Int64("reclaimed bytes", total.Bytes))
+	}
+}
+
+func (w *Worker) sweepBucket(ctx context.Context, bktInfo *data.BucketInfo, now time.Time) layer.AbortedUploads {
+	ttl := w.bucketUploadTTL(bktInfo.Name)
+	if ttl <= 0 {
+		return layer.AbortedUploads{}
+	}
+
+	res, err := w.obj.AbortStaleMultipartUploads(ctx, bktInfo, now.Add(-ttl))
+	if err != nil {
+		w.log.Error("couldn't abort stale multipart uploads", zap.String("bucket", bktInfo.Name),
+			zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+	}
+	if res == nil || res.Uploads == 0 {
+		return layer.AbortedUploads{}
+	}
+
+	w.mu.Lock()
+	stat := w.stats[bktInfo.Name]
+	stat.Uploads += res.Uploads
+	stat.Bytes += res.Bytes
+	w.stats[bktInfo.Name] = stat
+	w.mu.Unlock()
+
+	return *res
+}
+
+// bucketUploadTTL returns the TTL of multipart uploads of the bucket.
+func (w *Worker) bucketUploadTTL(bucket string) time.Duration {
+	if ttl, ok := w.bucketTTL[bucket]; ok {
+		return ttl
+	}
+	return w.ttl
+}
+
+// Describe implements prometheus.Collector.
+func (w *Worker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- w.uploadsDesc
+	ch <- w.bytesDesc
+}
+
+// Collect implements prometheus.Collector.
+func (w *Worker) Collect(ch chan<- prometheus.Metric) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for bucket, stat := range w.stats {
+		ch <- prometheus.MustNewConstMetric(w.uploadsDesc, prometheus.CounterValue, float64(stat.Uploads), bucket)
+		ch <- prometheus.MustNewConstMetric(w.bytesDesc, prometheus.CounterValue, float64(stat.Bytes), bucket)
+	}
+}
@@ -0,0 +1,67 @@
+package janitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type abortingLayer struct {
+	layer.Client
+	initiatedBefore map[string]time.Time
+}
+
+func (l *abortingLayer) AbortStaleMultipartUploads(_ context.Context, bktInfo *data.BucketInfo, initiatedBefore time.Time) (*layer.AbortedUploads, error) {
+	l.initiatedBefore[bktInfo.Name] = initiatedBefore
+	return &layer.AbortedUploads{Uploads: 2, Bytes: 100}, nil
+}
+
+func TestSweepBucket(t *testing.T) {
+	obj := &abortingLayer{initiatedBefore: make(map[string]time.Time)}
+	w, err := NewWorker(zap.NewNop(), obj, nil, &Config{
+		BucketTTL: map[string]time.Duration{
+			"short": time.Hour,
+			"kept":  0,
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, name := range []string{"default", "short", "kept", "default"} {
+		w.sweepBucket(context.Background(), &data.BucketInfo{Name: name}, now)
+	}
+
+	require.Equal(t, map[string]time.Time{
+		"default": now.Add(-DefaultTTL),
+		"short":   now.Add(-time.Hour),
+	}, obj.initiatedBefore)
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(w))
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, mfs, 2)
+
+	for _, mf := range mfs {
+		values := make(map[string]float64)
+		for _, m := range mf.GetMetric() {
+			values[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+		}
+
+		switch mf.GetName() {
+		case "neofs_s3_gw_multipart_janitor_aborted_uploads_total":
+			require.Equal(t, map[string]float64{"default": 4, "short": 2}, values)
+		case "neofs_s3_gw_multipart_janitor_reclaimed_bytes_total":
+			require.Equal(t, map[string]float64{"default": 200, "short": 100}, values)
+		default:
+			t.Fatalf("unexpected metric %s", mf.GetName())
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// Params contains encryption key info.
+type Params struct {
+	key []byte
+	// keyID is set if the key is managed by the gateway (SSE-S3).
+	keyID string
+}
+
+// ObjectEncryption contains encryption info of the stored object.
+type ObjectEncryption struct {
+	Algorithm string
+	// CustomerKeyMD5 is base64 encoded MD5 of the customer provided key.
+	CustomerKeyMD5 string
+	// KeyID is an identifier of the gateway managed key, it's set instead of CustomerKeyMD5 for SSE-S3.
+	KeyID string
+	// Salt is a random value the object data key is derived from.
+	Salt []byte
+	// DecryptedSize is a size of the plain payload.
+	DecryptedSize uint64
+}
+
+const (
+	// AESEncryptionAlgorithm is the only supported SSE-C algorithm.
+	AESEncryptionAlgorithm = "AES256"
+	// AESKeySize is a size of the customer provided key.
+	AESKeySize = 32
+
+	AttributeEncryptionAlgorithm = "S3-Encryption-Algorithm"
+	AttributeCustomerKeyMD5      = "S3-Encryption-Customer-Key-MD5"
+	AttributeKeyID               = "S3-Encryption-Key-ID"
+	AttributeSalt                = "S3-Encryption-Salt"
+	AttributeDecryptedSize       = "S3-Encryption-Decrypted-Size"
+
+	saltSize = 32
+)
+
+// Attributes is a list of object attributes used to store encryption info.
+var Attributes = []string{
+	AttributeEncryptionAlgorithm,
+	AttributeCustomerKeyMD5,
+	AttributeKeyID,
+	AttributeSalt,
+	AttributeDecryptedSize,
+}
+
+// NewSSECParams creates encryption params with the customer provided key.
+func NewSSECParams(key []byte) (*Params, error) {
+	if len(key) != AESKeySize {
+		return nil, fmt.Errorf("invalid key size: %d", len(key))
+	}
+
+	return &Params{key: key}, nil
+}
+
+// NewSSES3Params creates encryption params with the gateway managed key.
+func NewSSES3Params(keyID string, key []byte) (*Params, error) {
+	if len(key) != AESKeySize {
+		return nil, fmt.Errorf("invalid key size: %d", len(key))
+	}
+	if keyID == "" {
+		return nil, fmt.Errorf("empty key id")
+	}
+
+	return &Params{key: key, keyID: keyID}, nil
+}
+
+// Enabled checks if encryption is requested, p can be nil.
+func (p *Params) Enabled() bool {
+	return p != nil
+}
+
+// Managed checks if the key is managed by the gateway, p can be nil.
+func (p *Params) Managed() bool {
+	return p != nil && p.keyID != ""
+}
+
+// KeyID returns identifier of the gateway managed key.
+func (p *Params) KeyID() string {
+	return p.keyID
+}
+
+// CustomerKeyMD5 returns base64 encoded MD5 of the customer provided key.
+func (p *Params) CustomerKeyMD5() string {
+	sum := md5.Sum(p.key)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// NewObjectEncryption forms encryption info of the new object with the plain payload of the size.
+func (p *Params) NewObjectEncryption(size uint64) (ObjectEncryption, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return ObjectEncryption{}, fmt.Errorf("generate salt: %w", err)
+	}
+
+	res := ObjectEncryption{
+		Algorithm:     AESEncryptionAlgorithm,
+		Salt:          salt,
+		DecryptedSize: size,
+	}
+	if p.Managed() {
+		res.KeyID = p.keyID
+	} else {
+		res.CustomerKeyMD5 = p.CustomerKeyMD5()
+	}
+
+	return res, nil
+}
+
+// MatchObjectEncryption checks if the params can be used to decrypt the object.
+// Both params and object must be either encrypted or not. Objects encrypted with
+// the gateway managed key are matched by nil params too, the key must be taken
+// from the key ring then.
+func (p *Params) MatchObjectEncryption(info ObjectEncryption) error {
+	switch {
+	case !info.Enabled() && !p.Enabled():
+		return nil
+	case !info.Enabled():
+		return errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+	case info.Managed():
+		if p.Enabled() && p.keyID != info.KeyID {
+			return errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+		}
+		return nil
+	case !p.Enabled():
+		return errors.GetAPIError(errors.ErrSSEEncryptedObject)
+	case p.Managed():
+		return errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+	case info.CustomerKeyMD5 != p.CustomerKeyMD5():
+		return errors.GetAPIError(errors.ErrInvalidSSECustomerParameters)
+	default:
+		return nil
+	}
+}
+
+// aead returns cipher of the object data key.
+func (p *Params) aead(info ObjectEncryption) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write(info.Salt)
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Enabled checks if the object is encrypted.
+func (e ObjectEncryption) Enabled() bool {
+	return e.Algorithm != ""
+}
+
+// Managed checks if the object is encrypted with the gateway managed key.
+func (e ObjectEncryption) Managed() bool {
+	return e.KeyID != ""
+}
+
+// EncryptedSize returns size of the stored encrypted payload.
+func (e ObjectEncryption) EncryptedSize() uint64 {
+	return EncryptedSize(e.DecryptedSize)
+}
+
+// Attributes returns object attributes to store encryption info.
+func (e ObjectEncryption) Attributes() [][2]string {
+	keyAttribute := [2]string{AttributeCustomerKeyMD5, e.CustomerKeyMD5}
+	if e.Managed() {
+		keyAttribute = [2]string{AttributeKeyID, e.KeyID}
+	}
+
+	return [][2]string{
+		{AttributeEncryptionAlgorithm, e.Algorithm},
+		keyAttribute,
+		{AttributeSalt, base64.StdEncoding.EncodeToString(e.Salt)},
+		{AttributeDecryptedSize, strconv.FormatUint(e.DecryptedSize, 10)},
+	}
+}
+
+// ObjectEncryptionFromAttributes restores encryption info from object attributes.
+// Not encrypted info is returned if the object isn't encrypted.
+func ObjectEncryptionFromAttributes(attrs map[string]string) (ObjectEncryption, error) {
+	var (
+		res ObjectEncryption
+		err error
+	)
+
+	if res.Algorithm = attrs[AttributeEncryptionAlgorithm]; res.Algorithm == "" {
+		return res, nil
+	}
+	if res.Algorithm != AESEncryptionAlgorithm {
+		return ObjectEncryption{}, fmt.Errorf("unsupported encryption algorithm: %s", res.Algorithm)
+	}
+
+	res.CustomerKeyMD5 = attrs[AttributeCustomerKeyMD5]
+	res.KeyID = attrs[AttributeKeyID]
+	if res.Salt, err = base64.StdEncoding.DecodeString(attrs[AttributeSalt]); err != nil || len(res.Salt) != saltSize {
+		return ObjectEncryption{}, fmt.Errorf("invalid encryption salt")
+	}
+	if res.DecryptedSize, err = strconv.ParseUint(attrs[AttributeDecryptedSize], 10, 64); err != nil {
+		return ObjectEncryption{}, fmt.Errorf("invalid decrypted size: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,223 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestParams(t *testing.T) *Params {
+	key := make([]byte, AESKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	p, err := NewSSECParams(key)
+	require.NoError(t, err)
+	return p
+}
+
+func encryptPayload(t *testing.T, p *Params, payload []byte) ([]byte, ObjectEncryption) {
+	info, err := p.NewObjectEncryption(uint64(len(payload)))
+	require.NoError(t, err)
+
+	r, err := p.EncryptReader(bytes.NewReader(payload), info)
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Len(t, encrypted, int(info.EncryptedSize()))
+
+	return encrypted, info
+}
+
+func decryptRange(p *Params, encrypted []byte, info ObjectEncryption, start, end uint64) ([]byte, error) {
+	off, ln := info.EncryptedRange(start, end)
+	r, err := p.DecryptReader(bytes.NewReader(encrypted[off:off+ln]), info, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	p := newTestParams(t)
+
+	for _, size := range []int{1, 100, blockSize - 1, blockSize, blockSize + 1, 3*blockSize + 17} {
+		payload := make([]byte, size)
+		_, err := rand.Read(payload)
+		require.NoError(t, err)
+
+		encrypted, info := encryptPayload(t, p, payload)
+		require.NotEqual(t, payload, encrypted[:size])
+
+		for _, rng := range [][2]uint64{
+			{0, uint64(size - 1)},
+			{0, 0},
+			{uint64(size - 1), uint64(size - 1)},
+			{uint64(size / 2), uint64(size - 1)},
+			{uint64(size / 3), uint64(size / 2)},
+		} {
+			plain, err := decryptRange(p, encrypted, info, rng[0], rng[1])
+			require.NoError(t, err)
+			require.Equal(t, payload[rng[0]:rng[1]+1], plain, "size %d, range %v", size, rng)
+		}
+	}
+}
+
+func TestEncryptEmptyPayload(t *testing.T) {
+	p := newTestParams(t)
+
+	encrypted, info := encryptPayload(t, p, nil)
+	require.Len(t, encrypted, tagSize)
+
+	r, err := p.DecryptReader(bytes.NewReader(encrypted), info, 0, 0)
+	require.NoError(t, err)
+	plain, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Empty(t, plain)
+}
+
+func TestDecryptInvalidPayload(t *testing.T) {
+	p := newTestParams(t)
+
+	payload := make([]byte, 2*blockSize)
+	encrypted, info := encryptPayload(t, p, payload)
+	end := uint64(len(payload) - 1)
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := decryptRange(newTestParams(t), encrypted, info, 0, end)
+		require.Error(t, err)
+	})
+
+	t.Run("modified payload", func(t *testing.T) {
+		modified := append([]byte(nil), encrypted...)
+		modified[blockSize] ^= 1
+		_, err := decryptRange(p, modified, info, 0, end)
+		require.Error(t, err)
+	})
+
+	t.Run("truncated payload", func(t *testing.T) {
+		info := info
+		info.DecryptedSize = blockSize
+		_, err := decryptRange(p, encrypted[:encryptedBlockSize], info, 0, blockSize-1)
+		require.Error(t, err)
+	})
+}
+
+func TestMatchObjectEncryption(t *testing.T) {
+	p := newTestParams(t)
+	info, err := p.NewObjectEncryption(10)
+	require.NoError(t, err)
+
+	var nilParams *Params
+	require.NoError(t, p.MatchObjectEncryption(info))
+	require.NoError(t, nilParams.MatchObjectEncryption(ObjectEncryption{}))
+
+	for _, tc := range []struct {
+		params *Params
+		info   ObjectEncryption
+		err    errors.ErrorCode
+	}{
+		{params: nilParams, info: info, err: errors.ErrSSEEncryptedObject},
+		{params: p, info: ObjectEncryption{}, err: errors.ErrInvalidEncryptionParameters},
+		{params: newTestParams(t), info: info, err: errors.ErrInvalidSSECustomerParameters},
+	} {
+		err = tc.params.MatchObjectEncryption(tc.info)
+		require.True(t, errors.IsS3Error(err, tc.err), err)
+	}
+}
+
+func TestObjectEncryptionAttributes(t *testing.T) {
+	p := newTestParams(t)
+	info, err := p.NewObjectEncryption(12345)
+	require.NoError(t, err)
+
+	attrs := make(map[string]string)
+	for _, attr := range info.Attributes() {
+		attrs[attr[0]] = attr[1]
+	}
+
+	restored, err := ObjectEncryptionFromAttributes(attrs)
+	require.NoError(t, err)
+	require.Equal(t, info, restored)
+
+	restored, err = ObjectEncryptionFromAttributes(map[string]string{})
+	require.NoError(t, err)
+	require.False(t, restored.Enabled())
+}
+
+func TestKeyRing(t *testing.T) {
+	oldKey, newKey := make([]byte, AESKeySize), make([]byte, AESKeySize)
+	_, err := rand.Read(oldKey)
+	require.NoError(t, err)
+	_, err = rand.Read(newKey)
+	require.NoError(t, err)
+
+	_, err = NewKeyRing("unknown", map[string][]byte{"old": oldKey})
+	require.Error(t, err)
+	_, err = NewKeyRing("old", map[string][]byte{"old": oldKey[:16]})
+	require.Error(t, err)
+
+	ring, err := NewKeyRing("old", map[string][]byte{"old": oldKey})
+	require.NoError(t, err)
+	oldParams, err := ring.CurrentParams()
+	require.NoError(t, err)
+	require.True(t, oldParams.Managed())
+
+	payload := []byte("payload")
+	encrypted, info := encryptPayload(t, oldParams, payload)
+	require.Equal(t, "old", info.KeyID)
+	require.Empty(t, info.CustomerKeyMD5)
+
+	// rotate key, objects encrypted with the old one must be still readable
+	ring, err = NewKeyRing("new", map[string][]byte{"old": oldKey, "new": newKey})
+	require.NoError(t, err)
+	require.Equal(t, "new", ring.CurrentKeyID())
+
+	params, err := ring.Params(info.KeyID)
+	require.NoError(t, err)
+	plain, err := decryptRange(params, encrypted, info, 0, uint64(len(payload)-1))
+	require.NoError(t, err)
+	require.Equal(t, payload, plain)
+
+	_, err = ring.Params("unknown")
+	require.Error(t, err)
+
+	var nilRing *KeyRing
+	_, err = nilRing.CurrentParams()
+	require.True(t, errors.IsS3Error(err, errors.ErrKMSNotConfigured))
+}
+
+func TestMatchManagedObjectEncryption(t *testing.T) {
+	ring, err := NewKeyRing("key", map[string][]byte{"key": make([]byte, AESKeySize)})
+	require.NoError(t, err)
+	managed, err := ring.CurrentParams()
+	require.NoError(t, err)
+	info, err := managed.NewObjectEncryption(10)
+	require.NoError(t, err)
+
+	var nilParams *Params
+	require.NoError(t, nilParams.MatchObjectEncryption(info))
+	require.NoError(t, managed.MatchObjectEncryption(info))
+
+	err = newTestParams(t).MatchObjectEncryption(info)
+	require.True(t, errors.IsS3Error(err, errors.ErrInvalidEncryptionParameters), err)
+
+	customerInfo, err := newTestParams(t).NewObjectEncryption(10)
+	require.NoError(t, err)
+	err = managed.MatchObjectEncryption(customerInfo)
+	require.True(t, errors.IsS3Error(err, errors.ErrInvalidEncryptionParameters), err)
+
+	attrs := make(map[string]string)
+	for _, attr := range info.Attributes() {
+		attrs[attr[0]] = attr[1]
+	}
+	require.NotContains(t, attrs, AttributeCustomerKeyMD5)
+
+	restored, err := ObjectEncryptionFromAttributes(attrs)
+	require.NoError(t, err)
+	require.Equal(t, info, restored)
+}
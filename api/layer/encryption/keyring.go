@@ -0,0 +1,68 @@
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// KeyRing contains keys managed by the gateway, they are used to encrypt objects
+// in buckets with default encryption (SSE-S3). New objects are encrypted with the
+// current key, other keys are kept to decrypt objects stored before key rotation.
+type KeyRing struct {
+	keys    map[string][]byte
+	current string
+}
+
+// NewKeyRing creates key ring with the keys, currentID must be one of the key ids.
+func NewKeyRing(currentID string, keys map[string][]byte) (*KeyRing, error) {
+	res := &KeyRing{
+		keys:    make(map[string][]byte, len(keys)),
+		current: currentID,
+	}
+
+	for id, key := range keys {
+		if id == "" {
+			return nil, fmt.Errorf("empty key id")
+		}
+		if len(key) != AESKeySize {
+			return nil, fmt.Errorf("invalid size of key '%s': %d", id, len(key))
+		}
+		res.keys[id] = append([]byte(nil), key...)
+	}
+
+	if _, ok := res.keys[currentID]; !ok {
+		return nil, fmt.Errorf("current key '%s' not found", currentID)
+	}
+
+	return res, nil
+}
+
+// CurrentKeyID returns id of the key new objects are encrypted with.
+func (k *KeyRing) CurrentKeyID() string {
+	return k.current
+}
+
+// CurrentParams returns encryption params with the current key. ErrKMSNotConfigured
+// is returned if the key ring is nil.
+func (k *KeyRing) CurrentParams() (*Params, error) {
+	if k == nil {
+		return nil, errors.GetAPIError(errors.ErrKMSNotConfigured)
+	}
+	return k.Params(k.current)
+}
+
+// Params returns encryption params with the key. ErrKMSNotConfigured is returned
+// if the key ring is nil.
+func (k *KeyRing) Params(keyID string) (*Params, error) {
+	if k == nil {
+		return nil, errors.GetAPIError(errors.ErrKMSNotConfigured)
+	}
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key '%s'", keyID)
+	}
+
+	return NewSSES3Params(keyID, key)
+}
@@ -0,0 +1,227 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Payload is split into blocks encrypted independently, so any range of the
+// object can be decrypted without reading the whole payload. Nonce of the block
+// is its sequence number, the last block is additionally marked to detect
+// truncation. Data key is unique for every object, so nonces are never reused.
+const (
+	blockSize          = 64 * 1024
+	tagSize            = 16
+	encryptedBlockSize = blockSize + tagSize
+
+	finalBlockFlag = 0x80
+)
+
+// EncryptedSize returns size of the encrypted payload with the plain size.
+// Empty payload is encrypted to the single empty block.
+func EncryptedSize(size uint64) uint64 {
+	return size + blocksCount(size)*tagSize
+}
+
+func blocksCount(size uint64) uint64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + blockSize - 1) / blockSize
+}
+
+func blockNonce(nonce []byte, seq uint64, final bool) []byte {
+	for i := range nonce {
+		nonce[i] = 0
+	}
+	binary.BigEndian.PutUint64(nonce, seq)
+	if final {
+		nonce[8] = finalBlockFlag
+	}
+	return nonce
+}
+
+// EncryptedRange returns offset and length of the encrypted payload which contains
+// plain range [start, end]. The returned range must be passed to DecryptReader.
+func (e ObjectEncryption) EncryptedRange(start, end uint64) (uint64, uint64) {
+	first, last := start/blockSize, end/blockSize
+	if e.DecryptedSize == 0 {
+		first, last = 0, 0
+	}
+
+	off := first * encryptedBlockSize
+	ln := (last - first + 1) * encryptedBlockSize
+	if size := e.EncryptedSize(); off+ln > size {
+		ln = size - off
+	}
+
+	return off, ln
+}
+
+type encryptReader struct {
+	src   io.Reader
+	aead  cipher.AEAD
+	nonce []byte
+	seq   uint64
+
+	plain    []byte
+	buffered int
+	srcDone  bool
+
+	encrypted []byte
+	out       []byte
+	done      bool
+}
+
+// EncryptReader returns reader of the encrypted payload read from r.
+func (p *Params) EncryptReader(r io.Reader, info ObjectEncryption) (io.Reader, error) {
+	aead, err := p.aead(info)
+	if err != nil {
+		return nil, fmt.Errorf("init cipher: %w", err)
+	}
+
+	return &encryptReader{
+		src:   r,
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+		// one more byte is read to find out if the block is the last one
+		plain:     make([]byte, blockSize+1),
+		encrypted: make([]byte, 0, encryptedBlockSize),
+	}, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealBlock(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *encryptReader) sealBlock() error {
+	if !r.srcDone {
+		n, err := io.ReadFull(r.src, r.plain[r.buffered:])
+		r.buffered += n
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			r.srcDone = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	final := r.srcDone && r.buffered <= blockSize
+	n := r.buffered
+	if n > blockSize {
+		n = blockSize
+	}
+
+	r.out = r.aead.Seal(r.encrypted[:0], blockNonce(r.nonce, r.seq, final), r.plain[:n], nil)
+	r.buffered = copy(r.plain, r.plain[n:r.buffered])
+	r.seq++
+	r.done = final
+
+	return nil
+}
+
+type decryptReader struct {
+	src   io.Reader
+	aead  cipher.AEAD
+	nonce []byte
+	seq   uint64
+	last  uint64
+
+	encrypted []byte
+	plain     []byte
+	out       []byte
+	skip      uint64
+	left      uint64
+}
+
+// DecryptReader returns reader of plain payload range [start, end] from the reader r
+// of encrypted payload range returned by EncryptedRange. Payload of the empty object
+// is verified and nothing is read.
+func (p *Params) DecryptReader(r io.Reader, info ObjectEncryption, start, end uint64) (io.Reader, error) {
+	aead, err := p.aead(info)
+	if err != nil {
+		return nil, fmt.Errorf("init cipher: %w", err)
+	}
+
+	res := &decryptReader{
+		src:       r,
+		aead:      aead,
+		nonce:     make([]byte, aead.NonceSize()),
+		seq:       start / blockSize,
+		last:      blocksCount(info.DecryptedSize) - 1,
+		encrypted: make([]byte, encryptedBlockSize),
+		plain:     make([]byte, 0, blockSize),
+		skip:      start % blockSize,
+		left:      end - start + 1,
+	}
+	if info.DecryptedSize == 0 {
+		res.seq, res.skip, res.left = 0, 0, 0
+		if err = res.openBlock(); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.left == 0 {
+			return 0, io.EOF
+		}
+		if err := r.openBlock(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *decryptReader) openBlock() error {
+	if r.seq > r.last {
+		return io.ErrUnexpectedEOF
+	}
+
+	n, err := io.ReadFull(r.src, r.encrypted)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], blockNonce(r.nonce, r.seq, r.seq == r.last), r.encrypted[:n], nil)
+	if err != nil {
+		return fmt.Errorf("decrypt block %d: %w", r.seq, err)
+	}
+	r.seq++
+
+	if r.skip > uint64(len(plain)) {
+		return io.ErrUnexpectedEOF
+	}
+	plain = plain[r.skip:]
+	r.skip = 0
+
+	if uint64(len(plain)) > r.left {
+		plain = plain[:r.left]
+	}
+	r.left -= uint64(len(plain))
+	r.out = plain
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
@@ -51,6 +54,10 @@ type (
 		bucketCache *cache.BucketCache
 		systemCache *cache.SystemCache
 		treeService TreeService
+		keyRing     *encryption.KeyRing
+		events      *events.Bus
+		usage       *UsageTracker
+		read        *ReadConfig
 	}
 
 	Config struct {
@@ -59,6 +66,14 @@ type (
 		AnonKey      AnonymousKey
 		Resolver     *resolver.BucketResolver
 		TreeService  TreeService
+		// KeyRing contains keys used for bucket default encryption, it can be nil.
+		KeyRing *encryption.KeyRing
+		// Events is a bus to publish events of background processes to, it can be nil.
+		Events *events.Bus
+		// Usage tracks usage of the buckets to enforce quotas, it can be nil.
+		Usage *UsageTracker
+		// Read contains parameters of parallel reads of object payloads, it can be nil.
+		Read *ReadConfig
 	}
 
 	// AnonymousKey contains data for anonymous requests.
@@ -81,6 +96,7 @@ type (
 		ObjectInfo *data.ObjectInfo
 		BucketInfo *data.BucketInfo
 		Writer     io.Writer
+		Encryption *encryption.Params
 	}
 
 	// HeadObjectParams stores object head request parameters.
@@ -112,6 +128,14 @@ type (
 		Reader  io.Reader
 		Header  map[string]string
 		Lock    *data.ObjectLock
+		// ContentMD5 and ContentSHA256Hash are payload hashes provided by the client, optional.
+		ContentMD5        []byte
+		ContentSHA256Hash []byte
+		// Checksum sets the algorithm of additional checksum to calculate, optional.
+		// If checksum value is set, it's compared with the calculated one.
+		Checksum *data.Checksum
+		// Encryption is set if the object must be encrypted.
+		Encryption *encryption.Params
 	}
 
 	DeleteObjectParams struct {
@@ -142,6 +166,9 @@ type (
 		Header     map[string]string
 		Range      *RangeParams
 		Lock       *data.ObjectLock
+		// SrcEncryption is used to decrypt the source object, Encryption to encrypt the new one.
+		SrcEncryption *encryption.Params
+		Encryption    *encryption.Params
 	}
 	// CreateBucketParams stores bucket create request parameters.
 	CreateBucketParams struct {
@@ -192,11 +219,32 @@ type (
 
 		GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 		PutBucketSettings(ctx context.Context, p *PutSettingsParams) error
+		// IsUsageEnabled checks if usage of the buckets is tracked, so quotas are enforced.
+		IsUsageEnabled() bool
 
 		PutBucketCORS(ctx context.Context, p *PutCORSParams) error
 		GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*data.CORSConfiguration, error)
 		DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) error
 
+		PutBucketLifecycleConfiguration(ctx context.Context, p *PutBucketLifecycleParams) error
+		GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.LifecycleConfiguration, error)
+		DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error
+		ApplyBucketLifecycle(ctx context.Context, bktInfo *data.BucketInfo, now time.Time) error
+
+		PutBucketReplicationConfiguration(ctx context.Context, p *PutBucketReplicationParams) error
+		GetBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error)
+		DeleteBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error
+		GetObjectReplicationStatus(ctx context.Context, p *ObjectVersion, nodeVersion *data.NodeVersion) (string, error)
+		PutObjectReplicationStatus(ctx context.Context, p *ObjectVersion, status string) error
+
+		PutBucketWebsiteConfiguration(ctx context.Context, p *PutBucketWebsiteParams) error
+		GetBucketWebsiteConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.WebsiteConfiguration, error)
+		DeleteBucketWebsiteConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error
+
+		PutBucketLoggingConfiguration(ctx context.Context, p *PutBucketLoggingParams) error
+		GetBucketLoggingConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketLoggingStatus, error)
+		DeleteBucketLoggingConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error
+
 		ListBuckets(ctx context.Context) ([]*data.BucketInfo, error)
 		GetBucketInfo(ctx context.Context, name string) (*data.BucketInfo, error)
 		GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*BucketACL, error)
@@ -214,6 +262,9 @@ type (
 		PutBucketTagging(ctx context.Context, cnrID cid.ID, tagSet map[string]string) error
 		DeleteBucketTagging(ctx context.Context, cnrID cid.ID) error
 
+		GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error)
+		PutBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo, policy []byte) error
+
 		GetObjectTagging(ctx context.Context, p *ObjectVersion) (string, map[string]string, error)
 		PutObjectTagging(ctx context.Context, p *ObjectVersion, tagSet map[string]string) (*data.NodeVersion, error)
 		DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*data.NodeVersion, error)
@@ -230,10 +281,11 @@ type (
 
 		CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) error
 		CompleteMultipartUpload(ctx context.Context, p *CompleteMultipartParams) (*UploadData, *data.ObjectInfo, error)
-		UploadPart(ctx context.Context, p *UploadPartParams) (string, error)
+		UploadPart(ctx context.Context, p *UploadPartParams) (*data.ObjectInfo, error)
 		UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.ObjectInfo, error)
 		ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (*ListMultipartUploadsInfo, error)
 		AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error
+		AbortStaleMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, initiatedBefore time.Time) (*AbortedUploads, error)
 		ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error)
 
 		PutBucketNotificationConfiguration(ctx context.Context, p *PutBucketNotificationConfigurationParams) error
@@ -272,6 +324,21 @@ func DefaultCachesConfigs(logger *zap.Logger) *CachesConfig {
 // NewLayer creates an instance of a layer. It checks credentials
 // and establishes gRPC connection with the node.
 func NewLayer(log *zap.Logger, neoFS NeoFS, config *Config) Client {
+	var read *ReadConfig
+	if config.Read != nil {
+		read = &ReadConfig{
+			Concurrency: config.Read.Concurrency,
+			ChunkSize:   config.Read.ChunkSize,
+			BufferSize:  config.Read.BufferSize,
+		}
+		if read.ChunkSize == 0 {
+			read.ChunkSize = DefaultReadChunkSize
+		}
+		if read.BufferSize == 0 {
+			read.BufferSize = DefaultReadBufferSize
+		}
+	}
+
 	return &layer{
 		neoFS:       neoFS,
 		log:         log,
@@ -283,6 +350,10 @@ func NewLayer(log *zap.Logger, neoFS NeoFS, config *Config) Client {
 		bucketCache: cache.NewBucketCache(config.Caches.Buckets),
 		systemCache: cache.NewSystemCache(config.Caches.System),
 		treeService: config.TreeService,
+		keyRing:     config.KeyRing,
+		events:      config.Events,
+		usage:       config.Usage,
+		read:        read,
 	}
 }
 
@@ -387,6 +458,12 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 	params.oid = p.ObjectInfo.ID
 	params.bktInfo = p.BucketInfo
 
+	encInfo := p.ObjectInfo.EncryptionInfo
+	encParams, err := n.resolveEncryptionParams(p.Encryption, encInfo)
+	if err != nil {
+		return err
+	}
+
 	if p.Range != nil {
 		if p.Range.Start > p.Range.End {
 			panic("invalid range")
@@ -396,6 +473,23 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 		params.ln = p.Range.End - p.Range.Start + 1
 	}
 
+	if encInfo.Enabled() {
+		return n.getDecryptedObject(ctx, p, params, encParams)
+	}
+
+	off, ln := params.off, params.ln
+	if p.Range == nil {
+		ln = uint64(p.ObjectInfo.Size)
+	}
+	if n.parallelReadEnabled(ln) {
+		chunks := splitPayloadRange(off, ln, payloadSegments(p.ObjectInfo), n.read.ChunkSize)
+		return n.copyPayloadParallel(ctx, p.Writer, params, chunks)
+	}
+	if len(p.ObjectInfo.Parts) != 0 {
+		chunks := splitPayloadRange(off, ln, payloadSegments(p.ObjectInfo), math.MaxUint64)
+		return n.copyPayloadChunks(ctx, p.Writer, params, chunks)
+	}
+
 	payload, err := n.initObjectPayloadReader(ctx, params)
 	if err != nil {
 		return fmt.Errorf("init object payload reader: %w", err)
@@ -417,6 +511,64 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 	return nil
 }
 
+// resolveEncryptionParams checks if the params match the object encryption and returns params
+// to decrypt the object. Key of the object encrypted with the gateway managed key is taken from the key ring.
+func (n *layer) resolveEncryptionParams(params *encryption.Params, info encryption.ObjectEncryption) (*encryption.Params, error) {
+	if err := params.MatchObjectEncryption(info); err != nil {
+		return nil, err
+	}
+
+	if info.Managed() && !params.Enabled() {
+		return n.keyRing.Params(info.KeyID)
+	}
+
+	return params, nil
+}
+
+// bucketEncryptionParams returns params to encrypt the new object in the bucket with:
+// the requested ones or params with the current managed key if the bucket has default encryption.
+func (n *layer) bucketEncryptionParams(params *encryption.Params, settings *data.BucketSettings) (*encryption.Params, error) {
+	if params.Enabled() || settings.EncryptionConfiguration == nil {
+		return params, nil
+	}
+
+	return n.keyRing.CurrentParams()
+}
+
+// getDecryptedObject reads blocks of the encrypted payload which contain the requested
+// range and writes decrypted range.
+func (n *layer) getDecryptedObject(ctx context.Context, p *GetObjectParams, params getParams, encParams *encryption.Params) error {
+	encInfo := p.ObjectInfo.EncryptionInfo
+
+	start, end := uint64(0), encInfo.DecryptedSize-1
+	if p.Range != nil {
+		start, end = p.Range.Start, p.Range.End
+	} else if encInfo.DecryptedSize == 0 {
+		end = 0
+	}
+
+	params.off, params.ln = encInfo.EncryptedRange(start, end)
+	if params.off == 0 && params.ln == encInfo.EncryptedSize() {
+		params.ln = 0
+	}
+
+	payload, err := n.initObjectPayloadReader(ctx, params)
+	if err != nil {
+		return fmt.Errorf("init object payload reader: %w", err)
+	}
+
+	decrypted, err := encParams.DecryptReader(payload, encInfo, start, end)
+	if err != nil {
+		return fmt.Errorf("init object payload decrypter: %w", err)
+	}
+
+	if _, err = io.Copy(p.Writer, decrypted); err != nil {
+		return fmt.Errorf("copy object payload: %w", err)
+	}
+
+	return nil
+}
+
 // GetObjectInfo returns meta information about the object.
 func (n *layer) GetObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ExtendedObjectInfo, error) {
 	if len(p.VersionID) == 0 {
@@ -436,6 +588,7 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Obje
 			Writer:     pw,
 			Range:      p.Range,
 			BucketInfo: p.ScrBktInfo,
+			Encryption: p.SrcEncryption,
 		})
 
 		if err = pw.CloseWithError(err); err != nil {
@@ -444,11 +597,12 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Obje
 	}()
 
 	return n.PutObject(ctx, &PutObjectParams{
-		BktInfo: p.DstBktInfo,
-		Object:  p.DstObject,
-		Size:    p.SrcSize,
-		Reader:  pr,
-		Header:  p.Header,
+		BktInfo:    p.DstBktInfo,
+		Object:     p.DstObject,
+		Size:       p.SrcSize,
+		Reader:     pr,
+		Header:     p.Header,
+		Encryption: p.Encryption,
 	})
 }
 
@@ -474,7 +628,9 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 			return obj
 		}
 
+		updateUsage := n.trackObjectUsage(ctx, bkt, obj.Name)
 		obj.Error = n.treeService.RemoveVersion(ctx, bkt.CID, nodeVersion.ID)
+		updateUsage()
 		n.listsCache.CleanCacheEntriesContainingObject(obj.Name, bkt.CID)
 		return obj
 	}
@@ -514,7 +670,10 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 		IsUnversioned: settings.VersioningSuspended(),
 	}
 
-	if obj.Error = n.treeService.AddVersion(ctx, bkt.CID, newVersion); obj.Error != nil {
+	updateUsage := n.trackObjectUsage(ctx, bkt, obj.Name)
+	obj.Error = n.treeService.AddVersion(ctx, bkt.CID, newVersion)
+	updateUsage()
+	if obj.Error != nil {
 		return obj
 	}
 
@@ -549,7 +708,20 @@ func (n *layer) removeOldVersion(ctx context.Context, bkt *data.BucketInfo, node
 		return obj.VersionID, nil
 	}
 
-	return "", n.objectDelete(ctx, bkt, nodeVersion.OID)
+	if err := n.objectDelete(ctx, bkt, nodeVersion.OID); err != nil {
+		return "", err
+	}
+
+	for _, part := range nodeVersion.Parts {
+		if err := n.objectDelete(ctx, bkt, part.OID); err != nil {
+			n.log.Warn("could not delete part of the completed object",
+				zap.Stringer("object id", part.OID),
+				zap.Stringer("bucket id", bkt.CID),
+				zap.Error(err))
+		}
+	}
+
+	return "", nil
 }
 
 // DeleteObjects from the storage.
@@ -596,5 +768,8 @@ func (n *layer) DeleteBucket(ctx context.Context, p *DeleteBucketParams) error {
 	}
 
 	n.bucketCache.Delete(p.BktInfo.Name)
+	if n.usage != nil {
+		n.usage.remove(p.BktInfo.CID)
+	}
 	return n.neoFS.DeleteContainer(ctx, p.BktInfo.CID, p.SessionToken)
 }
@@ -0,0 +1,382 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
+	"go.uber.org/zap"
+)
+
+// PutBucketLifecycleParams stores PutBucketLifecycleConfiguration request parameters.
+type PutBucketLifecycleParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.LifecycleConfiguration
+}
+
+func (n *layer) PutBucketLifecycleConfiguration(ctx context.Context, p *PutBucketLifecycleParams) error {
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal lifecycle configuration: %w", err)
+	}
+
+	sysName := p.BktInfo.LifecycleConfigurationObjectName()
+
+	prm := PrmObjectCreate{
+		Container: p.BktInfo.CID,
+		Creator:   p.BktInfo.Owner,
+		Payload:   bytes.NewReader(confXML),
+		Filename:  sysName,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketLifecycleConfiguration(ctx, p.BktInfo.CID, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete lifecycle configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	if err = n.systemCache.PutLifecycleConfiguration(systemObjectKey(p.BktInfo, sysName), p.Configuration); err != nil {
+		n.log.Error("couldn't cache system object", zap.Error(err))
+	}
+
+	return nil
+}
+
+func (n *layer) GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.LifecycleConfiguration, error) {
+	systemCacheKey := systemObjectKey(bktInfo, bktInfo.LifecycleConfigurationObjectName())
+
+	if conf := n.systemCache.GetLifecycleConfiguration(systemCacheKey); conf != nil {
+		return conf, nil
+	}
+
+	objID, err := n.treeService.GetBucketLifecycleConfiguration(ctx, bktInfo.CID)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrNoSuchLifecycleConfiguration)
+		}
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &data.LifecycleConfiguration{}
+	if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+		return nil, fmt.Errorf("unmarshal lifecycle configuration: %w", err)
+	}
+
+	if err = n.systemCache.PutLifecycleConfiguration(systemCacheKey, conf); err != nil {
+		n.log.Warn("couldn't put system meta to objects cache",
+			zap.Stringer("bucket id", bktInfo.CID),
+			zap.Error(err))
+	}
+
+	return conf, nil
+}
+
+func (n *layer) DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error {
+	objID, err := n.treeService.DeleteBucketLifecycleConfiguration(ctx, bktInfo.CID)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.systemCache.Delete(systemObjectKey(bktInfo, bktInfo.LifecycleConfigurationObjectName()))
+
+	return nil
+}
+
+// ApplyBucketLifecycle expires object versions and aborts multipart uploads
+// of the bucket according to its lifecycle configuration at the moment now.
+// Buckets without lifecycle configuration are skipped.
+func (n *layer) ApplyBucketLifecycle(ctx context.Context, bktInfo *data.BucketInfo, now time.Time) error {
+	conf, err := n.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchLifecycleConfiguration) {
+			return nil
+		}
+		return fmt.Errorf("get lifecycle configuration: %w", err)
+	}
+
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return fmt.Errorf("get bucket settings: %w", err)
+	}
+
+	for _, rule := range conf.Rules {
+		if !rule.Enabled() {
+			continue
+		}
+
+		if rule.Expiration != nil || rule.NoncurrentVersionExpiration != nil {
+			if err = n.expireVersions(ctx, bktInfo, settings, rule, now); err != nil {
+				return fmt.Errorf("expire versions by rule '%s': %w", rule.ID, err)
+			}
+		}
+
+		if rule.AbortIncompleteMultipartUpload != nil {
+			if err = n.abortExpiredUploads(ctx, bktInfo, rule, now); err != nil {
+				return fmt.Errorf("abort multipart uploads by rule '%s': %w", rule.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *layer) expireVersions(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, rule data.LifecycleRule, now time.Time) error {
+	nodeVersions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo.CID, rule.RulePrefix())
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get all versions from tree service: %w", err)
+	}
+
+	versions := make(map[string][]*data.NodeVersion)
+	for _, nodeVersion := range nodeVersions {
+		versions[nodeVersion.FilePath] = append(versions[nodeVersion.FilePath], nodeVersion)
+	}
+
+	for name, objVersions := range versions {
+		sort.Slice(objVersions, func(i, j int) bool {
+			return objVersions[j].Timestamp < objVersions[i].Timestamp // sort in reverse order
+		})
+
+		if rule.Expiration != nil {
+			n.expireCurrentVersion(ctx, bktInfo, settings, rule, objVersions, now)
+		}
+
+		if rule.NoncurrentVersionExpiration != nil && rule.NoncurrentVersionExpiration.NoncurrentDays != nil {
+			n.expireNoncurrentVersions(ctx, bktInfo, settings, rule, objVersions, now)
+		}
+
+		n.listsCache.CleanCacheEntriesContainingObject(name, bktInfo.CID)
+	}
+
+	return nil
+}
+
+func (n *layer) expireCurrentVersion(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, rule data.LifecycleRule, versions []*data.NodeVersion, now time.Time) {
+	latest := versions[0]
+
+	if latest.DeleteMarker != nil {
+		expireMarker := rule.Expiration.ExpiredObjectDeleteMarker
+		if expireMarker != nil && *expireMarker && len(versions) == 1 {
+			n.deleteExpiredVersion(ctx, bktInfo, settings, latest, latest.OID.EncodeToString())
+		}
+		return
+	}
+
+	created, ok := n.versionCreationTime(ctx, bktInfo, latest)
+	if !ok || !isExpired(rule.Expiration, created, now) || !n.matchLifecycleTags(ctx, bktInfo, rule, latest) ||
+		n.isVersionLocked(ctx, bktInfo, latest, now) {
+		return
+	}
+
+	n.deleteExpiredVersion(ctx, bktInfo, settings, latest, "")
+}
+
+func (n *layer) expireNoncurrentVersions(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, rule data.LifecycleRule, versions []*data.NodeVersion, now time.Time) {
+	var keep int
+	if rule.NoncurrentVersionExpiration.NewerNoncurrentVersions != nil {
+		keep = *rule.NoncurrentVersionExpiration.NewerNoncurrentVersions
+	}
+	days := *rule.NoncurrentVersionExpiration.NoncurrentDays
+	if len(versions) <= 1+keep {
+		return
+	}
+
+	// version becomes noncurrent when the next one is created,
+	// creation time is fetched before the next version can be deleted
+	nextCreated, nextOK := n.versionCreationTime(ctx, bktInfo, versions[keep])
+	for i := 1 + keep; i < len(versions); i++ {
+		noncurrentSince, ok := nextCreated, nextOK
+		nextCreated, nextOK = n.versionCreationTime(ctx, bktInfo, versions[i])
+		if !ok || now.Before(data.ExpirationDate(noncurrentSince, days)) {
+			continue
+		}
+
+		if versions[i].DeleteMarker == nil &&
+			(!n.matchLifecycleTags(ctx, bktInfo, rule, versions[i]) || n.isVersionLocked(ctx, bktInfo, versions[i], now)) {
+			continue
+		}
+
+		n.deleteExpiredVersion(ctx, bktInfo, settings, versions[i], versions[i].OID.EncodeToString())
+	}
+}
+
+func (n *layer) deleteExpiredVersion(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, version *data.NodeVersion, versionID string) {
+	obj := n.deleteObject(ctx, bktInfo, settings, &VersionedObject{
+		Name:      version.FilePath,
+		VersionID: versionID,
+	})
+	if obj.Error != nil {
+		n.log.Error("couldn't expire object version", zap.Error(obj.Error),
+			zap.String("bucket", bktInfo.Name),
+			zap.String("object", version.FilePath),
+			zap.Stringer("oid", version.OID))
+		return
+	}
+
+	n.log.Debug("object version is expired by lifecycle rule",
+		zap.String("bucket", bktInfo.Name),
+		zap.String("object", version.FilePath),
+		zap.Stringer("oid", version.OID))
+
+	if n.events == nil {
+		return
+	}
+
+	e := &events.Event{
+		Name:    events.LifecycleExpirationDelete,
+		BktInfo: bktInfo,
+		Object:  &data.NotificationInfo{Name: version.FilePath, Version: versionID},
+	}
+	if obj.DeleteMarkVersion != "" {
+		e.Name = events.LifecycleExpirationDeleteMarkerCreated
+		e.Object.Version = obj.DeleteMarkVersion
+		e.Object.HashSum = obj.DeleteMarkerEtag
+	}
+	n.events.Publish(ctx, e)
+}
+
+func (n *layer) abortExpiredUploads(ctx context.Context, bktInfo *data.BucketInfo, rule data.LifecycleRule, now time.Time) error {
+	days := rule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+	if days == nil {
+		return nil
+	}
+
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo.CID, rule.RulePrefix())
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get multipart uploads: %w", err)
+	}
+
+	for _, upload := range uploads {
+		if now.Before(data.ExpirationDate(upload.Created, *days)) {
+			continue
+		}
+
+		p := &UploadInfoParams{
+			UploadID: upload.UploadID,
+			Bkt:      bktInfo,
+			Key:      upload.Key,
+		}
+
+		if err = n.AbortMultipartUpload(ctx, p); err != nil {
+			n.log.Error("couldn't abort expired multipart upload", zap.Error(err),
+				zap.String("bucket", bktInfo.Name),
+				zap.String("object", upload.Key),
+				zap.String("uploadID", upload.UploadID))
+			continue
+		}
+
+		n.log.Debug("multipart upload is aborted by lifecycle rule",
+			zap.String("bucket", bktInfo.Name),
+			zap.String("object", upload.Key),
+			zap.String("uploadID", upload.UploadID))
+	}
+
+	return nil
+}
+
+func (n *layer) versionCreationTime(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) (time.Time, bool) {
+	if version.DeleteMarker != nil {
+		return version.DeleteMarker.Created, true
+	}
+
+	objInfo := n.objectInfoFromObjectsCacheOrNeoFS(ctx, bktInfo, version, "", "")
+	if objInfo == nil {
+		return time.Time{}, false
+	}
+
+	return objInfo.Created, true
+}
+
+func (n *layer) matchLifecycleTags(ctx context.Context, bktInfo *data.BucketInfo, rule data.LifecycleRule, version *data.NodeVersion) bool {
+	if len(rule.RuleTags()) == 0 {
+		return true
+	}
+
+	tagSet, err := n.treeService.GetObjectTagging(ctx, bktInfo.CID, version)
+	if err != nil {
+		n.log.Warn("couldn't get object tagging", zap.Error(err),
+			zap.String("bucket", bktInfo.Name),
+			zap.String("object", version.FilePath))
+		return false
+	}
+
+	return rule.MatchTags(tagSet)
+}
+
+func (n *layer) isVersionLocked(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, now time.Time) bool {
+	if !bktInfo.ObjectLockEnabled {
+		return false
+	}
+
+	lockInfo, err := n.treeService.GetLock(ctx, bktInfo.CID, version.ID)
+	if err != nil {
+		n.log.Warn("couldn't get lock info", zap.Error(err),
+			zap.String("bucket", bktInfo.Name),
+			zap.String("object", version.FilePath))
+		return true
+	}
+	if lockInfo == nil {
+		return false
+	}
+
+	if lockInfo.IsLegalHoldSet() {
+		return true
+	}
+
+	if lockInfo.IsRetentionSet() {
+		until, err := time.Parse(time.RFC3339, lockInfo.UntilDate())
+		return err != nil || now.Before(until)
+	}
+
+	return false
+}
+
+func isExpired(expiration *data.LifecycleExpiration, created, now time.Time) bool {
+	if expiration.Days != nil && !now.Before(data.ExpirationDate(created, *expiration.Days)) {
+		return true
+	}
+
+	if expiration.Date != "" {
+		date, err := time.Parse(time.RFC3339, expiration.Date)
+		return err == nil && !now.Before(date)
+	}
+
+	return false
+}
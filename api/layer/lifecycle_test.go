@@ -0,0 +1,155 @@
+package layer
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) putObjectCreatedAt(objName string, created time.Time) *data.ObjectInfo {
+	content := []byte("content")
+	objInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  objName,
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader(content),
+		Header:  map[string]string{object.AttributeTimestamp: strconv.FormatInt(created.Unix(), 10)},
+	})
+	require.NoError(tc.t, err)
+
+	return objInfo
+}
+
+func (tc *testContext) putLifecycle(rules ...data.LifecycleRule) {
+	err := tc.layer.PutBucketLifecycleConfiguration(tc.ctx, &PutBucketLifecycleParams{
+		BktInfo:       tc.bktInfo,
+		Configuration: &data.LifecycleConfiguration{Rules: rules},
+	})
+	require.NoError(tc.t, err)
+}
+
+func TestLifecycleConfiguration(t *testing.T) {
+	tc := prepareContext(t)
+
+	_, err := tc.layer.GetBucketLifecycleConfiguration(tc.ctx, tc.bktInfo)
+	require.Error(t, err)
+
+	days := 1
+	tc.putLifecycle(data.LifecycleRule{
+		ID:         "rule",
+		Status:     data.LifecycleStatusEnabled,
+		Expiration: &data.LifecycleExpiration{Days: &days},
+	})
+
+	conf, err := tc.layer.GetBucketLifecycleConfiguration(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Len(t, conf.Rules, 1)
+	require.Equal(t, "rule", conf.Rules[0].ID)
+
+	err = tc.layer.DeleteBucketLifecycleConfiguration(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	_, err = tc.layer.GetBucketLifecycleConfiguration(tc.ctx, tc.bktInfo)
+	require.Error(t, err)
+}
+
+func TestApplyBucketLifecycleExpiration(t *testing.T) {
+	tc := prepareContext(t)
+
+	created := time.Now()
+	tc.putObjectCreatedAt("logs/obj", created)
+	dataObj := tc.putObjectCreatedAt("data/obj", created)
+
+	days := 1
+	tc.putLifecycle(data.LifecycleRule{
+		Status:     data.LifecycleStatusEnabled,
+		Filter:     &data.LifecycleRuleFilter{Prefix: "logs/"},
+		Expiration: &data.LifecycleExpiration{Days: &days},
+	})
+
+	err := tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, created)
+	require.NoError(t, err)
+	require.Len(t, tc.listObjectsV2(), 2)
+
+	err = tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, created.Add(48*time.Hour))
+	require.NoError(t, err)
+	tc.checkListObjects(dataObj.ID)
+	tc.getObject("logs/obj", "", true)
+}
+
+func TestApplyBucketLifecycleVersioned(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: settings,
+	})
+	require.NoError(t, err)
+
+	created := time.Now()
+	for i := 0; i < 4; i++ {
+		tc.putObjectCreatedAt(tc.obj, created)
+	}
+
+	days, keep := 1, 1
+	tc.putLifecycle(data.LifecycleRule{
+		Status: data.LifecycleStatusEnabled,
+		NoncurrentVersionExpiration: &data.NoncurrentVersionExpiration{
+			NoncurrentDays:          &days,
+			NewerNoncurrentVersions: &keep,
+		},
+	})
+
+	err = tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, created.Add(48*time.Hour))
+	require.NoError(t, err)
+
+	versions := tc.listVersions()
+	require.Len(t, versions.Version, 2)
+	require.Empty(t, versions.DeleteMarker)
+
+	tc.putLifecycle(data.LifecycleRule{
+		Status:     data.LifecycleStatusEnabled,
+		Expiration: &data.LifecycleExpiration{Days: &days},
+	})
+
+	err = tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, created.Add(48*time.Hour))
+	require.NoError(t, err)
+
+	versions = tc.listVersions()
+	require.Len(t, versions.Version, 2)
+	require.Len(t, versions.DeleteMarker, 1)
+	tc.getObject(tc.obj, "", true)
+}
+
+func TestApplyBucketLifecycleAbortMultipart(t *testing.T) {
+	tc := prepareContext(t)
+
+	uploadInfo := &UploadInfoParams{
+		UploadID: "upload-id",
+		Bkt:      tc.bktInfo,
+		Key:      tc.obj,
+	}
+	err := tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: uploadInfo})
+	require.NoError(t, err)
+
+	days := 1
+	tc.putLifecycle(data.LifecycleRule{
+		Status:                         data.LifecycleStatusEnabled,
+		AbortIncompleteMultipartUpload: &data.AbortIncompleteMultipartUpload{DaysAfterInitiation: &days},
+	})
+
+	err = tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, time.Now())
+	require.NoError(t, err)
+	_, err = tc.layer.ListParts(tc.ctx, &ListPartsParams{Info: uploadInfo, MaxParts: 10})
+	require.NoError(t, err)
+
+	err = tc.layer.ApplyBucketLifecycle(tc.ctx, tc.bktInfo, time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+	_, err = tc.layer.ListParts(tc.ctx, &ListPartsParams{Info: uploadInfo, MaxParts: 10})
+	require.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"go.uber.org/zap"
+)
+
+// PutBucketLoggingParams stores PutBucketLogging request parameters.
+type PutBucketLoggingParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.BucketLoggingStatus
+}
+
+func (n *layer) PutBucketLoggingConfiguration(ctx context.Context, p *PutBucketLoggingParams) error {
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal logging configuration: %w", err)
+	}
+
+	sysName := p.BktInfo.LoggingConfigurationObjectName()
+
+	prm := PrmObjectCreate{
+		Container: p.BktInfo.CID,
+		Creator:   p.BktInfo.Owner,
+		Payload:   bytes.NewReader(confXML),
+		Filename:  sysName,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketLoggingConfiguration(ctx, p.BktInfo.CID, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete logging configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	if err = n.systemCache.PutLoggingConfiguration(systemObjectKey(p.BktInfo, sysName), p.Configuration); err != nil {
+		n.log.Error("couldn't cache system object", zap.Error(err))
+	}
+
+	return nil
+}
+
+func (n *layer) GetBucketLoggingConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketLoggingStatus, error) {
+	systemCacheKey := systemObjectKey(bktInfo, bktInfo.LoggingConfigurationObjectName())
+
+	if conf := n.systemCache.GetLoggingConfiguration(systemCacheKey); conf != nil {
+		return conf, nil
+	}
+
+	objID, err := n.treeService.GetBucketLoggingConfiguration(ctx, bktInfo.CID)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			// logging is disabled by default
+			return &data.BucketLoggingStatus{}, nil
+		}
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &data.BucketLoggingStatus{}
+	if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+		return nil, fmt.Errorf("unmarshal logging configuration: %w", err)
+	}
+
+	if err = n.systemCache.PutLoggingConfiguration(systemCacheKey, conf); err != nil {
+		n.log.Warn("couldn't put system meta to objects cache",
+			zap.Stringer("bucket id", bktInfo.CID),
+			zap.Error(err))
+	}
+
+	return conf, nil
+}
+
+func (n *layer) DeleteBucketLoggingConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error {
+	objID, err := n.treeService.DeleteBucketLoggingConfiguration(ctx, bktInfo.CID)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.systemCache.Delete(systemObjectKey(bktInfo, bktInfo.LoggingConfigurationObjectName()))
+
+	return nil
+}
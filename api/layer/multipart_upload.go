@@ -2,6 +2,8 @@ package layer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	stderrors "errors"
 	"fmt"
@@ -11,8 +13,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
@@ -34,17 +38,24 @@ const (
 	uploadMaxSize       = 5 * 1073741824 // 5GB
 )
 
+// maxManifestSize is a maximum size of the encoded manifest stored in the version node,
+// payloads of parts of the uploads with larger manifests are copied on completion.
+var maxManifestSize = 64 * 1024
+
 type (
 	UploadInfoParams struct {
-		UploadID string
-		Bkt      *data.BucketInfo
-		Key      string
+		UploadID   string
+		Bkt        *data.BucketInfo
+		Key        string
+		Encryption *encryption.Params
 	}
 
 	CreateMultipartParams struct {
 		Info   *UploadInfoParams
 		Header map[string]string
 		Data   *UploadData
+		// ChecksumAlgorithm is an algorithm of additional checksums of parts, optional.
+		ChecksumAlgorithm string
 	}
 
 	UploadData struct {
@@ -57,14 +68,21 @@ type (
 		PartNumber int
 		Size       int64
 		Reader     io.Reader
+		// ContentMD5 and ContentSHA256Hash are payload hashes provided by the client, optional.
+		ContentMD5        []byte
+		ContentSHA256Hash []byte
+		// Checksum is an additional checksum of the part provided by the client, optional.
+		// It must match the checksum algorithm of multipart upload if it's set.
+		Checksum *data.Checksum
 	}
 
 	UploadCopyParams struct {
-		Info       *UploadInfoParams
-		SrcObjInfo *data.ObjectInfo
-		SrcBktInfo *data.BucketInfo
-		PartNumber int
-		Range      *RangeParams
+		Info          *UploadInfoParams
+		SrcObjInfo    *data.ObjectInfo
+		SrcBktInfo    *data.BucketInfo
+		SrcEncryption *encryption.Params
+		PartNumber    int
+		Range         *RangeParams
 	}
 
 	CompleteMultipartParams struct {
@@ -75,6 +93,7 @@ type (
 	CompletedPart struct {
 		ETag       string
 		PartNumber int
+		data.ChecksumFields
 	}
 
 	Part struct {
@@ -82,6 +101,7 @@ type (
 		LastModified string
 		PartNumber   int
 		Size         int64
+		data.ChecksumFields
 	}
 
 	ListMultipartUploadsParams struct {
@@ -121,6 +141,14 @@ type (
 		Owner    user.ID
 		Created  time.Time
 	}
+
+	// AbortedUploads contains results of aborting stale multipart uploads of a bucket.
+	AbortedUploads struct {
+		// Uploads is a number of aborted uploads.
+		Uploads int
+		// Bytes is a total size of deleted parts.
+		Bytes int64
+	}
 )
 
 func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) error {
@@ -131,17 +159,35 @@ func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartPar
 	}
 
 	info := &data.MultipartInfo{
-		Key:      p.Info.Key,
-		UploadID: p.Info.UploadID,
-		Owner:    n.Owner(ctx),
-		Created:  time.Now(),
-		Meta:     make(map[string]string, metaSize),
+		Key:               p.Info.Key,
+		UploadID:          p.Info.UploadID,
+		Owner:             n.Owner(ctx),
+		Created:           time.Now(),
+		Meta:              make(map[string]string, metaSize),
+		ChecksumAlgorithm: p.ChecksumAlgorithm,
 	}
 
 	for key, val := range p.Header {
 		info.Meta[metaPrefix+key] = val
 	}
 
+	bktSettings, err := n.GetBucketSettings(ctx, p.Info.Bkt)
+	if err != nil {
+		return fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+	encParams, err := n.bucketEncryptionParams(p.Info.Encryption, bktSettings)
+	if err != nil {
+		return err
+	}
+
+	if encParams.Managed() {
+		info.Meta[encryption.AttributeEncryptionAlgorithm] = encryption.AESEncryptionAlgorithm
+		info.Meta[encryption.AttributeKeyID] = encParams.KeyID()
+	} else if encParams.Enabled() {
+		info.Meta[encryption.AttributeEncryptionAlgorithm] = encryption.AESEncryptionAlgorithm
+		info.Meta[encryption.AttributeCustomerKeyMD5] = encParams.CustomerKeyMD5()
+	}
+
 	if p.Data != nil {
 		for key, val := range p.Data.ACLHeaders {
 			info.Meta[aclPrefix+key] = val
@@ -155,29 +201,56 @@ func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartPar
 	return n.treeService.CreateMultipartUpload(ctx, p.Info.Bkt.CID, info)
 }
 
-func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (string, error) {
+func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (*data.ObjectInfo, error) {
 	multipartInfo, err := n.treeService.GetMultipartUpload(ctx, p.Info.Bkt.CID, p.Info.Key, p.Info.UploadID)
 	if err != nil {
 		if stderrors.Is(err, ErrNodeNotFound) {
-			return "", errors.GetAPIError(errors.ErrNoSuchUpload)
+			return nil, errors.GetAPIError(errors.ErrNoSuchUpload)
 		}
-		return "", err
+		return nil, err
 	}
 
 	if p.Size > uploadMaxSize {
-		return "", errors.GetAPIError(errors.ErrEntityTooLarge)
+		return nil, errors.GetAPIError(errors.ErrEntityTooLarge)
 	}
 
-	objInfo, err := n.uploadPart(ctx, multipartInfo, p)
+	encParams, err := n.multipartEncryptionParams(multipartInfo, p.Info.Encryption)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return n.uploadPart(ctx, multipartInfo, p, encParams)
+}
+
+// multipartEncryptionParams checks if encryption params match ones the multipart upload was initiated with
+// and returns params to encrypt and decrypt parts with.
+func (n *layer) multipartEncryptionParams(multipartInfo *data.MultipartInfo, params *encryption.Params) (*encryption.Params, error) {
+	encInfo := encryption.ObjectEncryption{
+		Algorithm:      multipartInfo.Meta[encryption.AttributeEncryptionAlgorithm],
+		CustomerKeyMD5: multipartInfo.Meta[encryption.AttributeCustomerKeyMD5],
+		KeyID:          multipartInfo.Meta[encryption.AttributeKeyID],
+	}
+	if encInfo.Enabled() && !encInfo.Managed() && !params.Enabled() {
+		return nil, errors.GetAPIError(errors.ErrSSEMultipartEncrypted)
 	}
 
-	return objInfo.HashSum, nil
+	return n.resolveEncryptionParams(params, encInfo)
 }
 
-func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInfo, p *UploadPartParams) (*data.ObjectInfo, error) {
+func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInfo, p *UploadPartParams, encParams *encryption.Params) (*data.ObjectInfo, error) {
+	var err error
 	bktInfo := p.Info.Bkt
+
+	if n.usage != nil {
+		settings, err := n.GetBucketSettings(ctx, bktInfo)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+		}
+		if err = n.checkQuota(ctx, bktInfo, settings, p.Size, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	prm := PrmObjectCreate{
 		Container:  bktInfo.CID,
 		Creator:    bktInfo.Owner,
@@ -188,7 +261,29 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 	prm.Attributes[0][0], prm.Attributes[0][1] = UploadIDAttributeName, p.Info.UploadID
 	prm.Attributes[1][0], prm.Attributes[1][1] = UploadPartNumberAttributeName, strconv.Itoa(p.PartNumber)
 
-	id, hash, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	checksum := p.Checksum
+	if algorithm := multipartInfo.ChecksumAlgorithm; algorithm != "" {
+		if checksum == nil {
+			checksum = &data.Checksum{Algorithm: algorithm}
+		} else if checksum.Algorithm != algorithm {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+				fmt.Errorf("checksum type mismatch, expected '%s', actual '%s'", algorithm, checksum.Algorithm))
+		}
+	}
+
+	payloadParams := putPayloadParams{
+		contentMD5:    p.ContentMD5,
+		contentSHA256: p.ContentSHA256Hash,
+		checksum:      checksum,
+		encryption:    encParams,
+	}
+	if encParams.Enabled() {
+		if payloadParams.encInfo, err = encParams.NewObjectEncryption(uint64(p.Size)); err != nil {
+			return nil, err
+		}
+	}
+
+	id, hash, checksum, err := n.objectPutAndCheckHash(ctx, prm, bktInfo, payloadParams)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +295,7 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		OID:      id,
 		Size:     p.Size,
 		ETag:     hex.EncodeToString(hash),
+		Checksum: checksum,
 		Created:  time.Now(),
 	}
 
@@ -208,7 +304,14 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 	if err != nil && !oldPartIDNotFound {
 		return nil, err
 	}
-	if !oldPartIDNotFound {
+	if oldPartIDNotFound {
+		n.updateMultipartUsage(bktInfo, partInfo.Size)
+	} else {
+		// size of the replaced part is unknown
+		if n.usage != nil {
+			n.usage.invalidate(bktInfo.CID)
+		}
+
 		if err = n.objectDelete(ctx, bktInfo, oldPartID); err != nil {
 			n.log.Error("couldn't delete old part object", zap.Error(err),
 				zap.String("cnrID", bktInfo.CID.EncodeToString()),
@@ -221,11 +324,14 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		ID:  id,
 		CID: bktInfo.CID,
 
-		Owner:   bktInfo.Owner,
-		Bucket:  bktInfo.Name,
-		Size:    partInfo.Size,
-		Created: partInfo.Created,
-		HashSum: partInfo.ETag,
+		Owner:    bktInfo.Owner,
+		Bucket:   bktInfo.Name,
+		Size:     partInfo.Size,
+		Created:  partInfo.Created,
+		HashSum:  partInfo.ETag,
+		Checksum: partInfo.Checksum,
+
+		EncryptionInfo: payloadParams.encInfo,
 	}
 
 	return objInfo, nil
@@ -250,6 +356,17 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 	if size > uploadMaxSize {
 		return nil, errors.GetAPIError(errors.ErrEntityTooLarge)
 	}
+	if err = api.CheckObjectSize(ctx, uint64(size)); err != nil {
+		return nil, err
+	}
+
+	encParams, err := n.multipartEncryptionParams(multipartInfo, p.Info.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.SrcEncryption.MatchObjectEncryption(p.SrcObjInfo.EncryptionInfo); err != nil {
+		return nil, err
+	}
 
 	pr, pw := io.Pipe()
 
@@ -259,6 +376,7 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 			Writer:     pw,
 			Range:      p.Range,
 			BucketInfo: p.SrcBktInfo,
+			Encryption: p.SrcEncryption,
 		})
 
 		if err = pw.CloseWithError(err); err != nil {
@@ -273,7 +391,7 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 		Reader:     pr,
 	}
 
-	return n.uploadPart(ctx, multipartInfo, params)
+	return n.uploadPart(ctx, multipartInfo, params, encParams)
 }
 
 // implements io.Reader of payloads of the object list stored in the NeoFS network.
@@ -284,6 +402,9 @@ type multiObjectReader struct {
 
 	prm getParams
 
+	// encryption is set if parts are encrypted
+	encryption *encryption.Params
+
 	curReader io.Reader
 
 	parts []*data.PartInfo
@@ -303,7 +424,11 @@ func (x *multiObjectReader) Read(p []byte) (n int, err error) {
 
 	x.prm.oid = x.parts[0].OID
 
-	x.curReader, err = x.layer.initObjectPayloadReader(x.ctx, x.prm)
+	if x.encryption.Enabled() {
+		x.curReader, err = x.layer.initDecryptedPayloadReader(x.ctx, x.prm, x.encryption)
+	} else {
+		x.curReader, err = x.layer.initObjectPayloadReader(x.ctx, x.prm)
+	}
 	if err != nil {
 		return n, fmt.Errorf("init payload reader for the next part: %w", err)
 	}
@@ -327,6 +452,11 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		return nil, nil, err
 	}
 
+	encParams, err := n.multipartEncryptionParams(multipartInfo, p.Info.Encryption)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if len(partsInfo) < len(p.Parts) {
 		return nil, nil, errors.GetAPIError(errors.ErrInvalidPart)
 	}
@@ -337,7 +467,7 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 	var completedPartsHeader strings.Builder
 	for i, part := range p.Parts {
 		partInfo := partsInfo[part.PartNumber]
-		if partInfo == nil || part.ETag != partInfo.ETag {
+		if partInfo == nil || part.ETag != partInfo.ETag || !partChecksumMatches(part, partInfo) {
 			return nil, nil, errors.GetAPIError(errors.ErrInvalidPart)
 		}
 		// for the last part we have no minimum size limit
@@ -356,6 +486,10 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		}
 	}
 
+	if err = api.CheckObjectSize(ctx, uint64(multipartObjetSize)); err != nil {
+		return nil, nil, err
+	}
+
 	initMetadata := make(map[string]string, len(multipartInfo.Meta)+1)
 	initMetadata[UploadCompletedParts] = completedPartsHeader.String()
 
@@ -373,22 +507,54 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		}
 	}
 
-	r := &multiObjectReader{
-		ctx:   ctx,
-		layer: n,
-		parts: parts,
+	var checksum *data.Checksum
+	if multipartInfo.ChecksumAlgorithm != "" {
+		if checksum, err = compositeChecksum(multipartInfo.ChecksumAlgorithm, parts); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	r.prm.bktInfo = p.Info.Bkt
+	var uploadedSize int64
+	for _, partInfo := range partsInfo {
+		uploadedSize += partInfo.Size
+	}
 
-	obj, err := n.PutObject(ctx, &PutObjectParams{
-		BktInfo: p.Info.Bkt,
-		Object:  p.Info.Key,
-		Reader:  r,
-		Header:  initMetadata,
-		Size:    multipartObjetSize,
-	})
+	putParams := &PutObjectParams{
+		BktInfo:    p.Info.Bkt,
+		Object:     p.Info.Key,
+		Header:     initMetadata,
+		Size:       multipartObjetSize,
+		Checksum:   checksum,
+		Encryption: encParams,
+	}
+
+	manifest := make([]data.ManifestPart, len(parts))
+	for i, part := range parts {
+		manifest[i] = data.ManifestPart{OID: part.OID, Size: part.Size, ETag: part.ETag}
+	}
+
+	var obj *data.ObjectInfo
+	if encParams.Enabled() || len(data.EncodeManifest(manifest)) > maxManifestSize {
+		// parts are encrypted separately, so the object is encrypted as a whole again,
+		// too large manifest doesn't fit into the version node, so payloads are copied too
+		r := &multiObjectReader{
+			ctx:        ctx,
+			layer:      n,
+			parts:      parts,
+			encryption: encParams,
+		}
+		r.prm.bktInfo = p.Info.Bkt
+		putParams.Reader = r
+
+		obj, err = n.putObject(ctx, putParams, uploadedSize)
+	} else {
+		obj, err = n.putObjectManifest(ctx, putParams, parts, manifest, uploadedSize)
+	}
 	if err != nil {
+		if errors.IsS3Error(err, errors.ErrQuotaExceeded) {
+			return nil, nil, err
+		}
+
 		n.log.Error("could not put a completed object (multipart upload)",
 			zap.String("uploadID", p.Info.UploadID),
 			zap.String("uploadKey", p.Info.Key),
@@ -397,9 +563,19 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		return nil, nil, errors.GetAPIError(errors.ErrInternalError)
 	}
 
+	// parts of the object completed without copying are kept
+	keep := make(map[oid.ID]struct{}, len(obj.Parts))
+	for _, part := range obj.Parts {
+		keep[part.OID] = struct{}{}
+	}
+
 	var addr oid.Address
 	addr.SetContainer(p.Info.Bkt.CID)
 	for _, partInfo := range partsInfo {
+		if _, ok := keep[partInfo.OID]; ok {
+			continue
+		}
+
 		if err = n.objectDelete(ctx, p.Info.Bkt, partInfo.OID); err != nil {
 			n.log.Warn("could not delete upload part",
 				zap.Stringer("object id", &partInfo.OID),
@@ -410,7 +586,159 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		n.objCache.Delete(addr)
 	}
 
-	return uploadData, obj, n.treeService.DeleteMultipartUpload(ctx, p.Info.Bkt.CID, multipartInfo.ID)
+	if err = n.treeService.DeleteMultipartUpload(ctx, p.Info.Bkt.CID, multipartInfo.ID); err != nil {
+		return nil, nil, err
+	}
+	n.updateMultipartUsage(p.Info.Bkt, -uploadedSize)
+
+	return uploadData, obj, nil
+}
+
+// putObjectManifest saves the object completed from the parts without copying of their payloads.
+// NeoFS object of the version contains headers only, the payload is read from the parts
+// listed in the version node.
+func (n *layer) putObjectManifest(ctx context.Context, p *PutObjectParams, parts []*data.PartInfo, manifest []data.ManifestPart, reclaimed int64) (*data.ObjectInfo, error) {
+	own := n.Owner(ctx)
+
+	bktSettings, err := n.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+
+	if err = n.checkQuota(ctx, p.BktInfo, bktSettings, p.Size, reclaimed); err != nil {
+		return nil, err
+	}
+
+	if len(p.Header[api.ContentType]) == 0 {
+		p.Header[api.ContentType] = n.detectPartsContentType(ctx, p.BktInfo, p.Object, parts)
+	}
+
+	prm := PrmObjectCreate{
+		Container:  p.BktInfo.CID,
+		Creator:    own,
+		Filename:   p.Object,
+		Attributes: make([][2]string, 0, len(p.Header)),
+	}
+
+	for k, v := range p.Header {
+		prm.Attributes = append(prm.Attributes, [2]string{k, v})
+	}
+
+	id, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	newVersion := &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{
+			OID:      id,
+			FilePath: p.Object,
+			Size:     p.Size,
+			ETag:     manifestETag(parts),
+			Checksum: p.Checksum,
+		},
+		IsUnversioned: !bktSettings.VersioningEnabled(),
+		Parts:         manifest,
+	}
+
+	objInfo := &data.ObjectInfo{
+		ID:  id,
+		CID: p.BktInfo.CID,
+
+		Owner:       own,
+		Bucket:      p.BktInfo.Name,
+		Name:        p.Object,
+		Size:        p.Size,
+		Created:     time.Now(),
+		Headers:     p.Header,
+		ContentType: p.Header[api.ContentType],
+		HashSum:     newVersion.ETag,
+		Checksum:    p.Checksum,
+		Parts:       manifest,
+	}
+
+	if err = n.addObjectVersion(ctx, p, newVersion, objInfo); err != nil {
+		return nil, err
+	}
+
+	return objInfo, nil
+}
+
+// detectPartsContentType detects content type of the object by the name or the beginning of the first part.
+func (n *layer) detectPartsContentType(ctx context.Context, bktInfo *data.BucketInfo, objectName string, parts []*data.PartInfo) string {
+	if contentType := MimeByFileName(objectName); len(contentType) != 0 || len(parts) == 0 || parts[0].Size == 0 {
+		return contentType
+	}
+
+	prm := getParams{
+		oid:     parts[0].OID,
+		bktInfo: bktInfo,
+		ln:      contentTypeDetectSize,
+	}
+	if parts[0].Size < contentTypeDetectSize {
+		prm.ln = uint64(parts[0].Size)
+	}
+
+	payload, err := n.initObjectPayloadReader(ctx, prm)
+	if err != nil {
+		n.log.Warn("couldn't read part to detect content type", zap.String("object", objectName), zap.Error(err))
+		return ""
+	}
+
+	contentType, err := newDetector(payload).Detect()
+	if err != nil {
+		n.log.Warn("couldn't detect content type", zap.String("object", objectName), zap.Error(err))
+	}
+	return contentType
+}
+
+// manifestETag calculates ETag of the object completed from the parts as a hash
+// of the part hashes with the number of parts, like S3 does.
+func manifestETag(parts []*data.PartInfo) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		partHash, err := hex.DecodeString(part.ETag)
+		if err != nil {
+			partHash = []byte(part.ETag)
+		}
+		hash.Write(partHash)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+}
+
+// partChecksumMatches checks the part checksum from complete multipart upload request if it's set.
+func partChecksumMatches(part *CompletedPart, partInfo *data.PartInfo) bool {
+	checksum := part.Checksum()
+	if checksum == nil {
+		return true
+	}
+
+	return partInfo.Checksum != nil && *checksum == *partInfo.Checksum
+}
+
+// compositeChecksum calculates checksum of part checksums, all parts must have checksum of the algorithm.
+func compositeChecksum(algorithm string, parts []*data.PartInfo) (*data.Checksum, error) {
+	checksumHash := data.NewChecksumHash(algorithm)
+	if checksumHash == nil {
+		return nil, errors.GetAPIError(errors.ErrInvalidChecksumAlgorithm)
+	}
+
+	for _, part := range parts {
+		if part.Checksum == nil || part.Checksum.Algorithm != algorithm {
+			return nil, errors.GetAPIError(errors.ErrInvalidPart)
+		}
+		partChecksum, err := base64.StdEncoding.DecodeString(part.Checksum.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum of part %d: %w", part.Number, err)
+		}
+		checksumHash.Write(partChecksum)
+	}
+
+	return &data.Checksum{
+		Algorithm: algorithm,
+		Value:     base64.StdEncoding.EncodeToString(checksumHash.Sum(nil)) + "-" + strconv.Itoa(len(parts)),
+	}, nil
 }
 
 func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (*ListMultipartUploadsInfo, error) {
@@ -474,19 +802,84 @@ func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUpload
 }
 
 func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error {
+	_, err := n.abortMultipartUpload(ctx, p)
+	return err
+}
+
+// abortMultipartUpload deletes parts and the upload, it returns the size of the deleted parts.
+func (n *layer) abortMultipartUpload(ctx context.Context, p *UploadInfoParams) (int64, error) {
 	multipartInfo, parts, err := n.getUploadParts(ctx, p)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	var uploadedSize, deletedSize int64
 	for _, info := range parts {
+		uploadedSize += info.Size
 		if err = n.objectDelete(ctx, p.Bkt, info.OID); err != nil {
 			n.log.Warn("couldn't delete part", zap.String("cid", p.Bkt.CID.EncodeToString()),
 				zap.String("oid", info.OID.EncodeToString()), zap.Int("part number", info.Number))
+			continue
 		}
+		deletedSize += info.Size
+	}
+
+	if err = n.treeService.DeleteMultipartUpload(ctx, p.Bkt.CID, multipartInfo.ID); err != nil {
+		return 0, err
+	}
+	n.updateMultipartUsage(p.Bkt, -uploadedSize)
+
+	return deletedSize, nil
+}
+
+// AbortStaleMultipartUploads aborts multipart uploads of the bucket initiated before the specified time.
+// Uploads which can't be aborted are logged and skipped.
+func (n *layer) AbortStaleMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, initiatedBefore time.Time) (*AbortedUploads, error) {
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo.CID, "")
+	if err != nil {
+		if stderrors.Is(err, ErrNodeNotFound) {
+			return &AbortedUploads{}, nil
+		}
+		return nil, fmt.Errorf("get multipart uploads: %w", err)
+	}
+
+	res := &AbortedUploads{}
+	for _, upload := range uploads {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+
+		if !upload.Created.Before(initiatedBefore) {
+			continue
+		}
+
+		p := &UploadInfoParams{
+			UploadID: upload.UploadID,
+			Bkt:      bktInfo,
+			Key:      upload.Key,
+		}
+
+		reclaimed, err := n.abortMultipartUpload(ctx, p)
+		if err != nil {
+			n.log.Error("couldn't abort stale multipart upload", zap.Error(err),
+				zap.String("bucket", bktInfo.Name),
+				zap.String("object", upload.Key),
+				zap.String("uploadID", upload.UploadID))
+			continue
+		}
+
+		res.Uploads++
+		res.Bytes += reclaimed
+
+		n.log.Info("stale multipart upload is aborted",
+			zap.String("bucket", bktInfo.Name),
+			zap.String("object", upload.Key),
+			zap.String("uploadID", upload.UploadID),
+			zap.Time("created", upload.Created),
+			zap.Int64("reclaimed bytes", reclaimed))
 	}
 
-	return n.treeService.DeleteMultipartUpload(ctx, p.Bkt.CID, multipartInfo.ID)
+	return res, nil
 }
 
 func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error) {
@@ -502,10 +895,11 @@ func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsIn
 
 	for _, partInfo := range partsInfo {
 		parts = append(parts, &Part{
-			ETag:         partInfo.ETag,
-			LastModified: partInfo.Created.UTC().Format(time.RFC3339),
-			PartNumber:   partInfo.Number,
-			Size:         partInfo.Size,
+			ETag:           partInfo.ETag,
+			LastModified:   partInfo.Created.UTC().Format(time.RFC3339),
+			PartNumber:     partInfo.Number,
+			Size:           partInfo.Size,
+			ChecksumFields: data.NewChecksumFields(partInfo.Checksum),
 		})
 	}
 
@@ -1,12 +1,143 @@
 package layer
 
 import (
+	"bytes"
+	"crypto/rand"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/stretchr/testify/require"
 )
 
+func TestAbortStaleMultipartUploads(t *testing.T) {
+	tc := prepareContext(t)
+
+	createUpload := func(key string, part []byte) {
+		info := &UploadInfoParams{UploadID: key + "-upload", Bkt: tc.bktInfo, Key: key}
+		require.NoError(t, tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: info, Header: make(map[string]string)}))
+		_, err := tc.layer.UploadPart(tc.ctx, &UploadPartParams{Info: info, PartNumber: 1, Size: int64(len(part)), Reader: bytes.NewReader(part)})
+		require.NoError(t, err)
+	}
+
+	createUpload("stale", []byte("stale part"))
+
+	uploads, err := tc.layer.(*layer).treeService.GetMultipartUploadsByPrefix(tc.ctx, tc.bktInfo.CID, "")
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	uploads[0].Created = time.Now().Add(-48 * time.Hour)
+
+	createUpload("fresh", []byte("fresh part"))
+
+	res, err := tc.layer.AbortStaleMultipartUploads(tc.ctx, tc.bktInfo, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, &AbortedUploads{Uploads: 1, Bytes: int64(len("stale part"))}, res)
+
+	uploads, err = tc.layer.(*layer).treeService.GetMultipartUploadsByPrefix(tc.ctx, tc.bktInfo.CID, "")
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, "fresh", uploads[0].Key)
+	require.Len(t, tc.testNeoFS.Objects(), 1)
+
+	res, err = tc.layer.AbortStaleMultipartUploads(tc.ctx, tc.bktInfo, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, &AbortedUploads{}, res)
+}
+
+func TestCompleteMultipartUploadManifest(t *testing.T) {
+	tc := prepareContext(t)
+	tc.obj = "multipart"
+
+	info := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: tc.obj}
+	require.NoError(t, tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: info, Header: make(map[string]string)}))
+
+	content := make([]byte, uploadMinSize+100)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	parts := [][]byte{content[:uploadMinSize], content[uploadMinSize:], []byte("unused part")}
+	completed := make([]*CompletedPart, 0, len(parts))
+	for i, part := range parts {
+		partInfo, err := tc.layer.UploadPart(tc.ctx, &UploadPartParams{Info: info, PartNumber: i + 1, Size: int64(len(part)), Reader: bytes.NewReader(part)})
+		require.NoError(t, err)
+		completed = append(completed, &CompletedPart{ETag: partInfo.HashSum, PartNumber: i + 1})
+	}
+
+	_, objInfo, err := tc.layer.CompleteMultipartUpload(tc.ctx, &CompleteMultipartParams{Info: info, Parts: completed[:2]})
+	require.NoError(t, err)
+	require.Len(t, objInfo.Parts, 2)
+	require.EqualValues(t, len(content), objInfo.Size)
+	require.True(t, strings.HasSuffix(objInfo.HashSum, "-2"))
+
+	// the head object and two completed parts are stored, the unused part is removed
+	require.Len(t, tc.testNeoFS.Objects(), 3)
+	require.Empty(t, tc.getObjectByID(objInfo.ID).Payload())
+
+	headInfo, payload := tc.getObject(tc.obj, "", false)
+	require.Equal(t, content, payload)
+	require.Equal(t, objInfo.HashSum, headInfo.HashSum)
+	require.Equal(t, objInfo.Size, headInfo.Size)
+
+	readRange := func(start, end uint64) []byte {
+		buf := bytes.NewBuffer(nil)
+		err := tc.layer.GetObject(tc.ctx, &GetObjectParams{
+			ObjectInfo: headInfo,
+			Writer:     buf,
+			BucketInfo: tc.bktInfo,
+			Range:      &RangeParams{Start: start, End: end},
+		})
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+
+	require.Equal(t, content[uploadMinSize-10:uploadMinSize+10], readRange(uploadMinSize-10, uploadMinSize+9))
+	require.Equal(t, content[uploadMinSize+50:], readRange(uploadMinSize+50, uint64(len(content)-1)))
+
+	tc.layer.(*layer).read = &ReadConfig{Concurrency: 4, ChunkSize: 1024 * 1024, BufferSize: 4 * 1024 * 1024}
+	_, payload = tc.getObject(tc.obj, "", false)
+	require.Equal(t, content, payload)
+	require.Equal(t, content[10:uploadMinSize+10], readRange(10, uploadMinSize+9))
+
+	tc.deleteObject(tc.obj, "", &data.BucketSettings{Versioning: data.VersioningUnversioned})
+	require.Empty(t, tc.testNeoFS.Objects())
+}
+
+func TestCompleteMultipartUploadLargeManifest(t *testing.T) {
+	tc := prepareContext(t)
+	tc.obj = "multipart"
+
+	defer func(size int) { maxManifestSize = size }(maxManifestSize)
+	maxManifestSize = 1
+
+	info := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: tc.obj}
+	require.NoError(t, tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: info, Header: make(map[string]string)}))
+
+	content := make([]byte, uploadMinSize+100)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	parts := [][]byte{content[:uploadMinSize], content[uploadMinSize:]}
+	completed := make([]*CompletedPart, 0, len(parts))
+	for i, part := range parts {
+		partInfo, err := tc.layer.UploadPart(tc.ctx, &UploadPartParams{Info: info, PartNumber: i + 1, Size: int64(len(part)), Reader: bytes.NewReader(part)})
+		require.NoError(t, err)
+		completed = append(completed, &CompletedPart{ETag: partInfo.HashSum, PartNumber: i + 1})
+	}
+
+	_, objInfo, err := tc.layer.CompleteMultipartUpload(tc.ctx, &CompleteMultipartParams{Info: info, Parts: completed})
+	require.NoError(t, err)
+	require.Empty(t, objInfo.Parts)
+
+	// payloads of the parts are copied into the completed object and the parts are removed
+	require.Len(t, tc.testNeoFS.Objects(), 1)
+	require.Equal(t, content, tc.getObjectByID(objInfo.ID).Payload())
+
+	_, payload := tc.getObject(tc.obj, "", false)
+	require.Equal(t, content, payload)
+}
+
 func TestTrimAfterUploadIDAndKey(t *testing.T) {
 	uploads := []*UploadInfo{
 		{Key: "j", UploadID: "k"}, // key < id <
@@ -176,9 +176,18 @@ func (t *TestNeoFS) ReadObject(_ context.Context, prm PrmObjectRead) (*ObjectPar
 	sAddr := addr.EncodeToString()
 
 	if obj, ok := t.objects[sAddr]; ok {
+		payload := obj.Payload()
+		if prm.PayloadRange[0]+prm.PayloadRange[1] > 0 {
+			off, ln := prm.PayloadRange[0], prm.PayloadRange[1]
+			if off+ln > uint64(len(payload)) {
+				return nil, fmt.Errorf("invalid range %d-%d of object %s", off, off+ln, addr)
+			}
+			payload = payload[off : off+ln]
+		}
+
 		return &ObjectPart{
 			Head:    obj,
-			Payload: io.NopCloser(bytes.NewReader(obj.Payload())),
+			Payload: io.NopCloser(bytes.NewReader(payload)),
 		}, nil
 	}
 
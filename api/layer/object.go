@@ -1,11 +1,15 @@
 package layer
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"mime"
 	"path/filepath"
@@ -18,6 +22,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
@@ -58,6 +63,17 @@ type (
 		FetchOwner        bool
 	}
 
+	// putPayloadParams contains parameters of payload verification and encryption on object put.
+	putPayloadParams struct {
+		// payload hashes provided by the client, optional
+		contentMD5, contentSHA256 []byte
+		// additional checksum to calculate, optional
+		checksum *data.Checksum
+		// payload is encrypted with encInfo if encryption is set
+		encryption *encryption.Params
+		encInfo    encryption.ObjectEncryption
+	}
+
 	allObjectParams struct {
 		Bucket            *data.BucketInfo
 		Delimiter         string
@@ -117,6 +133,39 @@ func (n *layer) initObjectPayloadReader(ctx context.Context, p getParams) (io.Re
 	return res.Payload, nil
 }
 
+// initDecryptedPayloadReader initializes reader of the decrypted full payload of the NeoFS object.
+// Encryption info is read from the object header.
+func (n *layer) initDecryptedPayloadReader(ctx context.Context, p getParams, params *encryption.Params) (io.Reader, error) {
+	prm := PrmObjectRead{
+		Container:   p.bktInfo.CID,
+		Object:      p.oid,
+		WithHeader:  true,
+		WithPayload: true,
+	}
+
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, p.bktInfo.Owner)
+
+	res, err := n.neoFS.ReadObject(ctx, prm)
+	if err != nil {
+		return nil, n.transformNeofsError(ctx, err)
+	}
+
+	encInfo, err := encryption.ObjectEncryptionFromAttributes(userHeaders(res.Head.Attributes()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption info: %w", err)
+	}
+	if params, err = n.resolveEncryptionParams(params, encInfo); err != nil {
+		return nil, err
+	}
+
+	var end uint64
+	if encInfo.DecryptedSize > 0 {
+		end = encInfo.DecryptedSize - 1
+	}
+
+	return params.DecryptReader(res.Payload, encInfo, 0, end)
+}
+
 // objectGet returns an object with payload in the object.
 func (n *layer) objectGet(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (*object.Object, error) {
 	prm := PrmObjectRead{
@@ -147,6 +196,11 @@ func MimeByFileName(name string) string {
 
 // PutObject stores object into NeoFS, took payload from io.Reader.
 func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.ObjectInfo, error) {
+	return n.putObject(ctx, p, 0)
+}
+
+// putObject stores the object, the reclaimed size is freed by the write and isn't checked against the bucket quota.
+func (n *layer) putObject(ctx context.Context, p *PutObjectParams, reclaimed int64) (*data.ObjectInfo, error) {
 	own := n.Owner(ctx)
 
 	bktSettings, err := n.GetBucketSettings(ctx, p.BktInfo)
@@ -154,6 +208,10 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Object
 		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
 	}
 
+	if err = n.checkQuota(ctx, p.BktInfo, bktSettings, p.Size, reclaimed); err != nil {
+		return nil, err
+	}
+
 	newVersion := &data.NodeVersion{
 		BaseNodeVersion: data.BaseNodeVersion{
 			FilePath: p.Object,
@@ -191,30 +249,31 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Object
 		prm.Attributes = append(prm.Attributes, [2]string{k, v})
 	}
 
-	id, hash, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	encParams, err := n.bucketEncryptionParams(p.Encryption, bktSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	newVersion.OID = id
-	newVersion.ETag = hex.EncodeToString(hash)
-	if err = n.treeService.AddVersion(ctx, p.BktInfo.CID, newVersion); err != nil {
-		return nil, fmt.Errorf("couldn't add new verion to tree service: %w", err)
+	payloadParams := putPayloadParams{
+		contentMD5:    p.ContentMD5,
+		contentSHA256: p.ContentSHA256Hash,
+		checksum:      p.Checksum,
+		encryption:    encParams,
 	}
-
-	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
-		objVersion := &ObjectVersion{
-			BktInfo:    p.BktInfo,
-			ObjectName: p.Object,
-			VersionID:  id.EncodeToString(),
-		}
-
-		if err = n.PutLockInfo(ctx, objVersion, p.Lock); err != nil {
+	if encParams.Enabled() {
+		if payloadParams.encInfo, err = encParams.NewObjectEncryption(uint64(p.Size)); err != nil {
 			return nil, err
 		}
 	}
 
-	n.listsCache.CleanCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+	id, hash, checksum, err := n.objectPutAndCheckHash(ctx, prm, p.BktInfo, payloadParams)
+	if err != nil {
+		return nil, err
+	}
+
+	newVersion.OID = id
+	newVersion.ETag = hex.EncodeToString(hash)
+	newVersion.Checksum = checksum
 
 	objInfo := &data.ObjectInfo{
 		ID:  id,
@@ -228,8 +287,42 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Object
 		Headers:     p.Header,
 		ContentType: p.Header[api.ContentType],
 		HashSum:     newVersion.ETag,
+		Checksum:    checksum,
+
+		EncryptionInfo: payloadParams.encInfo,
 	}
 
+	if err = n.addObjectVersion(ctx, p, newVersion, objInfo); err != nil {
+		return nil, err
+	}
+
+	return objInfo, nil
+}
+
+// addObjectVersion saves the new version of the stored object to the tree service,
+// puts the lock of the object if it's set and caches the object info.
+func (n *layer) addObjectVersion(ctx context.Context, p *PutObjectParams, newVersion *data.NodeVersion, objInfo *data.ObjectInfo) error {
+	updateUsage := n.trackObjectUsage(ctx, p.BktInfo, p.Object)
+	err := n.treeService.AddVersion(ctx, p.BktInfo.CID, newVersion)
+	updateUsage()
+	if err != nil {
+		return fmt.Errorf("couldn't add new verion to tree service: %w", err)
+	}
+
+	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
+		objVersion := &ObjectVersion{
+			BktInfo:    p.BktInfo,
+			ObjectName: p.Object,
+			VersionID:  newVersion.OID.EncodeToString(),
+		}
+
+		if err = n.PutLockInfo(ctx, objVersion, p.Lock); err != nil {
+			return err
+		}
+	}
+
+	n.listsCache.CleanCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+
 	if err = n.objCache.PutObject(objInfo); err != nil {
 		n.log.Warn("couldn't add object to cache", zap.Error(err),
 			zap.String("object_name", p.Object), zap.String("bucket_name", p.BktInfo.Name),
@@ -241,7 +334,7 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Object
 			zap.Error(err))
 	}
 
-	return objInfo, nil
+	return nil
 }
 
 func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.BucketInfo, objectName string) (*data.ExtendedObjectInfo, error) {
@@ -268,6 +361,7 @@ func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.Bucke
 		return nil, err
 	}
 	objInfo := objectInfoFromMeta(bkt, meta)
+	fillFromNodeVersion(objInfo, node)
 	if err = n.objCache.PutObject(objInfo); err != nil {
 		n.log.Warn("couldn't put object info to cache",
 			zap.Stringer("object id", node.OID),
@@ -331,6 +425,7 @@ func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadOb
 	}
 
 	objInfo := objectInfoFromMeta(bkt, meta)
+	fillFromNodeVersion(objInfo, foundVersion)
 	if err = n.objCache.PutObject(objInfo); err != nil {
 		n.log.Warn("couldn't put obj to object cache",
 			zap.String("bucket name", objInfo.Bucket),
@@ -372,6 +467,97 @@ func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktIn
 	return id, hash.Sum(nil), n.transformNeofsError(ctx, err)
 }
 
+// objectPutAndCheckHash invokes objectPutAndHash and compares payload hashes with
+// the ones provided by the client (nil hashes are not checked). On mismatch the stored
+// object is removed and BadDigest or XAmzContentSHA256Mismatch error is returned.
+// Additional checksum of the algorithm from checksum param is calculated and returned
+// (checksum value is compared if set); composite checksum is returned as is.
+// Payload is encrypted if encryption is set, hashes are calculated over the plain payload
+// then, but the returned hash is a hash of the stored one.
+func (n *layer) objectPutAndCheckHash(ctx context.Context, prm PrmObjectCreate, bktInfo *data.BucketInfo, p putPayloadParams) (oid.ID, []byte, *data.Checksum, error) {
+	md5Hash := md5.New()
+	if p.contentMD5 != nil {
+		prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
+			md5Hash.Write(buf)
+		})
+	}
+
+	checksum := p.checksum
+	var checksumHash hash.Hash
+	if checksum != nil && !checksum.IsComposite() {
+		if checksumHash = data.NewChecksumHash(checksum.Algorithm); checksumHash == nil {
+			return oid.ID{}, nil, nil, apiErrors.GetAPIError(apiErrors.ErrInvalidChecksumAlgorithm)
+		}
+		prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
+			checksumHash.Write(buf)
+		})
+	}
+
+	var (
+		err         error
+		plainSize   uint64
+		plainSHA256 = sha256.New()
+	)
+	if p.encryption.Enabled() {
+		if prm.Payload == nil {
+			prm.Payload = bytes.NewReader(nil)
+		}
+		prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
+			plainSize += uint64(len(buf))
+			plainSHA256.Write(buf)
+		})
+		if prm.Payload, err = p.encryption.EncryptReader(prm.Payload, p.encInfo); err != nil {
+			return oid.ID{}, nil, nil, fmt.Errorf("couldn't encrypt payload: %w", err)
+		}
+		if prm.PayloadSize != 0 {
+			prm.PayloadSize = p.encInfo.EncryptedSize()
+		}
+		prm.Attributes = append(prm.Attributes, p.encInfo.Attributes()...)
+	}
+
+	id, sha256Hash, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	if err != nil {
+		return oid.ID{}, nil, nil, err
+	}
+
+	if checksumHash != nil {
+		calculated := &data.Checksum{
+			Algorithm: checksum.Algorithm,
+			Value:     base64.StdEncoding.EncodeToString(checksumHash.Sum(nil)),
+		}
+		if checksum.Value != "" && checksum.Value != calculated.Value {
+			err = apiErrors.GetAPIError(apiErrors.ErrBadChecksum)
+		}
+		checksum = calculated
+	}
+
+	payloadSHA256 := sha256Hash
+	if p.encryption.Enabled() {
+		payloadSHA256 = plainSHA256.Sum(nil)
+		if plainSize != p.encInfo.DecryptedSize {
+			err = apiErrors.GetAPIError(apiErrors.ErrIncompleteBody)
+		}
+	}
+
+	if p.contentMD5 != nil && !bytes.Equal(p.contentMD5, md5Hash.Sum(nil)) {
+		err = apiErrors.GetAPIError(apiErrors.ErrBadDigest)
+	} else if p.contentSHA256 != nil && !bytes.Equal(p.contentSHA256, payloadSHA256) {
+		err = apiErrors.GetAPIError(apiErrors.ErrContentSHA256Mismatch)
+	}
+
+	if err != nil {
+		if errDelete := n.objectDelete(ctx, bktInfo, id); errDelete != nil {
+			n.log.Error("couldn't delete object with mismatched payload hash", zap.Error(errDelete),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", id.EncodeToString()))
+		}
+		return oid.ID{}, nil, nil, err
+	}
+
+	return id, sha256Hash, checksum, nil
+}
+
 // ListObjectsV1 returns objects in a bucket for requests of Version 1.
 func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error) {
 	var result ListObjectsInfoV1
@@ -539,7 +725,7 @@ func (n *layer) initWorkerPool(ctx context.Context, size int, p allObjectParams,
 				wg.Add(1)
 				err = pool.Submit(func() {
 					defer wg.Done()
-					if oi := n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.Bucket, node.OID, p.Prefix, p.Delimiter); oi != nil {
+					if oi := n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.Bucket, node, p.Prefix, p.Delimiter); oi != nil {
 						select {
 						case <-ctx.Done():
 						case objCh <- oi:
@@ -597,7 +783,7 @@ func (n *layer) getAllObjectsVersions(ctx context.Context, bkt *data.BucketInfo,
 			oi.Created = nodeVersion.DeleteMarker.Created
 			oi.IsDeleteMarker = true
 		} else {
-			if oi = n.objectInfoFromObjectsCacheOrNeoFS(ctx, bkt, nodeVersion.OID, prefix, delimiter); oi == nil {
+			if oi = n.objectInfoFromObjectsCacheOrNeoFS(ctx, bkt, nodeVersion, prefix, delimiter); oi == nil {
 				continue
 			}
 		}
@@ -678,17 +864,18 @@ func triageExtendedObjects(allObjects []*data.ExtendedObjectInfo) (prefixes []st
 	return
 }
 
-func (n *layer) objectInfoFromObjectsCacheOrNeoFS(ctx context.Context, bktInfo *data.BucketInfo, obj oid.ID, prefix, delimiter string) (oi *data.ObjectInfo) {
-	oi = n.objCache.GetObject(newAddress(bktInfo.CID, obj))
+func (n *layer) objectInfoFromObjectsCacheOrNeoFS(ctx context.Context, bktInfo *data.BucketInfo, node *data.NodeVersion, prefix, delimiter string) (oi *data.ObjectInfo) {
+	oi = n.objCache.GetObject(newAddress(bktInfo.CID, node.OID))
 
 	if oi == nil {
-		meta, err := n.objectHead(ctx, bktInfo, obj)
+		meta, err := n.objectHead(ctx, bktInfo, node.OID)
 		if err != nil {
 			n.log.Warn("could not fetch object meta", zap.Error(err))
 			return nil
 		}
 
 		oi = objectInfoFromMeta(bktInfo, meta)
+		fillFromNodeVersion(oi, node)
 		if err = n.objCache.PutObject(oi); err != nil {
 			n.log.Warn("couldn't cache an object", zap.Error(err))
 		}
@@ -0,0 +1,238 @@
+package layer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// Default values of parallel read parameters.
+const (
+	DefaultReadChunkSize  = 16 * 1024 * 1024 // 16 MB
+	DefaultReadBufferSize = 64 * 1024 * 1024 // 64 MB
+)
+
+type (
+	// ReadConfig contains parameters of parallel reads of large object payloads.
+	ReadConfig struct {
+		// Concurrency is a maximum number of ranges of the payload read at the same time
+		// by a single request. Payload is read by a single stream if it's less than 2.
+		Concurrency int
+		// ChunkSize is a maximum size of the range read by a single NeoFS request.
+		ChunkSize uint64
+		// BufferSize is a maximum size of the payload read ahead by a single request.
+		BufferSize uint64
+	}
+
+	// payloadSegment is a part of the object payload stored in the NeoFS object
+	// with the specified ID starting from the offset.
+	payloadSegment struct {
+		oid       oid.ID
+		off, size uint64
+	}
+
+	// payloadChunk is a range of the NeoFS object payload read by a single request.
+	payloadChunk struct {
+		oid     oid.ID
+		off, ln uint64
+	}
+
+	chunkResult struct {
+		payload []byte
+		err     error
+	}
+)
+
+// parallelReadEnabled checks if the payload range is large enough to be read in parallel.
+func (n *layer) parallelReadEnabled(ln uint64) bool {
+	return n.read != nil && n.read.Concurrency > 1 && ln > n.read.ChunkSize
+}
+
+// readAheadWindow returns the number of chunks read at the same time,
+// so the buffered payload doesn't exceed the buffer size.
+func (n *layer) readAheadWindow() int {
+	window := int(n.read.BufferSize / n.read.ChunkSize)
+	if window > n.read.Concurrency {
+		window = n.read.Concurrency
+	}
+	if window < 1 {
+		window = 1
+	}
+	return window
+}
+
+// payloadSegments returns segments of the object payload: parts of the object completed from the
+// multipart upload without copying, ranges of parts of the object completed with copying or
+// the whole payload of the regular object.
+func payloadSegments(info *data.ObjectInfo) []payloadSegment {
+	if len(info.Parts) != 0 {
+		segments := make([]payloadSegment, len(info.Parts))
+		for i, part := range info.Parts {
+			segments[i] = payloadSegment{oid: part.OID, size: uint64(part.Size)}
+		}
+		return segments
+	}
+
+	if completedParts, ok := info.Headers[UploadCompletedParts]; ok {
+		sizes, err := parseCompletedPartSizes(completedParts)
+		if err == nil && sumSizes(sizes) == uint64(info.Size) {
+			segments := make([]payloadSegment, len(sizes))
+			var off uint64
+			for i, size := range sizes {
+				segments[i] = payloadSegment{oid: info.ID, off: off, size: size}
+				off += size
+			}
+			return segments
+		}
+	}
+
+	return []payloadSegment{{oid: info.ID, size: uint64(info.Size)}}
+}
+
+// copyPayloadChunks reads chunks of the payload one by one.
+func (n *layer) copyPayloadChunks(ctx context.Context, w io.Writer, p getParams, chunks []payloadChunk) error {
+	for _, chunk := range chunks {
+		prm := p
+		prm.oid, prm.off, prm.ln = chunk.oid, chunk.off, chunk.ln
+
+		payload, err := n.initObjectPayloadReader(ctx, prm)
+		if err != nil {
+			return fmt.Errorf("init object payload reader: %w", err)
+		}
+		if _, err = io.Copy(w, payload); err != nil {
+			return fmt.Errorf("copy object payload: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyPayloadParallel reads chunks of the payload concurrently and writes them in order.
+func (n *layer) copyPayloadParallel(ctx context.Context, w io.Writer, p getParams, chunks []payloadChunk) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan chunkResult, len(chunks))
+	start := func(i int) {
+		results[i] = make(chan chunkResult, 1)
+		go func(res chan<- chunkResult, chunk payloadChunk) {
+			payload, err := n.readPayloadChunk(ctx, p, chunk)
+			res <- chunkResult{payload: payload, err: err}
+		}(results[i], chunks[i])
+	}
+
+	next := 0
+	for window := n.readAheadWindow(); next < len(chunks) && next < window; next++ {
+		start(next)
+	}
+
+	for i := range chunks {
+		res := <-results[i]
+		if res.err != nil {
+			return fmt.Errorf("read payload range %d-%d of %s: %w", chunks[i].off, chunks[i].off+chunks[i].ln-1, chunks[i].oid, res.err)
+		}
+
+		if _, err := w.Write(res.payload); err != nil {
+			return fmt.Errorf("copy object payload: %w", err)
+		}
+
+		if next < len(chunks) {
+			start(next)
+			next++
+		}
+	}
+
+	return nil
+}
+
+func (n *layer) readPayloadChunk(ctx context.Context, p getParams, chunk payloadChunk) ([]byte, error) {
+	prm := PrmObjectRead{
+		Container:    p.bktInfo.CID,
+		Object:       chunk.oid,
+		WithPayload:  true,
+		PayloadRange: [2]uint64{chunk.off, chunk.ln},
+	}
+
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, p.bktInfo.Owner)
+
+	res, err := n.neoFS.ReadObject(ctx, prm)
+	if err != nil {
+		return nil, n.transformNeofsError(ctx, err)
+	}
+	defer res.Payload.Close()
+
+	payload := make([]byte, chunk.ln)
+	if _, err = io.ReadFull(res.Payload, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// splitPayloadRange splits the payload range into chunks which don't cross segment
+// boundaries and don't exceed the chunk size.
+func splitPayloadRange(off, ln uint64, segments []payloadSegment, chunkSize uint64) []payloadChunk {
+	var (
+		chunks []payloadChunk
+		end    = off + ln
+		pos    uint64
+	)
+
+	for _, segment := range segments {
+		segmentEnd := pos + segment.size
+		start, stop := off, end
+		if start < pos {
+			start = pos
+		}
+		if stop > segmentEnd {
+			stop = segmentEnd
+		}
+
+		for start < stop {
+			size := stop - start
+			if size > chunkSize {
+				size = chunkSize
+			}
+			chunks = append(chunks, payloadChunk{oid: segment.oid, off: segment.off + start - pos, ln: size})
+			start += size
+		}
+
+		pos = segmentEnd
+	}
+
+	return chunks
+}
+
+// parseCompletedPartSizes returns sizes of the parts from S3-Completed-Parts header.
+func parseCompletedPartSizes(completedParts string) ([]uint64, error) {
+	partInfos := strings.Split(completedParts, ",")
+	sizes := make([]uint64, len(partInfos))
+	for i, partInfo := range partInfos {
+		// part number, part size, etag and optional additional checksum
+		fields := strings.Split(partInfo, "-")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid completed part '%s'", partInfo)
+		}
+
+		size, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of completed part '%s': %w", partInfo, err)
+		}
+		sizes[i] = size
+	}
+
+	return sizes, nil
+}
+
+func sumSizes(sizes []uint64) uint64 {
+	var sum uint64
+	for _, size := range sizes {
+		sum += size
+	}
+	return sum
+}
@@ -0,0 +1,110 @@
+package layer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPayloadRange(t *testing.T) {
+	obj, part1, part2, part3 := oidtest.ID(), oidtest.ID(), oidtest.ID(), oidtest.ID()
+
+	for _, tc := range []struct {
+		name     string
+		off, ln  uint64
+		segments []payloadSegment
+		expected []payloadChunk
+	}{
+		{
+			name:     "single chunk",
+			off:      5,
+			ln:       10,
+			segments: []payloadSegment{{oid: obj, size: 100}},
+			expected: []payloadChunk{{oid: obj, off: 5, ln: 10}},
+		},
+		{
+			name:     "chunks",
+			off:      0,
+			ln:       25,
+			segments: []payloadSegment{{oid: obj, size: 25}},
+			expected: []payloadChunk{{oid: obj, off: 0, ln: 10}, {oid: obj, off: 10, ln: 10}, {oid: obj, off: 20, ln: 5}},
+		},
+		{
+			name:     "part boundaries",
+			off:      0,
+			ln:       30,
+			segments: []payloadSegment{{oid: obj, size: 15}, {oid: obj, off: 15, size: 15}},
+			expected: []payloadChunk{{oid: obj, off: 0, ln: 10}, {oid: obj, off: 10, ln: 5}, {oid: obj, off: 15, ln: 10}, {oid: obj, off: 25, ln: 5}},
+		},
+		{
+			name:     "range inside parts",
+			off:      12,
+			ln:       10,
+			segments: []payloadSegment{{oid: part1, size: 15}, {oid: part2, size: 5}, {oid: part3, size: 10}},
+			expected: []payloadChunk{{oid: part1, off: 12, ln: 3}, {oid: part2, off: 0, ln: 5}, {oid: part3, off: 0, ln: 2}},
+		},
+		{
+			name:     "range in the last part",
+			off:      25,
+			ln:       5,
+			segments: []payloadSegment{{oid: part1, size: 15}, {oid: part2, size: 5}, {oid: part3, size: 10}},
+			expected: []payloadChunk{{oid: part3, off: 5, ln: 5}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, splitPayloadRange(tc.off, tc.ln, tc.segments, 10))
+		})
+	}
+}
+
+func TestParseCompletedPartSizes(t *testing.T) {
+	sizes, err := parseCompletedPartSizes("1-5242880-etag1,2-100-etag2-checksum")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5242880, 100}, sizes)
+
+	_, err = parseCompletedPartSizes("1-size-etag")
+	require.Error(t, err)
+
+	_, err = parseCompletedPartSizes("1-100")
+	require.Error(t, err)
+}
+
+func TestParallelRead(t *testing.T) {
+	tc := prepareContext(t)
+	tc.layer.(*layer).read = &ReadConfig{Concurrency: 3, ChunkSize: 10, BufferSize: 25}
+
+	content := make([]byte, 105)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	for _, header := range []map[string]string{
+		{},
+		{UploadCompletedParts: "1-50-etag1,2-55-etag2"},
+		{UploadCompletedParts: "1-50-etag1,2-50-etag2"},
+	} {
+		objInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  tc.obj,
+			Size:    int64(len(content)),
+			Reader:  bytes.NewReader(content),
+			Header:  header,
+		})
+		require.NoError(t, err)
+
+		_, payload := tc.getObject(tc.obj, "", false)
+		require.Equal(t, content, payload)
+
+		buf := bytes.NewBuffer(nil)
+		err = tc.layer.GetObject(tc.ctx, &GetObjectParams{
+			ObjectInfo: objInfo,
+			Writer:     buf,
+			BucketInfo: tc.bktInfo,
+			Range:      &RangeParams{Start: 7, End: 93},
+		})
+		require.NoError(t, err)
+		require.Equal(t, content[7:94], buf.Bytes())
+	}
+}
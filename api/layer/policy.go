@@ -0,0 +1,52 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+// GetBucketPolicy returns the original bucket policy document.
+// ErrNoSuchBucketPolicy is returned if the bucket has no policy.
+func (n *layer) GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error) {
+	policy := n.systemCache.GetPolicy(bucketPolicyCacheKey(bktInfo))
+	if policy == nil {
+		var err error
+		if policy, err = n.treeService.GetBucketPolicy(ctx, bktInfo.CID); err != nil {
+			if !errorsStd.Is(err, ErrNodeNotFound) {
+				return nil, err
+			}
+			policy = []byte{}
+		}
+
+		if err = n.systemCache.PutPolicy(bucketPolicyCacheKey(bktInfo), policy); err != nil {
+			n.log.Error("couldn't cache system object", zap.Error(err))
+		}
+	}
+
+	if len(policy) == 0 {
+		return nil, errors.GetAPIError(errors.ErrNoSuchBucketPolicy)
+	}
+
+	return policy, nil
+}
+
+// PutBucketPolicy saves the original bucket policy document.
+func (n *layer) PutBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo, policy []byte) error {
+	if err := n.treeService.PutBucketPolicy(ctx, bktInfo.CID, policy); err != nil {
+		return err
+	}
+
+	if err := n.systemCache.PutPolicy(bucketPolicyCacheKey(bktInfo), policy); err != nil {
+		n.log.Error("couldn't cache system object", zap.Error(err))
+	}
+
+	return nil
+}
+
+func bucketPolicyCacheKey(bktInfo *data.BucketInfo) string {
+	return ".policy." + bktInfo.CID.EncodeToString()
+}
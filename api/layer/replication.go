@@ -0,0 +1,142 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+// PutBucketReplicationParams stores PutBucketReplication request parameters.
+type PutBucketReplicationParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.ReplicationConfiguration
+}
+
+func (n *layer) PutBucketReplicationConfiguration(ctx context.Context, p *PutBucketReplicationParams) error {
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal replication configuration: %w", err)
+	}
+
+	sysName := p.BktInfo.ReplicationConfigurationObjectName()
+
+	prm := PrmObjectCreate{
+		Container: p.BktInfo.CID,
+		Creator:   p.BktInfo.Owner,
+		Payload:   bytes.NewReader(confXML),
+		Filename:  sysName,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketReplicationConfiguration(ctx, p.BktInfo.CID, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete replication configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	if err = n.systemCache.PutReplicationConfiguration(systemObjectKey(p.BktInfo, sysName), p.Configuration); err != nil {
+		n.log.Error("couldn't cache system object", zap.Error(err))
+	}
+
+	return nil
+}
+
+func (n *layer) GetBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error) {
+	systemCacheKey := systemObjectKey(bktInfo, bktInfo.ReplicationConfigurationObjectName())
+
+	if conf := n.systemCache.GetReplicationConfiguration(systemCacheKey); conf != nil {
+		return conf, nil
+	}
+
+	objID, err := n.treeService.GetBucketReplicationConfiguration(ctx, bktInfo.CID)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrReplicationConfigurationNotFoundError)
+		}
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &data.ReplicationConfiguration{}
+	if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+		return nil, fmt.Errorf("unmarshal replication configuration: %w", err)
+	}
+
+	if err = n.systemCache.PutReplicationConfiguration(systemCacheKey, conf); err != nil {
+		n.log.Warn("couldn't put system meta to objects cache",
+			zap.Stringer("bucket id", bktInfo.CID),
+			zap.Error(err))
+	}
+
+	return conf, nil
+}
+
+func (n *layer) DeleteBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error {
+	objID, err := n.treeService.DeleteBucketReplicationConfiguration(ctx, bktInfo.CID)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.systemCache.Delete(systemObjectKey(bktInfo, bktInfo.ReplicationConfigurationObjectName()))
+
+	return nil
+}
+
+// GetObjectReplicationStatus returns replication status of the object version.
+// Node version is requested from the tree service if it's nil.
+func (n *layer) GetObjectReplicationStatus(ctx context.Context, p *ObjectVersion, nodeVersion *data.NodeVersion) (string, error) {
+	if nodeVersion == nil {
+		var err error
+		if nodeVersion, err = n.getNodeVersion(ctx, p); err != nil {
+			return "", err
+		}
+	}
+
+	return nodeVersion.ReplicationStatus, nil
+}
+
+// PutObjectReplicationStatus sets replication status of the object version.
+func (n *layer) PutObjectReplicationStatus(ctx context.Context, p *ObjectVersion, status string) error {
+	nodeVersion, err := n.getNodeVersion(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if err = n.treeService.PutReplicationStatus(ctx, p.BktInfo.CID, nodeVersion, status); err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return errors.GetAPIError(errors.ErrNoSuchKey)
+		}
+		return fmt.Errorf("put replication status: %w", err)
+	}
+
+	return nil
+}
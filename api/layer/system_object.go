@@ -211,6 +211,10 @@ func (n *layer) PutBucketSettings(ctx context.Context, p *PutSettingsParams) err
 		n.log.Error("couldn't cache system object", zap.Error(err))
 	}
 
+	if n.usage != nil {
+		n.usage.setQuota(p.BktInfo.CID, p.Settings.Quota)
+	}
+
 	return nil
 }
 
@@ -11,6 +11,14 @@ import (
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 )
 
+const (
+	lifecycleNodeName    = "bucket-lifecycle"
+	replicationNodeName  = "bucket-replication"
+	websiteNodeName      = "bucket-website"
+	notificationNodeName = "bucket-notifications"
+	loggingNodeName      = "bucket-logging"
+)
+
 type TreeServiceMock struct {
 	settings   map[string]*data.BucketSettings
 	versions   map[string]map[string][]*data.NodeVersion
@@ -18,6 +26,7 @@ type TreeServiceMock struct {
 	locks      map[string]map[uint64]*data.LockInfo
 	multiparts map[string]map[string][]*data.MultipartInfo
 	parts      map[string]map[int]*data.PartInfo
+	policies   map[string][]byte
 }
 
 func (t *TreeServiceMock) GetObjectTaggingAndLock(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
@@ -56,6 +65,20 @@ func (t *TreeServiceMock) DeleteBucketTagging(ctx context.Context, cnrID cid.ID)
 	panic("implement me")
 }
 
+func (t *TreeServiceMock) GetBucketPolicy(_ context.Context, cnrID cid.ID) ([]byte, error) {
+	policy, ok := t.policies[cnrID.EncodeToString()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	return policy, nil
+}
+
+func (t *TreeServiceMock) PutBucketPolicy(_ context.Context, cnrID cid.ID, policy []byte) error {
+	t.policies[cnrID.EncodeToString()] = policy
+	return nil
+}
+
 func NewTreeService() *TreeServiceMock {
 	return &TreeServiceMock{
 		settings:   make(map[string]*data.BucketSettings),
@@ -64,6 +87,7 @@ func NewTreeService() *TreeServiceMock {
 		locks:      make(map[string]map[uint64]*data.LockInfo),
 		multiparts: make(map[string]map[string][]*data.MultipartInfo),
 		parts:      make(map[string]map[int]*data.PartInfo),
+		policies:   make(map[string][]byte),
 	}
 }
 
@@ -81,12 +105,29 @@ func (t *TreeServiceMock) GetSettingsNode(_ context.Context, id cid.ID) (*data.B
 	return settings, nil
 }
 
-func (t *TreeServiceMock) GetNotificationConfigurationNode(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetNotificationConfigurationNode(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, ok := t.system[cnrID.EncodeToString()][notificationNodeName]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return node.OID, nil
 }
 
-func (t *TreeServiceMock) PutNotificationConfigurationNode(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) PutNotificationConfigurationNode(_ context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	cnrSystemMap, ok := t.system[cnrID.EncodeToString()]
+	if !ok {
+		cnrSystemMap = make(map[string]*data.BaseNodeVersion)
+		t.system[cnrID.EncodeToString()] = cnrSystemMap
+	}
+
+	oldNode, ok := cnrSystemMap[notificationNodeName]
+	cnrSystemMap[notificationNodeName] = &data.BaseNodeVersion{OID: objID, FilePath: notificationNodeName}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return oldNode.OID, nil
 }
 
 func (t *TreeServiceMock) GetBucketCORS(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
@@ -101,6 +142,165 @@ func (t *TreeServiceMock) DeleteBucketCORS(ctx context.Context, cnrID cid.ID) (o
 	panic("implement me")
 }
 
+func (t *TreeServiceMock) GetBucketLifecycleConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, ok := t.system[cnrID.EncodeToString()][lifecycleNodeName]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) PutBucketLifecycleConfiguration(_ context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	cnrSystemMap, ok := t.system[cnrID.EncodeToString()]
+	if !ok {
+		cnrSystemMap = make(map[string]*data.BaseNodeVersion)
+		t.system[cnrID.EncodeToString()] = cnrSystemMap
+	}
+
+	oldNode, ok := cnrSystemMap[lifecycleNodeName]
+	cnrSystemMap[lifecycleNodeName] = &data.BaseNodeVersion{OID: objID, FilePath: lifecycleNodeName}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return oldNode.OID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketLifecycleConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	cnrSystemMap := t.system[cnrID.EncodeToString()]
+
+	node, ok := cnrSystemMap[lifecycleNodeName]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(cnrSystemMap, lifecycleNodeName)
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) GetBucketReplicationConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, ok := t.system[cnrID.EncodeToString()][replicationNodeName]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) PutBucketReplicationConfiguration(_ context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	cnrSystemMap, ok := t.system[cnrID.EncodeToString()]
+	if !ok {
+		cnrSystemMap = make(map[string]*data.BaseNodeVersion)
+		t.system[cnrID.EncodeToString()] = cnrSystemMap
+	}
+
+	oldNode, ok := cnrSystemMap[replicationNodeName]
+	cnrSystemMap[replicationNodeName] = &data.BaseNodeVersion{OID: objID, FilePath: replicationNodeName}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return oldNode.OID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketReplicationConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	cnrSystemMap := t.system[cnrID.EncodeToString()]
+
+	node, ok := cnrSystemMap[replicationNodeName]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(cnrSystemMap, replicationNodeName)
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) GetBucketWebsiteConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, ok := t.system[cnrID.EncodeToString()][websiteNodeName]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) PutBucketWebsiteConfiguration(_ context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	cnrSystemMap, ok := t.system[cnrID.EncodeToString()]
+	if !ok {
+		cnrSystemMap = make(map[string]*data.BaseNodeVersion)
+		t.system[cnrID.EncodeToString()] = cnrSystemMap
+	}
+
+	oldNode, ok := cnrSystemMap[websiteNodeName]
+	cnrSystemMap[websiteNodeName] = &data.BaseNodeVersion{OID: objID, FilePath: websiteNodeName}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return oldNode.OID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketWebsiteConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	cnrSystemMap := t.system[cnrID.EncodeToString()]
+
+	node, ok := cnrSystemMap[websiteNodeName]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(cnrSystemMap, websiteNodeName)
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) GetBucketLoggingConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, ok := t.system[cnrID.EncodeToString()][loggingNodeName]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) PutBucketLoggingConfiguration(_ context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	cnrSystemMap, ok := t.system[cnrID.EncodeToString()]
+	if !ok {
+		cnrSystemMap = make(map[string]*data.BaseNodeVersion)
+		t.system[cnrID.EncodeToString()] = cnrSystemMap
+	}
+
+	oldNode, ok := cnrSystemMap[loggingNodeName]
+	cnrSystemMap[loggingNodeName] = &data.BaseNodeVersion{OID: objID, FilePath: loggingNodeName}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return oldNode.OID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketLoggingConfiguration(_ context.Context, cnrID cid.ID) (oid.ID, error) {
+	cnrSystemMap := t.system[cnrID.EncodeToString()]
+
+	node, ok := cnrSystemMap[loggingNodeName]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(cnrSystemMap, loggingNodeName)
+
+	return node.OID, nil
+}
+
+func (t *TreeServiceMock) PutReplicationStatus(_ context.Context, cnrID cid.ID, objVersion *data.NodeVersion, status string) error {
+	for _, version := range t.versions[cnrID.EncodeToString()][objVersion.FilePath] {
+		if version.ID == objVersion.ID {
+			version.ReplicationStatus = status
+			return nil
+		}
+	}
+
+	return ErrNodeNotFound
+}
+
 func (t *TreeServiceMock) GetVersions(_ context.Context, cnrID cid.ID, objectName string) ([]*data.NodeVersion, error) {
 	cnrVersionsMap, ok := t.versions[cnrID.EncodeToString()]
 	if !ok {
@@ -191,6 +391,15 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, cnrID cid.ID, newVersion
 		return nil
 	}
 
+	// node IDs must be unique within the container to remove versions by ID
+	for _, versions := range cnrVersionsMap {
+		for _, version := range versions {
+			if version.ID >= newVersion.ID {
+				newVersion.ID = version.ID + 1
+			}
+		}
+	}
+
 	versions, ok := cnrVersionsMap[newVersion.FilePath]
 	if !ok {
 		cnrVersionsMap[newVersion.FilePath] = []*data.NodeVersion{newVersion}
@@ -202,7 +411,6 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, cnrID cid.ID, newVersion
 	})
 
 	if len(versions) != 0 {
-		newVersion.ID = versions[len(versions)-1].ID + 1
 		newVersion.Timestamp = versions[len(versions)-1].Timestamp + 1
 	}
 
@@ -274,8 +482,17 @@ func (t *TreeServiceMock) CreateMultipartUpload(_ context.Context, cnrID cid.ID,
 	return nil
 }
 
-func (t *TreeServiceMock) GetMultipartUploadsByPrefix(ctx context.Context, cnrID cid.ID, prefix string) ([]*data.MultipartInfo, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetMultipartUploadsByPrefix(_ context.Context, cnrID cid.ID, prefix string) ([]*data.MultipartInfo, error) {
+	cnrMultipartsMap := t.multiparts[cnrID.EncodeToString()]
+
+	var result []*data.MultipartInfo
+	for key, multiparts := range cnrMultipartsMap {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, multiparts...)
+		}
+	}
+
+	return result, nil
 }
 
 func (t *TreeServiceMock) GetMultipartUpload(_ context.Context, cnrID cid.ID, objectName, uploadID string) (*data.MultipartInfo, error) {
@@ -309,10 +526,14 @@ func (t *TreeServiceMock) AddPart(ctx context.Context, cnrID cid.ID, multipartNo
 		partsMap = make(map[int]*data.PartInfo)
 	}
 
+	oldPart, ok := partsMap[info.Number]
 	partsMap[info.Number] = info
 
 	t.parts[info.UploadID] = partsMap
-	return oid.ID{}, nil
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	return oldPart.OID, nil
 }
 
 func (t *TreeServiceMock) GetParts(_ context.Context, cnrID cid.ID, multipartNodeID uint64) ([]*data.PartInfo, error) {
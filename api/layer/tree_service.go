@@ -45,6 +45,69 @@ type TreeService interface {
 	// If object id to remove is not found returns ErrNoNodeToRemove error.
 	DeleteBucketCORS(ctx context.Context, cnrID cid.ID) (oid.ID, error)
 
+	// GetBucketLifecycleConfiguration gets an object id that corresponds to object with bucket lifecycle configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// PutBucketLifecycleConfiguration puts a node to a system tree and returns objectID of a previous lifecycle configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketLifecycleConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// GetBucketReplicationConfiguration gets an object id that corresponds to object with bucket replication configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// PutBucketReplicationConfiguration puts a node to a system tree and returns objectID of a previous replication configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketReplicationConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// GetBucketWebsiteConfiguration gets an object id that corresponds to object with bucket website configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// PutBucketWebsiteConfiguration puts a node to a system tree and returns objectID of a previous website configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketWebsiteConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// GetBucketLoggingConfiguration gets an object id that corresponds to object with bucket logging configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// PutBucketLoggingConfiguration puts a node to a system tree and returns objectID of a previous logging configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketLoggingConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error)
+
+	// PutReplicationStatus updates the version node with the replication status of the object version.
+	PutReplicationStatus(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion, status string) error
+
 	GetObjectTagging(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion) (map[string]string, error)
 	PutObjectTagging(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion, tagSet map[string]string) error
 	DeleteObjectTagging(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion) error
@@ -53,6 +116,12 @@ type TreeService interface {
 	PutBucketTagging(ctx context.Context, cnrID cid.ID, tagSet map[string]string) error
 	DeleteBucketTagging(ctx context.Context, cnrID cid.ID) error
 
+	// GetBucketPolicy returns the original bucket policy document.
+	//
+	// If tree node is not found returns ErrNodeNotFound error.
+	GetBucketPolicy(ctx context.Context, cnrID cid.ID) ([]byte, error)
+	PutBucketPolicy(ctx context.Context, cnrID cid.ID, policy []byte) error
+
 	GetVersions(ctx context.Context, cnrID cid.ID, objectName string) ([]*data.NodeVersion, error)
 	GetLatestVersion(ctx context.Context, cnrID cid.ID, objectName string) (*data.NodeVersion, error)
 	GetLatestVersionsByPrefix(ctx context.Context, cnrID cid.ID, prefix string) ([]*data.NodeVersion, error)
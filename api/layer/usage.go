@@ -0,0 +1,389 @@
+package layer
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DefaultUsageRefreshInterval is a default interval to recalculate usage counters of the buckets.
+const DefaultUsageRefreshInterval = time.Hour
+
+type (
+	// UsageTracker maintains usage counters of the buckets. Counters are calculated from the version
+	// nodes of the tree service when the bucket is written for the first time and are updated
+	// incrementally on every change made by the gateway. They're recalculated after the refresh
+	// interval to take into account changes made by other gateways.
+	UsageTracker struct {
+		refreshInterval time.Duration
+		// trackAll is set if usage of all buckets is required, e.g. for metrics,
+		// otherwise only buckets with quotas are tracked.
+		trackAll bool
+
+		mu      sync.RWMutex
+		buckets map[cid.ID]*bucketUsage
+
+		objectsDesc        *prometheus.Desc
+		bytesDesc          *prometheus.Desc
+		versionsDesc       *prometheus.Desc
+		multipartBytesDesc *prometheus.Desc
+		hardLimitDesc      *prometheus.Desc
+		softLimitDesc      *prometheus.Desc
+	}
+
+	bucketUsage struct {
+		name   string
+		usage  data.BucketUsage
+		quota  *data.BucketQuota
+		loaded time.Time
+	}
+
+	// BucketUsageInfo contains usage counters and quota of the bucket.
+	BucketUsageInfo struct {
+		Bucket    string `json:"bucket"`
+		Container string `json:"container"`
+		data.BucketUsage
+		Quota             *data.BucketQuota `json:"quota,omitempty"`
+		SoftLimitExceeded bool              `json:"soft_limit_exceeded,omitempty"`
+		Updated           time.Time         `json:"updated"`
+	}
+)
+
+// NewUsageTracker creates a tracker of bucket usage. If trackAll isn't set, usage of buckets
+// without quotas isn't calculated.
+func NewUsageTracker(refreshInterval time.Duration, trackAll bool) *UsageTracker {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultUsageRefreshInterval
+	}
+
+	labels := []string{"bucket"}
+	return &UsageTracker{
+		refreshInterval: refreshInterval,
+		trackAll:        trackAll,
+		buckets:         make(map[cid.ID]*bucketUsage),
+		objectsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "objects"),
+			"Number of objects in the bucket", labels, nil),
+		bytesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "bytes"),
+			"Total size of object versions in the bucket", labels, nil),
+		versionsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "versions"),
+			"Number of object versions in the bucket", labels, nil),
+		multipartBytesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "multipart_bytes"),
+			"Total size of uploaded parts of incomplete multipart uploads in the bucket", labels, nil),
+		hardLimitDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "quota_hard_limit_bytes"),
+			"Hard quota of the bucket", labels, nil),
+		softLimitDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "bucket", "quota_soft_limit_bytes"),
+			"Soft quota of the bucket", labels, nil),
+	}
+}
+
+// get returns usage of the bucket if it's calculated and isn't outdated.
+func (t *UsageTracker) get(cnrID cid.ID) (data.BucketUsage, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	b, ok := t.buckets[cnrID]
+	if !ok || time.Since(b.loaded) > t.refreshInterval {
+		return data.BucketUsage{}, false
+	}
+
+	return b.usage, true
+}
+
+func (t *UsageTracker) set(bktInfo *data.BucketInfo, usage data.BucketUsage, quota *data.BucketQuota) {
+	t.mu.Lock()
+	t.buckets[bktInfo.CID] = &bucketUsage{
+		name:   bktInfo.Name,
+		usage:  usage,
+		quota:  quota,
+		loaded: time.Now(),
+	}
+	t.mu.Unlock()
+}
+
+// tracked checks if usage of the bucket is calculated, so its changes must be counted.
+func (t *UsageTracker) tracked(cnrID cid.ID) bool {
+	t.mu.RLock()
+	_, ok := t.buckets[cnrID]
+	t.mu.RUnlock()
+	return ok
+}
+
+func (t *UsageTracker) add(cnrID cid.ID, delta data.BucketUsage) {
+	t.mu.Lock()
+	if b, ok := t.buckets[cnrID]; ok {
+		b.usage.Add(delta)
+	}
+	t.mu.Unlock()
+}
+
+func (t *UsageTracker) setQuota(cnrID cid.ID, quota *data.BucketQuota) {
+	t.mu.Lock()
+	if b, ok := t.buckets[cnrID]; ok {
+		b.quota = quota
+	}
+	t.mu.Unlock()
+}
+
+// invalidate makes usage of the bucket be recalculated on the next write.
+func (t *UsageTracker) invalidate(cnrID cid.ID) {
+	t.mu.Lock()
+	if b, ok := t.buckets[cnrID]; ok {
+		b.loaded = time.Time{}
+	}
+	t.mu.Unlock()
+}
+
+func (t *UsageTracker) remove(cnrID cid.ID) {
+	t.mu.Lock()
+	delete(t.buckets, cnrID)
+	t.mu.Unlock()
+}
+
+// Buckets returns usage of the buckets known to the tracker sorted by bucket name.
+func (t *UsageTracker) Buckets() []*BucketUsageInfo {
+	t.mu.RLock()
+	res := make([]*BucketUsageInfo, 0, len(t.buckets))
+	for cnrID, b := range t.buckets {
+		res = append(res, &BucketUsageInfo{
+			Bucket:            b.name,
+			Container:         cnrID.EncodeToString(),
+			BucketUsage:       b.usage,
+			Quota:             b.quota,
+			SoftLimitExceeded: b.quota != nil && b.quota.SoftLimit != 0 && b.usage.Size() > int64(b.quota.SoftLimit),
+			Updated:           b.loaded,
+		})
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Bucket < res[j].Bucket })
+	return res
+}
+
+// Handler returns the handler of the admin service to show usage of the buckets.
+// The bucket query parameter filters the buckets by name.
+func (t *UsageTracker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/usage", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		buckets := t.Buckets()
+		if name := r.URL.Query().Get("bucket"); name != "" {
+			filtered := buckets[:0]
+			for _, b := range buckets {
+				if b.Bucket == name {
+					filtered = append(filtered, b)
+				}
+			}
+			if len(filtered) == 0 {
+				http.Error(w, "usage of the bucket is unknown", http.StatusNotFound)
+				return
+			}
+			buckets = filtered
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(buckets)
+	})
+
+	return mux
+}
+
+// Describe implements prometheus.Collector.
+func (t *UsageTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.objectsDesc
+	ch <- t.bytesDesc
+	ch <- t.versionsDesc
+	ch <- t.multipartBytesDesc
+	ch <- t.hardLimitDesc
+	ch <- t.softLimitDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *UsageTracker) Collect(ch chan<- prometheus.Metric) {
+	for _, b := range t.Buckets() {
+		ch <- prometheus.MustNewConstMetric(t.objectsDesc, prometheus.GaugeValue, float64(b.Objects), b.Bucket)
+		ch <- prometheus.MustNewConstMetric(t.bytesDesc, prometheus.GaugeValue, float64(b.Bytes), b.Bucket)
+		ch <- prometheus.MustNewConstMetric(t.versionsDesc, prometheus.GaugeValue, float64(b.Versions), b.Bucket)
+		ch <- prometheus.MustNewConstMetric(t.multipartBytesDesc, prometheus.GaugeValue, float64(b.MultipartBytes), b.Bucket)
+		if b.Quota != nil {
+			ch <- prometheus.MustNewConstMetric(t.hardLimitDesc, prometheus.GaugeValue, float64(b.Quota.HardLimit), b.Bucket)
+			ch <- prometheus.MustNewConstMetric(t.softLimitDesc, prometheus.GaugeValue, float64(b.Quota.SoftLimit), b.Bucket)
+		}
+	}
+}
+
+func (n *layer) IsUsageEnabled() bool {
+	return n.usage != nil
+}
+
+func (n *layer) bucketUsage(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings) (data.BucketUsage, error) {
+	if usage, ok := n.usage.get(bktInfo.CID); ok {
+		return usage, nil
+	}
+
+	usage, err := n.calculateBucketUsage(ctx, bktInfo)
+	if err != nil {
+		return data.BucketUsage{}, err
+	}
+
+	n.usage.set(bktInfo, usage, settings.Quota)
+	return usage, nil
+}
+
+// calculateBucketUsage walks through all version nodes and multipart uploads of the bucket.
+func (n *layer) calculateBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (data.BucketUsage, error) {
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo.CID, "")
+	if err != nil && !stderrors.Is(err, ErrNodeNotFound) {
+		return data.BucketUsage{}, err
+	}
+
+	usage := versionsUsage(versions)
+
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo.CID, "")
+	if err != nil && !stderrors.Is(err, ErrNodeNotFound) {
+		return data.BucketUsage{}, err
+	}
+
+	for _, upload := range uploads {
+		parts, err := n.treeService.GetParts(ctx, bktInfo.CID, upload.ID)
+		if err != nil {
+			return data.BucketUsage{}, err
+		}
+		usage.MultipartBytes += partsSize(parts)
+	}
+
+	return usage, nil
+}
+
+// versionsUsage calculates usage of the object versions.
+func versionsUsage(versions []*data.NodeVersion) data.BucketUsage {
+	var usage data.BucketUsage
+	latest := make(map[string]*data.NodeVersion)
+
+	for _, version := range versions {
+		if version.DeleteMarker == nil {
+			usage.Versions++
+			usage.Bytes += version.Size
+		}
+
+		l, ok := latest[version.FilePath]
+		if !ok || l.Timestamp < version.Timestamp || l.Timestamp == version.Timestamp && l.ID < version.ID {
+			latest[version.FilePath] = version
+		}
+	}
+
+	for _, version := range latest {
+		if version.DeleteMarker == nil {
+			usage.Objects++
+		}
+	}
+
+	return usage
+}
+
+func partsSize(parts []*data.PartInfo) int64 {
+	var size int64
+	for _, part := range parts {
+		size += part.Size
+	}
+	return size
+}
+
+// checkQuota checks if the bucket has room for the payload of the specified size.
+// The reclaimed size is freed by the write, e.g. parts of the completed multipart upload.
+func (n *layer) checkQuota(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, size, reclaimed int64) error {
+	if n.usage == nil {
+		return nil
+	}
+
+	if settings.Quota == nil && !n.usage.trackAll {
+		// the quota may be removed, so changes of the bucket aren't tracked anymore
+		n.usage.remove(bktInfo.CID)
+		return nil
+	}
+
+	// usage of the bucket is calculated on the first write, so its changes are tracked since then
+	usage, err := n.bucketUsage(ctx, bktInfo, settings)
+	if err != nil {
+		return err
+	}
+	n.usage.setQuota(bktInfo.CID, settings.Quota)
+
+	if settings.Quota == nil {
+		return nil
+	}
+
+	total := usage.Size() + size - reclaimed
+	if settings.Quota.HardLimit != 0 && total > int64(settings.Quota.HardLimit) {
+		return errors.GetAPIError(errors.ErrQuotaExceeded)
+	}
+
+	if settings.Quota.SoftLimit != 0 && total > int64(settings.Quota.SoftLimit) {
+		n.log.Warn("bucket soft quota is exceeded", zap.String("bucket", bktInfo.Name),
+			zap.Uint64("soft_limit", settings.Quota.SoftLimit), zap.Int64("size", total))
+	}
+
+	return nil
+}
+
+// trackObjectUsage remembers usage of the object versions before they're changed. The returned
+// function must be called after the change to update counters of the bucket by the difference.
+func (n *layer) trackObjectUsage(ctx context.Context, bktInfo *data.BucketInfo, objectName string) func() {
+	if n.usage == nil || !n.usage.tracked(bktInfo.CID) {
+		return func() {}
+	}
+
+	before, err := n.objectUsage(ctx, bktInfo, objectName)
+	if err != nil {
+		n.log.Warn("couldn't get object usage", zap.String("bucket", bktInfo.Name),
+			zap.String("object", objectName), zap.Error(err))
+		n.usage.invalidate(bktInfo.CID)
+		return func() {}
+	}
+
+	return func() {
+		after, err := n.objectUsage(ctx, bktInfo, objectName)
+		if err != nil {
+			n.log.Warn("couldn't get object usage", zap.String("bucket", bktInfo.Name),
+				zap.String("object", objectName), zap.Error(err))
+			n.usage.invalidate(bktInfo.CID)
+			return
+		}
+		n.usage.add(bktInfo.CID, after.Sub(before))
+	}
+}
+
+func (n *layer) objectUsage(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (data.BucketUsage, error) {
+	versions, err := n.treeService.GetVersions(ctx, bktInfo.CID, objectName)
+	if err != nil && !stderrors.Is(err, ErrNodeNotFound) {
+		return data.BucketUsage{}, err
+	}
+
+	return versionsUsage(versions), nil
+}
+
+// updateMultipartUsage changes the size of incomplete multipart uploads of the bucket.
+func (n *layer) updateMultipartUsage(bktInfo *data.BucketInfo, delta int64) {
+	if n.usage != nil {
+		n.usage.add(bktInfo.CID, data.BucketUsage{MultipartBytes: delta})
+	}
+}
@@ -0,0 +1,124 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionsUsage(t *testing.T) {
+	versions := []*data.NodeVersion{
+		{BaseNodeVersion: data.BaseNodeVersion{ID: 1, Timestamp: 1, FilePath: "a", Size: 10}},
+		{BaseNodeVersion: data.BaseNodeVersion{ID: 2, Timestamp: 2, FilePath: "a", Size: 20}},
+		{BaseNodeVersion: data.BaseNodeVersion{ID: 3, Timestamp: 1, FilePath: "b", Size: 5}},
+		{BaseNodeVersion: data.BaseNodeVersion{ID: 4, Timestamp: 2, FilePath: "b"}, DeleteMarker: &data.DeleteMarkerInfo{}},
+	}
+
+	require.Equal(t, data.BucketUsage{Objects: 1, Bytes: 35, Versions: 3}, versionsUsage(versions))
+}
+
+func TestBucketUsage(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+	n.usage = NewUsageTracker(time.Hour, true)
+
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	_, ok := n.usage.get(tc.bktInfo.CID)
+	require.False(t, ok)
+
+	// usage is calculated on the first write
+	tc.putObject([]byte("content"))
+	usage, ok := n.usage.get(tc.bktInfo.CID)
+	require.True(t, ok)
+	require.Equal(t, data.BucketUsage{Objects: 1, Bytes: 7, Versions: 1}, usage)
+
+	t.Run("recalculation", func(t *testing.T) {
+		n.usage.invalidate(tc.bktInfo.CID)
+		usage, err = n.bucketUsage(tc.ctx, tc.bktInfo, settings)
+		require.NoError(t, err)
+		require.Equal(t, data.BucketUsage{Objects: 1, Bytes: 7, Versions: 1}, usage)
+	})
+
+	tc.putObject([]byte("new content"))
+	usage, _ = n.usage.get(tc.bktInfo.CID)
+	require.Equal(t, data.BucketUsage{Objects: 1, Bytes: 18, Versions: 2}, usage)
+
+	tc.deleteObject(tc.obj, "", settings)
+	usage, _ = n.usage.get(tc.bktInfo.CID)
+	require.Equal(t, data.BucketUsage{Objects: 0, Bytes: 18, Versions: 2}, usage)
+
+	t.Run("multipart upload", func(t *testing.T) {
+		info := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: "multipart"}
+		require.NoError(t, tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: info, Header: make(map[string]string)}))
+
+		_, err = tc.layer.UploadPart(tc.ctx, &UploadPartParams{Info: info, PartNumber: 1, Size: 4, Reader: bytes.NewReader([]byte("part"))})
+		require.NoError(t, err)
+		usage, _ = n.usage.get(tc.bktInfo.CID)
+		require.EqualValues(t, 4, usage.MultipartBytes)
+
+		require.NoError(t, tc.layer.AbortMultipartUpload(tc.ctx, info))
+		usage, _ = n.usage.get(tc.bktInfo.CID)
+		require.EqualValues(t, 0, usage.MultipartBytes)
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		settings.Quota = &data.BucketQuota{HardLimit: 30, SoftLimit: 20}
+		err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+		require.NoError(t, err)
+
+		tc.putObject([]byte("content"))
+
+		_, err = tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  tc.obj,
+			Size:    10,
+			Reader:  bytes.NewReader([]byte("0123456789")),
+			Header:  make(map[string]string),
+		})
+		require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded), err)
+
+		buckets := n.usage.Buckets()
+		require.Len(t, buckets, 1)
+		require.Equal(t, tc.bktInfo.Name, buckets[0].Bucket)
+		require.Equal(t, settings.Quota, buckets[0].Quota)
+		require.True(t, buckets[0].SoftLimitExceeded)
+		require.EqualValues(t, 25, buckets[0].Size())
+	})
+}
+
+func TestBucketUsageQuotaOnly(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+	n.usage = NewUsageTracker(time.Hour, false)
+
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	// usage of the bucket without quota isn't calculated
+	tc.putObject([]byte("content"))
+	require.False(t, n.usage.tracked(tc.bktInfo.CID))
+
+	settings.Quota = &data.BucketQuota{HardLimit: 100}
+	err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	tc.putObject([]byte("new content"))
+	usage, ok := n.usage.get(tc.bktInfo.CID)
+	require.True(t, ok)
+	require.Equal(t, data.BucketUsage{Objects: 1, Bytes: 11, Versions: 1}, usage)
+
+	settings.Quota = nil
+	err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	tc.putObject([]byte("content"))
+	require.False(t, n.usage.tracked(tc.bktInfo.CID))
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 )
@@ -68,6 +69,17 @@ func userHeaders(attrs []object.Attribute) map[string]string {
 	return result
 }
 
+// fillFromNodeVersion sets fields of the object info which are stored in the version node only.
+func fillFromNodeVersion(objInfo *data.ObjectInfo, node *data.NodeVersion) {
+	objInfo.Checksum = node.Checksum
+	if len(node.Parts) != 0 {
+		// payload of the object is stored in the parts
+		objInfo.Parts = node.Parts
+		objInfo.Size = node.Size
+		objInfo.HashSum = node.ETag
+	}
+}
+
 func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectInfo {
 	var (
 		mimeType string
@@ -87,6 +99,20 @@ func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectI
 		delete(headers, object.AttributeTimestamp)
 	}
 
+	encInfo, err := encryption.ObjectEncryptionFromAttributes(headers)
+	if err != nil {
+		// keep the object encrypted to not return its payload as is
+		encInfo = encryption.ObjectEncryption{Algorithm: headers[encryption.AttributeEncryptionAlgorithm]}
+	}
+	for _, key := range encryption.Attributes {
+		delete(headers, key)
+	}
+
+	size := int64(meta.PayloadSize())
+	if encInfo.Enabled() {
+		size = int64(encInfo.DecryptedSize)
+	}
+
 	objID, _ := meta.ID()
 	payloadChecksum, _ := meta.PayloadChecksum()
 	return &data.ObjectInfo{
@@ -100,8 +126,10 @@ func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectI
 		ContentType: mimeType,
 		Headers:     headers,
 		Owner:       *meta.OwnerID(),
-		Size:        int64(meta.PayloadSize()),
+		Size:        size,
 		HashSum:     hex.EncodeToString(payloadChecksum.Value()),
+
+		EncryptionInfo: encInfo,
 	}
 }
 
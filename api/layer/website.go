@@ -0,0 +1,112 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+// PutBucketWebsiteParams stores PutBucketWebsite request parameters.
+type PutBucketWebsiteParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.WebsiteConfiguration
+}
+
+func (n *layer) PutBucketWebsiteConfiguration(ctx context.Context, p *PutBucketWebsiteParams) error {
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal website configuration: %w", err)
+	}
+
+	sysName := p.BktInfo.WebsiteConfigurationObjectName()
+
+	prm := PrmObjectCreate{
+		Container: p.BktInfo.CID,
+		Creator:   p.BktInfo.Owner,
+		Payload:   bytes.NewReader(confXML),
+		Filename:  sysName,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketWebsiteConfiguration(ctx, p.BktInfo.CID, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete website configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	if err = n.systemCache.PutWebsiteConfiguration(systemObjectKey(p.BktInfo, sysName), p.Configuration); err != nil {
+		n.log.Error("couldn't cache system object", zap.Error(err))
+	}
+
+	return nil
+}
+
+func (n *layer) GetBucketWebsiteConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.WebsiteConfiguration, error) {
+	systemCacheKey := systemObjectKey(bktInfo, bktInfo.WebsiteConfigurationObjectName())
+
+	if conf := n.systemCache.GetWebsiteConfiguration(systemCacheKey); conf != nil {
+		return conf, nil
+	}
+
+	objID, err := n.treeService.GetBucketWebsiteConfiguration(ctx, bktInfo.CID)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrNoSuchWebsiteConfiguration)
+		}
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &data.WebsiteConfiguration{}
+	if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+		return nil, fmt.Errorf("unmarshal website configuration: %w", err)
+	}
+
+	if err = n.systemCache.PutWebsiteConfiguration(systemCacheKey, conf); err != nil {
+		n.log.Warn("couldn't put system meta to objects cache",
+			zap.Stringer("bucket id", bktInfo.CID),
+			zap.Error(err))
+	}
+
+	return conf, nil
+}
+
+func (n *layer) DeleteBucketWebsiteConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error {
+	objID, err := n.treeService.DeleteBucketWebsiteConfiguration(ctx, bktInfo.CID)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.systemCache.Delete(systemObjectKey(bktInfo, bktInfo.WebsiteConfigurationObjectName()))
+
+	return nil
+}
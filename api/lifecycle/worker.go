@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is a default interval between two lifecycle sweeps.
+const DefaultInterval = time.Hour
+
+type (
+	// Config contains parameters of the lifecycle worker.
+	Config struct {
+		// Interval between two sweeps.
+		Interval time.Duration
+		// AccessKeys are access key IDs which credentials are used to process
+		// buckets of their owners.
+		AccessKeys []string
+		// Registry is an optional access key registry to resolve AccessKeys
+		// which aren't access box addresses.
+		Registry *tokens.Registry
+		// Events is an optional bus which the expiration events are published to
+		// by the object layer, the worker declares them as produced.
+		Events *events.Bus
+	}
+
+	// Worker periodically applies lifecycle configurations to buckets.
+	// Expired object versions are deleted or replaced by delete markers,
+	// expired multipart uploads are aborted.
+	Worker struct {
+		log        *zap.Logger
+		obj        layer.Client
+		creds      tokens.Credentials
+		interval   time.Duration
+		accessKeys []string
+		registry   *tokens.Registry
+	}
+)
+
+// NewWorker creates a lifecycle worker. Buckets are listed and processed
+// on behalf of owners of the configured access keys.
+func NewWorker(log *zap.Logger, obj layer.Client, creds tokens.Credentials, cfg *Config) (*Worker, error) {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	for _, accessKeyID := range cfg.AccessKeys {
+		if err := tokens.CheckAccessKey(cfg.Registry, accessKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Events != nil {
+		cfg.Events.RegisterProducer(events.LifecycleExpirationDelete, events.LifecycleExpirationDeleteMarkerCreated)
+	}
+
+	return &Worker{
+		log:        log,
+		obj:        obj,
+		creds:      creds,
+		interval:   interval,
+		accessKeys: cfg.AccessKeys,
+		registry:   cfg.Registry,
+	}, nil
+}
+
+// Run starts sweeping buckets every interval until the context is done.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("lifecycle worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.sweep(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			w.log.Info("lifecycle worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) sweep(ctx context.Context, now time.Time) {
+	for _, accessKeyID := range w.accessKeys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		addr, err := tokens.ResolveAccessKey(ctx, w.registry, accessKeyID)
+		if err != nil {
+			w.log.Error("couldn't resolve access key id", zap.String("access_key_id", accessKeyID), zap.Error(err))
+			continue
+		}
+
+		box, err := w.creds.GetBox(ctx, addr)
+		if err != nil {
+			w.log.Error("couldn't get access box", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		boxCtx := context.WithValue(ctx, api.BoxData, box)
+
+		buckets, err := w.obj.ListBuckets(boxCtx)
+		if err != nil {
+			w.log.Error("couldn't list buckets", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+
+		for _, bktInfo := range buckets {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err = w.obj.ApplyBucketLifecycle(boxCtx, bktInfo, now); err != nil {
+				w.log.Error("couldn't apply bucket lifecycle", zap.String("bucket", bktInfo.Name),
+					zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+			}
+		}
+	}
+}
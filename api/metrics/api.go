@@ -222,6 +222,13 @@ func (w *writeCounter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+// Flush -- calls the underlying Flush.
+func (w *writeCounter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func (r *readCounter) Read(p []byte) (int, error) {
 	n, err := r.ReadCloser.Read(p)
 	atomic.AddUint64(&r.countBytes, uint64(n))
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type outboxAdminResponse struct {
+	Events []*OutboxEvent `json:"events,omitempty"`
+	Count  int            `json:"count"`
+}
+
+// OutboxHandler returns an HTTP handler to inspect and manage stuck events in the outbox:
+//
+//	GET  /outbox/events[?target=<arn>]         lists events without payloads;
+//	POST /outbox/replay?target=<arn>[&id=<id>] resets attempts and delivers events immediately;
+//	POST /outbox/drop?target=<arn>[&id=<id>]   removes events without delivery.
+//
+// All events of the target are replayed or dropped if the id isn't set.
+func (c *Controller) OutboxHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/outbox/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		events, err := c.outbox.Events(r.URL.Query().Get("target"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeOutboxResponse(w, &outboxAdminResponse{Events: events, Count: len(events)})
+	})
+	mux.HandleFunc("/outbox/replay", c.outboxUpdateHandler(c.ReplayEvents))
+	mux.HandleFunc("/outbox/drop", c.outboxUpdateHandler(c.DropEvents))
+
+	return mux
+}
+
+func (c *Controller) outboxUpdateHandler(f func(target string, id uint64) (int, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		target := query.Get("target")
+		if target == "" {
+			http.Error(w, "target is required", http.StatusBadRequest)
+			return
+		}
+
+		var id uint64
+		if idStr := query.Get("id"); idStr != "" {
+			var err error
+			if id, err = strconv.ParseUint(idStr, 10, 64); err != nil || id == 0 {
+				http.Error(w, "invalid id", http.StatusBadRequest)
+				return
+			}
+		}
+
+		n, err := f(target, id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeOutboxResponse(w, &outboxAdminResponse{Count: n})
+	}
+}
+
+func writeOutboxResponse(w http.ResponseWriter, resp *outboxAdminResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
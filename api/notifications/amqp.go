@@ -0,0 +1,154 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type (
+	amqpTarget struct {
+		arn        string
+		url        string
+		config     amqp.Config
+		exchange   string
+		routingKey string
+		timeout    time.Duration
+
+		mu       sync.Mutex
+		conn     *amqp.Connection
+		channel  *amqp.Channel
+		confirms chan amqp.Confirmation
+	}
+)
+
+// NewAMQPTarget creates a target which publishes events to the AMQP exchange with the routing key.
+// The channel is in publisher confirms mode, so an event is sent when the broker acknowledges it.
+func NewAMQPTarget(arn string, p *TargetOptions) (Target, error) {
+	u, err := url.Parse(p.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amqp url: %w", err)
+	}
+
+	config := amqp.Config{Dial: amqp.DefaultDial(p.Timeout)}
+
+	switch u.Scheme {
+	case "amqp":
+		if p.hasTLSFiles() {
+			return nil, fmt.Errorf("tls options require amqps url scheme")
+		}
+	case "amqps":
+		if config.TLSClientConfig, err = p.tlsConfig(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported amqp url scheme '%s'", u.Scheme)
+	}
+
+	return &amqpTarget{
+		arn:        arn,
+		url:        p.Endpoint,
+		config:     config,
+		exchange:   p.Exchange,
+		routingKey: p.Topic,
+		timeout:    p.Timeout,
+	}, nil
+}
+
+func (t *amqpTarget) ARN() string {
+	return t.arn
+}
+
+func (t *amqpTarget) Send(ctx context.Context, msg []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.publish(ctx, msg)
+	if err != nil && t.conn != nil {
+		// the connection could be closed by the broker, so retry once with a new one
+		t.reset()
+		if err = t.publish(ctx, msg); err != nil {
+			t.reset()
+		}
+	}
+
+	return err
+}
+
+func (t *amqpTarget) publish(ctx context.Context, msg []byte) error {
+	if t.conn == nil {
+		if err := t.connect(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	err := t.channel.PublishWithContext(ctx, t.exchange, t.routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         msg,
+	})
+	if err != nil {
+		return fmt.Errorf("publish to amqp: %w", err)
+	}
+
+	select {
+	case confirm, ok := <-t.confirms:
+		if !ok {
+			return fmt.Errorf("amqp channel is closed")
+		}
+		if !confirm.Ack {
+			return fmt.Errorf("amqp broker rejected the message")
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for amqp confirmation: %w", ctx.Err())
+	}
+}
+
+func (t *amqpTarget) connect() error {
+	conn, err := amqp.DialConfig(t.url, t.config)
+	if err != nil {
+		return fmt.Errorf("connect to amqp broker: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("open amqp channel: %w", err)
+	}
+
+	if err = channel.Confirm(false); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("enable amqp publisher confirms: %w", err)
+	}
+
+	t.conn = conn
+	t.channel = channel
+	t.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	return nil
+}
+
+// reset closes the connection, so a new one is opened on the next send.
+// Confirmations of the previous channel are dropped with it.
+func (t *amqpTarget) reset() {
+	if t.conn != nil {
+		_ = t.conn.Close()
+	}
+	t.conn, t.channel, t.confirms = nil, nil, nil
+}
+
+func (t *amqpTarget) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.reset()
+	return nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"go.uber.org/zap"
@@ -25,7 +27,19 @@ const (
 )
 
 type (
+	// Options contains configuration of the notification controller.
 	Options struct {
+		// NATS is an optional default NATS JetStream connection. Notification configurations
+		// with an ARN which isn't the ARN of a target are sent to the JetStream subject named as the ARN.
+		NATS    *NATSOptions
+		Targets []*TargetOptions
+		// Outbox is an optional persistent queue of events. If it's set, events are stored
+		// in the outbox and delivered to targets in the background.
+		Outbox *OutboxOptions
+	}
+
+	// NATSOptions contains parameters of a NATS JetStream connection.
+	NATSOptions struct {
 		URL                       string
 		TLSCertFilepath           string
 		TLSAuthPrivateKeyFilePath string
@@ -33,12 +47,29 @@ type (
 		RootCAFiles               []string
 	}
 
+	// Controller sends events to the notification targets addressed by ARN.
 	Controller struct {
-		logger              *zap.Logger
-		taskQueueConnection *nats.Conn
-		jsClient            nats.JetStreamContext
-		handlers            map[string]Stream
-		mu                  sync.RWMutex
+		logger   *zap.Logger
+		nats     *natsTarget
+		targets  map[string]Target
+		handlers map[string]Stream
+		mu       sync.RWMutex
+
+		outbox *Outbox
+		// dispatchCtx is set when dispatching is started, wakes contain
+		// channels to notify dispatchers about new events of the targets.
+		dispatchCtx context.Context
+		wakes       map[string]chan struct{}
+		dmu         sync.Mutex
+	}
+
+	// Target is a sink of notification events.
+	Target interface {
+		// ARN returns the ARN which is used in bucket notification configurations to address the target.
+		ARN() string
+		// Send delivers the event to the target.
+		Send(ctx context.Context, msg []byte) error
+		Close() error
 	}
 
 	Stream struct {
@@ -101,50 +132,101 @@ type (
 	}
 )
 
+// NewController connects to the default NATS server and creates the targets from the options.
 func NewController(p *Options, l *zap.Logger) (*Controller, error) {
-	ncopts := []nats.Option{
-		nats.Timeout(p.Timeout),
+	c := &Controller{
+		logger:   l,
+		targets:  make(map[string]Target, len(p.Targets)),
+		handlers: make(map[string]Stream),
+		wakes:    make(map[string]chan struct{}),
 	}
 
-	if len(p.TLSCertFilepath) != 0 && len(p.TLSAuthPrivateKeyFilePath) != 0 {
-		ncopts = append(ncopts, nats.ClientCert(p.TLSCertFilepath, p.TLSAuthPrivateKeyFilePath))
+	if p.Outbox != nil {
+		outbox, err := NewOutbox(p.Outbox)
+		if err != nil {
+			return nil, err
+		}
+		c.outbox = outbox
 	}
-	if len(p.RootCAFiles) != 0 {
-		ncopts = append(ncopts, nats.RootCAs(p.RootCAFiles...))
+
+	if p.NATS != nil {
+		nt, err := newNATSTarget("", p.NATS, "")
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.nats = nt
 	}
 
-	nc, err := nats.Connect(p.URL, ncopts...)
-	if err != nil {
-		return nil, fmt.Errorf("connect to nats: %w", err)
+	for _, opts := range p.Targets {
+		t, err := NewTarget(opts)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("create target '%s': %w", opts.Name, err)
+		}
+		if err = c.AddTarget(t); err != nil {
+			c.Close()
+			return nil, err
+		}
 	}
 
-	js, err := nc.JetStream()
-	if err != nil {
-		return nil, fmt.Errorf("get jet stream: %w", err)
+	return c, nil
+}
+
+// AddTarget registers the target in the controller.
+func (c *Controller) AddTarget(t Target) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.targets[t.ARN()]; ok {
+		return fmt.Errorf("target '%s' is already registered", t.ARN())
 	}
+	c.targets[t.ARN()] = t
 
-	return &Controller{
-		logger:              l,
-		taskQueueConnection: nc,
-		jsClient:            js,
-		handlers:            make(map[string]Stream),
-	}, nil
+	return nil
+}
+
+// Close closes connections to all targets.
+func (c *Controller) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.nats != nil {
+		if err := c.nats.Close(); err != nil {
+			c.logger.Error("couldn't close nats connection", zap.Error(err))
+		}
+	}
+	for arn, t := range c.targets {
+		if err := t.Close(); err != nil {
+			c.logger.Error("couldn't close notification target", zap.String("arn", arn), zap.Error(err))
+		}
+	}
+	if c.outbox != nil {
+		if err := c.outbox.Close(); err != nil {
+			c.logger.Error("couldn't close notification outbox", zap.Error(err))
+		}
+	}
 }
 
 func (c *Controller) Subscribe(ctx context.Context, topic string, handler layer.MsgHandler) error {
+	if c.nats == nil {
+		return fmt.Errorf("nats isn't configured")
+	}
+
 	ch := make(chan *nats.Msg, 1)
 
 	c.mu.RLock()
-	if _, ok := c.handlers[topic]; ok {
+	_, ok := c.handlers[topic]
+	c.mu.RUnlock()
+	if ok {
 		return fmt.Errorf("already subscribed to topic '%s'", topic)
 	}
-	c.mu.RUnlock()
 
-	if _, err := c.jsClient.AddStream(&nats.StreamConfig{Name: topic}); err != nil {
+	if _, err := c.nats.js.AddStream(&nats.StreamConfig{Name: topic}); err != nil {
 		return fmt.Errorf("add stream: %w", err)
 	}
 
-	if _, err := c.jsClient.ChanSubscribe(topic, ch); err != nil {
+	if _, err := c.nats.js.ChanSubscribe(topic, ch); err != nil {
 		return fmt.Errorf("could not subscribe: %w", err)
 	}
 
@@ -180,7 +262,10 @@ func (c *Controller) Listen(ctx context.Context) {
 	}
 }
 
+// SendNotifications sends the event to the topics. If the outbox is used, the method returns
+// when the events are persisted in the outbox, an error is returned if any of them isn't.
 func (c *Controller) SendNotifications(topics map[string]string, p *handler.SendNotificationParams) error {
+	var res error
 	event := prepareEvent(p)
 
 	for id, topic := range topics {
@@ -188,13 +273,21 @@ func (c *Controller) SendNotifications(topics map[string]string, p *handler.Send
 		msg, err := json.Marshal(event)
 		if err != nil {
 			c.logger.Error("couldn't marshal an event", zap.String("subject", topic), zap.Error(err))
+			continue
 		}
-		if err = c.publish(topic, msg); err != nil {
+
+		if c.outbox != nil {
+			if _, err = c.outbox.Put(topic, msg); err != nil {
+				res = err
+				continue
+			}
+			c.wake(topic)
+		} else if err = c.publish(context.Background(), topic, msg); err != nil {
 			c.logger.Error("couldn't send an event to topic", zap.String("subject", topic), zap.Error(err))
 		}
 	}
 
-	return nil
+	return res
 }
 
 func (c *Controller) SendTestNotification(topic, bucketName, requestID, HostID string) error {
@@ -212,14 +305,14 @@ func (c *Controller) SendTestNotification(topic, bucketName, requestID, HostID s
 		return fmt.Errorf("couldn't marshal test event: %w", err)
 	}
 
-	return c.publish(topic, msg)
+	return c.publish(context.Background(), topic, msg)
 }
 
 func prepareEvent(p *handler.SendNotificationParams) *Event {
 	return &Event{
 		Records: []EventRecord{
 			{
-				EventVersion: EventVersion21,
+				EventVersion: eventVersion(p.Event),
 				EventSource:  "neofs:s3",
 				AWSRegion:    "",
 				EventTime:    time.Now(),
@@ -252,9 +345,37 @@ func prepareEvent(p *handler.SendNotificationParams) *Event {
 	}
 }
 
-func (c *Controller) publish(topic string, msg []byte) error {
-	if _, err := c.jsClient.Publish(topic, msg); err != nil {
-		return fmt.Errorf("couldn't send  event: %w", err)
+func eventVersion(eventType string) string {
+	for _, e := range []string{handler.EventLifecycleExpiration, handler.EventLifecycleTransition, handler.EventIntelligentTiering,
+		handler.EventObjectACLPut, handler.EventObjectTagging, handler.EventObjectRestore} {
+		if eventType == e || strings.HasPrefix(eventType, strings.TrimSuffix(e, "*")) {
+			return EventVersion23
+		}
+	}
+
+	if strings.HasPrefix(eventType, strings.TrimSuffix(handler.EventReplication, "*")) {
+		return EventVersion22
+	}
+
+	return EventVersion21
+}
+
+// publish sends the message to the target with the ARN. If there is no such target,
+// the ARN is considered as a subject of the default NATS JetStream.
+func (c *Controller) publish(ctx context.Context, arn string, msg []byte) error {
+	c.mu.RLock()
+	t, ok := c.targets[arn]
+	c.mu.RUnlock()
+
+	if !ok {
+		if c.nats == nil || strings.HasPrefix(arn, ARNPrefix) {
+			return errors.GetAPIError(errors.ErrARNNotification)
+		}
+		return c.nats.publish(arn, msg)
+	}
+
+	if err := t.Send(ctx, msg); err != nil {
+		return fmt.Errorf("couldn't send event: %w", err)
 	}
 
 	return nil
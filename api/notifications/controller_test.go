@@ -0,0 +1,317 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testTarget struct {
+	arn      string
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+func (t *testTarget) ARN() string {
+	return t.arn
+}
+
+func (t *testTarget) Send(_ context.Context, msg []byte) error {
+	t.mu.Lock()
+	t.messages = append(t.messages, msg)
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *testTarget) Close() error {
+	return nil
+}
+
+func TestControllerRouting(t *testing.T) {
+	c, err := NewController(&Options{}, zap.NewExample())
+	require.NoError(t, err)
+
+	queue := &testTarget{arn: TargetARN("queue", TargetTypeWebhook)}
+	topic := &testTarget{arn: TargetARN("topic", TargetTypeKafka)}
+	require.NoError(t, c.AddTarget(queue))
+	require.NoError(t, c.AddTarget(topic))
+	require.Error(t, c.AddTarget(&testTarget{arn: queue.arn}))
+
+	err = c.SendTestNotification(queue.arn, "bucket", "request", "host")
+	require.NoError(t, err)
+	require.Len(t, queue.messages, 1)
+	require.Empty(t, topic.messages)
+
+	testEvent := &TestEvent{}
+	require.NoError(t, json.Unmarshal(queue.messages[0], testEvent))
+	require.Equal(t, "s3:TestEvent", testEvent.Event)
+	require.Equal(t, "bucket", testEvent.Bucket)
+
+	t.Run("unknown arn", func(t *testing.T) {
+		err := c.SendTestNotification(TargetARN("unknown", TargetTypeKafka), "bucket", "request", "host")
+		require.True(t, errors.IsS3Error(err, errors.ErrARNNotification))
+
+		// NATS subjects can't be used without NATS connection
+		err = c.SendTestNotification("subject", "bucket", "request", "host")
+		require.True(t, errors.IsS3Error(err, errors.ErrARNNotification))
+	})
+
+	t.Run("event", func(t *testing.T) {
+		p := &handler.SendNotificationParams{
+			Event:            handler.EventObjectCreatedPut,
+			NotificationInfo: &data.NotificationInfo{Name: "object", Size: 10},
+			BktInfo:          &data.BucketInfo{Name: "bucket"},
+			ReqInfo:          &api.ReqInfo{},
+		}
+		err := c.SendNotifications(map[string]string{"topic-config": topic.arn}, p)
+		require.NoError(t, err)
+		require.Len(t, topic.messages, 1)
+
+		event := &Event{}
+		require.NoError(t, json.Unmarshal(topic.messages[0], event))
+		require.Len(t, event.Records, 1)
+		require.Equal(t, "topic-config", event.Records[0].S3.ConfigurationID)
+		require.Equal(t, "object", event.Records[0].S3.Object.Key)
+	})
+}
+
+func TestWebhookTarget(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		bodies   [][]byte
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "sha256="+SignWebhookPayload([]byte("secret"), body), r.Header.Get(WebhookSignatureHeader))
+
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+
+		switch string(body) {
+		case "retry":
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case "fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		bodies = append(bodies, body)
+	}))
+	defer srv.Close()
+
+	target, err := NewTarget(&TargetOptions{
+		Name:      "hook",
+		Type:      TargetTypeWebhook,
+		Endpoint:  srv.URL,
+		Secret:    "secret",
+		AuthToken: "token",
+		Retries:   3,
+	})
+	require.NoError(t, err)
+	defer target.Close()
+	target.(*webhookTarget).backoff = time.Millisecond
+
+	reset := func() {
+		mu.Lock()
+		attempts, bodies = 0, nil
+		mu.Unlock()
+	}
+
+	t.Run("success", func(t *testing.T) {
+		reset()
+		require.NoError(t, target.Send(context.Background(), []byte("event")))
+		require.Equal(t, 1, attempts)
+		require.Equal(t, [][]byte{[]byte("event")}, bodies)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		reset()
+		require.NoError(t, target.Send(context.Background(), []byte("retry")))
+		require.Equal(t, 3, attempts)
+		require.Len(t, bodies, 1)
+	})
+
+	t.Run("client error isn't retried", func(t *testing.T) {
+		reset()
+		require.Error(t, target.Send(context.Background(), []byte("bad")))
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		reset()
+		require.Error(t, target.Send(context.Background(), []byte("fail")))
+		require.Equal(t, 3, attempts)
+	})
+}
+
+// flakyTarget fails the first attempts to send every message.
+type flakyTarget struct {
+	testTarget
+	failures int
+	attempts map[string]int
+}
+
+func (t *flakyTarget) Send(ctx context.Context, msg []byte) error {
+	t.mu.Lock()
+	t.attempts[string(msg)]++
+	fail := t.attempts[string(msg)] <= t.failures
+	t.mu.Unlock()
+
+	if fail {
+		return errors.GetAPIError(errors.ErrInternalError)
+	}
+	return t.testTarget.Send(ctx, msg)
+}
+
+func (t *flakyTarget) received() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := make([]string, len(t.messages))
+	for i := range t.messages {
+		res[i] = string(t.messages[i])
+	}
+	return res
+}
+
+func newOutboxController(t *testing.T, backoff time.Duration) *Controller {
+	c, err := NewController(&Options{Outbox: &OutboxOptions{
+		Path:       t.TempDir(),
+		MinBackoff: backoff,
+		MaxBackoff: 2 * backoff,
+	}}, zap.NewExample())
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	return c
+}
+
+func TestControllerOutbox(t *testing.T) {
+	c := newOutboxController(t, 10*time.Millisecond)
+
+	target := &flakyTarget{testTarget: testTarget{arn: TargetARN("flaky", TargetTypeWebhook)}, failures: 2, attempts: make(map[string]int)}
+	require.NoError(t, c.AddTarget(target))
+
+	p := &handler.SendNotificationParams{
+		Event:   handler.EventObjectCreatedPut,
+		BktInfo: &data.BucketInfo{Name: "bucket"},
+		ReqInfo: &api.ReqInfo{},
+	}
+	for _, name := range []string{"first", "second", "third"} {
+		p.NotificationInfo = &data.NotificationInfo{Name: name}
+		require.NoError(t, c.SendNotifications(map[string]string{"id": target.arn}, p))
+	}
+
+	// events are stored until dispatching is started
+	events, err := c.Outbox().Events(target.arn)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Empty(t, target.received())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Dispatch(ctx)
+
+	require.Eventually(t, func() bool { return len(target.received()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	var keys []string
+	for _, msg := range target.received() {
+		event := &Event{}
+		require.NoError(t, json.Unmarshal([]byte(msg), event))
+		keys = append(keys, event.Records[0].S3.Object.Key)
+	}
+	require.Equal(t, []string{"first", "second", "third"}, keys)
+
+	require.Eventually(t, func() bool {
+		events, err := c.Outbox().Events("")
+		return err == nil && len(events) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestOutboxHandler(t *testing.T) {
+	c := newOutboxController(t, time.Hour)
+	arn := TargetARN("late", TargetTypeWebhook)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Dispatch(ctx)
+
+	p := &handler.SendNotificationParams{
+		Event:            handler.EventObjectCreatedPut,
+		NotificationInfo: &data.NotificationInfo{Name: "object"},
+		BktInfo:          &data.BucketInfo{Name: "bucket"},
+		ReqInfo:          &api.ReqInfo{},
+	}
+	require.NoError(t, c.SendNotifications(map[string]string{"first": arn, "second": arn}, p))
+
+	srv := httptest.NewServer(c.OutboxHandler())
+	defer srv.Close()
+
+	request := func(method, path string) (int, *outboxAdminResponse) {
+		req, err := http.NewRequest(method, srv.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		res := &outboxAdminResponse{}
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+		}
+		return resp.StatusCode, res
+	}
+
+	// the target isn't registered, so the first event is stuck and blocks the second one
+	var res *outboxAdminResponse
+	require.Eventually(t, func() bool {
+		_, res = request(http.MethodGet, "/outbox/events")
+		return res.Count == 2 && res.Events[0].Attempts > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Zero(t, res.Events[1].Attempts)
+	require.Contains(t, res.Events[0].LastError, "ARN")
+
+	status, _ := request(http.MethodPost, "/outbox/replay")
+	require.Equal(t, http.StatusBadRequest, status)
+	status, _ = request(http.MethodDelete, "/outbox/drop?target="+arn)
+	require.Equal(t, http.StatusMethodNotAllowed, status)
+
+	// the events are delivered when the target appears and the first event is replayed
+	target := &testTarget{arn: arn}
+	require.NoError(t, c.AddTarget(target))
+	status, res = request(http.MethodPost, "/outbox/replay?target="+arn+"&id="+strconv.FormatUint(res.Events[0].ID, 10))
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, res.Count)
+
+	require.Eventually(t, func() bool {
+		_, res = request(http.MethodGet, "/outbox/events?target="+arn)
+		return res.Count == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, target.messages, 2)
+
+	status, res = request(http.MethodPost, "/outbox/drop?target="+arn)
+	require.Equal(t, http.StatusOK, status)
+	require.Zero(t, res.Count)
+}
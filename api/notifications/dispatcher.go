@@ -0,0 +1,128 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Outbox returns the outbox of the controller, it's nil if the outbox isn't used.
+func (c *Controller) Outbox() *Outbox {
+	return c.outbox
+}
+
+// Dispatch delivers events from the outbox until the context is done. Every target has
+// a separate dispatcher which delivers events one by one in the order they were added,
+// a failed event is retried with exponential backoff and blocks the following events of the target.
+func (c *Controller) Dispatch(ctx context.Context) {
+	if c.outbox == nil {
+		return
+	}
+
+	c.dmu.Lock()
+	c.dispatchCtx = ctx
+	c.dmu.Unlock()
+
+	targets, err := c.outbox.Targets()
+	if err != nil {
+		c.logger.Error("couldn't list outbox targets", zap.Error(err))
+	}
+	for _, target := range targets {
+		c.wake(target)
+	}
+}
+
+// ReplayEvents schedules events of the target for immediate delivery, see Outbox.Replay.
+func (c *Controller) ReplayEvents(target string, id uint64) (int, error) {
+	n, err := c.outbox.Replay(target, id)
+	if err == nil && n > 0 {
+		c.wake(target)
+	}
+	return n, err
+}
+
+// DropEvents removes events of the target without delivery, see Outbox.Drop.
+func (c *Controller) DropEvents(target string, id uint64) (int, error) {
+	return c.outbox.Drop(target, id)
+}
+
+// wake notifies the dispatcher of the target about new events and starts it if it isn't running.
+func (c *Controller) wake(target string) {
+	c.dmu.Lock()
+	defer c.dmu.Unlock()
+
+	if c.dispatchCtx == nil {
+		// events are delivered when dispatching is started
+		return
+	}
+
+	ch, ok := c.wakes[target]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		c.wakes[target] = ch
+		go c.dispatch(c.dispatchCtx, target, ch)
+	}
+
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Controller) dispatch(ctx context.Context, target string, wake <-chan struct{}) {
+	log := c.logger.With(zap.String("target", target))
+
+	for ctx.Err() == nil {
+		var timer <-chan time.Time
+
+		ev, err := c.outbox.Head(target)
+		switch {
+		case err != nil:
+			log.Error("couldn't get event from outbox", zap.Error(err))
+			timer = time.After(c.outbox.maxBackoff)
+		case ev != nil:
+			if wait := time.Until(ev.NextAttempt); wait > 0 {
+				timer = time.After(wait)
+				break
+			}
+
+			if err = c.deliver(ctx, log, ev); err == nil {
+				continue
+			}
+			log.Error("couldn't update outbox", zap.Uint64("id", ev.ID), zap.Error(err))
+			timer = time.After(c.outbox.maxBackoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-timer:
+		}
+	}
+}
+
+// deliver sends the event to the target and removes it from the outbox or schedules
+// the next attempt. The error is returned only if the outbox can't be updated.
+func (c *Controller) deliver(ctx context.Context, log *zap.Logger, ev *OutboxEvent) error {
+	err := c.publish(ctx, ev.Target, ev.Payload)
+	if err == nil {
+		return c.outbox.Delete(ev)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err = c.outbox.Failed(ev, err); err != nil {
+		return err
+	}
+	log.Warn("couldn't deliver event, it will be retried",
+		zap.Uint64("id", ev.ID),
+		zap.Int("attempts", ev.Attempts),
+		zap.Time("next_attempt", ev.NextAttempt),
+		zap.String("error", ev.LastError))
+
+	return nil
+}
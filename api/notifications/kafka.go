@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const kafkaClientID = "neofs-s3-gw"
+
+type kafkaTarget struct {
+	arn    string
+	writer *kafka.Writer
+}
+
+// NewKafkaTarget creates a target which produces events to the Kafka topic.
+// Events are distributed across the topic partitions in a round-robin manner.
+func NewKafkaTarget(arn string, p *TargetOptions) (Target, error) {
+	if len(p.Brokers) == 0 {
+		return nil, fmt.Errorf("empty kafka brokers")
+	}
+	if p.Topic == "" {
+		return nil, fmt.Errorf("empty kafka topic")
+	}
+
+	transport := &kafka.Transport{
+		DialTimeout: p.Timeout,
+		ClientID:    kafkaClientID,
+	}
+
+	if p.TLS || p.hasTLSFiles() {
+		tlsConfig, err := p.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLS = tlsConfig
+	}
+
+	mechanism, err := kafkaSASLMechanism(p)
+	if err != nil {
+		return nil, err
+	}
+	transport.SASL = mechanism
+
+	return &kafkaTarget{
+		arn: arn,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(p.Brokers...),
+			Topic:        p.Topic,
+			Balancer:     &kafka.RoundRobin{},
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    1,
+			ReadTimeout:  p.Timeout,
+			WriteTimeout: p.Timeout,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+func kafkaSASLMechanism(p *TargetOptions) (sasl.Mechanism, error) {
+	switch p.SASLMechanism {
+	case "":
+		if p.User != "" {
+			return nil, fmt.Errorf("empty kafka sasl mechanism")
+		}
+		return nil, nil
+	case KafkaSASLPlain:
+		return plain.Mechanism{Username: p.User, Password: p.Password}, nil
+	case KafkaSASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, p.User, p.Password)
+	case KafkaSASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, p.User, p.Password)
+	default:
+		return nil, fmt.Errorf("unknown kafka sasl mechanism '%s'", p.SASLMechanism)
+	}
+}
+
+func (t *kafkaTarget) ARN() string {
+	return t.arn
+}
+
+func (t *kafkaTarget) Send(ctx context.Context, msg []byte) error {
+	if err := t.writer.WriteMessages(ctx, kafka.Message{Value: msg}); err != nil {
+		return fmt.Errorf("produce to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (t *kafkaTarget) Close() error {
+	return t.writer.Close()
+}
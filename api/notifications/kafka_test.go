@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaTarget(t *testing.T) {
+	certFile, keyFile := newTestCertificate(t)
+
+	for _, tc := range []struct {
+		name      string
+		options   TargetOptions
+		tls       bool
+		mechanism string
+		err       bool
+	}{
+		{name: "plain", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events"}},
+		{name: "no brokers", options: TargetOptions{Topic: "events"}, err: true},
+		{name: "no topic", options: TargetOptions{Brokers: []string{"localhost:9092"}}, err: true},
+		{name: "tls", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events", TLS: true}, tls: true},
+		{name: "root ca", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			RootCAFiles: []string{certFile}}, tls: true},
+		{name: "client certificate", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			TLSCertFilepath: certFile, TLSAuthPrivateKeyFilePath: keyFile}, tls: true},
+		{name: "invalid root ca", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			RootCAFiles: []string{keyFile}}, err: true},
+		{name: "sasl plain", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			User: "user", Password: "password", SASLMechanism: KafkaSASLPlain}, mechanism: KafkaSASLPlain},
+		{name: "sasl scram", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			User: "user", Password: "password", SASLMechanism: KafkaSASLScramSHA512}, mechanism: KafkaSASLScramSHA512},
+		{name: "no sasl mechanism", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			User: "user", Password: "password"}, err: true},
+		{name: "unknown sasl mechanism", options: TargetOptions{Brokers: []string{"localhost:9092"}, Topic: "events",
+			User: "user", Password: "password", SASLMechanism: "GSSAPI"}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.options.Name = "kafka"
+			tc.options.Type = TargetTypeKafka
+
+			target, err := NewTarget(&tc.options)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "arn:neofs:sqs::kafka:kafka", target.ARN())
+
+			transport := target.(*kafkaTarget).writer.Transport.(*kafka.Transport)
+			require.Equal(t, tc.tls, transport.TLS != nil)
+			if tc.mechanism == "" {
+				require.Nil(t, transport.SASL)
+			} else {
+				require.Equal(t, tc.mechanism, transport.SASL.Name())
+			}
+			require.NoError(t, target.Close())
+		})
+	}
+
+	t.Run("unavailable broker", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		require.NoError(t, ln.Close())
+
+		target, err := NewKafkaTarget("arn", &TargetOptions{
+			Brokers: []string{ln.Addr().String()},
+			Topic:   "events",
+			Timeout: time.Second,
+		})
+		require.NoError(t, err)
+		defer target.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		require.Error(t, target.Send(ctx, []byte("event")))
+	})
+}
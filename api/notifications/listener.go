@@ -0,0 +1,108 @@
+package notifications
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"go.uber.org/zap"
+)
+
+// DefaultListenerBufferSize is a default number of events buffered for every listener.
+const DefaultListenerBufferSize = 1000
+
+type (
+	// Broadcaster fans out events to ListenBucketNotification clients. It works without
+	// notification targets, events are only sent to the listeners connected to the gateway.
+	Broadcaster struct {
+		logger     *zap.Logger
+		bufferSize int
+
+		mu        sync.RWMutex
+		listeners map[string]map[*listener]struct{}
+	}
+
+	listener struct {
+		filter *handler.ListenFilter
+		ch     chan []byte
+	}
+)
+
+var _ handler.Listener = (*Broadcaster)(nil)
+
+// NewBroadcaster creates a broadcaster which buffers up to bufferSize events for every listener.
+func NewBroadcaster(l *zap.Logger, bufferSize int) *Broadcaster {
+	if bufferSize <= 0 {
+		bufferSize = DefaultListenerBufferSize
+	}
+
+	return &Broadcaster{
+		logger:     l,
+		bufferSize: bufferSize,
+		listeners:  make(map[string]map[*listener]struct{}),
+	}
+}
+
+// Listen implements handler.Listener.
+func (b *Broadcaster) Listen(bucket string, filter *handler.ListenFilter) (<-chan []byte, func()) {
+	lst := &listener{
+		filter: filter,
+		ch:     make(chan []byte, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	if b.listeners[bucket] == nil {
+		b.listeners[bucket] = make(map[*listener]struct{})
+	}
+	b.listeners[bucket][lst] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return lst.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.listeners[bucket], lst)
+			if len(b.listeners[bucket]) == 0 {
+				delete(b.listeners, bucket)
+			}
+			b.mu.Unlock()
+			close(lst.ch)
+		})
+	}
+}
+
+// Publish implements handler.Listener. The event is dropped for the listeners
+// which don't keep up with events, so slow clients never block requests.
+func (b *Broadcaster) Publish(p *handler.SendNotificationParams) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	listeners := b.listeners[p.BktInfo.Name]
+	if len(listeners) == 0 {
+		return
+	}
+
+	var msg []byte
+	for lst := range listeners {
+		if !lst.filter.Match(p.Event, p.NotificationInfo.Name) {
+			continue
+		}
+
+		if msg == nil {
+			var err error
+			if msg, err = json.Marshal(prepareEvent(p)); err != nil {
+				b.logger.Error("couldn't marshal an event", zap.String("bucket", p.BktInfo.Name), zap.Error(err))
+				return
+			}
+		}
+
+		select {
+		case lst.ch <- msg:
+		default:
+			b.logger.Warn("listener is too slow, event is dropped",
+				zap.String("bucket", p.BktInfo.Name),
+				zap.String("event", p.Event),
+				zap.String("object", p.NotificationInfo.Name))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster(zap.NewExample(), 2)
+
+	publish := func(bucket, event, object string) {
+		b.Publish(&handler.SendNotificationParams{
+			Event:            event,
+			NotificationInfo: &data.NotificationInfo{Name: object},
+			BktInfo:          &data.BucketInfo{Name: bucket},
+			ReqInfo:          &api.ReqInfo{},
+		})
+	}
+
+	all, cancelAll := b.Listen("bucket", &handler.ListenFilter{Events: []string{handler.EventObjectCreated, handler.EventObjectRemoved}})
+	images, cancelImages := b.Listen("bucket", &handler.ListenFilter{Prefix: "img/", Events: []string{handler.EventObjectCreatedPut}})
+	other, cancelOther := b.Listen("other", &handler.ListenFilter{Events: []string{handler.EventObjectCreated}})
+	defer cancelOther()
+
+	publish("bucket", handler.EventObjectCreatedPut, "img/cat.png")
+	publish("bucket", handler.EventObjectRemovedDelete, "img/cat.png")
+
+	event := &Event{}
+	require.NoError(t, json.Unmarshal(<-images, event))
+	require.Equal(t, "img/cat.png", event.Records[0].S3.Object.Key)
+	require.Equal(t, "bucket", event.Records[0].S3.Bucket.Name)
+	require.Empty(t, images)
+	require.Empty(t, other)
+
+	t.Run("slow listener doesn't block publishing", func(t *testing.T) {
+		// the buffer of the listener is already full
+		publish("bucket", handler.EventObjectCreatedCopy, "img/dog.png")
+		require.Len(t, all, 2)
+
+		require.NoError(t, json.Unmarshal(<-all, event))
+		require.Equal(t, handler.EventObjectCreatedPut, event.Records[0].EventName)
+		require.NoError(t, json.Unmarshal(<-all, event))
+		require.Equal(t, handler.EventObjectRemovedDelete, event.Records[0].EventName)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		cancelAll()
+		cancelAll()
+		cancelImages()
+		_, ok := <-all
+		require.False(t, ok)
+
+		publish("bucket", handler.EventObjectCreatedPut, "img/cat.png")
+		require.NotContains(t, b.listeners, "bucket")
+		require.Len(t, b.listeners["other"], 1)
+	})
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsTarget struct {
+	arn     string
+	subject string
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+}
+
+func newNATSTarget(arn string, p *NATSOptions, subject string) (*natsTarget, error) {
+	ncopts := []nats.Option{
+		nats.Timeout(p.Timeout),
+	}
+
+	if len(p.TLSCertFilepath) != 0 && len(p.TLSAuthPrivateKeyFilePath) != 0 {
+		ncopts = append(ncopts, nats.ClientCert(p.TLSCertFilepath, p.TLSAuthPrivateKeyFilePath))
+	}
+	if len(p.RootCAFiles) != 0 {
+		ncopts = append(ncopts, nats.RootCAs(p.RootCAFiles...))
+	}
+
+	nc, err := nats.Connect(p.URL, ncopts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("get jet stream: %w", err)
+	}
+
+	return &natsTarget{
+		arn:     arn,
+		subject: subject,
+		conn:    nc,
+		js:      js,
+	}, nil
+}
+
+func (t *natsTarget) ARN() string {
+	return t.arn
+}
+
+func (t *natsTarget) Send(_ context.Context, msg []byte) error {
+	return t.publish(t.subject, msg)
+}
+
+func (t *natsTarget) publish(subject string, msg []byte) error {
+	if _, err := t.js.Publish(subject, msg); err != nil {
+		return fmt.Errorf("couldn't send  event: %w", err)
+	}
+
+	return nil
+}
+
+func (t *natsTarget) Close() error {
+	t.conn.Close()
+	return nil
+}
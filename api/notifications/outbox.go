@@ -0,0 +1,359 @@
+package notifications
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultOutboxMinBackoff is a default delay before the first retry of a failed event.
+	DefaultOutboxMinBackoff = time.Second
+	// DefaultOutboxMaxBackoff is a default limit of the delay between retries of a failed event.
+	DefaultOutboxMaxBackoff = 5 * time.Minute
+
+	outboxFileName = "outbox.db"
+)
+
+var outboxTargetsBucket = []byte("targets")
+
+type (
+	// OutboxOptions contains parameters of the notification outbox.
+	OutboxOptions struct {
+		// Path is a directory of the outbox database.
+		Path       string
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+	}
+
+	// Outbox is a persistent queue of events. Events of every target are stored in a separate
+	// bucket in the order they were added, so they can be delivered in the same order.
+	Outbox struct {
+		// putFailures is a number of events which couldn't be stored, it's accessed atomically.
+		putFailures uint64
+
+		db         *bbolt.DB
+		minBackoff time.Duration
+		maxBackoff time.Duration
+
+		backlogDesc *prometheus.Desc
+		oldestDesc  *prometheus.Desc
+		failureDesc *prometheus.Desc
+	}
+
+	// OutboxEvent is an event waiting for delivery to the target.
+	OutboxEvent struct {
+		ID          uint64    `json:"id"`
+		Target      string    `json:"target"`
+		Created     time.Time `json:"created"`
+		Attempts    int       `json:"attempts"`
+		LastError   string    `json:"lastError,omitempty"`
+		NextAttempt time.Time `json:"nextAttempt"`
+		Payload     []byte    `json:"payload,omitempty"`
+	}
+)
+
+var _ prometheus.Collector = (*Outbox)(nil)
+
+// NewOutbox opens or creates the outbox database in the directory.
+func NewOutbox(p *OutboxOptions) (*Outbox, error) {
+	if p.Path == "" {
+		return nil, fmt.Errorf("empty outbox path")
+	}
+	if err := os.MkdirAll(p.Path, 0700); err != nil {
+		return nil, fmt.Errorf("create outbox directory: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Join(p.Path, outboxFileName), 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open outbox: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(outboxTargetsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init outbox: %w", err)
+	}
+
+	o := &Outbox{
+		db:         db,
+		minBackoff: p.MinBackoff,
+		maxBackoff: p.MaxBackoff,
+		backlogDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "notifications", "outbox_events"),
+			"Number of events waiting for delivery to the target",
+			[]string{"target"}, nil),
+		oldestDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "notifications", "outbox_oldest_event_age_seconds"),
+			"Age of the oldest event waiting for delivery to the target",
+			[]string{"target"}, nil),
+		failureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("neofs_s3_gw", "notifications", "outbox_put_failures_total"),
+			"Number of events which couldn't be stored in the outbox",
+			nil, nil),
+	}
+	if o.minBackoff <= 0 {
+		o.minBackoff = DefaultOutboxMinBackoff
+	}
+	if o.maxBackoff < o.minBackoff {
+		o.maxBackoff = DefaultOutboxMaxBackoff
+	}
+
+	return o, nil
+}
+
+// Close closes the outbox database.
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+// Put stores the event for the target. The event is persisted when the method returns.
+func (o *Outbox) Put(target string, payload []byte) (*OutboxEvent, error) {
+	now := time.Now()
+	ev := &OutboxEvent{
+		Target:      target,
+		Created:     now,
+		NextAttempt: now,
+		Payload:     payload,
+	}
+
+	err := o.db.Update(func(tx *bbolt.Tx) error {
+		bkt, err := tx.Bucket(outboxTargetsBucket).CreateBucketIfNotExists([]byte(target))
+		if err != nil {
+			return err
+		}
+		if ev.ID, err = bkt.NextSequence(); err != nil {
+			return err
+		}
+		return putOutboxEvent(bkt, ev)
+	})
+	if err != nil {
+		atomic.AddUint64(&o.putFailures, 1)
+		return nil, fmt.Errorf("put event to outbox: %w", err)
+	}
+
+	return ev, nil
+}
+
+// Targets returns targets which have ever had events in the outbox.
+func (o *Outbox) Targets() ([]string, error) {
+	var targets []string
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(outboxTargetsBucket).ForEach(func(k, _ []byte) error {
+			targets = append(targets, string(k))
+			return nil
+		})
+	})
+
+	return targets, err
+}
+
+// Head returns the oldest event of the target or nil if there are no events.
+func (o *Outbox) Head(target string) (*OutboxEvent, error) {
+	var ev *OutboxEvent
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(outboxTargetsBucket).Bucket([]byte(target))
+		if bkt == nil {
+			return nil
+		}
+
+		if k, v := bkt.Cursor().First(); k != nil {
+			ev = new(OutboxEvent)
+			return json.Unmarshal(v, ev)
+		}
+		return nil
+	})
+
+	return ev, err
+}
+
+// Events returns events of the target or events of all targets if the target is empty.
+// Payloads of the events aren't returned.
+func (o *Outbox) Events(target string) ([]*OutboxEvent, error) {
+	var events []*OutboxEvent
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		return o.forEachTarget(tx, target, func(bkt *bbolt.Bucket) error {
+			return bkt.ForEach(func(_, v []byte) error {
+				ev := new(OutboxEvent)
+				if err := json.Unmarshal(v, ev); err != nil {
+					return err
+				}
+				ev.Payload = nil
+				events = append(events, ev)
+				return nil
+			})
+		})
+	})
+
+	return events, err
+}
+
+// Delete removes the delivered event.
+func (o *Outbox) Delete(ev *OutboxEvent) error {
+	return o.db.Update(func(tx *bbolt.Tx) error {
+		if bkt := tx.Bucket(outboxTargetsBucket).Bucket([]byte(ev.Target)); bkt != nil {
+			return bkt.Delete(outboxKey(ev.ID))
+		}
+		return nil
+	})
+}
+
+// Failed records the failed delivery attempt and schedules the next one with exponential backoff.
+func (o *Outbox) Failed(ev *OutboxEvent, cause error) error {
+	ev.Attempts++
+	ev.LastError = cause.Error()
+	ev.NextAttempt = time.Now().Add(o.backoff(ev.Attempts))
+
+	return o.db.Update(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(outboxTargetsBucket).Bucket([]byte(ev.Target))
+		if bkt == nil || bkt.Get(outboxKey(ev.ID)) == nil {
+			// the event was dropped during the delivery
+			return nil
+		}
+		return putOutboxEvent(bkt, ev)
+	})
+}
+
+// Replay schedules the event of the target for immediate delivery and resets its attempts.
+// All events of the target are replayed if the id is zero. It returns the number of replayed events.
+func (o *Outbox) Replay(target string, id uint64) (int, error) {
+	now := time.Now()
+	return o.update(target, id, func(bkt *bbolt.Bucket, k, v []byte) error {
+		ev := new(OutboxEvent)
+		if err := json.Unmarshal(v, ev); err != nil {
+			return err
+		}
+		ev.Attempts = 0
+		ev.LastError = ""
+		ev.NextAttempt = now
+		return putOutboxEvent(bkt, ev)
+	})
+}
+
+// Drop removes the event of the target without delivery. All events of the target
+// are removed if the id is zero. It returns the number of dropped events.
+func (o *Outbox) Drop(target string, id uint64) (int, error) {
+	return o.update(target, id, func(bkt *bbolt.Bucket, k, _ []byte) error {
+		return bkt.Delete(k)
+	})
+}
+
+func (o *Outbox) update(target string, id uint64, f func(bkt *bbolt.Bucket, k, v []byte) error) (int, error) {
+	var n int
+	err := o.db.Update(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(outboxTargetsBucket).Bucket([]byte(target))
+		if bkt == nil {
+			return nil
+		}
+
+		if id != 0 {
+			k := outboxKey(id)
+			v := bkt.Get(k)
+			if v == nil {
+				return nil
+			}
+			n++
+			return f(bkt, k, v)
+		}
+
+		// collect keys first, bucket mustn't be modified during iteration
+		var keys [][]byte
+		err := bkt.ForEach(func(k, _ []byte) error {
+			keys = append(keys, append([]byte{}, k...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err = f(bkt, k, bkt.Get(k)); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+
+	return n, err
+}
+
+func (o *Outbox) forEachTarget(tx *bbolt.Tx, target string, f func(bkt *bbolt.Bucket) error) error {
+	root := tx.Bucket(outboxTargetsBucket)
+	if target != "" {
+		if bkt := root.Bucket([]byte(target)); bkt != nil {
+			return f(bkt)
+		}
+		return nil
+	}
+
+	return root.ForEach(func(k, _ []byte) error {
+		return f(root.Bucket(k))
+	})
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	backoff := o.minBackoff
+	for i := 1; i < attempts && backoff < o.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > o.maxBackoff {
+		backoff = o.maxBackoff
+	}
+	return backoff
+}
+
+// Describe implements prometheus.Collector.
+func (o *Outbox) Describe(ch chan<- *prometheus.Desc) {
+	ch <- o.backlogDesc
+	ch <- o.oldestDesc
+	ch <- o.failureDesc
+}
+
+// Collect implements prometheus.Collector.
+func (o *Outbox) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(o.failureDesc, prometheus.CounterValue, float64(atomic.LoadUint64(&o.putFailures)))
+
+	now := time.Now()
+	_ = o.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(outboxTargetsBucket).ForEach(func(k, _ []byte) error {
+			bkt := tx.Bucket(outboxTargetsBucket).Bucket(k)
+			target := string(k)
+
+			var age float64
+			if _, v := bkt.Cursor().First(); v != nil {
+				ev := new(OutboxEvent)
+				if err := json.Unmarshal(v, ev); err == nil {
+					age = now.Sub(ev.Created).Seconds()
+				}
+			}
+
+			ch <- prometheus.MustNewConstMetric(o.backlogDesc, prometheus.GaugeValue, float64(bkt.Stats().KeyN), target)
+			ch <- prometheus.MustNewConstMetric(o.oldestDesc, prometheus.GaugeValue, age, target)
+			return nil
+		})
+	})
+}
+
+func putOutboxEvent(bkt *bbolt.Bucket, ev *OutboxEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(outboxKey(ev.ID), data)
+}
+
+func outboxKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOutbox(t *testing.T) *Outbox {
+	outbox, err := NewOutbox(&OutboxOptions{
+		Path:       t.TempDir(),
+		MinBackoff: time.Second,
+		MaxBackoff: 4 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = outbox.Close() })
+
+	return outbox
+}
+
+func TestOutbox(t *testing.T) {
+	outbox := newTestOutbox(t)
+
+	for _, payload := range []string{"first", "second"} {
+		_, err := outbox.Put("target", []byte(payload))
+		require.NoError(t, err)
+	}
+	_, err := outbox.Put("other", []byte("other"))
+	require.NoError(t, err)
+
+	targets, err := outbox.Targets()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"target", "other"}, targets)
+
+	head, err := outbox.Head("target")
+	require.NoError(t, err)
+	require.Equal(t, "first", string(head.Payload))
+	require.Zero(t, head.Attempts)
+
+	t.Run("failed attempts", func(t *testing.T) {
+		for i, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+			require.NoError(t, outbox.Failed(head, errors.New("unavailable")))
+
+			actual, err := outbox.Head("target")
+			require.NoError(t, err)
+			require.Equal(t, i+1, actual.Attempts)
+			require.Equal(t, "unavailable", actual.LastError)
+			require.WithinDuration(t, time.Now().Add(backoff), actual.NextAttempt, 100*time.Millisecond)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		n, err := outbox.Replay("target", head.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		actual, err := outbox.Head("target")
+		require.NoError(t, err)
+		require.Zero(t, actual.Attempts)
+		require.Empty(t, actual.LastError)
+		require.False(t, actual.NextAttempt.After(time.Now()))
+
+		n, err = outbox.Replay("unknown", 0)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		reg := prometheus.NewPedanticRegistry()
+		require.NoError(t, reg.Register(outbox))
+
+		mfs, err := reg.Gather()
+		require.NoError(t, err)
+		require.Len(t, mfs, 3)
+
+		backlog := make(map[string]float64)
+		for _, m := range mfs[0].GetMetric() {
+			backlog[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+		require.Equal(t, "neofs_s3_gw_notifications_outbox_events", mfs[0].GetName())
+		require.Equal(t, map[string]float64{"target": 2, "other": 1}, backlog)
+		require.Equal(t, "neofs_s3_gw_notifications_outbox_put_failures_total", mfs[2].GetName())
+		require.Zero(t, mfs[2].GetMetric()[0].GetCounter().GetValue())
+	})
+
+	t.Run("delete and drop", func(t *testing.T) {
+		require.NoError(t, outbox.Delete(head))
+
+		events, err := outbox.Events("")
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Nil(t, events[0].Payload)
+
+		n, err := outbox.Drop("target", 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		head, err := outbox.Head("target")
+		require.NoError(t, err)
+		require.Nil(t, head)
+
+		events, err = outbox.Events("target")
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
+
+func TestOutboxPersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	outbox, err := NewOutbox(&OutboxOptions{Path: dir})
+	require.NoError(t, err)
+	ev, err := outbox.Put("target", []byte("event"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.Close())
+
+	outbox, err = NewOutbox(&OutboxOptions{Path: dir})
+	require.NoError(t, err)
+	defer outbox.Close()
+
+	head, err := outbox.Head("target")
+	require.NoError(t, err)
+	require.Equal(t, ev.ID, head.ID)
+	require.Equal(t, "event", string(head.Payload))
+
+	// sequence isn't reset after reopening
+	next, err := outbox.Put("target", []byte("next"))
+	require.NoError(t, err)
+	require.Greater(t, next.ID, ev.ID)
+}
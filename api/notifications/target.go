@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Types of the notification targets.
+const (
+	TargetTypeNATS    = "nats"
+	TargetTypeWebhook = "webhook"
+	TargetTypeKafka   = "kafka"
+	TargetTypeAMQP    = "amqp"
+)
+
+// SASL mechanisms of Kafka targets.
+const (
+	KafkaSASLPlain       = "PLAIN"
+	KafkaSASLScramSHA256 = "SCRAM-SHA-256"
+	KafkaSASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// ARNPrefix is a prefix of the ARNs of notification targets.
+const ARNPrefix = "arn:neofs:sqs::"
+
+const (
+	// DefaultTargetTimeout is a default timeout of a single attempt to send an event to a target.
+	DefaultTargetTimeout = 10 * time.Second
+	// DefaultWebhookRetries is a default number of attempts to send an event to a webhook.
+	DefaultWebhookRetries = 3
+)
+
+// TargetOptions contains parameters of a notification target. Set of used parameters depends on the type.
+type TargetOptions struct {
+	// Name is a unique name of the target, it's a part of the target ARN.
+	Name string
+	// Type is one of nats, webhook, kafka and amqp.
+	Type string
+	// Endpoint is the NATS server URL, the webhook URL or the AMQP broker URL.
+	Endpoint string
+	// Brokers are addresses of Kafka brokers.
+	Brokers []string
+	// Topic is the NATS JetStream subject, the Kafka topic or the AMQP routing key.
+	Topic string
+	// Exchange is the AMQP exchange, the default exchange is used if it's empty.
+	Exchange string
+	// Secret is used to sign webhook requests with HMAC-SHA256.
+	Secret string
+	// AuthToken is sent in Authorization header of webhook requests as a bearer token.
+	AuthToken string
+	// Retries is a number of attempts to send an event to a webhook.
+	Retries int
+	// Timeout is a timeout of a single attempt to send an event.
+	Timeout time.Duration
+	// TLS enables TLS connections to Kafka brokers, AMQP uses TLS with amqps URL scheme.
+	TLS bool
+	// User and Password authenticate the Kafka client with SASLMechanism.
+	User          string
+	Password      string
+	SASLMechanism string
+
+	TLSCertFilepath           string
+	TLSAuthPrivateKeyFilePath string
+	RootCAFiles               []string
+}
+
+// TargetARN returns the ARN of the target with the name and type, e.g. arn:neofs:sqs::orders:webhook.
+func TargetARN(name, typ string) string {
+	return ARNPrefix + name + ":" + typ
+}
+
+// NewTarget creates a target of the type specified in the options.
+func NewTarget(p *TargetOptions) (Target, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("empty target name")
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultTargetTimeout
+	}
+
+	arn := TargetARN(p.Name, p.Type)
+
+	switch p.Type {
+	case TargetTypeNATS:
+		return newNATSTarget(arn, &NATSOptions{
+			URL:                       p.Endpoint,
+			TLSCertFilepath:           p.TLSCertFilepath,
+			TLSAuthPrivateKeyFilePath: p.TLSAuthPrivateKeyFilePath,
+			Timeout:                   p.Timeout,
+			RootCAFiles:               p.RootCAFiles,
+		}, p.Topic)
+	case TargetTypeWebhook:
+		return NewWebhookTarget(arn, p)
+	case TargetTypeKafka:
+		return NewKafkaTarget(arn, p)
+	case TargetTypeAMQP:
+		return NewAMQPTarget(arn, p)
+	default:
+		return nil, fmt.Errorf("unknown target type '%s'", p.Type)
+	}
+}
+
+// tlsConfig returns the TLS configuration with the client certificate and root CAs from the options.
+func (p *TargetOptions) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(p.TLSCertFilepath) != 0 && len(p.TLSAuthPrivateKeyFilePath) != 0 {
+		cert, err := tls.LoadX509KeyPair(p.TLSCertFilepath, p.TLSAuthPrivateKeyFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(p.RootCAFiles) != 0 {
+		pool := x509.NewCertPool()
+		for _, file := range p.RootCAFiles {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("read root ca: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("no certificates in root ca '%s'", file)
+			}
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
+
+// hasTLSFiles checks if the client certificate or root CAs are set.
+func (p *TargetOptions) hasTLSFiles() bool {
+	return len(p.TLSCertFilepath) != 0 || len(p.TLSAuthPrivateKeyFilePath) != 0 || len(p.RootCAFiles) != 0
+}
@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate creates a self-signed certificate and returns paths to the certificate and the key.
+func newTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyData, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestTargetTLSConfig(t *testing.T) {
+	certFile, keyFile := newTestCertificate(t)
+
+	cfg, err := (&TargetOptions{}).tlsConfig()
+	require.NoError(t, err)
+	require.Empty(t, cfg.Certificates)
+	require.Nil(t, cfg.RootCAs)
+	require.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+
+	cfg, err = (&TargetOptions{
+		TLSCertFilepath:           certFile,
+		TLSAuthPrivateKeyFilePath: keyFile,
+		RootCAFiles:               []string{certFile},
+	}).tlsConfig()
+	require.NoError(t, err)
+	require.Len(t, cfg.Certificates, 1)
+	require.NotNil(t, cfg.RootCAs)
+
+	_, err = (&TargetOptions{TLSCertFilepath: keyFile, TLSAuthPrivateKeyFilePath: keyFile}).tlsConfig()
+	require.Error(t, err)
+
+	_, err = (&TargetOptions{RootCAFiles: []string{filepath.Join(t.TempDir(), "missing.pem")}}).tlsConfig()
+	require.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSignatureHeader contains hex encoded HMAC-SHA256 of the request body
+// signed with the target secret, prefixed with "sha256=".
+const WebhookSignatureHeader = "X-Neofs-Signature"
+
+const webhookRetryBackoff = 500 * time.Millisecond
+
+type webhookTarget struct {
+	arn       string
+	endpoint  string
+	secret    []byte
+	authToken string
+	retries   int
+	backoff   time.Duration
+	client    *http.Client
+}
+
+// NewWebhookTarget creates a target which POSTs events to the HTTP endpoint.
+// Failed requests (network errors, 429 and 5xx responses) are retried with exponential backoff.
+func NewWebhookTarget(arn string, p *TargetOptions) (Target, error) {
+	if p.Endpoint == "" {
+		return nil, fmt.Errorf("empty webhook endpoint")
+	}
+
+	retries := p.Retries
+	if retries <= 0 {
+		retries = DefaultWebhookRetries
+	}
+
+	client := &http.Client{Timeout: p.Timeout}
+	if p.hasTLSFiles() {
+		tlsConfig, err := p.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+
+	return &webhookTarget{
+		arn:       arn,
+		endpoint:  p.Endpoint,
+		secret:    []byte(p.Secret),
+		authToken: p.AuthToken,
+		retries:   retries,
+		backoff:   webhookRetryBackoff,
+		client:    client,
+	}, nil
+}
+
+func (t *webhookTarget) ARN() string {
+	return t.arn
+}
+
+func (t *webhookTarget) Send(ctx context.Context, msg []byte) error {
+	var err error
+	backoff := t.backoff
+	for i := 0; i < t.retries; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		if retry, err = t.send(ctx, msg); !retry {
+			return err
+		}
+	}
+
+	return fmt.Errorf("webhook failed after %d attempts: %w", t.retries, err)
+}
+
+func (t *webhookTarget) send(ctx context.Context, msg []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(msg))
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(t.secret) != 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(t.secret, msg))
+	}
+	if t.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.authToken)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("send request: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status: %s", resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+}
+
+func (t *webhookTarget) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// SignWebhookPayload returns hex encoded HMAC-SHA256 of the payload, receivers
+// of webhook events use it to check the signature header.
+func SignWebhookPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
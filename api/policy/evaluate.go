@@ -0,0 +1,383 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is a result of the policy evaluation.
+type Result int
+
+const (
+	// ResultNone means that no statement is applicable to the request.
+	ResultNone Result = iota
+	// ResultAllow means that the request is allowed explicitly.
+	ResultAllow
+	// ResultDeny means that the request is denied explicitly.
+	ResultDeny
+)
+
+// Request contains request info the policy is evaluated against.
+type Request struct {
+	// Action is an S3 action, e.g. s3:GetObject.
+	Action string
+	// Resource is an ARN of the bucket or the object.
+	Resource string
+	// Principals contains identifiers of the requester, it's empty for anonymous requests.
+	Principals []string
+	// Conditions contains request values of the condition keys, keys are case-insensitive.
+	Conditions map[string][]string
+}
+
+// Evaluate evaluates the policy for the request. Explicit deny takes precedence over allow.
+func (p *Policy) Evaluate(req Request) Result {
+	conditions := make(map[string][]string, len(req.Conditions))
+	for key, values := range req.Conditions {
+		conditions[strings.ToLower(key)] = values
+	}
+
+	res := ResultNone
+	for _, st := range p.Statement {
+		if !st.matches(req, conditions) {
+			continue
+		}
+		if st.Effect == EffectDeny {
+			return ResultDeny
+		}
+		res = ResultAllow
+	}
+
+	return res
+}
+
+// Restricts checks if the request is in the scope of an Allow statement with conditions or negations,
+// i.e. the principal, the action and the resource of the statement match the request regardless of them.
+// Such statements are translated to eACL without conditions and negations, so the gateway must reject
+// the request in their scope if the policy doesn't allow it.
+func (p *Policy) Restricts(req Request) bool {
+	for _, st := range p.Statement {
+		if st.Effect == EffectAllow && st.Restricted() && st.inScope(req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Restricted checks if the statement has conditions or negations which can't be enforced by eACL.
+func (s Statement) Restricted() bool {
+	return len(s.Condition) != 0 || s.NotPrincipal != nil || len(s.NotAction) != 0 || len(s.NotResource) != 0
+}
+
+// MatchesAction checks if the action is covered by Action or not excluded by NotAction of the statement.
+func (s Statement) MatchesAction(action string) bool {
+	if len(s.NotAction) > 0 {
+		return !matchAny(s.NotAction, action, true)
+	}
+	return matchAny(s.Action, action, true)
+}
+
+// inScope matches the request against the statement ignoring its conditions and negations.
+func (s Statement) inScope(req Request) bool {
+	return (s.Principal == nil || s.Principal.matches(req.Principals)) &&
+		(len(s.Action) == 0 || matchAny(s.Action, req.Action, true)) &&
+		(len(s.Resource) == 0 || matchAny(s.Resource, req.Resource, false))
+}
+
+func (s Statement) matches(req Request, conditions map[string][]string) bool {
+	if s.Principal != nil && !s.Principal.matches(req.Principals) ||
+		s.NotPrincipal != nil && s.NotPrincipal.matches(req.Principals) {
+		return false
+	}
+
+	if len(s.Action) > 0 && !matchAny(s.Action, req.Action, true) ||
+		len(s.NotAction) > 0 && matchAny(s.NotAction, req.Action, true) {
+		return false
+	}
+
+	if len(s.Resource) > 0 && !matchAny(s.Resource, req.Resource, false) ||
+		len(s.NotResource) > 0 && matchAny(s.NotResource, req.Resource, false) {
+		return false
+	}
+
+	for operator, keys := range s.Condition {
+		for key, values := range keys {
+			if !evaluateCondition(operator, values, conditions[strings.ToLower(key)]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (p *Principal) matches(principals []string) bool {
+	if p.Wildcard {
+		return true
+	}
+
+	for _, principal := range principals {
+		for _, val := range p.AWS {
+			if strings.EqualFold(normalizePrincipal(val), principal) {
+				return true
+			}
+		}
+		for _, val := range p.CanonicalUser {
+			if strings.EqualFold(val, principal) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchAny(patterns Values, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, value, ignoreCase) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches the value against the pattern with '*' (any sequence)
+// and '?' (any single character) wildcards.
+func wildcardMatch(pattern, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+	}
+
+	var p, v int
+	star, mark := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, v
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			v = mark
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+type setOperator int
+
+const (
+	setNone setOperator = iota
+	setAnyValue
+	setAllValues
+)
+
+var negatedOperators = map[string]string{
+	"StringNotEquals":           "StringEquals",
+	"StringNotEqualsIgnoreCase": "StringEqualsIgnoreCase",
+	"StringNotLike":             "StringLike",
+	"NumericNotEquals":          "NumericEquals",
+	"DateNotEquals":             "DateEquals",
+	"NotIpAddress":              "IpAddress",
+}
+
+var operators = map[string]func(policyValue, requestValue string) bool{
+	"StringEquals": func(p, r string) bool { return p == r },
+	"StringEqualsIgnoreCase": func(p, r string) bool {
+		return strings.EqualFold(p, r)
+	},
+	"StringLike": func(p, r string) bool { return wildcardMatch(p, r, false) },
+	"NumericEquals": func(p, r string) bool {
+		return compareNumbers(p, r, func(c int) bool { return c == 0 })
+	},
+	"NumericLessThan": func(p, r string) bool {
+		return compareNumbers(p, r, func(c int) bool { return c < 0 })
+	},
+	"NumericLessThanEquals": func(p, r string) bool {
+		return compareNumbers(p, r, func(c int) bool { return c <= 0 })
+	},
+	"NumericGreaterThan": func(p, r string) bool {
+		return compareNumbers(p, r, func(c int) bool { return c > 0 })
+	},
+	"NumericGreaterThanEquals": func(p, r string) bool {
+		return compareNumbers(p, r, func(c int) bool { return c >= 0 })
+	},
+	"DateEquals": func(p, r string) bool {
+		return compareDates(p, r, func(c int) bool { return c == 0 })
+	},
+	"DateLessThan": func(p, r string) bool {
+		return compareDates(p, r, func(c int) bool { return c < 0 })
+	},
+	"DateLessThanEquals": func(p, r string) bool {
+		return compareDates(p, r, func(c int) bool { return c <= 0 })
+	},
+	"DateGreaterThan": func(p, r string) bool {
+		return compareDates(p, r, func(c int) bool { return c > 0 })
+	},
+	"DateGreaterThanEquals": func(p, r string) bool {
+		return compareDates(p, r, func(c int) bool { return c >= 0 })
+	},
+	"Bool": func(p, r string) bool { return strings.EqualFold(p, r) },
+	"IpAddress": func(p, r string) bool {
+		ip := net.ParseIP(r)
+		if ip == nil {
+			return false
+		}
+		if _, network, err := net.ParseCIDR(p); err == nil {
+			return network.Contains(ip)
+		}
+		return ip.Equal(net.ParseIP(p))
+	},
+}
+
+// parseOperator splits the condition operator to the set operator, base operator and IfExists flag.
+func parseOperator(operator string) (setOperator, string, bool, error) {
+	set := setNone
+	switch {
+	case strings.HasPrefix(operator, "ForAnyValue:"):
+		set, operator = setAnyValue, strings.TrimPrefix(operator, "ForAnyValue:")
+	case strings.HasPrefix(operator, "ForAllValues:"):
+		set, operator = setAllValues, strings.TrimPrefix(operator, "ForAllValues:")
+	}
+
+	ifExists := strings.HasSuffix(operator, "IfExists")
+	operator = strings.TrimSuffix(operator, "IfExists")
+
+	if operator == "Null" && set == setNone && !ifExists {
+		return set, operator, false, nil
+	}
+
+	base := operator
+	if positive, ok := negatedOperators[operator]; ok {
+		base = positive
+	}
+	if _, ok := operators[base]; !ok {
+		return 0, "", false, fmt.Errorf("unsupported condition operator: %s", operator)
+	}
+
+	return set, operator, ifExists, nil
+}
+
+func evaluateCondition(operator string, policyValues, requestValues []string) bool {
+	set, operator, ifExists, err := parseOperator(operator)
+	if err != nil {
+		return false
+	}
+
+	if operator == "Null" {
+		for _, val := range policyValues {
+			if strings.EqualFold(val, strconv.FormatBool(len(requestValues) == 0)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(requestValues) == 0 {
+		switch {
+		case ifExists:
+			return true
+		case set == setAllValues:
+			return true
+		case set == setAnyValue:
+			return false
+		}
+		// negated operators match absent keys
+		_, negated := negatedOperators[operator]
+		return negated
+	}
+
+	positive, negated := negatedOperators[operator]
+	if !negated {
+		positive = operator
+	}
+	match := operators[positive]
+
+	matchesAny := func(requestValue string) bool {
+		for _, policyValue := range policyValues {
+			if match(policyValue, requestValue) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if set == setAllValues {
+		for _, val := range requestValues {
+			if matchesAny(val) == negated {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, val := range requestValues {
+		if matchesAny(val) {
+			return !negated
+		}
+	}
+	return negated
+}
+
+func compareNumbers(policyValue, requestValue string, check func(int) bool) bool {
+	p, err := strconv.ParseFloat(policyValue, 64)
+	if err != nil {
+		return false
+	}
+	r, err := strconv.ParseFloat(requestValue, 64)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case r < p:
+		return check(-1)
+	case r > p:
+		return check(1)
+	default:
+		return check(0)
+	}
+}
+
+func compareDates(policyValue, requestValue string, check func(int) bool) bool {
+	p, err := parseDate(policyValue)
+	if err != nil {
+		return false
+	}
+	r, err := parseDate(requestValue)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case r.Before(p):
+		return check(-1)
+	case r.After(p):
+		return check(1)
+	default:
+		return check(0)
+	}
+}
+
+func parseDate(value string) (time.Time, error) {
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(epoch, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,244 @@
+// Package policy implements parsing and evaluation of S3 bucket policies.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Effects of the policy statement.
+const (
+	EffectAllow = "Allow"
+	EffectDeny  = "Deny"
+)
+
+const (
+	// ResourceARNPrefix is a prefix of S3 resources in the policy.
+	ResourceARNPrefix = "arn:aws:s3:::"
+	// Wildcard matches any principal, action or resource.
+	Wildcard = "*"
+
+	iamARNPrefix = "arn:aws:iam::"
+)
+
+type (
+	// Policy is a bucket policy document.
+	Policy struct {
+		Version   string      `json:"Version,omitempty"`
+		ID        string      `json:"Id,omitempty"`
+		Statement []Statement `json:"Statement"`
+	}
+
+	// Statement is a single statement of the bucket policy.
+	Statement struct {
+		Sid          string     `json:"Sid,omitempty"`
+		Effect       string     `json:"Effect"`
+		Principal    *Principal `json:"Principal,omitempty"`
+		NotPrincipal *Principal `json:"NotPrincipal,omitempty"`
+		Action       Values     `json:"Action,omitempty"`
+		NotAction    Values     `json:"NotAction,omitempty"`
+		Resource     Values     `json:"Resource,omitempty"`
+		NotResource  Values     `json:"NotResource,omitempty"`
+		Condition    Condition  `json:"Condition,omitempty"`
+	}
+
+	// Principal is a principal of the statement, either "*" or the lists of accounts and canonical users.
+	Principal struct {
+		Wildcard      bool
+		AWS           Values
+		CanonicalUser Values
+	}
+
+	// Condition maps condition operators to the condition keys and their values.
+	Condition map[string]map[string]Values
+
+	// Values is a list of strings which can be encoded as a single JSON value.
+	// Numbers and booleans are kept in their text form.
+	Values []string
+)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Values) UnmarshalJSON(data []byte) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		list = []json.RawMessage{data}
+	}
+
+	res := make(Values, 0, len(list))
+	for _, raw := range list {
+		val, err := scalarValue(raw)
+		if err != nil {
+			return err
+		}
+		res = append(res, val)
+	}
+
+	*v = res
+	return nil
+}
+
+func scalarValue(raw json.RawMessage) (string, error) {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str, nil
+	}
+
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return strconv.FormatBool(b), nil
+	}
+
+	var num json.Number
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&num); err == nil {
+		return num.String(), nil
+	}
+
+	return "", fmt.Errorf("invalid value: %s", raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		if str != Wildcard {
+			return fmt.Errorf("invalid principal: %s", str)
+		}
+		p.Wildcard = true
+		return nil
+	}
+
+	var fields map[string]Values
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("invalid principal: %w", err)
+	}
+
+	for key, values := range fields {
+		switch key {
+		case "AWS":
+			p.AWS = values
+		case "CanonicalUser":
+			p.CanonicalUser = values
+		default:
+			return fmt.Errorf("unsupported principal type: %s", key)
+		}
+	}
+
+	for _, val := range p.AWS {
+		if val == Wildcard {
+			p.Wildcard = true
+		}
+	}
+
+	return nil
+}
+
+// Parse decodes and validates the bucket policy document. All the policy resources must belong to the bucket.
+func Parse(data []byte, bucket string) (*Policy, error) {
+	var res Policy
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	if len(res.Statement) == 0 {
+		return nil, fmt.Errorf("policy has no statements")
+	}
+
+	for i, st := range res.Statement {
+		if err := st.validate(bucket); err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+	}
+
+	return &res, nil
+}
+
+func (s Statement) validate(bucket string) error {
+	if s.Effect != EffectAllow && s.Effect != EffectDeny {
+		return fmt.Errorf("invalid effect: %s", s.Effect)
+	}
+	if (s.Principal == nil) == (s.NotPrincipal == nil) {
+		return fmt.Errorf("exactly one of Principal and NotPrincipal must be set")
+	}
+	if (len(s.Action) == 0) == (len(s.NotAction) == 0) {
+		return fmt.Errorf("exactly one of Action and NotAction must be set")
+	}
+	if (len(s.Resource) == 0) == (len(s.NotResource) == 0) {
+		return fmt.Errorf("exactly one of Resource and NotResource must be set")
+	}
+
+	for _, resource := range append(append(Values{}, s.Resource...), s.NotResource...) {
+		if !resourceOfBucket(resource, bucket) {
+			return fmt.Errorf("resource '%s' doesn't belong to bucket '%s'", resource, bucket)
+		}
+	}
+
+	for operator, keys := range s.Condition {
+		if _, _, _, err := parseOperator(operator); err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("condition operator '%s' has no keys", operator)
+		}
+	}
+
+	return nil
+}
+
+func resourceOfBucket(resource, bucket string) bool {
+	if !strings.HasPrefix(resource, ResourceARNPrefix) {
+		return false
+	}
+
+	name := strings.TrimPrefix(resource, ResourceARNPrefix)
+	if idx := strings.IndexByte(name, '/'); idx >= 0 {
+		name = name[:idx]
+	}
+
+	return wildcardMatch(name, bucket, false)
+}
+
+// BucketResource returns ARN of the bucket.
+func BucketResource(bucket string) string {
+	return ResourceARNPrefix + bucket
+}
+
+// ObjectResource returns ARN of the object in the bucket.
+func ObjectResource(bucket, object string) string {
+	return ResourceARNPrefix + bucket + "/" + object
+}
+
+// UsesConditionKey checks if any statement has condition key with the prefix (case-insensitive).
+// It allows not to calculate expensive request values if the policy doesn't need them.
+func (p *Policy) UsesConditionKey(prefix string) bool {
+	prefix = strings.ToLower(prefix)
+	for _, st := range p.Statement {
+		for _, keys := range st.Condition {
+			for key := range keys {
+				if strings.HasPrefix(strings.ToLower(key), prefix) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// normalizePrincipal extracts account from IAM ARN, other principals are returned as is.
+func normalizePrincipal(principal string) string {
+	if !strings.HasPrefix(principal, iamARNPrefix) {
+		return principal
+	}
+
+	account := strings.TrimPrefix(principal, iamARNPrefix)
+	if idx := strings.IndexByte(account, ':'); idx >= 0 {
+		account = account[:idx]
+	}
+
+	return account
+}
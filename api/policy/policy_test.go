@@ -0,0 +1,271 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testBucket = "bucket"
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:GetObject",
+			"Resource": ["arn:aws:s3:::bucket/*"],
+			"Condition": {"NumericLessThan": {"s3:max-keys": 10}, "Bool": {"aws:SecureTransport": true}}
+		}, {
+			"Effect": "Deny",
+			"NotPrincipal": {"AWS": ["arn:aws:iam::NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM:root"], "CanonicalUser": "02a1b2"},
+			"NotAction": ["s3:Get*", "s3:List*"],
+			"NotResource": "arn:aws:s3:::bucket/public/*"
+		}]
+	}`), testBucket)
+	require.NoError(t, err)
+	require.Len(t, p.Statement, 2)
+	require.True(t, p.Statement[0].Principal.Wildcard)
+	require.Equal(t, Values{"s3:GetObject"}, p.Statement[0].Action)
+	require.Equal(t, Values{"10"}, p.Statement[0].Condition["NumericLessThan"]["s3:max-keys"])
+	require.Equal(t, Values{"true"}, p.Statement[0].Condition["Bool"]["aws:SecureTransport"])
+	require.Equal(t, Values{"02a1b2"}, p.Statement[1].NotPrincipal.CanonicalUser)
+
+	for _, tc := range []struct {
+		name   string
+		policy string
+	}{
+		{name: "invalid json", policy: `{"Statement": [`},
+		{name: "no statements", policy: `{"Statement": []}`},
+		{name: "invalid effect", policy: `{"Statement": [{"Effect": "Maybe", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket"}]}`},
+		{name: "no principal", policy: `{"Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket"}]}`},
+		{name: "unsupported principal", policy: `{"Statement": [{"Effect": "Allow", "Principal": {"Service": "s3"}, "Action": "s3:*", "Resource": "arn:aws:s3:::bucket"}]}`},
+		{name: "action and not action", policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "NotAction": "s3:Get*", "Resource": "arn:aws:s3:::bucket"}]}`},
+		{name: "foreign resource", policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::other/*"}]}`},
+		{name: "unknown operator", policy: `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket", "Condition": {"StringSimilar": {"s3:prefix": "a"}}}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.policy), testBucket)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:GetObject",
+			"Resource": "arn:aws:s3:::bucket/public/*"
+		}, {
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": "s3:*",
+			"Resource": ["arn:aws:s3:::bucket", "arn:aws:s3:::bucket/*"],
+			"Condition": {"Bool": {"aws:SecureTransport": "false"}}
+		}, {
+			"Effect": "Deny",
+			"Principal": {"AWS": "arn:aws:iam::owner-id:root"},
+			"Action": "s3:DeleteObject",
+			"Resource": "arn:aws:s3:::bucket/protected/*"
+		}, {
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:ListBucket",
+			"Resource": "arn:aws:s3:::bucket",
+			"Condition": {"StringLike": {"s3:prefix": "public/*"}, "IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+		}, {
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": "s3:GetObject",
+			"Resource": "arn:aws:s3:::bucket/*",
+			"Condition": {"StringEquals": {"s3:ExistingObjectTag/classification": "secret"}}
+		}]
+	}`), testBucket)
+	require.NoError(t, err)
+	require.True(t, p.UsesConditionKey("s3:ExistingObjectTag/"))
+	require.False(t, p.UsesConditionKey("aws:UserAgent"))
+
+	secure := map[string][]string{"aws:SecureTransport": {"true"}}
+
+	for _, tc := range []struct {
+		name     string
+		req      Request
+		expected Result
+	}{
+		{
+			name:     "public object",
+			req:      Request{Action: "s3:GetObject", Resource: ObjectResource(testBucket, "public/obj"), Conditions: secure},
+			expected: ResultAllow,
+		},
+		{
+			name:     "private object",
+			req:      Request{Action: "s3:GetObject", Resource: ObjectResource(testBucket, "private/obj"), Conditions: secure},
+			expected: ResultNone,
+		},
+		{
+			name: "insecure transport",
+			req: Request{Action: "s3:GetObject", Resource: ObjectResource(testBucket, "public/obj"),
+				Conditions: map[string][]string{"aws:SecureTransport": {"false"}}},
+			expected: ResultDeny,
+		},
+		{
+			name: "owner deletes protected object",
+			req: Request{Action: "s3:DeleteObject", Resource: ObjectResource(testBucket, "protected/obj"),
+				Principals: []string{"owner-id"}, Conditions: secure},
+			expected: ResultDeny,
+		},
+		{
+			name: "other user deletes protected object",
+			req: Request{Action: "s3:DeleteObject", Resource: ObjectResource(testBucket, "protected/obj"),
+				Principals: []string{"other-id"}, Conditions: secure},
+			expected: ResultNone,
+		},
+		{
+			name: "list public prefix from private network",
+			req: Request{Action: "s3:ListBucket", Resource: BucketResource(testBucket), Conditions: map[string][]string{
+				"aws:securetransport": {"true"}, "s3:prefix": {"public/docs"}, "aws:SourceIp": {"10.1.2.3"},
+			}},
+			expected: ResultAllow,
+		},
+		{
+			name: "list public prefix from public network",
+			req: Request{Action: "s3:ListBucket", Resource: BucketResource(testBucket), Conditions: map[string][]string{
+				"aws:SecureTransport": {"true"}, "s3:prefix": {"public/docs"}, "aws:SourceIp": {"192.168.1.1"},
+			}},
+			expected: ResultNone,
+		},
+		{
+			name: "secret object",
+			req: Request{Action: "s3:GetObject", Resource: ObjectResource(testBucket, "public/obj"), Conditions: map[string][]string{
+				"aws:SecureTransport": {"true"}, "s3:ExistingObjectTag/classification": {"secret"},
+			}},
+			expected: ResultDeny,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, p.Evaluate(tc.req))
+		})
+	}
+}
+
+func TestRestricts(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:ListBucket",
+			"Resource": "arn:aws:s3:::bucket",
+			"Condition": {"StringLike": {"s3:prefix": "public/*"}}
+		}, {
+			"Effect": "Allow",
+			"NotPrincipal": {"CanonicalUser": "blocked"},
+			"Action": "s3:GetObject",
+			"NotResource": "arn:aws:s3:::bucket/private/*"
+		}, {
+			"Effect": "Allow",
+			"Principal": {"CanonicalUser": "writer"},
+			"NotAction": "s3:Delete*",
+			"Resource": "arn:aws:s3:::bucket/*"
+		}, {
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": "s3:PutObject",
+			"Resource": "arn:aws:s3:::bucket/*",
+			"Condition": {"Bool": {"aws:SecureTransport": "false"}}
+		}]
+	}`), testBucket)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		req      Request
+		expected bool
+	}{
+		{
+			name:     "list with any prefix",
+			req:      Request{Action: "s3:ListBucket", Resource: BucketResource(testBucket)},
+			expected: true,
+		},
+		{
+			name:     "get any object",
+			req:      Request{Action: "s3:GetObject", Resource: ObjectResource(testBucket, "private/obj"), Principals: []string{"blocked"}},
+			expected: true,
+		},
+		{
+			name:     "writer deletes object",
+			req:      Request{Action: "s3:DeleteObject", Resource: ObjectResource(testBucket, "obj"), Principals: []string{"writer"}},
+			expected: true,
+		},
+		{
+			name:     "other user deletes object",
+			req:      Request{Action: "s3:DeleteObject", Resource: ObjectResource(testBucket, "obj"), Principals: []string{"other"}},
+			expected: false,
+		},
+		{
+			name:     "put object",
+			req:      Request{Action: "s3:PutObject", Resource: ObjectResource(testBucket, "obj")},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, p.Restricts(tc.req))
+		})
+	}
+
+	require.True(t, p.Statement[2].MatchesAction("s3:PutObject"))
+	require.False(t, p.Statement[2].MatchesAction("s3:DeleteObject"))
+	require.True(t, p.Statement[0].MatchesAction("s3:listbucket"))
+	require.False(t, p.Statement[0].MatchesAction("s3:GetObject"))
+}
+
+func TestConditionOperators(t *testing.T) {
+	for _, tc := range []struct {
+		operator string
+		policy   []string
+		request  []string
+		expected bool
+	}{
+		{operator: "StringEquals", policy: []string{"a", "b"}, request: []string{"b"}, expected: true},
+		{operator: "StringEquals", policy: []string{"a"}, request: nil, expected: false},
+		{operator: "StringEqualsIfExists", policy: []string{"a"}, request: nil, expected: true},
+		{operator: "StringNotEquals", policy: []string{"a"}, request: []string{"b"}, expected: true},
+		{operator: "StringNotEquals", policy: []string{"a"}, request: []string{"a"}, expected: false},
+		{operator: "StringNotEquals", policy: []string{"a"}, request: nil, expected: true},
+		{operator: "StringEqualsIgnoreCase", policy: []string{"ABC"}, request: []string{"abc"}, expected: true},
+		{operator: "StringLike", policy: []string{"a?c*"}, request: []string{"abcdef"}, expected: true},
+		{operator: "StringNotLike", policy: []string{"a*"}, request: []string{"abc"}, expected: false},
+		{operator: "NumericLessThan", policy: []string{"10"}, request: []string{"9"}, expected: true},
+		{operator: "NumericGreaterThanEquals", policy: []string{"10"}, request: []string{"9"}, expected: false},
+		{operator: "NumericEquals", policy: []string{"10"}, request: []string{"ten"}, expected: false},
+		{operator: "DateLessThan", policy: []string{"2030-01-01T00:00:00Z"}, request: []string{"2029-12-31T23:59:59Z"}, expected: true},
+		{operator: "DateGreaterThan", policy: []string{"2030-01-01"}, request: []string{"1893456000"}, expected: false},
+		{operator: "Bool", policy: []string{"true"}, request: []string{"TRUE"}, expected: true},
+		{operator: "IpAddress", policy: []string{"192.168.0.0/16"}, request: []string{"192.168.10.1"}, expected: true},
+		{operator: "IpAddress", policy: []string{"192.168.0.1"}, request: []string{"192.168.0.1"}, expected: true},
+		{operator: "NotIpAddress", policy: []string{"192.168.0.0/16"}, request: []string{"10.0.0.1"}, expected: true},
+		{operator: "Null", policy: []string{"true"}, request: nil, expected: true},
+		{operator: "Null", policy: []string{"false"}, request: nil, expected: false},
+		{operator: "ForAnyValue:StringEquals", policy: []string{"a"}, request: []string{"b", "a"}, expected: true},
+		{operator: "ForAnyValue:StringEquals", policy: []string{"a"}, request: nil, expected: false},
+		{operator: "ForAllValues:StringEquals", policy: []string{"a", "b"}, request: []string{"b", "a"}, expected: true},
+		{operator: "ForAllValues:StringEquals", policy: []string{"a"}, request: []string{"a", "c"}, expected: false},
+		{operator: "ForAllValues:StringEquals", policy: []string{"a"}, request: nil, expected: true},
+	} {
+		require.Equal(t, tc.expected, evaluateCondition(tc.operator, tc.policy, tc.request),
+			"%s %v %v", tc.operator, tc.policy, tc.request)
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	require.True(t, wildcardMatch("*", "", false))
+	require.True(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/a/b", false))
+	require.False(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket", false))
+	require.True(t, wildcardMatch("s3:get*", "s3:GetObject", true))
+	require.False(t, wildcardMatch("s3:get*", "s3:GetObject", false))
+	require.True(t, wildcardMatch("a*b*c", "aXXbYYc", false))
+	require.False(t, wildcardMatch("a*b*c", "aXXbYY", false))
+	require.True(t, wildcardMatch("a?c", "abc", false))
+}
@@ -0,0 +1,407 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
+	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// Default values of the replication worker parameters.
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 1000
+)
+
+type (
+	// Config contains parameters of the replication worker.
+	Config struct {
+		// Workers is a number of objects replicated concurrently.
+		Workers int
+		// QueueSize is a number of object versions waiting for replication,
+		// new versions are not replicated if the queue is full.
+		QueueSize int
+		// Targets are remote S3 endpoints, replication rules refer them by
+		// the Account field of the destination.
+		Targets map[string]*Target
+		// Events is an optional bus to publish events about failed replication to.
+		Events *events.Bus
+	}
+
+	// Target is a remote S3 endpoint objects are replicated to.
+	Target struct {
+		Endpoint        string
+		Region          string
+		AccessKeyID     string
+		SecretAccessKey string
+	}
+
+	// Worker replicates new object versions according to bucket replication
+	// configurations. Objects are replicated to the buckets of the gateway
+	// or to the remote S3 endpoints.
+	Worker struct {
+		log     *zap.Logger
+		obj     layer.Client
+		workers int
+		queue   chan task
+		targets map[string]*target
+		events  *events.Bus
+	}
+
+	target struct {
+		client   *s3.S3
+		uploader *s3manager.Uploader
+	}
+
+	task struct {
+		ctx context.Context
+		prm *handler.ReplicationParams
+	}
+)
+
+var _ handler.Replicator = (*Worker)(nil)
+
+// NewWorker creates a replication worker.
+func NewWorker(log *zap.Logger, obj layer.Client, cfg *Config) (*Worker, error) {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	targets := make(map[string]*target, len(cfg.Targets))
+	for name, t := range cfg.Targets {
+		sess, err := session.NewSession(&aws.Config{
+			Endpoint:         aws.String(t.Endpoint),
+			Region:           aws.String(t.Region),
+			Credentials:      credentials.NewStaticCredentials(t.AccessKeyID, t.SecretAccessKey, ""),
+			S3ForcePathStyle: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create session for target '%s': %w", name, err)
+		}
+
+		targets[name] = &target{
+			client:   s3.New(sess),
+			uploader: s3manager.NewUploader(sess),
+		}
+	}
+
+	if cfg.Events != nil {
+		cfg.Events.RegisterProducer(events.ReplicationOperationFailedReplication)
+	}
+
+	return &Worker{
+		log:     log,
+		obj:     obj,
+		workers: workers,
+		queue:   make(chan task, queueSize),
+		targets: targets,
+		events:  cfg.Events,
+	}, nil
+}
+
+// Run starts replicating queued object versions until the context is done.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("replication worker started", zap.Int("workers", w.workers))
+
+	for i := 0; i < w.workers; i++ {
+		go w.consume(ctx)
+	}
+
+	<-ctx.Done()
+	w.log.Info("replication worker stopped")
+}
+
+// CheckDestination implements handler.Replicator. Remote destination must be
+// a configured target, local destination bucket must exist and have versioning enabled.
+func (w *Worker) CheckDestination(ctx context.Context, dst data.ReplicationDestination) error {
+	if dst.Account != "" {
+		if _, ok := w.targets[dst.Account]; !ok {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidReplicationDestination, fmt.Errorf("unknown target: %s", dst.Account))
+		}
+		return nil
+	}
+
+	bktInfo, err := w.obj.GetBucketInfo(ctx, dst.BucketName())
+	if err != nil {
+		return err
+	}
+
+	settings, err := w.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+	if !settings.VersioningEnabled() {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidReplicationDestination, fmt.Errorf("versioning isn't enabled on the destination bucket"))
+	}
+
+	return nil
+}
+
+// Replicate implements handler.Replicator. Object version is replicated on behalf
+// of the requester in background, it's skipped if the queue is full.
+func (w *Worker) Replicate(ctx context.Context, p *handler.ReplicationParams) {
+	box, err := layer.GetBoxData(ctx)
+	if err != nil {
+		w.log.Error("couldn't replicate object", zap.String("bucket", p.BktInfo.Name),
+			zap.String("object", p.ObjectName), zap.Error(err))
+		return
+	}
+
+	select {
+	case w.queue <- task{ctx: context.WithValue(context.Background(), api.BoxData, box), prm: p}:
+	default:
+		w.log.Error("replication queue is full", zap.String("bucket", p.BktInfo.Name),
+			zap.String("object", p.ObjectName), zap.String("version", p.VersionID))
+	}
+}
+
+func (w *Worker) consume(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-w.queue:
+			w.process(t.ctx, t.prm)
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, p *handler.ReplicationParams) {
+	log := w.log.With(zap.String("bucket", p.BktInfo.Name), zap.String("object", p.ObjectName),
+		zap.String("version", p.VersionID))
+
+	conf, err := w.obj.GetBucketReplicationConfiguration(ctx, p.BktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrReplicationConfigurationNotFoundError) {
+			log.Error("couldn't get replication configuration", zap.Error(err))
+		}
+		return
+	}
+
+	objVersion := &layer.ObjectVersion{
+		BktInfo:    p.BktInfo,
+		ObjectName: p.ObjectName,
+		VersionID:  p.VersionID,
+	}
+
+	rule, err := conf.MatchRule(p.ObjectName, func() (map[string]string, error) {
+		if p.DeleteMarker {
+			return nil, nil
+		}
+		prm := *objVersion
+		_, tags, err := w.obj.GetObjectTagging(ctx, &prm)
+		return tags, err
+	})
+	if err != nil {
+		log.Error("couldn't match replication rule", zap.Error(err))
+		return
+	}
+	if rule == nil {
+		return
+	}
+
+	if p.DeleteMarker {
+		if rule.ReplicateDeleteMarkers() {
+			if err = w.replicateDeleteMarker(ctx, rule.Destination, p.ObjectName); err != nil {
+				log.Error("couldn't replicate delete marker", zap.Error(err))
+			}
+		}
+		return
+	}
+
+	w.setStatus(ctx, log, objVersion, data.ReplicationStatusPending)
+
+	status := data.ReplicationStatusCompleted
+	if err = w.replicateObject(ctx, rule.Destination, objVersion); err != nil {
+		log.Error("couldn't replicate object", zap.Error(err))
+		status = data.ReplicationStatusFailed
+	}
+
+	w.setStatus(ctx, log, objVersion, status)
+
+	if status == data.ReplicationStatusFailed && w.events != nil {
+		w.events.Publish(ctx, &events.Event{
+			Name:    events.ReplicationOperationFailedReplication,
+			BktInfo: p.BktInfo,
+			Object:  &data.NotificationInfo{Name: p.ObjectName, Version: p.VersionID},
+		})
+	}
+}
+
+func (w *Worker) setStatus(ctx context.Context, log *zap.Logger, p *layer.ObjectVersion, status string) {
+	if err := w.obj.PutObjectReplicationStatus(ctx, p, status); err != nil {
+		log.Error("couldn't put replication status", zap.String("status", status), zap.Error(err))
+	}
+}
+
+func (w *Worker) replicateObject(ctx context.Context, dst data.ReplicationDestination, p *layer.ObjectVersion) error {
+	extendedInfo, err := w.obj.GetObjectInfo(ctx, &layer.HeadObjectParams{
+		BktInfo:   p.BktInfo,
+		Object:    p.ObjectName,
+		VersionID: p.VersionID,
+	})
+	if err != nil {
+		return fmt.Errorf("get object info: %w", err)
+	}
+	info := extendedInfo.ObjectInfo
+
+	if info.EncryptionInfo.Enabled() && !info.EncryptionInfo.Managed() {
+		return fmt.Errorf("object encrypted with customer-provided key can't be replicated")
+	}
+
+	prm := *p
+	_, tags, err := w.obj.GetObjectTagging(ctx, &prm)
+	if err != nil {
+		return fmt.Errorf("get object tagging: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := w.obj.GetObject(ctx, &layer.GetObjectParams{
+			ObjectInfo: info,
+			BucketInfo: p.BktInfo,
+			Writer:     pw,
+		})
+		_ = pw.CloseWithError(err)
+	}()
+	defer pr.Close()
+
+	if dst.Account != "" {
+		return w.uploadRemote(ctx, dst, info, tags, pr)
+	}
+	return w.putLocal(ctx, dst, info, tags, pr)
+}
+
+func (w *Worker) putLocal(ctx context.Context, dst data.ReplicationDestination, info *data.ObjectInfo, tags map[string]string, payload io.Reader) error {
+	bktInfo, err := w.obj.GetBucketInfo(ctx, dst.BucketName())
+	if err != nil {
+		return fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	header := make(map[string]string, len(info.Headers)+1)
+	for key, val := range info.Headers {
+		if !layer.IsSystemHeader(key) || key == api.CacheControl || key == api.Expires {
+			header[key] = val
+		}
+	}
+	if info.ContentType != "" {
+		header[api.ContentType] = info.ContentType
+	}
+
+	replica, err := w.obj.PutObject(ctx, &layer.PutObjectParams{
+		BktInfo: bktInfo,
+		Object:  info.Name,
+		Size:    info.Size,
+		Reader:  payload,
+		Header:  header,
+	})
+	if err != nil {
+		return fmt.Errorf("put replica: %w", err)
+	}
+
+	replicaVersion := &layer.ObjectVersion{
+		BktInfo:    bktInfo,
+		ObjectName: replica.Name,
+		VersionID:  replica.Version(),
+	}
+
+	if len(tags) != 0 {
+		if _, err = w.obj.PutObjectTagging(ctx, replicaVersion, tags); err != nil {
+			return fmt.Errorf("put replica tagging: %w", err)
+		}
+	}
+
+	return w.obj.PutObjectReplicationStatus(ctx, replicaVersion, data.ReplicationStatusReplica)
+}
+
+func (w *Worker) uploadRemote(ctx context.Context, dst data.ReplicationDestination, info *data.ObjectInfo, tags map[string]string, payload io.Reader) error {
+	t := w.targets[dst.Account]
+	if t == nil {
+		return fmt.Errorf("unknown target: %s", dst.Account)
+	}
+
+	input := &s3manager.UploadInput{
+		Bucket:   aws.String(dst.BucketName()),
+		Key:      aws.String(info.Name),
+		Body:     payload,
+		Metadata: make(map[string]*string),
+	}
+	if dst.StorageClass != "" {
+		input.StorageClass = aws.String(dst.StorageClass)
+	}
+	if info.ContentType != "" {
+		input.ContentType = aws.String(info.ContentType)
+	}
+	if cacheControl := info.Headers[api.CacheControl]; cacheControl != "" {
+		input.CacheControl = aws.String(cacheControl)
+	}
+	for key, val := range info.Headers {
+		if !layer.IsSystemHeader(key) {
+			input.Metadata[key] = aws.String(val)
+		}
+	}
+	if len(tags) != 0 {
+		query := make(url.Values, len(tags))
+		for key, val := range tags {
+			query.Set(key, val)
+		}
+		input.Tagging = aws.String(query.Encode())
+	}
+
+	if _, err := t.uploader.UploadWithContext(ctx, input); err != nil {
+		return fmt.Errorf("upload to target '%s': %w", dst.Account, err)
+	}
+
+	return nil
+}
+
+func (w *Worker) replicateDeleteMarker(ctx context.Context, dst data.ReplicationDestination, objName string) error {
+	if dst.Account != "" {
+		t := w.targets[dst.Account]
+		if t == nil {
+			return fmt.Errorf("unknown target: %s", dst.Account)
+		}
+
+		_, err := t.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(dst.BucketName()),
+			Key:    aws.String(objName),
+		})
+		return err
+	}
+
+	bktInfo, err := w.obj.GetBucketInfo(ctx, dst.BucketName())
+	if err != nil {
+		return fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	settings, err := w.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return fmt.Errorf("get destination bucket settings: %w", err)
+	}
+
+	deleted := w.obj.DeleteObjects(ctx, &layer.DeleteObjectParams{
+		BktInfo:  bktInfo,
+		Objects:  []*layer.VersionedObject{{Name: objName}},
+		Settings: settings,
+	})
+
+	return deleted[0].Error
+}
@@ -23,6 +23,7 @@ type (
 	ReqInfo struct {
 		sync.RWMutex
 		RemoteHost   string   // Client Host/IP
+		ClientIP     string   // Client IP for access control, forwarding headers are honored only from trusted proxies
 		Host         string   // Node Host/IP
 		UserAgent    string   // User Agent
 		DeploymentID string   // random generated s3-deployment-id
@@ -31,6 +32,8 @@ type (
 		BucketName   string   // Bucket name
 		ObjectName   string   // Object name
 		URL          *url.URL // Request url
+		User         string   // Requester, empty for anonymous requests
+		ErrorCode    string   // S3 error code of the response
 		tags         []KeyVal // Any additional info not accommodated by above fields
 	}
 
@@ -104,6 +107,35 @@ func GetSourceIP(r *http.Request) string {
 	return addr
 }
 
+// GetClientIP returns the address of the client for access control. Unlike
+// GetSourceIP, forwarding headers are used only if the request comes from one
+// of the trusted proxies, otherwise they could be forged by the client.
+func GetClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	addr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		addr = r.RemoteAddr
+	}
+
+	if !containsIP(trustedProxies, addr) {
+		return addr
+	}
+
+	if fwd := r.Header.Values(xForwardedFor); len(fwd) != 0 {
+		// every proxy appends the address of its peer, so the rightmost
+		// address which isn't a trusted proxy is the client one
+		hops := strings.Split(strings.Join(fwd, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr = strings.TrimSpace(hops[i])
+			if !containsIP(trustedProxies, addr) {
+				break
+			}
+		}
+		return addr
+	}
+
+	return GetSourceIP(r)
+}
+
 func prepareContext(w http.ResponseWriter, r *http.Request) context.Context {
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
@@ -135,6 +167,7 @@ func NewReqInfo(w http.ResponseWriter, r *http.Request, req ObjectRequest) *ReqI
 		ObjectName:   req.Object,
 		UserAgent:    r.UserAgent(),
 		RemoteHost:   GetSourceIP(r),
+		ClientIP:     GetClientIP(r, nil),
 		RequestID:    GetRequestID(w),
 		DeploymentID: deploymentID.String(),
 		URL:          r.URL,
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClientIP(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+	trusted := []*net.IPNet{proxies}
+
+	for _, tc := range []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "direct",
+			remoteAddr: "192.168.0.1:1234",
+			expected:   "192.168.0.1",
+		},
+		{
+			name:       "forged by client",
+			remoteAddr: "192.168.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.5", "X-Real-IP": "10.0.0.6"},
+			expected:   "192.168.0.1",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 192.168.0.1, 10.0.0.2"},
+			expected:   "192.168.0.1",
+		},
+		{
+			name:       "trusted proxy with real ip",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "192.168.0.1"},
+			expected:   "192.168.0.1",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.1:1234",
+			expected:   "10.0.0.1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			require.Equal(t, tc.expected, GetClientIP(r, trusted))
+		})
+	}
+}
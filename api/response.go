@@ -128,6 +128,7 @@ func WriteErrorResponse(w http.ResponseWriter, reqInfo *ReqInfo, err error) {
 
 	// Generates error response.
 	errorResponse := getAPIErrorResponse(reqInfo, err)
+	reqInfo.ErrorCode = errorResponse.Code
 	encodedErrorResponse := EncodeResponse(errorResponse)
 	WriteResponse(w, code, encodedErrorResponse, MimeXML)
 }
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"go.uber.org/zap"
+)
+
+var (
+	// listingRoutes are operations listing keys which start with the prefix query parameter.
+	listingRoutes = map[string]struct{}{
+		"ListObjectsV1":        {},
+		"ListObjectsV2":        {},
+		"ListObjectsV2M":       {},
+		"ListBucketVersions":   {},
+		"ListMultipartUploads": {},
+	}
+
+	// bodyKeysRoutes are operations which take object keys from the request
+	// body, so they can't be checked against key prefixes.
+	bodyKeysRoutes = map[string]struct{}{
+		"DeleteMultipleObjects": {},
+		"PostObject":            {},
+	}
+
+	// copyRoutes are operations which read the object from X-Amz-Copy-Source.
+	copyRoutes = map[string]struct{}{
+		"CopyObject":     {},
+		"UploadPartCopy": {},
+	}
+
+	// uploadRoutes are operations which upload object payload in the request body.
+	uploadRoutes = map[string]struct{}{
+		"PutObject":  {},
+		"UploadPart": {},
+		"PostObject": {},
+	}
+)
+
+// AttachRestrictions adds a check of the access box restrictions to router
+// using log for logging. It must be attached after AttachUserAuth, requests
+// without access box aren't checked.
+func AttachRestrictions(router *mux.Router, log *zap.Logger) {
+	router.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			box, ok := r.Context().Value(BoxData).(*accessbox.Box)
+			if ok && box.Restrictions != nil {
+				reqInfo := GetReqInfo(r.Context())
+				if err := checkRestrictions(r, reqInfo, box.Restrictions); err != nil {
+					log.Error("request is denied by access box restrictions",
+						zap.String("request_id", reqInfo.RequestID), zap.Error(err))
+					WriteErrorResponse(w, reqInfo, apiErrors.GetAPIError(apiErrors.ErrAccessDenied))
+					return
+				}
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	})
+}
+
+// CheckObjectSize checks the size of the object created from already stored
+// data (copies and completed multipart uploads) against the access box
+// restrictions from ctx, the payload size of uploads is checked by the router.
+func CheckObjectSize(ctx context.Context, size uint64) error {
+	box, ok := ctx.Value(BoxData).(*accessbox.Box)
+	if !ok || box.Restrictions == nil || box.Restrictions.MaxObjectSize == 0 {
+		return nil
+	}
+
+	if size > box.Restrictions.MaxObjectSize {
+		return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+	}
+
+	return nil
+}
+
+func checkRestrictions(r *http.Request, reqInfo *ReqInfo, restrictions *accessbox.Restrictions) error {
+	if len(restrictions.Operations) != 0 && !containsString(restrictions.Operations, reqInfo.API) {
+		return fmt.Errorf("operation %s isn't allowed", reqInfo.API)
+	}
+
+	if len(restrictions.SourceNetworks) != 0 && !containsIP(restrictions.SourceNetworks, reqInfo.ClientIP) {
+		return fmt.Errorf("source address %s isn't allowed", reqInfo.ClientIP)
+	}
+
+	if _, ok := uploadRoutes[reqInfo.API]; ok && restrictions.MaxObjectSize != 0 {
+		if r.ContentLength < 0 {
+			return errors.New("payload size is unknown")
+		}
+		if uint64(r.ContentLength) > restrictions.MaxObjectSize {
+			return fmt.Errorf("payload size %d exceeds %d", r.ContentLength, restrictions.MaxObjectSize)
+		}
+	}
+
+	key := reqInfo.ObjectName
+	if _, ok := listingRoutes[reqInfo.API]; ok {
+		key = r.URL.Query().Get("prefix")
+	} else if _, ok = bodyKeysRoutes[reqInfo.API]; ok && len(restrictions.Prefixes) != 0 {
+		return fmt.Errorf("operation %s isn't allowed with key prefix restrictions", reqInfo.API)
+	} else if key == "" {
+		// bucket operations aren't restricted by key prefixes
+		return checkObject(restrictions, reqInfo.BucketName, nil)
+	}
+
+	if err := checkObject(restrictions, reqInfo.BucketName, &key); err != nil {
+		return err
+	}
+
+	if _, ok := copyRoutes[reqInfo.API]; ok {
+		srcBucket, srcKey := copySource(r)
+		return checkObject(restrictions, srcBucket, &srcKey)
+	}
+
+	return nil
+}
+
+// checkObject checks the bucket and the key, the bucket is checked only if it's
+// set and the key only if it's not nil.
+func checkObject(restrictions *accessbox.Restrictions, bucket string, key *string) error {
+	if bucket != "" && len(restrictions.Buckets) != 0 && !containsString(restrictions.Buckets, bucket) {
+		return fmt.Errorf("bucket %s isn't allowed", bucket)
+	}
+
+	if key == nil || len(restrictions.Prefixes) == 0 {
+		return nil
+	}
+
+	for _, prefix := range restrictions.Prefixes {
+		if strings.HasPrefix(*key, prefix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key %s isn't allowed", *key)
+}
+
+// copySource returns the bucket and the key of X-Amz-Copy-Source header.
+func copySource(r *http.Request) (bucket, key string) {
+	src := r.Header.Get(AmzCopySource)
+	if u, err := url.Parse(src); err == nil {
+		src = u.Path
+	}
+
+	src = strings.TrimPrefix(src, SlashSeparator)
+	if i := strings.Index(src, SlashSeparator); i >= 0 {
+		return src[:i], src[i+len(SlashSeparator):]
+	}
+	return src, ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(networks []*net.IPNet, host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
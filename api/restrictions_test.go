@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRestrictions(t *testing.T) {
+	_, network, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	restrictions := &accessbox.Restrictions{
+		Buckets:        []string{"bucket", "backup"},
+		Prefixes:       []string{"photos/"},
+		Operations:     []string{"GetObject", "PutObject", "CopyObject", "ListObjectsV2", "GetBucketACL", "DeleteMultipleObjects"},
+		SourceNetworks: []*net.IPNet{network},
+		MaxObjectSize:  10,
+	}
+
+	for _, tc := range []struct {
+		name    string
+		api     string
+		url     string
+		bucket  string
+		object  string
+		host    string
+		size    int
+		header  [2]string
+		allowed bool
+	}{
+		{name: "allowed object", api: "GetObject", bucket: "bucket", object: "photos/cat.jpg", allowed: true},
+		{name: "operation", api: "DeleteObject", bucket: "bucket", object: "photos/cat.jpg"},
+		{name: "bucket", api: "GetObject", bucket: "other", object: "photos/cat.jpg"},
+		{name: "prefix", api: "GetObject", bucket: "bucket", object: "docs/cv.pdf"},
+		{name: "source address", api: "GetObject", bucket: "bucket", object: "photos/cat.jpg", host: "192.168.0.1"},
+		{name: "bucket operation", api: "GetBucketACL", bucket: "bucket", allowed: true},
+		{name: "allowed upload", api: "PutObject", bucket: "bucket", object: "photos/cat.jpg", size: 10, allowed: true},
+		{name: "object size", api: "PutObject", bucket: "bucket", object: "photos/cat.jpg", size: 11},
+		{name: "allowed listing", api: "ListObjectsV2", url: "/bucket?prefix=photos/2022", bucket: "bucket", allowed: true},
+		{name: "listing without prefix", api: "ListObjectsV2", url: "/bucket", bucket: "bucket"},
+		{name: "keys in body", api: "DeleteMultipleObjects", url: "/bucket?delete", bucket: "bucket"},
+		{name: "allowed copy", api: "CopyObject", bucket: "bucket", object: "photos/cat.jpg",
+			header: [2]string{AmzCopySource, "/backup/photos/cat%20copy.jpg"}, allowed: true},
+		{name: "copy source bucket", api: "CopyObject", bucket: "bucket", object: "photos/cat.jpg",
+			header: [2]string{AmzCopySource, "other/photos/cat.jpg"}},
+		{name: "copy source key", api: "CopyObject", bucket: "bucket", object: "photos/cat.jpg",
+			header: [2]string{AmzCopySource, "bucket/docs/cv.pdf"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.url == "" {
+				tc.url = "/" + tc.bucket + "/" + tc.object
+			}
+			if tc.host == "" {
+				tc.host = "10.0.0.1"
+			}
+
+			r := httptest.NewRequest(http.MethodPut, tc.url, nil)
+			r.ContentLength = int64(tc.size)
+			if tc.header[0] != "" {
+				r.Header.Set(tc.header[0], tc.header[1])
+			}
+
+			reqInfo := &ReqInfo{API: tc.api, BucketName: tc.bucket, ObjectName: tc.object, ClientIP: tc.host}
+
+			err := checkRestrictions(r, reqInfo, restrictions)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -2,12 +2,14 @@ package api
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
@@ -45,9 +47,14 @@ type (
 		GetBucketAccelerateHandler(http.ResponseWriter, *http.Request)
 		GetBucketRequestPaymentHandler(http.ResponseWriter, *http.Request)
 		GetBucketLoggingHandler(http.ResponseWriter, *http.Request)
+		PutBucketLoggingHandler(http.ResponseWriter, *http.Request)
+		GetBucketQuotaHandler(http.ResponseWriter, *http.Request)
+		PutBucketQuotaHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketQuotaHandler(http.ResponseWriter, *http.Request)
 		GetBucketReplicationHandler(http.ResponseWriter, *http.Request)
 		GetBucketTaggingHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketWebsiteHandler(http.ResponseWriter, *http.Request)
+		WebsiteHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketTaggingHandler(http.ResponseWriter, *http.Request)
 		GetBucketObjectLockConfigHandler(http.ResponseWriter, *http.Request)
 		GetBucketVersioningHandler(http.ResponseWriter, *http.Request)
@@ -58,6 +65,8 @@ type (
 		ListBucketObjectVersionsHandler(http.ResponseWriter, *http.Request)
 		ListObjectsV1Handler(http.ResponseWriter, *http.Request)
 		PutBucketLifecycleHandler(http.ResponseWriter, *http.Request)
+		PutBucketReplicationHandler(http.ResponseWriter, *http.Request)
+		PutBucketWebsiteHandler(http.ResponseWriter, *http.Request)
 		PutBucketEncryptionHandler(http.ResponseWriter, *http.Request)
 		PutBucketPolicyHandler(http.ResponseWriter, *http.Request)
 		PutBucketObjectLockConfigHandler(http.ResponseWriter, *http.Request)
@@ -70,6 +79,7 @@ type (
 		DeleteMultipleObjectsHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketPolicyHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketLifecycleHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketReplicationHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketEncryptionHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketHandler(http.ResponseWriter, *http.Request)
 		ListBucketsHandler(http.ResponseWriter, *http.Request)
@@ -115,6 +125,13 @@ func (lrw *logResponseWriter) WriteHeader(code int) {
 	})
 }
 
+// Flush implements http.Flusher to stream responses through the logger.
+func (lrw *logResponseWriter) Flush() {
+	if f, ok := lrw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func setRequestID(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// generate random UUIDv4
@@ -136,6 +153,16 @@ func setRequestID(h http.Handler) http.Handler {
 	})
 }
 
+// setClientIP sets the client address for access control into the request info.
+func setClientIP(trustedProxies []*net.IPNet) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			GetReqInfo(r.Context()).ClientIP = GetClientIP(r, trustedProxies)
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
 func appendCORS(handler Handler) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -186,21 +213,35 @@ func GetRequestID(v interface{}) string {
 }
 
 // Attach adds S3 API handlers from h to r for domains with m client limit using
-// center authentication and log logger.
-func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center auth.Center, log *zap.Logger) {
+// center authentication and log logger. Requests to subdomains of websiteDomains
+// are served as static websites of the buckets. Requests to buckets are passed
+// to the optional access logger. Forwarding headers are used to get the client
+// address for access control only if requests come from trustedProxies.
+func Attach(r *mux.Router, domains, websiteDomains []string, trustedProxies []*net.IPNet, m MaxClients, h Handler, center auth.Center, accessLogger AccessLogger, log *zap.Logger) {
+	attachWebsite(r, websiteDomains, trustedProxies, m, h, log)
+
 	api := r.PathPrefix(SlashSeparator).Subrouter()
 
 	api.Use(
 		// -- prepare request
 		setRequestID,
+		setClientIP(trustedProxies),
 
 		// -- logging error requests
 		logErrorResponse(log),
 	)
 
+	if accessLogger != nil {
+		// -- server access logs of the buckets
+		api.Use(accessLog(accessLogger))
+	}
+
 	// Attach user authentication for all S3 routes.
 	AttachUserAuth(api, center, log)
 
+	// Check restrictions of the access box the request is signed with.
+	AttachRestrictions(api, log)
+
 	buckets := make([]*mux.Router, 0, len(domains)+1)
 	buckets = append(buckets, api.PathPrefix("/{bucket}").Subrouter())
 
@@ -341,7 +382,7 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketacl", h.PutBucketACLHandler))).Queries("acl", "").
 			Name("PutBucketACL")
-		// GetBucketWebsiteHandler -- this is a dummy call.
+		// GetBucketWebsite
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketwebsite", h.GetBucketWebsiteHandler))).Queries("website", "").
 			Name("GetBucketWebsite")
@@ -353,15 +394,15 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketrequestpayment", h.GetBucketRequestPaymentHandler))).Queries("requestPayment", "").
 			Name("GetBucketRequestPayment")
-		// GetBucketLoggingHandler -- this is a dummy call.
+		// GetBucketLogging
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketlogging", h.GetBucketLoggingHandler))).Queries("logging", "").
 			Name("GetBucketLogging")
-		// GetBucketLifecycleHandler -- this is a dummy call.
+		// GetBucketQuota -- gateway extension.
 		bucket.Methods(http.MethodGet).HandlerFunc(
-			m.Handle(metrics.APIStats("getbucketlifecycle", h.GetBucketLifecycleHandler))).Queries("lifecycle", "").
-			Name("GetBucketLifecycle")
-		// GetBucketReplicationHandler -- this is a dummy call.
+			m.Handle(metrics.APIStats("getbucketquota", h.GetBucketQuotaHandler))).Queries("quota", "").
+			Name("GetBucketQuota")
+		// GetBucketReplication
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketreplication", h.GetBucketReplicationHandler))).Queries("replication", "").
 			Name("GetBucketReplication")
@@ -369,10 +410,14 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbuckettagging", h.GetBucketTaggingHandler))).Queries("tagging", "").
 			Name("GetBucketTagging")
-		// DeleteBucketWebsiteHandler
+		// DeleteBucketWebsite
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketwebsite", h.DeleteBucketWebsiteHandler))).Queries("website", "").
 			Name("DeleteBucketWebsite")
+		// DeleteBucketQuota -- gateway extension.
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketquota", h.DeleteBucketQuotaHandler))).Queries("quota", "").
+			Name("DeleteBucketQuota")
 		// DeleteBucketTaggingHandler
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebuckettagging", h.DeleteBucketTaggingHandler))).Queries("tagging", "").
@@ -413,6 +458,22 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketlifecycle", h.PutBucketLifecycleHandler))).Queries("lifecycle", "").
 			Name("PutBucketLifecycle")
+		// PutBucketReplication
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketreplication", h.PutBucketReplicationHandler))).Queries("replication", "").
+			Name("PutBucketReplication")
+		// PutBucketWebsite
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketwebsite", h.PutBucketWebsiteHandler))).Queries("website", "").
+			Name("PutBucketWebsite")
+		// PutBucketLogging
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketlogging", h.PutBucketLoggingHandler))).Queries("logging", "").
+			Name("PutBucketLogging")
+		// PutBucketQuota -- gateway extension.
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketquota", h.PutBucketQuotaHandler))).Queries("quota", "").
+			Name("PutBucketQuota")
 		// PutBucketEncryption
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketencryption", h.PutBucketEncryptionHandler))).Queries("encryption", "").
@@ -463,6 +524,10 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketlifecycle", h.DeleteBucketLifecycleHandler))).Queries("lifecycle", "").
 			Name("DeleteBucketLifecycle")
+		// DeleteBucketReplication
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketreplication", h.DeleteBucketReplicationHandler))).Queries("replication", "").
+			Name("DeleteBucketReplication")
 		// DeleteBucketEncryption
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketencryption", h.DeleteBucketEncryptionHandler))).Queries("encryption", "").
@@ -489,3 +554,22 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 	api.NotFoundHandler = metrics.APIStats("notfound", errorResponseHandler)
 	api.MethodNotAllowedHandler = metrics.APIStats("methodnotallowed", errorResponseHandler)
 }
+
+// attachWebsite adds website endpoints for the domains. Website endpoints serve only
+// GET and HEAD requests without authentication.
+func attachWebsite(r *mux.Router, domains []string, trustedProxies []*net.IPNet, m MaxClients, h Handler, log *zap.Logger) {
+	for _, domain := range domains {
+		website := r.Host("{bucket:.+}." + domain).Subrouter()
+		website.Use(
+			setRequestID,
+			setClientIP(trustedProxies),
+			logErrorResponse(log),
+		)
+
+		website.Methods(http.MethodGet, http.MethodHead).Path("/{object:.*}").HandlerFunc(
+			m.Handle(metrics.APIStats("website", h.WebsiteHandler))).Name("Website")
+		website.NewRoute().HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WriteErrorResponse(w, GetReqInfo(r.Context()), errors.GetAPIError(errors.ErrMethodNotAllowed))
+		}).Name("WebsiteMethodNotAllowed")
+	}
+}
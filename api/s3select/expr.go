@@ -0,0 +1,723 @@
+package s3select
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// expr is a node of the SQL expression tree.
+	expr interface {
+		eval(e *env) (interface{}, error)
+	}
+
+	// env is an environment the expression is evaluated in.
+	env struct {
+		rec   record
+		alias string
+		// aggregates contains results of aggregate functions, it's set when
+		// the projections of the aggregate query are evaluated.
+		aggregates []interface{}
+	}
+
+	// pathStep is a step of the path to the value in the record: a key or an array index.
+	pathStep struct {
+		name     string
+		quoted   bool
+		index    int
+		isIndex  bool
+		wildcard bool
+	}
+
+	literalExpr struct {
+		val interface{}
+	}
+
+	columnExpr struct {
+		path []pathStep
+	}
+
+	unaryExpr struct {
+		op string
+		x  expr
+	}
+
+	binaryExpr struct {
+		op   string
+		l, r expr
+	}
+
+	likeExpr struct {
+		x, pattern, escape expr
+		not                bool
+	}
+
+	betweenExpr struct {
+		x, lo, hi expr
+		not       bool
+	}
+
+	inExpr struct {
+		x    expr
+		list []expr
+		not  bool
+	}
+
+	isExpr struct {
+		x       expr
+		missing bool
+		not     bool
+	}
+
+	castExpr struct {
+		x   expr
+		typ string
+	}
+
+	funcExpr struct {
+		name string
+		args []expr
+	}
+
+	aggregateExpr struct {
+		name string
+		// arg is nil for COUNT(*).
+		arg expr
+		idx int
+	}
+)
+
+func (e *env) isAlias(name string) bool {
+	return strings.EqualFold(name, e.alias) || strings.EqualFold(name, "S3Object")
+}
+
+func (l *literalExpr) eval(*env) (interface{}, error) {
+	return l.val, nil
+}
+
+func (c *columnExpr) eval(e *env) (interface{}, error) {
+	if e.rec == nil {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure,
+			fmt.Errorf("column '%s' can't be used outside of aggregate function", c.name()))
+	}
+
+	steps := c.path
+	if !steps[0].quoted && !steps[0].isIndex && e.isAlias(steps[0].name) {
+		if len(steps) == 1 {
+			return e.rec.value(), nil
+		}
+		steps = steps[1:]
+	}
+
+	var val interface{}
+	if steps[0].isIndex {
+		val = navigate(e.rec.value(), steps[0])
+	} else {
+		val = e.rec.get(steps[0].name, steps[0].quoted)
+	}
+	for _, step := range steps[1:] {
+		val = navigate(val, step)
+	}
+
+	return val, nil
+}
+
+// name returns the name of the column used in the output.
+func (c *columnExpr) name() string {
+	for i := len(c.path) - 1; i >= 0; i-- {
+		if !c.path[i].isIndex {
+			return c.path[i].name
+		}
+	}
+	return ""
+}
+
+func navigate(val interface{}, step pathStep) interface{} {
+	if step.isIndex {
+		arr, ok := val.([]interface{})
+		if !ok || step.index < 0 || step.index >= len(arr) {
+			return missing
+		}
+		return arr[step.index]
+	}
+
+	obj, ok := val.(*jsonObject)
+	if !ok {
+		return missing
+	}
+	return obj.get(step.name, step.quoted)
+}
+
+func (u *unaryExpr) eval(e *env) (interface{}, error) {
+	val, err := u.x.eval(e)
+	if err != nil || isNull(val) {
+		return nil, err
+	}
+
+	if u.op == "NOT" {
+		b, ok := toBool(val)
+		if !ok {
+			return nil, invalidType("NOT", val)
+		}
+		return !b, nil
+	}
+
+	num, ok := toNumber(val)
+	if !ok {
+		return nil, invalidType(u.op, val)
+	}
+	if u.op == "+" {
+		return num, nil
+	}
+	if i, ok := num.(int64); ok {
+		return -i, nil
+	}
+	return -num.(float64), nil
+}
+
+func (b *binaryExpr) eval(e *env) (interface{}, error) {
+	switch b.op {
+	case "AND", "OR":
+		return b.evalLogical(e)
+	}
+
+	l, err := b.l.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	r, err := b.r.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(l) || isNull(r) {
+		return nil, nil
+	}
+
+	switch b.op {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		return compare(b.op, l, r), nil
+	case "||":
+		return formatValue(l) + formatValue(r), nil
+	default:
+		return arithmetic(b.op, l, r)
+	}
+}
+
+// evalLogical implements three-valued logic of AND and OR.
+func (b *binaryExpr) evalLogical(e *env) (interface{}, error) {
+	l, err := evalBool(b.l, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// short circuit
+	if l != nil && l.(bool) == (b.op == "OR") {
+		return l, nil
+	}
+
+	r, err := evalBool(b.r, e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r != nil && r.(bool) == (b.op == "OR"):
+		return r, nil
+	case l == nil || r == nil:
+		return nil, nil
+	}
+	return b.op == "AND", nil
+}
+
+// evalBool evaluates the expression to bool or nil (unknown).
+func evalBool(x expr, e *env) (interface{}, error) {
+	val, err := x.eval(e)
+	if err != nil || isNull(val) {
+		return nil, err
+	}
+
+	b, ok := toBool(val)
+	if !ok {
+		return nil, invalidType("boolean expression", val)
+	}
+	return b, nil
+}
+
+func compare(op string, l, r interface{}) interface{} {
+	res, ok := compareValues(l, r)
+	if !ok {
+		switch op {
+		case "=":
+			return false
+		case "!=", "<>":
+			return true
+		}
+		return nil
+	}
+
+	switch op {
+	case "=":
+		return res == 0
+	case "!=", "<>":
+		return res != 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	case ">":
+		return res > 0
+	default:
+		return res >= 0
+	}
+}
+
+func arithmetic(op string, l, r interface{}) (interface{}, error) {
+	ln, ok := toNumber(l)
+	if !ok {
+		return nil, invalidType(op, l)
+	}
+	rn, ok := toNumber(r)
+	if !ok {
+		return nil, invalidType(op, r)
+	}
+
+	li, lInt := ln.(int64)
+	ri, rInt := rn.(int64)
+	if lInt && rInt {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		}
+		if ri == 0 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments, fmt.Errorf("division by zero"))
+		}
+		if op == "/" {
+			return li / ri, nil
+		}
+		return li % ri, nil
+	}
+
+	lf, rf := toFloat(ln), toFloat(rn)
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		return lf / rf, nil
+	default:
+		return math.Mod(lf, rf), nil
+	}
+}
+
+func (l *likeExpr) eval(e *env) (interface{}, error) {
+	val, err := l.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := l.pattern.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(val) || isNull(pattern) {
+		return nil, nil
+	}
+
+	var escape rune
+	if l.escape != nil {
+		esc, err := l.escape.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		escStr, ok := esc.(string)
+		if !ok || utf8.RuneCountInString(escStr) != 1 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrLikeInvalidInputs, fmt.Errorf("escape must be a single character"))
+		}
+		escape, _ = utf8.DecodeRuneInString(escStr)
+	}
+
+	patternStr, ok := pattern.(string)
+	if !ok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrLikeInvalidInputs, fmt.Errorf("pattern must be a string"))
+	}
+
+	matched, err := likeMatch([]rune(patternStr), []rune(formatValue(val)), escape)
+	if err != nil {
+		return nil, err
+	}
+	return matched != l.not, nil
+}
+
+// likeMatch matches the value against the LIKE pattern: '%' is any sequence and '_' is any character.
+func likeMatch(pattern, value []rune, escape rune) (bool, error) {
+	for len(pattern) > 0 {
+		ch := pattern[0]
+		switch {
+		case escape != 0 && ch == escape:
+			if len(pattern) < 2 {
+				return false, errors.GetAPIErrorWithError(errors.ErrLikeInvalidInputs, fmt.Errorf("escape at the end of pattern"))
+			}
+			if len(value) == 0 || value[0] != pattern[1] {
+				return false, nil
+			}
+			pattern, value = pattern[2:], value[1:]
+		case ch == '%':
+			for len(pattern) > 0 && pattern[0] == '%' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true, nil
+			}
+			for i := 0; i <= len(value); i++ {
+				matched, err := likeMatch(pattern, value[i:], escape)
+				if err != nil || matched {
+					return matched, err
+				}
+			}
+			return false, nil
+		case ch == '_':
+			if len(value) == 0 {
+				return false, nil
+			}
+			pattern, value = pattern[1:], value[1:]
+		default:
+			if len(value) == 0 || value[0] != ch {
+				return false, nil
+			}
+			pattern, value = pattern[1:], value[1:]
+		}
+	}
+
+	return len(value) == 0, nil
+}
+
+func (b *betweenExpr) eval(e *env) (interface{}, error) {
+	val, err := b.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := b.lo.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := b.hi.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(val) || isNull(lo) || isNull(hi) {
+		return nil, nil
+	}
+
+	geLo, leHi := compare(">=", val, lo), compare("<=", val, hi)
+	if geLo == nil || leHi == nil {
+		return nil, nil
+	}
+	return (geLo.(bool) && leHi.(bool)) != b.not, nil
+}
+
+func (in *inExpr) eval(e *env) (interface{}, error) {
+	val, err := in.x.eval(e)
+	if err != nil || isNull(val) {
+		return nil, err
+	}
+
+	var unknown bool
+	for _, item := range in.list {
+		itemVal, err := item.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if isNull(itemVal) {
+			unknown = true
+			continue
+		}
+		if eq, ok := compare("=", val, itemVal).(bool); ok && eq {
+			return !in.not, nil
+		}
+	}
+
+	if unknown {
+		return nil, nil
+	}
+	return in.not, nil
+}
+
+func (is *isExpr) eval(e *env) (interface{}, error) {
+	val, err := is.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	res := isNull(val)
+	if is.missing {
+		res = val == missing
+	}
+	return res != is.not, nil
+}
+
+func (c *castExpr) eval(e *env) (interface{}, error) {
+	val, err := c.x.eval(e)
+	if err != nil || isNull(val) {
+		return nil, err
+	}
+
+	res, ok := castValue(val, c.typ)
+	if !ok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrCastFailed, fmt.Errorf("can't cast '%s' to %s", formatValue(val), c.typ))
+	}
+	return res, nil
+}
+
+// castTypes maps SQL type names to the canonical ones.
+var castTypes = map[string]string{
+	"BOOL":      "BOOL",
+	"BOOLEAN":   "BOOL",
+	"INT":       "INT",
+	"INTEGER":   "INT",
+	"STRING":    "STRING",
+	"VARCHAR":   "STRING",
+	"CHAR":      "STRING",
+	"FLOAT":     "FLOAT",
+	"DOUBLE":    "FLOAT",
+	"REAL":      "FLOAT",
+	"DECIMAL":   "FLOAT",
+	"NUMERIC":   "FLOAT",
+	"TIMESTAMP": "TIMESTAMP",
+}
+
+func castValue(val interface{}, typ string) (interface{}, bool) {
+	switch typ {
+	case "BOOL":
+		return toBool(val)
+	case "INT":
+		num, ok := toNumber(val)
+		if !ok {
+			return nil, false
+		}
+		if f, isFloat := num.(float64); isFloat {
+			return int64(f), true
+		}
+		return num, true
+	case "FLOAT":
+		num, ok := toNumber(val)
+		if !ok {
+			return nil, false
+		}
+		return toFloat(num), true
+	case "STRING":
+		return formatValue(val), true
+	default:
+		return toTimestamp(val)
+	}
+}
+
+// functionArity contains the allowed number of arguments of the scalar functions, -1 means any positive number.
+var functionArity = map[string][2]int{
+	"LOWER":            {1, 1},
+	"UPPER":            {1, 1},
+	"CHAR_LENGTH":      {1, 1},
+	"CHARACTER_LENGTH": {1, 1},
+	"TRIM":             {1, 1},
+	"SUBSTRING":        {2, 3},
+	"COALESCE":         {1, -1},
+	"NULLIF":           {2, 2},
+	"UTCNOW":           {0, 0},
+}
+
+func (f *funcExpr) eval(e *env) (interface{}, error) {
+	args := make([]interface{}, len(f.args))
+	for i, arg := range f.args {
+		val, err := arg.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+
+	switch f.name {
+	case "COALESCE":
+		for _, arg := range args {
+			if !isNull(arg) {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	case "NULLIF":
+		if isNull(args[0]) || isNull(args[1]) {
+			return args[0], nil
+		}
+		if eq, ok := compare("=", args[0], args[1]).(bool); ok && eq {
+			return nil, nil
+		}
+		return args[0], nil
+	case "UTCNOW":
+		return time.Now().UTC(), nil
+	}
+
+	for _, arg := range args {
+		if isNull(arg) {
+			return nil, nil
+		}
+	}
+
+	str := formatValue(args[0])
+	switch f.name {
+	case "LOWER":
+		return strings.ToLower(str), nil
+	case "UPPER":
+		return strings.ToUpper(str), nil
+	case "CHAR_LENGTH", "CHARACTER_LENGTH":
+		return int64(utf8.RuneCountInString(str)), nil
+	case "TRIM":
+		return strings.TrimSpace(str), nil
+	default:
+		return substring(str, args[1:])
+	}
+}
+
+// substring implements SQL SUBSTRING with 1-based start position.
+func substring(str string, args []interface{}) (interface{}, error) {
+	runes := []rune(str)
+
+	start, ok := toInt(args[0])
+	if !ok {
+		return nil, invalidType("SUBSTRING", args[0])
+	}
+	end := int64(len(runes)) + 1
+	if len(args) == 2 {
+		length, ok := toInt(args[1])
+		if !ok || length < 0 {
+			return nil, invalidType("SUBSTRING", args[1])
+		}
+		end = start + length
+	}
+
+	if start < 1 {
+		start = 1
+	}
+	if end > int64(len(runes))+1 {
+		end = int64(len(runes)) + 1
+	}
+	if start >= end {
+		return "", nil
+	}
+
+	return string(runes[start-1 : end-1]), nil
+}
+
+func toInt(val interface{}) (int64, bool) {
+	num, ok := toNumber(val)
+	if !ok {
+		return 0, false
+	}
+	if i, isInt := num.(int64); isInt {
+		return i, true
+	}
+	return int64(num.(float64)), true
+}
+
+func (a *aggregateExpr) eval(e *env) (interface{}, error) {
+	if e.aggregates == nil {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure,
+			fmt.Errorf("aggregate function %s can't be used in WHERE clause", a.name))
+	}
+	return e.aggregates[a.idx], nil
+}
+
+// aggregator accumulates values of the aggregate function.
+type aggregator struct {
+	fn    *aggregateExpr
+	count int64
+	sum   interface{}
+	value interface{}
+}
+
+func (a *aggregator) update(e *env) error {
+	if a.fn.arg == nil {
+		a.count++
+		return nil
+	}
+
+	val, err := a.fn.arg.eval(e)
+	if err != nil || isNull(val) {
+		return err
+	}
+	a.count++
+
+	switch a.fn.name {
+	case "SUM", "AVG":
+		num, ok := toNumber(val)
+		if !ok {
+			return invalidType(a.fn.name, val)
+		}
+		if a.sum == nil {
+			a.sum = num
+		} else if a.sum, err = arithmetic("+", a.sum, num); err != nil {
+			return err
+		}
+	case "MIN", "MAX":
+		if num, ok := toNumber(val); ok {
+			val = num
+		}
+		if a.value == nil {
+			a.value = val
+			return nil
+		}
+		res, ok := compareValues(val, a.value)
+		if !ok {
+			return invalidType(a.fn.name, val)
+		}
+		if a.fn.name == "MIN" && res < 0 || a.fn.name == "MAX" && res > 0 {
+			a.value = val
+		}
+	}
+
+	return nil
+}
+
+func (a *aggregator) result() interface{} {
+	switch a.fn.name {
+	case "COUNT":
+		return a.count
+	case "SUM":
+		return a.sum
+	case "AVG":
+		if a.count == 0 {
+			return nil
+		}
+		return toFloat(a.sum) / float64(a.count)
+	default:
+		return a.value
+	}
+}
+
+func invalidType(op string, val interface{}) error {
+	return errors.GetAPIErrorWithError(errors.ErrInvalidDataType,
+		fmt.Errorf("invalid operand of %s: '%s'", op, formatValue(val)))
+}
+
+func parseNumber(text string) (interface{}, error) {
+	if !strings.ContainsAny(text, ".eE") {
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return i, nil
+		}
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, errors.GetAPIErrorWithError(errors.ErrLexerInvalidLiteral, fmt.Errorf("invalid number: %s", text))
+	}
+	return f, nil
+}
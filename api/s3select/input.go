@@ -0,0 +1,450 @@
+package s3select
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// record is a single record of the input.
+	record interface {
+		// get returns the top-level field of the record or MISSING.
+		get(name string, caseSensitive bool) interface{}
+		// value returns the whole record.
+		value() interface{}
+		// fields returns names and values of all the fields, they are used for SELECT *.
+		fields() ([]string, []interface{})
+	}
+
+	// recordReader reads records from the input, io.EOF is returned at the end of the input.
+	recordReader interface {
+		read() (record, error)
+	}
+
+	csvRecord struct {
+		header  []string
+		columns []string
+	}
+
+	jsonRecord struct {
+		val interface{}
+	}
+)
+
+func (r *csvRecord) get(name string, caseSensitive bool) interface{} {
+	for i, h := range r.header {
+		if h == name || !caseSensitive && strings.EqualFold(h, name) {
+			return r.column(i)
+		}
+	}
+
+	if strings.HasPrefix(name, "_") {
+		if idx, err := strconv.Atoi(name[1:]); err == nil && idx > 0 {
+			return r.column(idx - 1)
+		}
+	}
+
+	return missing
+}
+
+func (r *csvRecord) column(idx int) interface{} {
+	if idx >= len(r.columns) {
+		return missing
+	}
+	return r.columns[idx]
+}
+
+func (r *csvRecord) value() interface{} {
+	names, values := r.fields()
+	obj := newJSONObject()
+	for i := range names {
+		obj.set(names[i], values[i])
+	}
+	return obj
+}
+
+func (r *csvRecord) fields() ([]string, []interface{}) {
+	names := make([]string, len(r.columns))
+	values := make([]interface{}, len(r.columns))
+	for i, col := range r.columns {
+		if i < len(r.header) {
+			names[i] = r.header[i]
+		} else {
+			names[i] = "_" + strconv.Itoa(i+1)
+		}
+		values[i] = col
+	}
+	return names, values
+}
+
+func (r *jsonRecord) get(name string, caseSensitive bool) interface{} {
+	if obj, ok := r.val.(*jsonObject); ok {
+		return obj.get(name, caseSensitive)
+	}
+	return missing
+}
+
+func (r *jsonRecord) value() interface{} {
+	return r.val
+}
+
+func (r *jsonRecord) fields() ([]string, []interface{}) {
+	if obj, ok := r.val.(*jsonObject); ok {
+		values := make([]interface{}, len(obj.keys))
+		for i, key := range obj.keys {
+			values[i] = obj.values[key]
+		}
+		return obj.keys, values
+	}
+	return []string{"_1"}, []interface{}{r.val}
+}
+
+// decompress wraps the input according to the compression type.
+func decompress(r io.Reader, compression string) (io.Reader, error) {
+	switch strings.ToUpper(compression) {
+	case "", CompressionNone:
+		return r, nil
+	case CompressionGZIP:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidCompressionFormat, err)
+		}
+		return gr, nil
+	case CompressionBZIP2:
+		return bzip2.NewReader(r), nil
+	default:
+		return nil, errors.GetAPIError(errors.ErrInvalidCompressionFormat)
+	}
+}
+
+// csvReader reads CSV records with the configurable delimiters and quotes.
+type csvReader struct {
+	r *bufio.Reader
+
+	header []string
+
+	fieldDelimiter  rune
+	recordDelimiter []rune
+	quote           rune
+	escape          rune
+	comment         rune
+	// allowQuotedRecordDelimiter allows record delimiters in the quoted fields,
+	// otherwise record delimiter always ends the record.
+	allowQuotedRecordDelimiter bool
+}
+
+func newCSVReader(r io.Reader, in *CSVInput) (*csvReader, error) {
+	res := &csvReader{
+		r:                          bufio.NewReader(r),
+		fieldDelimiter:             ',',
+		recordDelimiter:            []rune{'\n'},
+		quote:                      '"',
+		escape:                     '"',
+		allowQuotedRecordDelimiter: in.AllowQuotedRecordDelimiter,
+	}
+
+	var err error
+	if res.fieldDelimiter, err = singleRune(in.FieldDelimiter, res.fieldDelimiter); err != nil {
+		return nil, err
+	}
+	if res.quote, err = singleRune(in.QuoteCharacter, res.quote); err != nil {
+		return nil, err
+	}
+	if res.escape, err = singleRune(in.QuoteEscapeCharacter, res.quote); err != nil {
+		return nil, err
+	}
+	if res.comment, err = singleRune(in.Comments, 0); err != nil {
+		return nil, err
+	}
+	if in.RecordDelimiter != "" {
+		if res.recordDelimiter = []rune(in.RecordDelimiter); len(res.recordDelimiter) > 2 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequestParameter,
+				fmt.Errorf("record delimiter must be one or two characters"))
+		}
+	}
+
+	switch strings.ToUpper(in.FileHeaderInfo) {
+	case "", FileHeaderNone:
+	case FileHeaderUse, FileHeaderIgnore:
+		header, err := res.readColumns()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if strings.EqualFold(in.FileHeaderInfo, FileHeaderUse) {
+			res.header = header
+		}
+	default:
+		return nil, errors.GetAPIError(errors.ErrInvalidFileHeaderInfo)
+	}
+
+	return res, nil
+}
+
+func singleRune(s string, def rune) (rune, error) {
+	if s == "" {
+		return def, nil
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, errors.GetAPIErrorWithError(errors.ErrInvalidRequestParameter, fmt.Errorf("'%s' must be a single character", s))
+	}
+	ch, _ := utf8.DecodeRuneInString(s)
+	return ch, nil
+}
+
+func (c *csvReader) read() (record, error) {
+	columns, err := c.readColumns()
+	if err != nil {
+		return nil, err
+	}
+	return &csvRecord{header: c.header, columns: columns}, nil
+}
+
+// readColumns reads the next record, comment lines are skipped.
+func (c *csvReader) readColumns() ([]string, error) {
+	for {
+		columns, err := c.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if columns != nil {
+			return columns, nil
+		}
+	}
+}
+
+// readRecord reads a single record, nil columns are returned for the comment line.
+func (c *csvReader) readRecord() ([]string, error) {
+	var (
+		columns  []string
+		field    strings.Builder
+		inQuotes bool
+		quoted   bool
+		read     bool
+	)
+
+	for {
+		ch, _, err := c.r.ReadRune()
+		if err == io.EOF {
+			if !read {
+				return nil, io.EOF
+			}
+			return append(columns, field.String()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !read && c.comment != 0 && ch == c.comment {
+			return nil, c.skipRecord()
+		}
+		read = true
+
+		if inQuotes {
+			switch {
+			case ch == c.escape && c.escape != c.quote:
+				next, _, err := c.r.ReadRune()
+				if err != nil {
+					return nil, errors.GetAPIErrorWithError(errors.ErrCSVParsingError, fmt.Errorf("unexpected end of quoted field"))
+				}
+				field.WriteRune(next)
+			case ch == c.quote:
+				if next, _, err := c.r.ReadRune(); err == nil {
+					if next == c.quote {
+						field.WriteRune(c.quote)
+						continue
+					}
+					_ = c.r.UnreadRune()
+				}
+				inQuotes = false
+			case !c.allowQuotedRecordDelimiter && c.isRecordDelimiter(ch):
+				return append(columns, field.String()), nil
+			default:
+				field.WriteRune(ch)
+			}
+			continue
+		}
+
+		switch {
+		case ch == c.quote && field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+		case ch == c.fieldDelimiter:
+			columns = append(columns, field.String())
+			field.Reset()
+			quoted = false
+		case c.isRecordDelimiter(ch):
+			return append(columns, field.String()), nil
+		default:
+			field.WriteRune(ch)
+		}
+	}
+}
+
+// isRecordDelimiter checks if the rune starts record delimiter and consumes the rest of it.
+// CRLF is also accepted if the delimiter is LF.
+func (c *csvReader) isRecordDelimiter(ch rune) bool {
+	delim := c.recordDelimiter
+	if len(delim) == 1 && delim[0] == '\n' && ch == '\r' {
+		delim = []rune{'\r', '\n'}
+	}
+	if ch != delim[0] {
+		return false
+	}
+	if len(delim) == 1 {
+		return true
+	}
+
+	next, _, err := c.r.ReadRune()
+	if err != nil {
+		return false
+	}
+	if next != delim[1] {
+		_ = c.r.UnreadRune()
+		return false
+	}
+	return true
+}
+
+func (c *csvReader) skipRecord() error {
+	for {
+		ch, _, err := c.r.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if c.isRecordDelimiter(ch) {
+			return nil
+		}
+	}
+}
+
+// jsonReader reads JSON values from the input, records are selected from the
+// values by the source path of the statement.
+type jsonReader struct {
+	dec     *json.Decoder
+	source  []pathStep
+	pending []interface{}
+}
+
+func newJSONReader(r io.Reader, in *JSONInput, source []pathStep) (*jsonReader, error) {
+	switch strings.ToUpper(in.Type) {
+	case JSONDocument, JSONLines:
+	default:
+		return nil, errors.GetAPIError(errors.ErrInvalidJSONType)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &jsonReader{dec: dec, source: source}, nil
+}
+
+func (j *jsonReader) read() (record, error) {
+	for len(j.pending) == 0 {
+		val, err := decodeJSONValue(j.dec)
+		if err != nil {
+			if err != io.EOF {
+				err = errors.GetAPIErrorWithError(errors.ErrJSONParsingError, err)
+			}
+			return nil, err
+		}
+		j.pending = selectSource(val, j.source)
+	}
+
+	val := j.pending[0]
+	j.pending = j.pending[1:]
+	return &jsonRecord{val: val}, nil
+}
+
+// selectSource applies the source path (e.g. [*].items) to the JSON value.
+// Leading wildcard selects the value itself if it isn't an array.
+func selectSource(val interface{}, source []pathStep) []interface{} {
+	values := []interface{}{val}
+	for i, step := range source {
+		var next []interface{}
+		for _, v := range values {
+			if step.wildcard {
+				if arr, ok := v.([]interface{}); ok {
+					next = append(next, arr...)
+				} else if i == 0 {
+					next = append(next, v)
+				}
+				continue
+			}
+			if res := navigate(v, step); res != missing {
+				next = append(next, res)
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// decodeJSONValue decodes the next JSON value keeping the order of object keys.
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			obj := newJSONObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				val, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				obj.set(keyTok.(string), val)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			return obj, nil
+		case '[':
+			arr := make([]interface{}, 0)
+			for dec.More() {
+				val, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				arr = append(arr, val)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			return arr, nil
+		default:
+			return nil, fmt.Errorf("unexpected delimiter '%s'", v)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	default:
+		return v, nil
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package s3select
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is checks if the token is the keyword or the operator (case-insensitive).
+func (t token) is(text string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenOperator) && strings.EqualFold(t.text, text)
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s' at position %d", t.text, t.pos)
+}
+
+var operators = []string{"<=", ">=", "<>", "!=", "||", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", "[", "]"}
+
+// tokenize splits the SQL expression to tokens.
+func tokenize(expr string) ([]token, error) {
+	var res []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '\'' || ch == '"':
+			text, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenString
+			if ch == '"' {
+				kind = tokenQuotedIdent
+			}
+			res = append(res, token{kind: kind, text: text, pos: i})
+			i = next
+		case unicode.IsDigit(ch) || ch == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			res = append(res, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(ch) || ch == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			res = append(res, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			op := matchOperator(runes[i:])
+			if op == "" {
+				return nil, errors.GetAPIErrorWithError(errors.ErrLexerInvalidChar,
+					fmt.Errorf("invalid character '%c' at position %d", ch, i))
+			}
+			res = append(res, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(res, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func matchOperator(runes []rune) string {
+	for _, op := range operators {
+		if len(runes) >= len(op) && string(runes[:len(op)]) == op {
+			return op
+		}
+	}
+	return ""
+}
+
+// readQuoted reads the string started at the position, doubled quote is an escaped quote.
+func readQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			sb.WriteRune(quote)
+			i++
+			continue
+		}
+		return sb.String(), i + 1, nil
+	}
+
+	return "", 0, errors.GetAPIErrorWithError(errors.ErrLexerInvalidLiteral,
+		fmt.Errorf("unterminated quoted string at position %d", start))
+}
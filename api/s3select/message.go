@@ -0,0 +1,126 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"sync/atomic"
+)
+
+// Event types of the SelectObjectContent response stream.
+const (
+	eventRecords  = "Records"
+	eventStats    = "Stats"
+	eventProgress = "Progress"
+	eventCont     = "Cont"
+	eventEnd      = "End"
+)
+
+const headerValueTypeString = 7
+
+type (
+	// Stats contains statistics of the processed query.
+	Stats struct {
+		XMLName        xml.Name `xml:"Stats"`
+		BytesScanned   int64    `xml:"BytesScanned"`
+		BytesProcessed int64    `xml:"BytesProcessed"`
+		BytesReturned  int64    `xml:"BytesReturned"`
+	}
+
+	// Progress contains statistics of the query in progress.
+	Progress struct {
+		XMLName        xml.Name `xml:"Progress"`
+		BytesScanned   int64    `xml:"BytesScanned"`
+		BytesProcessed int64    `xml:"BytesProcessed"`
+		BytesReturned  int64    `xml:"BytesReturned"`
+	}
+
+	header struct {
+		name, value string
+	}
+)
+
+// encodeMessage encodes the message in the AWS event stream format:
+// total length, headers length, prelude CRC, headers, payload and message CRC.
+func encodeMessage(headers []header, payload []byte) []byte {
+	var hdrs bytes.Buffer
+	for _, h := range headers {
+		hdrs.WriteByte(byte(len(h.name)))
+		hdrs.WriteString(h.name)
+		hdrs.WriteByte(headerValueTypeString)
+		_ = binary.Write(&hdrs, binary.BigEndian, uint16(len(h.value)))
+		hdrs.WriteString(h.value)
+	}
+
+	totalLen := 4 + 4 + 4 + hdrs.Len() + len(payload) + 4
+
+	msg := make([]byte, totalLen)
+	binary.BigEndian.PutUint32(msg, uint32(totalLen))
+	binary.BigEndian.PutUint32(msg[4:], uint32(hdrs.Len()))
+	binary.BigEndian.PutUint32(msg[8:], crc32.ChecksumIEEE(msg[:8]))
+	n := copy(msg[12:], hdrs.Bytes())
+	copy(msg[12+n:], payload)
+	binary.BigEndian.PutUint32(msg[totalLen-4:], crc32.ChecksumIEEE(msg[:totalLen-4]))
+
+	return msg
+}
+
+func eventHeaders(eventType, contentType string) []header {
+	res := []header{
+		{name: ":event-type", value: eventType},
+		{name: ":message-type", value: "event"},
+	}
+	if contentType != "" {
+		res = append(res, header{name: ":content-type", value: contentType})
+	}
+	return res
+}
+
+func recordsMessage(payload []byte) []byte {
+	return encodeMessage(eventHeaders(eventRecords, "application/octet-stream"), payload)
+}
+
+func xmlMessage(eventType string, body interface{}) []byte {
+	payload, err := xml.Marshal(body)
+	if err != nil {
+		// stats contain only numbers, so it never happens
+		panic(err)
+	}
+	return encodeMessage(eventHeaders(eventType, "text/xml"), payload)
+}
+
+// contMessage keeps the connection alive while no records are sent.
+func contMessage() []byte {
+	return encodeMessage(eventHeaders(eventCont, ""), nil)
+}
+
+func endMessage() []byte {
+	return encodeMessage(eventHeaders(eventEnd, ""), nil)
+}
+
+func errorMessage(code, message string) []byte {
+	return encodeMessage([]header{
+		{name: ":error-code", value: code},
+		{name: ":error-message", value: message},
+		{name: ":message-type", value: "error"},
+	}, nil)
+}
+
+// countingReader counts bytes read from the underlying reader.
+// The counter can be read concurrently with reading.
+type countingReader struct {
+	n int64
+	r io.Reader
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+func (c *countingReader) count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
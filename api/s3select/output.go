@@ -0,0 +1,108 @@
+package s3select
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// recordWriter serializes result records to the buffer.
+type recordWriter interface {
+	write(buf *bytes.Buffer, names []string, values []interface{}) error
+}
+
+type csvWriter struct {
+	fieldDelimiter  string
+	recordDelimiter string
+	quote           string
+	escape          string
+	quoteAlways     bool
+}
+
+func newCSVWriter(out *CSVOutput) (*csvWriter, error) {
+	res := &csvWriter{
+		fieldDelimiter:  ",",
+		recordDelimiter: "\n",
+		quote:           `"`,
+	}
+
+	if out.FieldDelimiter != "" {
+		res.fieldDelimiter = out.FieldDelimiter
+	}
+	if out.RecordDelimiter != "" {
+		res.recordDelimiter = out.RecordDelimiter
+	}
+	if out.QuoteCharacter != "" {
+		res.quote = out.QuoteCharacter
+	}
+	res.escape = res.quote
+	if out.QuoteEscapeCharacter != "" {
+		res.escape = out.QuoteEscapeCharacter
+	}
+
+	switch strings.ToUpper(out.QuoteFields) {
+	case "", QuoteFieldsAsNeeded:
+	case QuoteFieldsAlways:
+		res.quoteAlways = true
+	default:
+		return nil, errors.GetAPIError(errors.ErrInvalidQuoteFields)
+	}
+
+	return res, nil
+}
+
+func (c *csvWriter) write(buf *bytes.Buffer, _ []string, values []interface{}) error {
+	for i, val := range values {
+		if i != 0 {
+			buf.WriteString(c.fieldDelimiter)
+		}
+
+		field := formatValue(val)
+		if !c.quoteAlways && !c.needsQuotes(field) {
+			buf.WriteString(field)
+			continue
+		}
+
+		buf.WriteString(c.quote)
+		buf.WriteString(strings.ReplaceAll(field, c.quote, c.escape+c.quote))
+		buf.WriteString(c.quote)
+	}
+	buf.WriteString(c.recordDelimiter)
+
+	return nil
+}
+
+func (c *csvWriter) needsQuotes(field string) bool {
+	return strings.Contains(field, c.fieldDelimiter) || strings.Contains(field, c.quote) ||
+		strings.Contains(field, c.recordDelimiter) || strings.ContainsAny(field, "\r\n")
+}
+
+type jsonWriter struct {
+	recordDelimiter string
+}
+
+func newJSONWriter(out *JSONOutput) *jsonWriter {
+	res := &jsonWriter{recordDelimiter: "\n"}
+	if out.RecordDelimiter != "" {
+		res.recordDelimiter = out.RecordDelimiter
+	}
+	return res
+}
+
+func (j *jsonWriter) write(buf *bytes.Buffer, names []string, values []interface{}) error {
+	obj := newJSONObject()
+	for i, name := range names {
+		if values[i] == missing {
+			continue
+		}
+		obj.set(name, values[i])
+	}
+
+	if err := writeJSONValue(buf, obj); err != nil {
+		return err
+	}
+	buf.WriteString(j.recordDelimiter)
+
+	return nil
+}
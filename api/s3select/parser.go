@@ -0,0 +1,614 @@
+package s3select
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// statement is a parsed SELECT statement.
+	statement struct {
+		// all is true for SELECT *.
+		all   bool
+		items []selectItem
+		alias string
+		// source is a path to the records in the JSON document, e.g. S3Object[*].items.
+		source     []pathStep
+		where      expr
+		limit      int64
+		aggregates []*aggregateExpr
+	}
+
+	selectItem struct {
+		expr expr
+		name string
+	}
+
+	parser struct {
+		tokens []token
+		pos    int
+
+		aggregates []*aggregateExpr
+		// inAggregate is true while an argument of the aggregate function is parsed.
+		inAggregate bool
+		// columnOutsideAggregate is true if the column was referenced not in an aggregate function.
+		columnOutsideAggregate bool
+	}
+)
+
+var aggregateFunctions = map[string]struct{}{
+	"COUNT": {},
+	"SUM":   {},
+	"AVG":   {},
+	"MIN":   {},
+	"MAX":   {},
+}
+
+// reservedKeywords can't be used as aliases without quotes.
+var reservedKeywords = map[string]struct{}{
+	"SELECT": {}, "FROM": {}, "WHERE": {}, "LIMIT": {}, "AS": {}, "AND": {}, "OR": {}, "NOT": {},
+	"LIKE": {}, "ESCAPE": {}, "BETWEEN": {}, "IN": {}, "IS": {}, "NULL": {}, "MISSING": {},
+	"TRUE": {}, "FALSE": {}, "CAST": {},
+}
+
+// parseStatement parses the SQL SELECT statement.
+func parseStatement(sql string) (*statement, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	return p.parseSelect()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) accept(text string) bool {
+	if p.peek().is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected(fmt.Sprintf("'%s'", text))
+	}
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	return errors.GetAPIErrorWithError(errors.ErrParseUnexpectedToken,
+		fmt.Errorf("expected %s, got %s", expected, p.peek()))
+}
+
+func (p *parser) parseSelect() (*statement, error) {
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+
+	st := &statement{limit: -1}
+	if err := p.parseSelectList(st); err != nil {
+		return nil, err
+	}
+
+	if !p.accept("FROM") {
+		return nil, errors.GetAPIErrorWithError(errors.ErrParseSelectMissingFrom, fmt.Errorf("got %s", p.peek()))
+	}
+	if err := p.parseFrom(st); err != nil {
+		return nil, err
+	}
+
+	if p.accept("WHERE") {
+		columnOutsideAggregate, aggregates := p.columnOutsideAggregate, len(p.aggregates)
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if len(p.aggregates) != aggregates {
+			return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure,
+				fmt.Errorf("aggregate functions can't be used in WHERE clause"))
+		}
+		p.columnOutsideAggregate = columnOutsideAggregate
+		st.where = where
+	}
+
+	if p.accept("LIMIT") {
+		tok := p.next()
+		limit, err := strconv.ParseInt(tok.text, 10, 64)
+		if tok.kind != tokenNumber || err != nil || limit < 0 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrParseExpectedNumber, fmt.Errorf("invalid limit %s", tok))
+		}
+		st.limit = limit
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected("end of expression")
+	}
+
+	st.aggregates = p.aggregates
+	if len(st.aggregates) != 0 && (st.all || p.columnOutsideAggregate) {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure,
+			fmt.Errorf("aggregate and non-aggregate projections can't be mixed"))
+	}
+
+	return st, nil
+}
+
+func (p *parser) parseSelectList(st *statement) error {
+	if p.accept("*") {
+		st.all = true
+		return nil
+	}
+
+	// alias.*
+	if p.pos+2 < len(p.tokens) && p.peek().kind == tokenIdent && p.tokens[p.pos+1].is(".") && p.tokens[p.pos+2].is("*") {
+		p.pos += 3
+		st.all = true
+		return nil
+	}
+
+	if p.peek().is("FROM") {
+		return errors.GetAPIError(errors.ErrParseEmptySelect)
+	}
+
+	for {
+		x, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+
+		item := selectItem{expr: x}
+		if p.accept("AS") {
+			tok := p.next()
+			if tok.kind != tokenIdent && tok.kind != tokenQuotedIdent {
+				return errors.GetAPIErrorWithError(errors.ErrParseExpectedIdentForAlias, fmt.Errorf("got %s", tok))
+			}
+			item.name = tok.text
+		} else if tok := p.peek(); tok.kind == tokenQuotedIdent || tok.kind == tokenIdent && !isReserved(tok.text) {
+			item.name = p.next().text
+		}
+
+		if item.name == "" {
+			if col, ok := x.(*columnExpr); ok {
+				item.name = col.name()
+			}
+		}
+		if item.name == "" {
+			item.name = "_" + strconv.Itoa(len(st.items)+1)
+		}
+
+		st.items = append(st.items, item)
+
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseFrom(st *statement) error {
+	tok := p.next()
+	if tok.kind != tokenIdent || !strings.EqualFold(tok.text, "S3Object") {
+		return errors.GetAPIErrorWithError(errors.ErrParseUnexpectedToken, fmt.Errorf("expected S3Object, got %s", tok))
+	}
+
+	for {
+		switch {
+		case p.accept("["):
+			step, err := p.parseIndex(true)
+			if err != nil {
+				return err
+			}
+			st.source = append(st.source, step)
+		case p.accept("."):
+			tok = p.next()
+			if tok.kind != tokenIdent && tok.kind != tokenQuotedIdent {
+				return errors.GetAPIErrorWithError(errors.ErrParseExpectedMember, fmt.Errorf("got %s", tok))
+			}
+			st.source = append(st.source, pathStep{name: tok.text, quoted: tok.kind == tokenQuotedIdent})
+		default:
+			p.accept("AS")
+			if tok = p.peek(); tok.kind == tokenQuotedIdent || tok.kind == tokenIdent && !isReserved(tok.text) {
+				st.alias = p.next().text
+			}
+			return nil
+		}
+	}
+}
+
+// parseIndex parses array index after '['.
+func (p *parser) parseIndex(allowWildcard bool) (pathStep, error) {
+	var step pathStep
+	if allowWildcard && p.accept("*") {
+		step.wildcard = true
+	} else {
+		tok := p.next()
+		index, err := strconv.Atoi(tok.text)
+		if tok.kind != tokenNumber || err != nil {
+			return step, errors.GetAPIErrorWithError(errors.ErrParseExpectedNumber, fmt.Errorf("invalid array index %s", tok))
+		}
+		step.index = index
+	}
+	step.isIndex = true
+
+	return step, p.expect("]")
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseBinary(0)
+}
+
+// binaryOperators lists binary operators by precedence levels from the lowest.
+var binaryOperators = [][]string{
+	{"OR"},
+	{"AND"},
+	nil, // NOT and predicates
+	{"+", "-", "||"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (expr, error) {
+	if level == 2 {
+		return p.parseNot()
+	}
+	if level == len(binaryOperators) {
+		return p.parseUnary()
+	}
+
+	l, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		for _, candidate := range binaryOperators[level] {
+			if p.accept(candidate) {
+				op = strings.ToUpper(candidate)
+				break
+			}
+		}
+		if op == "" {
+			return l, nil
+		}
+
+		r, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.accept("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", x: x}, nil
+	}
+
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() (expr, error) {
+	x, err := p.parseBinary(3)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			r, err := p.parseBinary(3)
+			if err != nil {
+				return nil, err
+			}
+			return &binaryExpr{op: op, l: x, r: r}, nil
+		}
+	}
+
+	if p.accept("IS") {
+		res := &isExpr{x: x, not: p.accept("NOT")}
+		switch {
+		case p.accept("NULL"):
+		case p.accept("MISSING"):
+			res.missing = true
+		default:
+			return nil, p.unexpected("NULL or MISSING")
+		}
+		return res, nil
+	}
+
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"):
+		res := &likeExpr{x: x, not: not}
+		if res.pattern, err = p.parseBinary(3); err != nil {
+			return nil, err
+		}
+		if p.accept("ESCAPE") {
+			if res.escape, err = p.parseBinary(3); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case p.accept("BETWEEN"):
+		res := &betweenExpr{x: x, not: not}
+		if res.lo, err = p.parseBinary(3); err != nil {
+			return nil, err
+		}
+		if err = p.expect("AND"); err != nil {
+			return nil, err
+		}
+		if res.hi, err = p.parseBinary(3); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case p.accept("IN"):
+		res := &inExpr{x: x, not: not}
+		if res.list, err = p.parseArgs(); err != nil {
+			return nil, err
+		}
+		if len(res.list) == 0 {
+			return nil, p.unexpected("expression")
+		}
+		return res, nil
+	case not:
+		return nil, p.unexpected("LIKE, BETWEEN or IN")
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	for _, op := range []string{"-", "+"} {
+		if p.accept(op) {
+			x, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			if lit, ok := x.(*literalExpr); ok && op == "-" {
+				switch v := lit.val.(type) {
+				case int64:
+					return &literalExpr{val: -v}, nil
+				case float64:
+					return &literalExpr{val: -v}, nil
+				}
+			}
+			return &unaryExpr{op: op, x: x}, nil
+		}
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokenNumber:
+		p.next()
+		val, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &literalExpr{val: val}, nil
+	case tokenString:
+		p.next()
+		return &literalExpr{val: tok.text}, nil
+	case tokenQuotedIdent:
+		return p.parseColumn()
+	case tokenIdent:
+		switch strings.ToUpper(tok.text) {
+		case "TRUE", "FALSE":
+			p.next()
+			return &literalExpr{val: strings.EqualFold(tok.text, "TRUE")}, nil
+		case "NULL":
+			p.next()
+			return &literalExpr{val: nil}, nil
+		case "MISSING":
+			p.next()
+			return &literalExpr{val: missing}, nil
+		}
+		if isReserved(tok.text) && !tok.is("CAST") {
+			return nil, p.unexpected("expression")
+		}
+		if p.tokens[p.pos+1].is("(") {
+			return p.parseFunction()
+		}
+		return p.parseColumn()
+	case tokenOperator:
+		if p.accept("(") {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		}
+	}
+
+	return nil, p.unexpected("expression")
+}
+
+func (p *parser) parseColumn() (expr, error) {
+	tok := p.next()
+	col := &columnExpr{path: []pathStep{{name: tok.text, quoted: tok.kind == tokenQuotedIdent}}}
+
+	for {
+		switch {
+		case p.accept("."):
+			tok = p.next()
+			if tok.kind != tokenIdent && tok.kind != tokenQuotedIdent {
+				return nil, errors.GetAPIErrorWithError(errors.ErrParseExpectedMember, fmt.Errorf("got %s", tok))
+			}
+			col.path = append(col.path, pathStep{name: tok.text, quoted: tok.kind == tokenQuotedIdent})
+		case p.accept("["):
+			step, err := p.parseIndex(false)
+			if err != nil {
+				return nil, err
+			}
+			col.path = append(col.path, step)
+		default:
+			if !p.inAggregate {
+				p.columnOutsideAggregate = true
+			}
+			return col, nil
+		}
+	}
+}
+
+func (p *parser) parseFunction() (expr, error) {
+	name := strings.ToUpper(p.next().text)
+
+	_, isAggregate := aggregateFunctions[name]
+	if name == "CAST" || name == "SUBSTRING" || isAggregate {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "CAST":
+			return p.parseCast()
+		case "SUBSTRING":
+			return p.parseSubstring()
+		default:
+			return p.parseAggregate(name)
+		}
+	}
+
+	arity, ok := functionArity[name]
+	if !ok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedFunction, fmt.Errorf("function %s", name))
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < arity[0] || arity[1] >= 0 && len(args) > arity[1] {
+		return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments,
+			fmt.Errorf("function %s got %d arguments", name, len(args)))
+	}
+
+	return &funcExpr{name: name, args: args}, nil
+}
+
+// parseArgs parses a parenthesized list of expressions.
+func (p *parser) parseArgs() ([]expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if p.accept(")") {
+		return nil, nil
+	}
+
+	var args []expr
+	for {
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, x)
+
+		if p.accept(")") {
+			return args, nil
+		}
+		if err = p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseCast() (expr, error) {
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect("AS"); err != nil {
+		return nil, err
+	}
+
+	tok := p.next()
+	typ, ok := castTypes[strings.ToUpper(tok.text)]
+	if tok.kind != tokenIdent || !ok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrParseExpectedTypeName, fmt.Errorf("got %s", tok))
+	}
+
+	return &castExpr{x: x, typ: typ}, p.expect(")")
+}
+
+// parseSubstring parses both SUBSTRING(x FROM start FOR length) and SUBSTRING(x, start, length).
+func (p *parser) parseSubstring() (expr, error) {
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &funcExpr{name: "SUBSTRING", args: []expr{x}}
+	fromForm := p.accept("FROM")
+	if !fromForm {
+		if err = p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+
+	start, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	res.args = append(res.args, start)
+
+	if fromForm && p.accept("FOR") || !fromForm && p.accept(",") {
+		length, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		res.args = append(res.args, length)
+	}
+
+	return res, p.expect(")")
+}
+
+func (p *parser) parseAggregate(name string) (expr, error) {
+	if p.inAggregate {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure, fmt.Errorf("nested aggregate function %s", name))
+	}
+
+	res := &aggregateExpr{name: name, idx: len(p.aggregates)}
+	if name == "COUNT" && p.accept("*") {
+		p.aggregates = append(p.aggregates, res)
+		return res, p.expect(")")
+	}
+
+	p.inAggregate = true
+	arg, err := p.parseExpr()
+	p.inAggregate = false
+	if err != nil {
+		return nil, err
+	}
+	res.arg = arg
+	p.aggregates = append(p.aggregates, res)
+
+	return res, p.expect(")")
+}
+
+func isReserved(text string) bool {
+	_, ok := reservedKeywords[strings.ToUpper(text)]
+	return ok
+}
@@ -0,0 +1,443 @@
+// Package s3select implements SelectObjectContent: SQL queries over CSV and JSON objects
+// with results streamed in the AWS event stream format.
+package s3select
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// Values of the request parameters.
+const (
+	ExpressionTypeSQL = "SQL"
+
+	CompressionNone  = "NONE"
+	CompressionGZIP  = "GZIP"
+	CompressionBZIP2 = "BZIP2"
+
+	FileHeaderNone   = "NONE"
+	FileHeaderUse    = "USE"
+	FileHeaderIgnore = "IGNORE"
+
+	JSONDocument = "DOCUMENT"
+	JSONLines    = "LINES"
+
+	QuoteFieldsAlways   = "ALWAYS"
+	QuoteFieldsAsNeeded = "ASNEEDED"
+)
+
+const (
+	maxExpressionLength = 256 * 1024
+	// maxRecordsPayload is a size of the records batch sent in a single message.
+	maxRecordsPayload = 128 * 1024
+	// keepAliveInterval is a period of Cont and Progress messages sent while no records are sent.
+	keepAliveInterval = 10 * time.Second
+)
+
+type (
+	// Request is a body of SelectObjectContent request.
+	Request struct {
+		XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+		Expression          string              `xml:"Expression"`
+		ExpressionType      string              `xml:"ExpressionType"`
+		InputSerialization  InputSerialization  `xml:"InputSerialization"`
+		OutputSerialization OutputSerialization `xml:"OutputSerialization"`
+		RequestProgress     RequestProgress     `xml:"RequestProgress"`
+		ScanRange           *ScanRange          `xml:"ScanRange"`
+	}
+
+	// InputSerialization describes the format of the object.
+	InputSerialization struct {
+		CompressionType string     `xml:"CompressionType"`
+		CSV             *CSVInput  `xml:"CSV"`
+		JSON            *JSONInput `xml:"JSON"`
+		Parquet         *struct{}  `xml:"Parquet"`
+	}
+
+	// CSVInput describes CSV object format.
+	CSVInput struct {
+		FileHeaderInfo             string `xml:"FileHeaderInfo"`
+		Comments                   string `xml:"Comments"`
+		QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter"`
+		RecordDelimiter            string `xml:"RecordDelimiter"`
+		FieldDelimiter             string `xml:"FieldDelimiter"`
+		QuoteCharacter             string `xml:"QuoteCharacter"`
+		AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter"`
+	}
+
+	// JSONInput describes JSON object format.
+	JSONInput struct {
+		Type string `xml:"Type"`
+	}
+
+	// OutputSerialization describes the format of the results.
+	OutputSerialization struct {
+		CSV  *CSVOutput  `xml:"CSV"`
+		JSON *JSONOutput `xml:"JSON"`
+	}
+
+	// CSVOutput describes CSV results format.
+	CSVOutput struct {
+		QuoteFields          string `xml:"QuoteFields"`
+		QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter"`
+		RecordDelimiter      string `xml:"RecordDelimiter"`
+		FieldDelimiter       string `xml:"FieldDelimiter"`
+		QuoteCharacter       string `xml:"QuoteCharacter"`
+	}
+
+	// JSONOutput describes JSON results format.
+	JSONOutput struct {
+		RecordDelimiter string `xml:"RecordDelimiter"`
+	}
+
+	// RequestProgress enables periodic Progress messages.
+	RequestProgress struct {
+		Enabled bool `xml:"Enabled"`
+	}
+
+	// ScanRange is a byte range of the object to query.
+	ScanRange struct {
+		Start *int64 `xml:"Start"`
+		End   *int64 `xml:"End"`
+	}
+
+	// Query is a validated SelectObjectContent request ready to be run over the object payload.
+	Query struct {
+		req    *Request
+		stmt   *statement
+		writer recordWriter
+
+		keepAliveInterval time.Duration
+	}
+)
+
+// NewQuery validates the request and parses its SQL expression.
+func NewQuery(req *Request) (*Query, error) {
+	if !strings.EqualFold(req.ExpressionType, ExpressionTypeSQL) {
+		return nil, errors.GetAPIError(errors.ErrInvalidExpressionType)
+	}
+	if len(req.Expression) == 0 {
+		return nil, errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+	if len(req.Expression) > maxExpressionLength {
+		return nil, errors.GetAPIError(errors.ErrExpressionTooLong)
+	}
+	if req.ScanRange != nil {
+		return nil, errors.GetAPIErrorWithError(errors.ErrNotImplemented, fmt.Errorf("scan range is not supported"))
+	}
+
+	in := req.InputSerialization
+	switch {
+	case in.Parquet != nil:
+		return nil, errors.GetAPIError(errors.ErrInvalidDataSource)
+	case in.CSV != nil && in.JSON != nil:
+		return nil, errors.GetAPIError(errors.ErrObjectSerializationConflict)
+	case in.CSV == nil && in.JSON == nil:
+		return nil, errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+
+	switch strings.ToUpper(in.CompressionType) {
+	case "", CompressionNone, CompressionGZIP, CompressionBZIP2:
+	default:
+		return nil, errors.GetAPIError(errors.ErrInvalidCompressionFormat)
+	}
+
+	if in.CSV != nil {
+		switch strings.ToUpper(in.CSV.FileHeaderInfo) {
+		case "", FileHeaderNone, FileHeaderUse, FileHeaderIgnore:
+		default:
+			return nil, errors.GetAPIError(errors.ErrInvalidFileHeaderInfo)
+		}
+	} else {
+		switch strings.ToUpper(in.JSON.Type) {
+		case JSONDocument, JSONLines:
+		default:
+			return nil, errors.GetAPIError(errors.ErrInvalidJSONType)
+		}
+	}
+
+	res := &Query{req: req, keepAliveInterval: keepAliveInterval}
+
+	out := req.OutputSerialization
+	switch {
+	case out.CSV != nil && out.JSON != nil:
+		return nil, errors.GetAPIError(errors.ErrObjectSerializationConflict)
+	case out.CSV != nil:
+		w, err := newCSVWriter(out.CSV)
+		if err != nil {
+			return nil, err
+		}
+		res.writer = w
+	case out.JSON != nil:
+		res.writer = newJSONWriter(out.JSON)
+	default:
+		return nil, errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+
+	stmt, err := parseStatement(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmt.source) != 0 && in.CSV != nil {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSyntax, fmt.Errorf("path in FROM clause is allowed only for JSON"))
+	}
+	res.stmt = stmt
+
+	return res, nil
+}
+
+// stream writes messages of the response and collects statistics.
+// Messages can be sent by the query and by the keepalive timer concurrently.
+type stream struct {
+	w         io.Writer
+	scanned   *countingReader
+	processed *countingReader
+	returned  int64
+	progress  bool
+	records   bytes.Buffer
+
+	mu       sync.Mutex
+	lastSent time.Time
+	err      error
+}
+
+func (s *stream) send(msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(msg)
+}
+
+// write sends the message, it must be called under the lock.
+// Once writing fails, the stream is broken and all the next messages fail too.
+func (s *stream) write(msg []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if _, err := s.w.Write(msg); err != nil {
+		s.err = err
+		return err
+	}
+	if f, ok := s.w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	s.lastSent = time.Now()
+	return nil
+}
+
+func (s *stream) progressMessage() []byte {
+	return xmlMessage(eventProgress, Progress{
+		BytesScanned:   s.scanned.count(),
+		BytesProcessed: s.processed.count(),
+		BytesReturned:  atomic.LoadInt64(&s.returned),
+	})
+}
+
+// flush sends buffered records.
+func (s *stream) flush() error {
+	if s.records.Len() == 0 {
+		return nil
+	}
+
+	atomic.AddInt64(&s.returned, int64(s.records.Len()))
+	if err := s.send(recordsMessage(s.records.Bytes())); err != nil {
+		return err
+	}
+	s.records.Reset()
+
+	if s.progress {
+		return s.send(s.progressMessage())
+	}
+	return nil
+}
+
+// keepAlive sends Cont messages (and Progress ones if they are requested) when nothing
+// was sent during the interval, e.g. while a long scan doesn't match any record,
+// so clients and proxies don't close the idle connection. The returned function stops it.
+func (s *stream) keepAlive(interval time.Duration) func() {
+	done, stopped := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			s.mu.Lock()
+			if time.Since(s.lastSent) >= interval {
+				// the error is returned to the query on the next send
+				if err := s.write(contMessage()); err == nil && s.progress {
+					_ = s.write(s.progressMessage())
+				}
+			}
+			s.mu.Unlock()
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// Run executes the query over the object payload and writes the event stream to w.
+// If the query fails, error message is written to the stream and the error is returned.
+func (q *Query) Run(payload io.Reader, w io.Writer) error {
+	s := &stream{
+		w:         w,
+		scanned:   &countingReader{r: payload},
+		processed: &countingReader{},
+		progress:  q.req.RequestProgress.Enabled,
+		lastSent:  time.Now(),
+	}
+
+	err := q.runWithKeepAlive(s)
+	if err != nil {
+		// records processed before the failure are still sent
+		if flushErr := s.flush(); flushErr != nil {
+			return fmt.Errorf("%w (send records: %s)", err, flushErr)
+		}
+		code, message := "InternalError", err.Error()
+		if s3Err, ok := err.(errors.Error); ok {
+			code, message = s3Err.Code, s3Err.Description
+		}
+		if sendErr := s.send(errorMessage(code, message)); sendErr != nil {
+			return fmt.Errorf("%w (send error message: %s)", err, sendErr)
+		}
+		return err
+	}
+
+	if err = s.send(xmlMessage(eventStats, Stats{
+		BytesScanned:   s.scanned.count(),
+		BytesProcessed: s.processed.count(),
+		BytesReturned:  atomic.LoadInt64(&s.returned),
+	})); err != nil {
+		return err
+	}
+
+	return s.send(endMessage())
+}
+
+func (q *Query) runWithKeepAlive(s *stream) error {
+	if q.keepAliveInterval > 0 {
+		stop := s.keepAlive(q.keepAliveInterval)
+		defer stop()
+	}
+
+	return q.run(s)
+}
+
+func (q *Query) run(s *stream) error {
+	in := q.req.InputSerialization
+	decompressed, err := decompress(s.scanned, in.CompressionType)
+	if err != nil {
+		return err
+	}
+	s.processed.r = decompressed
+
+	var reader recordReader
+	if in.CSV != nil {
+		reader, err = newCSVReader(s.processed, in.CSV)
+	} else {
+		reader, err = newJSONReader(s.processed, in.JSON, q.stmt.source)
+	}
+	if err != nil {
+		return err
+	}
+
+	stmt := q.stmt
+	e := &env{alias: stmt.alias}
+	aggregators := make([]*aggregator, len(stmt.aggregates))
+	for i, fn := range stmt.aggregates {
+		aggregators[i] = &aggregator{fn: fn}
+	}
+
+	var count int64
+	for len(aggregators) != 0 || stmt.limit < 0 || count < stmt.limit {
+		rec, err := reader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		e.rec = rec
+		if stmt.where != nil {
+			matched, err := evalBool(stmt.where, e)
+			if err != nil {
+				return err
+			}
+			if matched != true {
+				continue
+			}
+		}
+
+		if len(aggregators) != 0 {
+			for _, agg := range aggregators {
+				if err = agg.update(e); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err = q.writeRecord(s, e); err != nil {
+			return err
+		}
+		count++
+
+		if s.records.Len() >= maxRecordsPayload {
+			if err = s.flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(aggregators) != 0 && stmt.limit != 0 {
+		e.rec = nil
+		e.aggregates = make([]interface{}, len(aggregators))
+		for i, agg := range aggregators {
+			e.aggregates[i] = agg.result()
+		}
+		if err = q.writeRecord(s, e); err != nil {
+			return err
+		}
+	}
+
+	return s.flush()
+}
+
+func (q *Query) writeRecord(s *stream, e *env) error {
+	if q.stmt.all {
+		names, values := e.rec.fields()
+		return q.writer.write(&s.records, names, values)
+	}
+
+	names := make([]string, len(q.stmt.items))
+	values := make([]interface{}, len(q.stmt.items))
+	for i, item := range q.stmt.items {
+		val, err := item.expr.eval(e)
+		if err != nil {
+			return err
+		}
+		names[i], values[i] = item.name, val
+	}
+
+	return q.writer.write(&s.records, names, values)
+}
@@ -0,0 +1,431 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+func decodeMessages(t *testing.T, data []byte) []testMessage {
+	var res []testMessage
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 16)
+		totalLen := binary.BigEndian.Uint32(data)
+		headersLen := binary.BigEndian.Uint32(data[4:])
+		require.Equal(t, crc32.ChecksumIEEE(data[:8]), binary.BigEndian.Uint32(data[8:]))
+		require.Equal(t, crc32.ChecksumIEEE(data[:totalLen-4]), binary.BigEndian.Uint32(data[totalLen-4:]))
+
+		msg := testMessage{headers: make(map[string]string)}
+		hdrs := data[12 : 12+headersLen]
+		for len(hdrs) > 0 {
+			nameLen := int(hdrs[0])
+			name := string(hdrs[1 : 1+nameLen])
+			require.EqualValues(t, headerValueTypeString, hdrs[1+nameLen])
+			valueLen := int(binary.BigEndian.Uint16(hdrs[2+nameLen:]))
+			msg.headers[name] = string(hdrs[4+nameLen : 4+nameLen+valueLen])
+			hdrs = hdrs[4+nameLen+valueLen:]
+		}
+		msg.payload = data[12+headersLen : totalLen-4]
+
+		res = append(res, msg)
+		data = data[totalLen:]
+	}
+	return res
+}
+
+// runQuery runs the query and returns concatenated records and the messages.
+func runQuery(t *testing.T, req *Request, input []byte) (string, []testMessage, error) {
+	req.ExpressionType = ExpressionTypeSQL
+	query, err := NewQuery(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var out bytes.Buffer
+	err = query.Run(bytes.NewReader(input), &out)
+
+	var records strings.Builder
+	messages := decodeMessages(t, out.Bytes())
+	for _, msg := range messages {
+		if msg.headers[":event-type"] == eventRecords {
+			records.Write(msg.payload)
+		}
+	}
+	return records.String(), messages, err
+}
+
+func csvRequest(expr, headerInfo string) *Request {
+	return &Request{
+		Expression: expr,
+		InputSerialization: InputSerialization{
+			CSV: &CSVInput{FileHeaderInfo: headerInfo},
+		},
+		OutputSerialization: OutputSerialization{
+			CSV: &CSVOutput{},
+		},
+	}
+}
+
+func jsonRequest(expr, jsonType string) *Request {
+	return &Request{
+		Expression: expr,
+		InputSerialization: InputSerialization{
+			JSON: &JSONInput{Type: jsonType},
+		},
+		OutputSerialization: OutputSerialization{
+			JSON: &JSONOutput{},
+		},
+	}
+}
+
+const testCSV = `name,age,city
+Alice,30,Berlin
+Bob,25,"New York, NY"
+Carol,,Paris
+Dave,41,Berlin
+`
+
+func TestSelectCSV(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "SELECT * FROM S3Object", expected: "Alice,30,Berlin\nBob,25,\"New York, NY\"\nCarol,,Paris\nDave,41,Berlin\n"},
+		{expr: "SELECT name FROM S3Object s WHERE s.city = 'Berlin'", expected: "Alice\nDave\n"},
+		{expr: "SELECT s.name, s.age FROM S3Object AS s WHERE age <> '' AND CAST(age AS INT) > 26", expected: "Alice,30\nDave,41\n"},
+		{expr: "SELECT name FROM S3Object WHERE age = ''", expected: "Carol\n"},
+		{expr: "SELECT name FROM S3Object WHERE name LIKE '_a%'", expected: "Carol\nDave\n"},
+		{expr: "SELECT name FROM S3Object WHERE city IN ('Paris', 'Rome') OR name = 'Bob'", expected: "Bob\nCarol\n"},
+		{expr: "SELECT UPPER(name) FROM S3Object LIMIT 2", expected: "ALICE\nBOB\n"},
+		{expr: "SELECT _1 FROM S3Object WHERE _3 = 'Paris'", expected: "Carol\n"},
+		{expr: "SELECT COUNT(*), MIN(CAST(age AS INT)), MAX(name) FROM S3Object WHERE age <> ''", expected: "3,25,Dave\n"},
+		{expr: "SELECT SUM(CAST(age AS INT)), AVG(CAST(age AS FLOAT)) FROM S3Object WHERE city = 'Berlin'", expected: "71,35.5\n"},
+		{expr: "SELECT COUNT(*) FROM S3Object WHERE name = 'Nobody'", expected: "0\n"},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			res, messages, err := runQuery(t, csvRequest(tc.expr, FileHeaderUse), []byte(testCSV))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+
+			last := messages[len(messages)-1]
+			require.Equal(t, eventEnd, last.headers[":event-type"])
+			stats := messages[len(messages)-2]
+			require.Equal(t, eventStats, stats.headers[":event-type"])
+
+			var s Stats
+			require.NoError(t, xml.Unmarshal(stats.payload, &s))
+			require.EqualValues(t, len(testCSV), s.BytesScanned)
+			require.EqualValues(t, len(testCSV), s.BytesProcessed)
+			require.EqualValues(t, len(tc.expected), s.BytesReturned)
+		})
+	}
+}
+
+func TestSelectCSVOptions(t *testing.T) {
+	t.Run("no header", func(t *testing.T) {
+		res, _, err := runQuery(t, csvRequest("SELECT _1 FROM S3Object", FileHeaderNone), []byte("a;b\nc;d\n"))
+		require.NoError(t, err)
+		require.Equal(t, "a;b\nc;d\n", res)
+
+		req := csvRequest("SELECT _2 FROM S3Object", FileHeaderNone)
+		req.InputSerialization.CSV.FieldDelimiter = ";"
+		res, _, err = runQuery(t, req, []byte("a;b\r\nc;d"))
+		require.NoError(t, err)
+		require.Equal(t, "b\nd\n", res)
+	})
+
+	t.Run("ignore header", func(t *testing.T) {
+		res, _, err := runQuery(t, csvRequest("SELECT name FROM S3Object", FileHeaderIgnore), []byte(testCSV))
+		require.NoError(t, err)
+		require.Equal(t, "\n\n\n\n", res)
+	})
+
+	t.Run("comments and quotes", func(t *testing.T) {
+		req := csvRequest("SELECT * FROM S3Object", FileHeaderNone)
+		req.InputSerialization.CSV.Comments = "#"
+		req.InputSerialization.CSV.AllowQuotedRecordDelimiter = true
+		req.OutputSerialization.CSV.QuoteFields = QuoteFieldsAlways
+		req.OutputSerialization.CSV.FieldDelimiter = "|"
+		res, _, err := runQuery(t, req, []byte("#comment\n\"multi\nline\",\"with \"\"quote\"\"\"\n"))
+		require.NoError(t, err)
+		require.Equal(t, "\"multi\nline\"|\"with \"\"quote\"\"\"\n", res)
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		req := csvRequest("SELECT name, age AS years FROM S3Object WHERE city = 'Paris'", FileHeaderUse)
+		req.OutputSerialization = OutputSerialization{JSON: &JSONOutput{RecordDelimiter: ","}}
+		res, _, err := runQuery(t, req, []byte(testCSV))
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"Carol","years":""},`, res)
+	})
+}
+
+func TestSelectJSON(t *testing.T) {
+	lines := `{"id":1,"user":{"name":"alice","tags":["a","b"]},"score":9.5}
+{"id":2,"user":{"name":"bob","tags":[]},"score":null}
+{"id":3,"user":{"name":"<carol>"}}
+`
+
+	for _, tc := range []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "SELECT * FROM S3Object s WHERE s.id = 1", expected: `{"id":1,"user":{"name":"alice","tags":["a","b"]},"score":9.5}` + "\n"},
+		{expr: "SELECT s.user.name FROM S3Object s WHERE s.score IS NULL", expected: "{\"name\":\"bob\"}\n{\"name\":\"<carol>\"}\n"},
+		{expr: "SELECT s.id FROM S3Object s WHERE s.score IS MISSING", expected: "{\"id\":3}\n"},
+		{expr: "SELECT s.user.tags[1] AS tag FROM S3Object s", expected: "{\"tag\":\"b\"}\n{}\n{}\n"},
+		{expr: "SELECT COUNT(s.score) AS cnt, SUM(s.id) AS total FROM S3Object s", expected: "{\"cnt\":1,\"total\":6}\n"},
+		{expr: "SELECT s.id * 2 AS double FROM S3Object s WHERE s.id BETWEEN 2 AND 3 LIMIT 1", expected: "{\"double\":4}\n"},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			res, _, err := runQuery(t, jsonRequest(tc.expr, JSONLines), []byte(lines))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("document", func(t *testing.T) {
+		doc := `{"items": [{"v": 1}, {"v": 2}, {"v": 3}]}`
+		res, _, err := runQuery(t, jsonRequest("SELECT i.v FROM S3Object[*].items[*] i WHERE i.v >= 2", JSONDocument), []byte(doc))
+		require.NoError(t, err)
+		require.Equal(t, "{\"v\":2}\n{\"v\":3}\n", res)
+	})
+
+	t.Run("parsing error", func(t *testing.T) {
+		res, messages, err := runQuery(t, jsonRequest("SELECT * FROM S3Object", JSONLines), []byte("{\"a\":1}\n{\"a\":"))
+		require.True(t, errors.IsS3Error(err, errors.ErrJSONParsingError))
+		require.Equal(t, "{\"a\":1}\n", res)
+
+		last := messages[len(messages)-1]
+		require.Equal(t, "error", last.headers[":message-type"])
+		require.Equal(t, "JSONParsingError", last.headers[":error-code"])
+	})
+}
+
+func TestSelectCompression(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte(testCSV))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	req := csvRequest("SELECT name FROM S3Object WHERE city = 'Paris'", FileHeaderUse)
+	req.InputSerialization.CompressionType = CompressionGZIP
+	req.RequestProgress.Enabled = true
+	res, messages, err := runQuery(t, req, buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "Carol\n", res)
+
+	var types []string
+	for _, msg := range messages {
+		types = append(types, msg.headers[":event-type"])
+	}
+	require.Equal(t, []string{eventRecords, eventProgress, eventStats, eventEnd}, types)
+
+	var s Stats
+	require.NoError(t, xml.Unmarshal(messages[2].payload, &s))
+	require.EqualValues(t, buf.Len(), s.BytesScanned)
+	require.EqualValues(t, len(testCSV), s.BytesProcessed)
+
+	t.Run("invalid gzip", func(t *testing.T) {
+		_, _, err = runQuery(t, req, []byte(testCSV))
+		require.True(t, errors.IsS3Error(err, errors.ErrInvalidCompressionFormat))
+	})
+}
+
+func TestSelectLargeOutput(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 20000; i++ {
+		input.WriteString("some rather long value to fill the buffer,42\n")
+	}
+
+	res, messages, err := runQuery(t, csvRequest("SELECT * FROM S3Object", FileHeaderNone), []byte(input.String()))
+	require.NoError(t, err)
+	require.Equal(t, input.String(), res)
+	require.Greater(t, len(messages), 4)
+	for _, msg := range messages {
+		require.LessOrEqual(t, len(msg.payload), maxRecordsPayload+64)
+	}
+}
+
+// slowReader returns the data by lines with a delay before each one.
+type slowReader struct {
+	lines []string
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p, r.lines[0])
+	r.lines[0] = r.lines[0][n:]
+	if len(r.lines[0]) == 0 {
+		r.lines = r.lines[1:]
+	}
+	return n, nil
+}
+
+func TestSelectKeepAlive(t *testing.T) {
+	for _, progress := range []bool{false, true} {
+		req := csvRequest("SELECT name FROM S3Object WHERE city = 'Paris'", FileHeaderUse)
+		req.ExpressionType = ExpressionTypeSQL
+		req.RequestProgress.Enabled = progress
+		query, err := NewQuery(req)
+		require.NoError(t, err)
+		require.Equal(t, keepAliveInterval, query.keepAliveInterval)
+		query.keepAliveInterval = 10 * time.Millisecond
+
+		var out bytes.Buffer
+		payload := &slowReader{lines: strings.SplitAfter(testCSV, "\n"), delay: 30 * time.Millisecond}
+		require.NoError(t, query.Run(payload, &out))
+
+		counts := make(map[string]int)
+		var types []string
+		for _, msg := range decodeMessages(t, out.Bytes()) {
+			counts[msg.headers[":event-type"]]++
+			types = append(types, msg.headers[":event-type"])
+		}
+		require.Greater(t, counts[eventCont], 0)
+		require.Equal(t, progress, counts[eventProgress] > 0)
+		require.Equal(t, 1, counts[eventRecords])
+		require.Equal(t, []string{eventStats, eventEnd}, types[len(types)-2:])
+	}
+
+	t.Run("broken connection", func(t *testing.T) {
+		req := csvRequest("SELECT * FROM S3Object", FileHeaderUse)
+		req.ExpressionType = ExpressionTypeSQL
+		query, err := NewQuery(req)
+		require.NoError(t, err)
+		query.keepAliveInterval = 10 * time.Millisecond
+
+		payload := &slowReader{lines: strings.SplitAfter(testCSV, "\n"), delay: 30 * time.Millisecond}
+		require.Error(t, query.Run(payload, failingWriter{}))
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestNewQueryErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		req  func() *Request
+		code errors.ErrorCode
+	}{
+		{
+			name: "expression type",
+			req: func() *Request {
+				req := csvRequest("SELECT * FROM S3Object", "")
+				req.ExpressionType = "XPATH"
+				return req
+			},
+			code: errors.ErrInvalidExpressionType,
+		},
+		{
+			name: "parquet",
+			req: func() *Request {
+				req := csvRequest("SELECT * FROM S3Object", "")
+				req.InputSerialization = InputSerialization{Parquet: &struct{}{}}
+				return req
+			},
+			code: errors.ErrInvalidDataSource,
+		},
+		{
+			name: "serialization conflict",
+			req: func() *Request {
+				req := csvRequest("SELECT * FROM S3Object", "")
+				req.OutputSerialization.JSON = &JSONOutput{}
+				return req
+			},
+			code: errors.ErrObjectSerializationConflict,
+		},
+		{
+			name: "compression",
+			req: func() *Request {
+				req := csvRequest("SELECT * FROM S3Object", "")
+				req.InputSerialization.CompressionType = "ZSTD"
+				return req
+			},
+			code: errors.ErrInvalidCompressionFormat,
+		},
+		{
+			name: "file header info",
+			req:  func() *Request { return csvRequest("SELECT * FROM S3Object", "FIRST") },
+			code: errors.ErrInvalidFileHeaderInfo,
+		},
+		{
+			name: "json type",
+			req:  func() *Request { return jsonRequest("SELECT * FROM S3Object", "TREE") },
+			code: errors.ErrInvalidJSONType,
+		},
+		{
+			name: "quote fields",
+			req: func() *Request {
+				req := csvRequest("SELECT * FROM S3Object", "")
+				req.OutputSerialization.CSV.QuoteFields = "NEVER"
+				return req
+			},
+			code: errors.ErrInvalidQuoteFields,
+		},
+		{
+			name: "expression too long",
+			req: func() *Request {
+				return csvRequest("SELECT * FROM S3Object WHERE _1 = '"+strings.Repeat("a", maxExpressionLength)+"'", "")
+			},
+			code: errors.ErrExpressionTooLong,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := tc.req()
+			if req.ExpressionType == "" {
+				req.ExpressionType = ExpressionTypeSQL
+			}
+			_, err := NewQuery(req)
+			require.True(t, errors.IsS3Error(err, tc.code), err)
+		})
+	}
+}
+
+func TestRequestDecoding(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+   <Expression>SELECT s.name FROM S3Object s</Expression>
+   <ExpressionType>SQL</ExpressionType>
+   <RequestProgress><Enabled>true</Enabled></RequestProgress>
+   <InputSerialization>
+      <CompressionType>GZIP</CompressionType>
+      <CSV><FileHeaderInfo>USE</FileHeaderInfo><FieldDelimiter>	</FieldDelimiter></CSV>
+   </InputSerialization>
+   <OutputSerialization><JSON><RecordDelimiter>,</RecordDelimiter></JSON></OutputSerialization>
+</SelectObjectContentRequest>`
+
+	var req Request
+	require.NoError(t, xml.NewDecoder(strings.NewReader(body)).Decode(&req))
+	require.Equal(t, "SELECT s.name FROM S3Object s", req.Expression)
+	require.True(t, req.RequestProgress.Enabled)
+	require.Equal(t, CompressionGZIP, req.InputSerialization.CompressionType)
+	require.Equal(t, "\t", req.InputSerialization.CSV.FieldDelimiter)
+	require.Equal(t, ",", req.OutputSerialization.JSON.RecordDelimiter)
+
+	_, err := NewQuery(&req)
+	require.NoError(t, err)
+}
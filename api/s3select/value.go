@@ -0,0 +1,250 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of the SQL expressions are represented by nil (NULL), missingValue (MISSING),
+// bool, int64, float64, string, time.Time, *jsonObject and []interface{}.
+
+// missingValue is a value of the field absent in the record.
+type missingValue struct{}
+
+var missing = missingValue{}
+
+// jsonObject is a JSON object which keeps the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]interface{})}
+}
+
+func (o *jsonObject) set(key string, val interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = val
+}
+
+// get returns the value of the key, keys without quotes are matched case-insensitively.
+func (o *jsonObject) get(key string, caseSensitive bool) interface{} {
+	if val, ok := o.values[key]; ok {
+		return val
+	}
+	if !caseSensitive {
+		for _, k := range o.keys {
+			if strings.EqualFold(k, key) {
+				return o.values[k]
+			}
+		}
+	}
+	return missing
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, key := range o.keys {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeJSONValue(buf, key); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONValue(buf, o.values[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, val interface{}) error {
+	switch v := val.(type) {
+	case time.Time:
+		val = formatTimestamp(v)
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			val = formatValue(v)
+		}
+	}
+
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(val); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(data.Bytes(), []byte{'\n'}))
+	return nil
+}
+
+func isNull(val interface{}) bool {
+	return val == nil || val == missing
+}
+
+// formatValue returns text representation of the value used in CSV output.
+func formatValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil, missingValue:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case time.Time:
+		return formatTimestamp(v)
+	default:
+		var buf bytes.Buffer
+		if err := writeJSONValue(&buf, v); err != nil {
+			return fmt.Sprint(v)
+		}
+		return buf.String()
+	}
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s", s)
+}
+
+// toNumber converts the value to int64 or float64, strings are parsed.
+func toNumber(val interface{}) (interface{}, bool) {
+	switch v := val.(type) {
+	case int64, float64:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func toFloat(val interface{}) float64 {
+	if i, ok := val.(int64); ok {
+		return float64(i)
+	}
+	return val.(float64)
+}
+
+func toBool(val interface{}) (bool, bool) {
+	switch v := val.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// compareValues compares two non-null values. Strings are converted to the type of
+// the other operand (number, bool or timestamp) if possible. ok is false if values
+// are not comparable.
+func compareValues(a, b interface{}) (res int, ok bool) {
+	switch a.(type) {
+	case int64, float64:
+		if bn, isNum := toNumber(b); isNum {
+			return compareNumbers(a, bn), true
+		}
+	case bool:
+		if bb, isBool := toBool(b); isBool {
+			return compareBools(a.(bool), bb), true
+		}
+	case time.Time:
+		if bt, isTime := toTimestamp(b); isTime {
+			return compareTimes(a.(time.Time), bt), true
+		}
+	case string:
+		switch b.(type) {
+		case string:
+			return strings.Compare(a.(string), b.(string)), true
+		case int64, float64, bool, time.Time:
+			res, ok = compareValues(b, a)
+			return -res, ok
+		}
+	}
+
+	return 0, false
+}
+
+func toTimestamp(val interface{}) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := parseTimestamp(strings.TrimSpace(v))
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+func compareNumbers(a, b interface{}) int {
+	ai, aInt := a.(int64)
+	bi, bInt := b.(int64)
+	if aInt && bInt {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+
+	af, bf := toFloat(a), toFloat(b)
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
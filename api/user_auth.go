@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	"go.uber.org/zap"
 )
 
@@ -36,6 +37,9 @@ func AttachUserAuth(router *mux.Router, center auth.Center, log *zap.Logger) {
 				}
 			} else {
 				ctx = context.WithValue(r.Context(), BoxData, box)
+				if box.Gate.BearerToken != nil {
+					GetReqInfo(ctx).User = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
+				}
 			}
 
 			h.ServeHTTP(w, r.WithContext(ctx))
@@ -3,12 +3,18 @@ package authmate
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base32"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,10 +23,12 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	neofsecdsa "github.com/nspcc-dev/neofs-sdk-go/crypto/ecdsa"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
@@ -62,7 +70,7 @@ type NeoFS interface {
 	// It sets 'Timestamp' attribute to the current time.
 	// It returns the ID of the saved container.
 	//
-	// The container must be private with GET access for OTHERS group.
+	// The container must be private with GET, HEAD and SEARCH access for OTHERS group.
 	// Creation time should also be stamped.
 	//
 	// It returns exactly one non-nil value. It returns any error encountered which
@@ -76,6 +84,11 @@ type NeoFS interface {
 	//
 	// It returns any error encountered which prevented computing epochs.
 	TimeToEpoch(context.Context, time.Time) (uint64, uint64, error)
+
+	// DeleteObject marks the object to be removed from NeoFS network by address.
+	//
+	// It returns any error encountered which prevented the object from being removed.
+	DeleteObject(context.Context, oid.Address) error
 }
 
 // Agent contains client communicating with NeoFS and logger.
@@ -104,6 +117,22 @@ type (
 		Lifetime              time.Duration
 		AwsCliCredentialsFile string
 		ContainerPolicies     ContainerPolicies
+		// AccessKeyID is registered in the registry container instead of using
+		// the access box address as access key ID if it's set or RandomAccessKeyID is true.
+		AccessKeyID         string
+		RandomAccessKeyID   bool
+		RegistryContainerID cid.ID
+		Restrictions        Restrictions
+	}
+
+	// Restrictions contains limits the gateway applies to requests with the
+	// issued credentials. Empty fields don't restrict requests.
+	Restrictions struct {
+		Buckets       []string
+		Prefixes      []string
+		Operations    []string
+		SourceCIDRs   []string
+		MaxObjectSize uint64
 	}
 
 	// ContainerOptions groups parameters of auth container to put the secret into.
@@ -118,6 +147,43 @@ type (
 		SecretAddress  string
 		GatePrivateKey *keys.PrivateKey
 	}
+
+	// ListSecretsOptions contains options for passing to Agent.ListSecrets method.
+	ListSecretsOptions struct {
+		ContainerID cid.ID
+		NeoFSKey    *keys.PrivateKey
+	}
+
+	// UpdateSecretOptions contains options for passing to Agent.UpdateSecret method.
+	UpdateSecretOptions struct {
+		SecretAddress string
+		// AccessKeyID is printed instead of the access box address if it's set.
+		AccessKeyID       string
+		NeoFSKey          *keys.PrivateKey
+		GatePrivateKey    *keys.PrivateKey
+		GatesPublicKeys   []*keys.PublicKey
+		EACLRules         []byte
+		SessionTokenRules []byte
+		SkipSessionRules  bool
+		Lifetime          time.Duration
+	}
+
+	// RevokeSecretOptions contains options for passing to Agent.RevokeSecret method.
+	RevokeSecretOptions struct {
+		SecretAddress string
+		NeoFSKey      *keys.PrivateKey
+	}
+)
+
+const (
+	// accessBoxSuffix is a suffix of file names of access box objects.
+	accessBoxSuffix = "_access.box"
+	// accessKeySuffix is a suffix of file names of access key registry entries.
+	accessKeySuffix = "_access.key"
+	// generatedAccessKeyIDLength is a length of random access key IDs, the same as AWS uses.
+	generatedAccessKeyIDLength = 20
+	// expirationEpochAttribute is a system attribute of the last epoch of the object lifetime.
+	expirationEpochAttribute = "__NEOFS__EXPIRATION_EPOCH"
 )
 
 // lifetimeOptions holds NeoFS epochs, iat -- epoch which the token was issued at, exp -- epoch when the token expires.
@@ -139,6 +205,18 @@ type (
 		BearerToken     *bearer.Token `json:"-"`
 		SecretAccessKey string        `json:"secret_access_key"`
 	}
+
+	secretInfo struct {
+		AccessKeyID       string                            `json:"access_key_id"`
+		IssuedAt          string                            `json:"issued_at,omitempty"`
+		UpdatedAt         string                            `json:"updated_at,omitempty"`
+		ExpirationEpoch   uint64                            `json:"expiration_epoch"`
+		GatesPublicKeys   []string                          `json:"gates_public_keys,omitempty"`
+		ContainerPolicies map[string]string                 `json:"container_policies,omitempty"`
+		Restrictions      *accessbox.AccessBox_Restrictions `json:"restrictions,omitempty"`
+		Revoked           bool                              `json:"revoked"`
+		RevokedAt         string                            `json:"revoked_at,omitempty"`
+	}
 )
 
 func (a *Agent) checkContainer(ctx context.Context, opts ContainerOptions, idOwner user.ID) (cid.ID, error) {
@@ -200,6 +278,27 @@ func preparePolicy(policy ContainerPolicies) ([]*accessbox.AccessBox_ContainerPo
 	return result, nil
 }
 
+func prepareRestrictions(restrictions Restrictions) (*accessbox.AccessBox_Restrictions, error) {
+	if len(restrictions.Buckets) == 0 && len(restrictions.Prefixes) == 0 && len(restrictions.Operations) == 0 &&
+		len(restrictions.SourceCIDRs) == 0 && restrictions.MaxObjectSize == 0 {
+		return nil, nil
+	}
+
+	for _, cidr := range restrictions.SourceCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid source cidr '%s': %w", cidr, err)
+		}
+	}
+
+	return &accessbox.AccessBox_Restrictions{
+		Buckets:       restrictions.Buckets,
+		Prefixes:      restrictions.Prefixes,
+		Operations:    restrictions.Operations,
+		SourceCIDRs:   restrictions.SourceCIDRs,
+		MaxObjectSize: restrictions.MaxObjectSize,
+	}, nil
+}
+
 // IssueSecret creates an auth token, puts it in the NeoFS network and writes to io.Writer a new secret access key.
 func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecretOptions) error {
 	var (
@@ -213,6 +312,16 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 		return fmt.Errorf("prepare policies: %w", err)
 	}
 
+	restrictions, err := prepareRestrictions(options.Restrictions)
+	if err != nil {
+		return fmt.Errorf("prepare restrictions: %w", err)
+	}
+
+	alias, err := accessKeyAlias(options)
+	if err != nil {
+		return err
+	}
+
 	lifetime.Iat, lifetime.Exp, err = a.neoFS.TimeToEpoch(ctx, time.Now().Add(options.Lifetime))
 	if err != nil {
 		return fmt.Errorf("fetch time to epoch: %w", err)
@@ -229,6 +338,7 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 	}
 
 	box.ContainerPolicy = policies
+	box.Restrictions = restrictions
 
 	var idOwner user.ID
 	user.IDFromKey(&idOwner, options.NeoFSKey.PrivateKey.PublicKey)
@@ -241,6 +351,13 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 		return fmt.Errorf("check container: %w", err)
 	}
 
+	var staleEntries []oid.Address
+	if alias != "" {
+		if staleEntries, err = a.checkAccessKeyAlias(ctx, options.RegistryContainerID, alias, idOwner); err != nil {
+			return fmt.Errorf("check access key id: %w", err)
+		}
+	}
+
 	a.log.Info("store bearer token into NeoFS",
 		zap.Stringer("owner_tkn", idOwner))
 
@@ -251,10 +368,15 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 		return fmt.Errorf("failed to put bearer token: %w", err)
 	}
 
-	objID := addr.Object()
-	strIDObj := objID.EncodeToString()
+	strIDObj := addr.Object().EncodeToString()
+	accessKeyID := formatAccessKeyID(addr)
 
-	accessKeyID := addr.Container().EncodeToString() + "0" + strIDObj
+	if alias != "" {
+		if err = a.registerAccessKeyAlias(ctx, options.RegistryContainerID, alias, idOwner, addr, staleEntries); err != nil {
+			return fmt.Errorf("register access key id: %w", err)
+		}
+		accessKeyID = alias
+	}
 
 	ir := &issuingResult{
 		AccessKeyID:     accessKeyID,
@@ -288,6 +410,137 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 	return nil
 }
 
+// accessKeyAlias returns the access key ID requested for the secret, empty string
+// is returned if the access box address must be used as access key ID.
+func accessKeyAlias(options *IssueSecretOptions) (string, error) {
+	var (
+		alias = options.AccessKeyID
+		err   error
+	)
+
+	if options.RandomAccessKeyID {
+		if alias, err = generateAccessKeyID(); err != nil {
+			return "", fmt.Errorf("generate access key id: %w", err)
+		}
+	} else if alias != "" && !tokens.IsAccessKeyAlias(alias) {
+		return "", fmt.Errorf("invalid access key id '%s': 16-128 letters, digits and underscores are allowed, "+
+			"<cid>0<oid> format is reserved", alias)
+	}
+
+	if alias != "" && options.RegistryContainerID.Equals(cid.ID{}) {
+		return "", errors.New("registry container must be set to register access key id")
+	}
+
+	return alias, nil
+}
+
+// generateAccessKeyID returns a random access key ID of upper case letters and digits.
+func generateAccessKeyID() (string, error) {
+	buf := make([]byte, generatedAccessKeyIDLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.EncodeToString(buf)[:generatedAccessKeyIDLength], nil
+}
+
+// checkAccessKeyAlias makes sure the access key ID isn't used by an existing access box.
+// Gates trust only the registry entries of the registry container owner, so the issuer
+// must be the owner, and entries of other users are ignored. Registry entries of the
+// access key ID referring to removed access boxes are returned to be replaced.
+func (a *Agent) checkAccessKeyAlias(ctx context.Context, registry cid.ID, accessKeyID string, idOwner user.ID) ([]oid.Address, error) {
+	registryOwner, err := a.neoFS.ContainerOwner(ctx, registry)
+	if err != nil {
+		return nil, fmt.Errorf("get registry container owner: %w", err)
+	}
+	if !registryOwner.Equals(idOwner) {
+		return nil, fmt.Errorf("access key id can be registered only by the registry container owner %s", registryOwner)
+	}
+
+	ids, err := a.neoFS.SearchObjects(ctx, tokens.PrmObjectSearch{
+		Container:  registry,
+		Owner:      &registryOwner,
+		Attributes: [][2]string{{tokens.AttributeAccessKeyID, accessKeyID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search registry entries: %w", err)
+	}
+
+	entries := make([]oid.Address, len(ids))
+	for i, id := range ids {
+		entries[i].SetContainer(registry)
+		entries[i].SetObject(id)
+
+		header, err := a.neoFS.ReadObjectHeader(ctx, entries[i])
+		if err != nil {
+			return nil, fmt.Errorf("read registry entry: %w", err)
+		}
+
+		boxAddr, err := tokens.RegisteredAccessBox(header)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := a.accessBoxExists(ctx, boxAddr)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("access key id '%s' is already used by %s", accessKeyID, boxAddr)
+		}
+	}
+
+	return entries, nil
+}
+
+// accessBoxExists checks if the access box or any of its versions is stored in NeoFS.
+func (a *Agent) accessBoxExists(ctx context.Context, addr oid.Address) (bool, error) {
+	_, err := a.neoFS.ReadObjectHeader(ctx, addr)
+	if err == nil {
+		return true, nil
+	} else if !client.IsErrObjectNotFound(err) && !client.IsErrObjectAlreadyRemoved(err) {
+		return false, fmt.Errorf("read access box header: %w", err)
+	}
+
+	ids, err := a.neoFS.SearchObjects(ctx, tokens.PrmObjectSearch{
+		Container:  addr.Container(),
+		Attributes: [][2]string{{tokens.AttributeAccessBoxOrigin, addr.Object().EncodeToString()}},
+	})
+	if err != nil {
+		return false, fmt.Errorf("search access box versions: %w", err)
+	}
+
+	return len(ids) != 0, nil
+}
+
+// registerAccessKeyAlias replaces stale registry entries of the access key ID
+// with the entry referring to the access box. The entry doesn't expire since
+// the access box can be updated.
+func (a *Agent) registerAccessKeyAlias(ctx context.Context, registry cid.ID, accessKeyID string, idOwner user.ID, addr oid.Address, staleEntries []oid.Address) error {
+	for _, entry := range staleEntries {
+		if err := a.neoFS.DeleteObject(ctx, entry); err != nil {
+			return fmt.Errorf("delete stale registry entry: %w", err)
+		}
+	}
+
+	_, err := a.neoFS.CreateObject(ctx, tokens.PrmObjectCreate{
+		Creator:   idOwner,
+		Container: registry,
+		Filename:  accessKeyID + accessKeySuffix,
+		Attributes: [][2]string{
+			{tokens.AttributeAccessKeyID, accessKeyID},
+			{tokens.AttributeAccessBox, addr.EncodeToString()},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put registry entry: %w", err)
+	}
+
+	a.log.Info("access key id is registered", zap.String("access_key_id", accessKeyID), zap.Stringer("address", addr))
+
+	return nil
+}
+
 // ObtainSecret receives an existing secret access key from NeoFS and
 // writes to io.Writer the secret access key.
 func (a *Agent) ObtainSecret(ctx context.Context, w io.Writer, options *ObtainSecretOptions) error {
@@ -313,6 +566,384 @@ func (a *Agent) ObtainSecret(ctx context.Context, w io.Writer, options *ObtainSe
 	return enc.Encode(or)
 }
 
+// UpdateSecret puts a new version of the access box with fresh tokens and writes
+// to io.Writer the secret. The access key ID and the secret access key stay the
+// same, so clients don't need new credentials. Previous versions are removed.
+func (a *Agent) UpdateSecret(ctx context.Context, w io.Writer, options *UpdateSecretOptions) error {
+	var addr oid.Address
+	if err := addr.DecodeString(options.SecretAddress); err != nil {
+		return fmt.Errorf("failed to parse secret address: %w", err)
+	}
+
+	box, err := tokens.
+		New(a.neoFS, options.GatePrivateKey, cache.DefaultAccessBoxConfig(a.log)).
+		GetBox(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("failed to get current tokens: %w", err)
+	}
+
+	secret, err := hex.DecodeString(box.Gate.AccessKey)
+	if err != nil {
+		return fmt.Errorf("failed to decode secret access key: %w", err)
+	}
+
+	var lifetime lifetimeOptions
+	lifetime.Iat, lifetime.Exp, err = a.neoFS.TimeToEpoch(ctx, time.Now().Add(options.Lifetime))
+	if err != nil {
+		return fmt.Errorf("fetch time to epoch: %w", err)
+	}
+
+	gatesData, err := createTokens(&IssueSecretOptions{
+		NeoFSKey:          options.NeoFSKey,
+		GatesPublicKeys:   options.GatesPublicKeys,
+		EACLRules:         options.EACLRules,
+		SessionTokenRules: options.SessionTokenRules,
+		SkipSessionRules:  options.SkipSessionRules,
+	}, lifetime)
+	if err != nil {
+		return fmt.Errorf("create tokens: %w", err)
+	}
+
+	updatedBox, secrets, err := accessbox.PackTokensWithSecret(gatesData, secret)
+	if err != nil {
+		return fmt.Errorf("pack tokens: %w", err)
+	}
+
+	for _, policy := range box.Policies {
+		updatedBox.ContainerPolicy = append(updatedBox.ContainerPolicy, &accessbox.AccessBox_ContainerPolicy{
+			LocationConstraint: policy.LocationConstraint,
+			Policy:             policy.Policy.Marshal(),
+		})
+	}
+
+	if box.Restrictions != nil {
+		updatedBox.Restrictions = &accessbox.AccessBox_Restrictions{
+			Buckets:       box.Restrictions.Buckets,
+			Prefixes:      box.Restrictions.Prefixes,
+			Operations:    box.Restrictions.Operations,
+			MaxObjectSize: box.Restrictions.MaxObjectSize,
+		}
+		for _, network := range box.Restrictions.SourceNetworks {
+			updatedBox.Restrictions.SourceCIDRs = append(updatedBox.Restrictions.SourceCIDRs, network.String())
+		}
+	}
+
+	var idOwner user.ID
+	user.IDFromKey(&idOwner, options.NeoFSKey.PrivateKey.PublicKey)
+
+	versionAddr, err := tokens.
+		New(a.neoFS, secrets.EphemeralKey, cache.DefaultAccessBoxConfig(a.log)).
+		Update(ctx, addr, idOwner, updatedBox, lifetime.Exp, options.GatesPublicKeys...)
+	if err != nil {
+		return fmt.Errorf("failed to put updated bearer token: %w", err)
+	}
+
+	versions, err := a.searchVersions(ctx, addr, idOwner)
+	if err != nil {
+		a.log.Warn("couldn't search previous versions of access box", zap.Stringer("address", addr), zap.Error(err))
+	}
+	for _, version := range versions {
+		if version.Object().Equals(versionAddr.Object()) {
+			continue
+		}
+		if err = a.neoFS.DeleteObject(ctx, version); err != nil {
+			a.log.Warn("couldn't remove previous version of access box", zap.Stringer("address", version), zap.Error(err))
+		}
+	}
+
+	a.log.Info("access box is updated", zap.Stringer("address", addr), zap.Stringer("version", versionAddr))
+
+	accessKeyID := options.AccessKeyID
+	if accessKeyID == "" {
+		accessKeyID = formatAccessKeyID(addr)
+	}
+
+	ir := &issuingResult{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secrets.AccessKey,
+		OwnerPrivateKey: hex.EncodeToString(secrets.EphemeralKey.Bytes()),
+		WalletPublicKey: hex.EncodeToString(options.NeoFSKey.PublicKey().Bytes()),
+		ContainerID:     addr.Container().EncodeToString(),
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ir)
+}
+
+// searchVersions returns addresses of the access box versions put by the owner.
+func (a *Agent) searchVersions(ctx context.Context, addr oid.Address, idOwner user.ID) ([]oid.Address, error) {
+	ids, err := a.neoFS.SearchObjects(ctx, tokens.PrmObjectSearch{
+		Container:  addr.Container(),
+		Owner:      &idOwner,
+		Attributes: [][2]string{{tokens.AttributeAccessBoxOrigin, addr.Object().EncodeToString()}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]oid.Address, len(ids))
+	for i, id := range ids {
+		versions[i].SetContainer(addr.Container())
+		versions[i].SetObject(id)
+	}
+
+	return versions, nil
+}
+
+// ListSecrets writes to io.Writer secrets issued by the key owner in the container:
+// gates, expiration and container policies of every access box. Revoked secrets
+// are listed by their revocation markers.
+func (a *Agent) ListSecrets(ctx context.Context, w io.Writer, options *ListSecretsOptions) error {
+	var idOwner user.ID
+	user.IDFromKey(&idOwner, options.NeoFSKey.PrivateKey.PublicKey)
+
+	ids, err := a.neoFS.SearchObjects(ctx, tokens.PrmObjectSearch{Container: options.ContainerID, Owner: &idOwner})
+	if err != nil {
+		return fmt.Errorf("search objects: %w", err)
+	}
+
+	infos := make(map[string]*secretInfo, len(ids))
+	for _, id := range ids {
+		var addr oid.Address
+		addr.SetContainer(options.ContainerID)
+		addr.SetObject(id)
+
+		info, err := a.readSecretInfo(ctx, addr)
+		if err != nil {
+			a.log.Warn("couldn't read secret", zap.Stringer("address", addr), zap.Error(err))
+			continue
+		}
+		if info != nil {
+			infos[info.AccessKeyID] = mergeSecretInfo(infos[info.AccessKeyID], info)
+		}
+	}
+
+	secrets := make([]*secretInfo, 0, len(infos))
+	for _, info := range infos {
+		secrets = append(secrets, info)
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].AccessKeyID < secrets[j].AccessKeyID
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(secrets)
+}
+
+// readSecretInfo returns info about the access box, its version or its
+// revocation marker, nil is returned for other objects.
+func (a *Agent) readSecretInfo(ctx context.Context, addr oid.Address) (*secretInfo, error) {
+	header, err := a.neoFS.ReadObjectHeader(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("read object header: %w", err)
+	}
+
+	var (
+		info     = &secretInfo{AccessKeyID: formatAccessKeyID(addr)}
+		fileName string
+		created  string
+		updated  bool
+	)
+
+	for _, attr := range header.Attributes() {
+		switch attr.Key() {
+		case object.AttributeFileName:
+			fileName = attr.Value()
+		case object.AttributeTimestamp:
+			if unix, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+				created = time.Unix(unix, 0).UTC().Format(time.RFC3339)
+			}
+		case expirationEpochAttribute:
+			info.ExpirationEpoch, _ = strconv.ParseUint(attr.Value(), 10, 64)
+		case tokens.AttributeRevokedAccessBox, tokens.AttributeAccessBoxOrigin:
+			var boxID oid.ID
+			if err = boxID.DecodeString(attr.Value()); err != nil {
+				return nil, fmt.Errorf("invalid access box id in '%s' attribute: %w", attr.Key(), err)
+			}
+			var boxAddr oid.Address
+			boxAddr.SetContainer(addr.Container())
+			boxAddr.SetObject(boxID)
+
+			info.AccessKeyID = formatAccessKeyID(boxAddr)
+			info.Revoked = attr.Key() == tokens.AttributeRevokedAccessBox
+			updated = attr.Key() == tokens.AttributeAccessBoxOrigin
+		}
+	}
+
+	if info.Revoked {
+		info.RevokedAt = created
+		return info, nil
+	}
+
+	if !strings.HasSuffix(fileName, accessBoxSuffix) {
+		return nil, nil
+	}
+	if updated {
+		info.UpdatedAt = created
+	} else {
+		info.IssuedAt = created
+	}
+
+	payload, err := a.neoFS.ReadObjectPayload(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("read access box: %w", err)
+	}
+
+	var box accessbox.AccessBox
+	if err = box.Unmarshal(payload); err != nil {
+		return nil, fmt.Errorf("unmarshal access box: %w", err)
+	}
+
+	for _, gate := range box.Gates {
+		info.GatesPublicKeys = append(info.GatesPublicKeys, hex.EncodeToString(gate.GatePublicKey))
+	}
+
+	policies, err := box.GetPlacementPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("get container policies: %w", err)
+	}
+	for _, policy := range policies {
+		var sb strings.Builder
+		if err = policy.Policy.WriteStringTo(&sb); err != nil {
+			return nil, fmt.Errorf("encode placement policy: %w", err)
+		}
+		if info.ContainerPolicies == nil {
+			info.ContainerPolicies = make(map[string]string)
+		}
+		info.ContainerPolicies[policy.LocationConstraint] = sb.String()
+	}
+	info.Restrictions = box.Restrictions
+
+	return info, nil
+}
+
+// mergeSecretInfo combines info about the access box and its versions. The
+// revocation marker takes precedence, otherwise tokens of the latest version are listed.
+func mergeSecretInfo(cur, next *secretInfo) *secretInfo {
+	switch {
+	case cur == nil || next.Revoked:
+		return next
+	case cur.Revoked:
+		return cur
+	}
+
+	latest, other := cur, next
+	if next.UpdatedAt > cur.UpdatedAt {
+		latest, other = next, cur
+	}
+	if latest.IssuedAt == "" {
+		latest.IssuedAt = other.IssuedAt
+	}
+
+	return latest
+}
+
+// RevokeSecret puts the revocation marker into the container of the access box
+// and removes the access box and its versions from NeoFS. Gates stop accepting
+// the secret when they notice the marker or the removal.
+func (a *Agent) RevokeSecret(ctx context.Context, options *RevokeSecretOptions) error {
+	var addr oid.Address
+	if err := addr.DecodeString(options.SecretAddress); err != nil {
+		return fmt.Errorf("failed to parse secret address: %w", err)
+	}
+
+	var idOwner user.ID
+	user.IDFromKey(&idOwner, options.NeoFSKey.PrivateKey.PublicKey)
+
+	versions, err := a.searchVersions(ctx, addr, idOwner)
+	if err != nil {
+		return fmt.Errorf("search access box versions: %w", err)
+	}
+
+	var (
+		removed         []oid.Address
+		expirationEpoch uint64
+	)
+
+	// the access box itself may be expired if it has been updated
+	header, err := a.neoFS.ReadObjectHeader(ctx, addr)
+	if err != nil && len(versions) == 0 {
+		return fmt.Errorf("read access box header: %w", err)
+	} else if err == nil {
+		fileName, exp, err := accessBoxAttributes(header)
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(fileName, accessBoxSuffix) {
+			return fmt.Errorf("object %s isn't an access box", addr)
+		}
+		removed, expirationEpoch = append(removed, addr), exp
+	}
+
+	for _, version := range versions {
+		header, err = a.neoFS.ReadObjectHeader(ctx, version)
+		if err != nil {
+			return fmt.Errorf("read access box version header: %w", err)
+		}
+		_, exp, err := accessBoxAttributes(header)
+		if err != nil {
+			return err
+		}
+		if exp > expirationEpoch {
+			expirationEpoch = exp
+		}
+		removed = append(removed, version)
+	}
+
+	prm := tokens.PrmObjectCreate{
+		Creator:   idOwner,
+		Container: addr.Container(),
+		Filename:  addr.Object().EncodeToString() + accessBoxSuffix + ".revoked",
+		// the marker is needed until the revoked access box expires
+		ExpirationEpoch: expirationEpoch,
+		Attributes:      [][2]string{{tokens.AttributeRevokedAccessBox, addr.Object().EncodeToString()}},
+	}
+
+	// gates reject the access box with the marker even if it isn't removed yet
+	if _, err = a.neoFS.CreateObject(ctx, prm); err != nil {
+		return fmt.Errorf("put revocation marker: %w", err)
+	}
+
+	for _, boxAddr := range removed {
+		if err = a.neoFS.DeleteObject(ctx, boxAddr); err != nil {
+			return fmt.Errorf("delete access box: %w", err)
+		}
+	}
+
+	a.log.Info("access box is revoked", zap.Stringer("address", addr))
+
+	return nil
+}
+
+// accessBoxAttributes returns the file name and the expiration epoch of the access box object.
+func accessBoxAttributes(header *object.Object) (string, uint64, error) {
+	var (
+		fileName        string
+		expirationEpoch uint64
+		err             error
+	)
+
+	for _, attr := range header.Attributes() {
+		switch attr.Key() {
+		case object.AttributeFileName:
+			fileName = attr.Value()
+		case expirationEpochAttribute:
+			if expirationEpoch, err = strconv.ParseUint(attr.Value(), 10, 64); err != nil {
+				return "", 0, fmt.Errorf("invalid expiration epoch of access box: %w", err)
+			}
+		}
+	}
+
+	return fileName, expirationEpoch, nil
+}
+
+func formatAccessKeyID(addr oid.Address) string {
+	return addr.Container().EncodeToString() + "0" + addr.Object().EncodeToString()
+}
+
 func buildEACLTable(eaclTable []byte) (*eacl.Table, error) {
 	table := eacl.NewTable()
 	if len(eaclTable) != 0 {
@@ -0,0 +1,396 @@
+package authmate
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testObject struct {
+	header  *object.Object
+	payload []byte
+}
+
+type testNeoFS struct {
+	objects map[oid.Address]*testObject
+	owners  map[cid.ID]user.ID
+	exp     uint64
+}
+
+func newTestNeoFS() *testNeoFS {
+	return &testNeoFS{objects: make(map[oid.Address]*testObject), owners: make(map[cid.ID]user.ID), exp: 10}
+}
+
+func (n *testNeoFS) CreateObject(_ context.Context, prm tokens.PrmObjectCreate) (oid.ID, error) {
+	attrs := [][2]string{
+		{object.AttributeFileName, prm.Filename},
+		{object.AttributeTimestamp, strconv.FormatInt(time.Now().Unix(), 10)},
+		{expirationEpochAttribute, strconv.FormatUint(prm.ExpirationEpoch, 10)},
+	}
+
+	header := object.New()
+	header.SetOwnerID(&prm.Creator)
+	for _, kv := range append(attrs, prm.Attributes...) {
+		attr := object.NewAttribute()
+		attr.SetKey(kv[0])
+		attr.SetValue(kv[1])
+		header.SetAttributes(append(header.Attributes(), *attr)...)
+	}
+
+	id := oidtest.ID()
+	var addr oid.Address
+	addr.SetContainer(prm.Container)
+	addr.SetObject(id)
+	n.objects[addr] = &testObject{header: header, payload: prm.Payload}
+
+	return id, nil
+}
+
+func (n *testNeoFS) ReadObjectPayload(_ context.Context, addr oid.Address) ([]byte, error) {
+	obj, ok := n.objects[addr]
+	if !ok {
+		return nil, apistatus.ObjectNotFound{}
+	}
+	return obj.payload, nil
+}
+
+func (n *testNeoFS) ContainerExists(context.Context, cid.ID) error {
+	return nil
+}
+
+func (n *testNeoFS) CreateContainer(context.Context, PrmContainerCreate) (cid.ID, error) {
+	return cidtest.ID(), nil
+}
+
+func (n *testNeoFS) TimeToEpoch(context.Context, time.Time) (uint64, uint64, error) {
+	return 1, n.exp, nil
+}
+
+func (n *testNeoFS) SearchObjects(_ context.Context, prm tokens.PrmObjectSearch) ([]oid.ID, error) {
+	var ids []oid.ID
+	for addr, obj := range n.objects {
+		if !addr.Container().Equals(prm.Container) || prm.Owner != nil && !obj.header.OwnerID().Equals(*prm.Owner) {
+			continue
+		}
+		if hasAttributes(obj.header, prm.Attributes) {
+			ids = append(ids, addr.Object())
+		}
+	}
+	return ids, nil
+}
+
+func (n *testNeoFS) ContainerOwner(_ context.Context, id cid.ID) (user.ID, error) {
+	owner, ok := n.owners[id]
+	if !ok {
+		return user.ID{}, apistatus.ContainerNotFound{}
+	}
+	return owner, nil
+}
+
+func hasAttributes(header *object.Object, attrs [][2]string) bool {
+	for _, kv := range attrs {
+		var found bool
+		for _, attr := range header.Attributes() {
+			if attr.Key() == kv[0] && attr.Value() == kv[1] {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *testNeoFS) ReadObjectHeader(_ context.Context, addr oid.Address) (*object.Object, error) {
+	obj, ok := n.objects[addr]
+	if !ok {
+		return nil, apistatus.ObjectNotFound{}
+	}
+	return obj.header, nil
+}
+
+func (n *testNeoFS) DeleteObject(_ context.Context, addr oid.Address) error {
+	delete(n.objects, addr)
+	return nil
+}
+
+func TestListAndRevokeSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS := newTestNeoFS()
+	agent := New(zap.NewNop(), neoFS)
+	cnrID := cidtest.ID()
+
+	buf := bytes.NewBuffer(nil)
+	err = agent.IssueSecret(ctx, buf, &IssueSecretOptions{
+		Container:         ContainerOptions{ID: cnrID},
+		NeoFSKey:          key,
+		GatesPublicKeys:   []*keys.PublicKey{gateKey.PublicKey()},
+		SkipSessionRules:  true,
+		Lifetime:          time.Hour,
+		ContainerPolicies: ContainerPolicies{"backup": "REP 3"},
+	})
+	require.NoError(t, err)
+
+	var issued issuingResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issued))
+
+	listSecrets := func() []secretInfo {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, agent.ListSecrets(ctx, buf, &ListSecretsOptions{ContainerID: cnrID, NeoFSKey: key}))
+
+		var secrets []secretInfo
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &secrets))
+		return secrets
+	}
+
+	secrets := listSecrets()
+	require.Len(t, secrets, 1)
+	require.Equal(t, issued.AccessKeyID, secrets[0].AccessKeyID)
+	require.EqualValues(t, 10, secrets[0].ExpirationEpoch)
+	require.Equal(t, []string{hex.EncodeToString(gateKey.PublicKey().Bytes())}, secrets[0].GatesPublicKeys)
+	require.Contains(t, secrets[0].ContainerPolicies, "backup")
+	require.False(t, secrets[0].Revoked)
+	require.NotEmpty(t, secrets[0].IssuedAt)
+
+	secretAddress := cnrID.EncodeToString() + "/" + issued.AccessKeyID[len(cnrID.EncodeToString())+1:]
+	require.NoError(t, agent.RevokeSecret(ctx, &RevokeSecretOptions{SecretAddress: secretAddress, NeoFSKey: key}))
+
+	var addr oid.Address
+	require.NoError(t, addr.DecodeString(secretAddress))
+	_, err = neoFS.ReadObjectPayload(ctx, addr)
+	require.Error(t, err)
+
+	secrets = listSecrets()
+	require.Len(t, secrets, 1)
+	require.Equal(t, issued.AccessKeyID, secrets[0].AccessKeyID)
+	require.EqualValues(t, 10, secrets[0].ExpirationEpoch)
+	require.True(t, secrets[0].Revoked)
+	require.NotEmpty(t, secrets[0].RevokedAt)
+	require.Empty(t, secrets[0].GatesPublicKeys)
+
+	// the removed access box can't be revoked again
+	require.Error(t, agent.RevokeSecret(ctx, &RevokeSecretOptions{SecretAddress: secretAddress, NeoFSKey: key}))
+}
+
+func TestUpdateSecret(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS := newTestNeoFS()
+	agent := New(zap.NewNop(), neoFS)
+	cnrID := cidtest.ID()
+
+	buf := bytes.NewBuffer(nil)
+	err = agent.IssueSecret(ctx, buf, &IssueSecretOptions{
+		Container:         ContainerOptions{ID: cnrID},
+		NeoFSKey:          key,
+		GatesPublicKeys:   []*keys.PublicKey{gateKey.PublicKey()},
+		SkipSessionRules:  true,
+		Lifetime:          time.Hour,
+		ContainerPolicies: ContainerPolicies{"backup": "REP 3"},
+		Restrictions: Restrictions{
+			Buckets:     []string{"photos"},
+			SourceCIDRs: []string{"10.0.0.0/8"},
+		},
+	})
+	require.NoError(t, err)
+
+	var issued issuingResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issued))
+	secretAddress := strings.Replace(issued.AccessKeyID, "0", "/", 1)
+
+	for _, exp := range []uint64{20, 30} {
+		neoFS.exp = exp
+
+		buf.Reset()
+		err = agent.UpdateSecret(ctx, buf, &UpdateSecretOptions{
+			SecretAddress:    secretAddress,
+			NeoFSKey:         key,
+			GatePrivateKey:   gateKey,
+			GatesPublicKeys:  []*keys.PublicKey{gateKey.PublicKey()},
+			SkipSessionRules: true,
+			Lifetime:         time.Hour,
+		})
+		require.NoError(t, err)
+
+		var updated issuingResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &updated))
+		require.Equal(t, issued.AccessKeyID, updated.AccessKeyID)
+		require.Equal(t, issued.SecretAccessKey, updated.SecretAccessKey)
+	}
+
+	// the access box and its latest version are kept
+	require.Len(t, neoFS.objects, 2)
+
+	var addr oid.Address
+	require.NoError(t, addr.DecodeString(secretAddress))
+
+	box, err := tokens.New(neoFS, gateKey, cache.DefaultAccessBoxConfig(zap.NewNop())).GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.Equal(t, issued.SecretAccessKey, box.Gate.AccessKey)
+	require.False(t, box.Gate.BearerToken.InvalidAt(29))
+	require.Len(t, box.Policies, 1)
+	require.Equal(t, "backup", box.Policies[0].LocationConstraint)
+	require.Equal(t, []string{"photos"}, box.Restrictions.Buckets)
+	require.Len(t, box.Restrictions.SourceNetworks, 1)
+	require.Equal(t, "10.0.0.0/8", box.Restrictions.SourceNetworks[0].String())
+
+	buf.Reset()
+	require.NoError(t, agent.ListSecrets(ctx, buf, &ListSecretsOptions{ContainerID: cnrID, NeoFSKey: key}))
+
+	var secrets []secretInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &secrets))
+	require.Len(t, secrets, 1)
+	require.Equal(t, issued.AccessKeyID, secrets[0].AccessKeyID)
+	require.EqualValues(t, 30, secrets[0].ExpirationEpoch)
+	require.NotEmpty(t, secrets[0].IssuedAt)
+	require.NotEmpty(t, secrets[0].UpdatedAt)
+	require.Equal(t, []string{"photos"}, secrets[0].Restrictions.Buckets)
+
+	// the access box and all its versions are removed on revocation
+	require.NoError(t, agent.RevokeSecret(ctx, &RevokeSecretOptions{SecretAddress: secretAddress, NeoFSKey: key}))
+	require.Len(t, neoFS.objects, 1)
+	for _, obj := range neoFS.objects {
+		require.Equal(t, "30", attributeValue(obj.header, expirationEpochAttribute))
+	}
+}
+
+func attributeValue(header *object.Object, key string) string {
+	for _, attr := range header.Attributes() {
+		if attr.Key() == key {
+			return attr.Value()
+		}
+	}
+	return ""
+}
+
+func TestIssueSecretWithAccessKeyID(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS := newTestNeoFS()
+	agent := New(zap.NewNop(), neoFS)
+	registryID := cidtest.ID()
+
+	var owner user.ID
+	user.IDFromKey(&owner, key.PrivateKey.PublicKey)
+	neoFS.owners[registryID] = owner
+
+	issueWithKey := func(key *keys.PrivateKey, options *IssueSecretOptions) (*issuingResult, error) {
+		options.Container = ContainerOptions{ID: cidtest.ID()}
+		options.NeoFSKey = key
+		options.GatesPublicKeys = []*keys.PublicKey{gateKey.PublicKey()}
+		options.SkipSessionRules = true
+		options.Lifetime = time.Hour
+
+		buf := bytes.NewBuffer(nil)
+		if err := agent.IssueSecret(ctx, buf, options); err != nil {
+			return nil, err
+		}
+
+		var issued issuingResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &issued))
+		return &issued, nil
+	}
+	issue := func(options *IssueSecretOptions) (*issuingResult, error) {
+		return issueWithKey(key, options)
+	}
+
+	otherKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	// entries of other users are not trusted, so only the registry owner can register access key ids
+	_, err = issueWithKey(otherKey, &IssueSecretOptions{AccessKeyID: "CI_PIPELINE_KEY_01", RegistryContainerID: registryID})
+	require.Error(t, err)
+
+	var other user.ID
+	user.IDFromKey(&other, otherKey.PrivateKey.PublicKey)
+	_, err = neoFS.CreateObject(ctx, tokens.PrmObjectCreate{
+		Creator:   other,
+		Container: registryID,
+		Attributes: [][2]string{
+			{tokens.AttributeAccessKeyID, "CI_PIPELINE_KEY_01"},
+			{tokens.AttributeAccessBox, oidtest.Address().EncodeToString()},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = issue(&IssueSecretOptions{AccessKeyID: "CI_PIPELINE_KEY_01"})
+	require.Error(t, err, "registry container is required")
+
+	_, err = issue(&IssueSecretOptions{AccessKeyID: "short", RegistryContainerID: registryID})
+	require.Error(t, err)
+
+	issued, err := issue(&IssueSecretOptions{AccessKeyID: "CI_PIPELINE_KEY_01", RegistryContainerID: registryID})
+	require.NoError(t, err)
+	require.Equal(t, "CI_PIPELINE_KEY_01", issued.AccessKeyID)
+
+	registry := tokens.NewRegistry(neoFS, registryID, cache.DefaultObjectsNameConfig(zap.NewNop()))
+	addr, err := registry.Resolve(ctx, issued.AccessKeyID)
+	require.NoError(t, err)
+	require.Equal(t, issued.ContainerID, addr.Container().EncodeToString())
+
+	box, err := tokens.New(neoFS, gateKey, cache.DefaultAccessBoxConfig(zap.NewNop())).GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.Equal(t, issued.SecretAccessKey, box.Gate.AccessKey)
+
+	_, err = issue(&IssueSecretOptions{AccessKeyID: "CI_PIPELINE_KEY_01", RegistryContainerID: registryID})
+	require.Error(t, err, "access key id is in use")
+
+	// the access key id of the revoked secret can be used again
+	require.NoError(t, agent.RevokeSecret(ctx, &RevokeSecretOptions{SecretAddress: addr.EncodeToString(), NeoFSKey: key}))
+
+	reissued, err := issue(&IssueSecretOptions{AccessKeyID: "CI_PIPELINE_KEY_01", RegistryContainerID: registryID})
+	require.NoError(t, err)
+
+	ids, err := neoFS.SearchObjects(ctx, tokens.PrmObjectSearch{
+		Container:  registryID,
+		Owner:      &owner,
+		Attributes: [][2]string{{tokens.AttributeAccessKeyID, "CI_PIPELINE_KEY_01"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+
+	addr, err = tokens.NewRegistry(neoFS, registryID, cache.DefaultObjectsNameConfig(zap.NewNop())).Resolve(ctx, reissued.AccessKeyID)
+	require.NoError(t, err)
+	require.Equal(t, reissued.ContainerID, addr.Container().EncodeToString())
+
+	random, err := issue(&IssueSecretOptions{RandomAccessKeyID: true, RegistryContainerID: registryID})
+	require.NoError(t, err)
+	require.Len(t, random.AccessKeyID, generatedAccessKeyIDLength)
+	require.Equal(t, strings.ToUpper(random.AccessKeyID), random.AccessKeyID)
+	require.True(t, tokens.IsAccessKeyAlias(random.AccessKeyID))
+}
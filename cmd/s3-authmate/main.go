@@ -19,7 +19,9 @@ import (
 	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/authmate"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/wallet"
@@ -65,6 +67,13 @@ var (
 	containerPolicies        string
 	awcCliCredFile           string
 	timeoutFlag              time.Duration
+	randomAccessKeyIDFlag    bool
+	registryContainerIDFlag  string
+	allowedBucketsFlag       cli.StringSlice
+	allowedPrefixesFlag      cli.StringSlice
+	allowedOperationsFlag    cli.StringSlice
+	allowedSourceCIDRsFlag   cli.StringSlice
+	maxObjectSizeFlag        uint64
 )
 
 const (
@@ -156,7 +165,10 @@ func appFlags() []cli.Flag {
 func appCommands() []*cli.Command {
 	return []*cli.Command{
 		issueSecret(),
+		updateSecret(),
 		obtainSecret(),
+		listSecrets(),
+		revokeSecret(),
 		generatePresignedURL(),
 	}
 }
@@ -245,6 +257,54 @@ It will be ceil rounded to the nearest amount of epoch.`,
 				Required:    false,
 				Destination: &awcCliCredFile,
 			},
+			&cli.StringFlag{
+				Name:        "access-key-id",
+				Usage:       "access key id to register instead of using the access box address (16-128 letters, digits and underscores)",
+				Required:    false,
+				Destination: &accessKeyIDFlag,
+			},
+			&cli.BoolFlag{
+				Name:        "random-access-key-id",
+				Usage:       "register a random access key id instead of using the access box address",
+				Required:    false,
+				Destination: &randomAccessKeyIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "registry-container-id",
+				Usage:       "container of the access key registry to register the access key id in",
+				Required:    false,
+				Destination: &registryContainerIDFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "allowed-bucket",
+				Usage:       "name of the bucket the gate allows access to (use flags repeatedly for multiple buckets)",
+				Required:    false,
+				Destination: &allowedBucketsFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "allowed-prefix",
+				Usage:       "object key prefix the gate allows access to (use flags repeatedly for multiple prefixes)",
+				Required:    false,
+				Destination: &allowedPrefixesFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "allowed-operation",
+				Usage:       "S3 operation the gate allows, e.g. GetObject (use flags repeatedly for multiple operations)",
+				Required:    false,
+				Destination: &allowedOperationsFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "allowed-source-cidr",
+				Usage:       "network the gate accepts requests from, e.g. 10.0.0.0/8 (use flags repeatedly for multiple networks)",
+				Required:    false,
+				Destination: &allowedSourceCIDRsFlag,
+			},
+			&cli.Uint64Flag{
+				Name:        "max-object-size",
+				Usage:       "max size of the object payload in bytes the gate accepts in uploads, copies and completed multipart uploads",
+				Required:    false,
+				Destination: &maxObjectSizeFlag,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			ctx, log := prepare()
@@ -300,6 +360,13 @@ It will be ceil rounded to the nearest amount of epoch.`,
 				return cli.Exit(fmt.Sprintf("couldn't parse 'session-tokens' flag: %s", err.Error()), 8)
 			}
 
+			var registryContainerID cid.ID
+			if len(registryContainerIDFlag) > 0 {
+				if err = registryContainerID.DecodeString(registryContainerIDFlag); err != nil {
+					return cli.Exit(fmt.Sprintf("failed to parse registry container id: %s", err), 3)
+				}
+			}
+
 			issueSecretOptions := &authmate.IssueSecretOptions{
 				Container: authmate.ContainerOptions{
 					ID:              containerID,
@@ -314,6 +381,16 @@ It will be ceil rounded to the nearest amount of epoch.`,
 				ContainerPolicies:     policies,
 				Lifetime:              lifetimeFlag,
 				AwsCliCredentialsFile: awcCliCredFile,
+				AccessKeyID:           accessKeyIDFlag,
+				RandomAccessKeyID:     randomAccessKeyIDFlag,
+				RegistryContainerID:   registryContainerID,
+				Restrictions: authmate.Restrictions{
+					Buckets:       allowedBucketsFlag.Value(),
+					Prefixes:      allowedPrefixesFlag.Value(),
+					Operations:    allowedOperationsFlag.Value(),
+					SourceCIDRs:   allowedSourceCIDRsFlag.Value(),
+					MaxObjectSize: maxObjectSizeFlag,
+				},
 			}
 
 			var tcancel context.CancelFunc
@@ -542,6 +619,12 @@ func obtainSecret() *cli.Command {
 				Required:    true,
 				Destination: &accessKeyIDFlag,
 			},
+			&cli.StringFlag{
+				Name:        "registry-container-id",
+				Usage:       "container of the access key registry to resolve registered access key ids",
+				Required:    false,
+				Destination: &registryContainerIDFlag,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			ctx, log := prepare()
@@ -570,25 +653,364 @@ func obtainSecret() *cli.Command {
 				return cli.Exit(fmt.Sprintf("failed to create owner's private key: %s", err), 4)
 			}
 
-			secretAddress := strings.Replace(accessKeyIDFlag, "0", "/", 1)
+			var tcancel context.CancelFunc
+			ctx, tcancel = context.WithTimeout(ctx, timeoutFlag)
+			defer tcancel()
+
+			secretAddress, err := resolveSecretAddress(ctx, neoFS, accessKeyIDFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to resolve access key id: %s", err), 6)
+			}
 
 			obtainSecretOptions := &authmate.ObtainSecretOptions{
 				SecretAddress:  secretAddress,
 				GatePrivateKey: gateCreds,
 			}
 
+			if err = agent.ObtainSecret(ctx, os.Stdout, obtainSecretOptions); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to obtain secret: %s", err), 5)
+			}
+
+			return nil
+		},
+	}
+	return command
+}
+
+func listSecrets() *cli.Command {
+	return &cli.Command{
+		Name:  "list-secrets",
+		Usage: "List secrets issued by the wallet owner in the auth container",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "wallet",
+				Value:       "",
+				Usage:       "path to the wallet",
+				Required:    true,
+				Destination: &walletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &accountAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "peer",
+				Value:       "",
+				Usage:       "address of neofs peer to connect to",
+				Required:    true,
+				Destination: &peerAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "container-id",
+				Usage:       "auth container id the secrets are put into",
+				Required:    true,
+				Destination: &containerIDFlag,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, log := prepare()
+
+			password := wallet.GetPassword(viper.GetViper(), envWalletPassphrase)
+			key, err := wallet.GetKeyFromPath(walletPathFlag, accountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load neofs private key: %s", err), 1)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			neoFS, err := createNeoFS(ctx, log, &key.PrivateKey, peerAddressFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to create NeoFS component: %s", err), 2)
+			}
+
+			agent := authmate.New(log, neoFS)
+
+			var containerID cid.ID
+			if err = containerID.DecodeString(containerIDFlag); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to parse auth container id: %s", err), 3)
+			}
+
+			listSecretsOptions := &authmate.ListSecretsOptions{
+				ContainerID: containerID,
+				NeoFSKey:    key,
+			}
+
 			var tcancel context.CancelFunc
 			ctx, tcancel = context.WithTimeout(ctx, timeoutFlag)
 			defer tcancel()
 
-			if err = agent.ObtainSecret(ctx, os.Stdout, obtainSecretOptions); err != nil {
-				return cli.Exit(fmt.Sprintf("failed to obtain secret: %s", err), 5)
+			if err = agent.ListSecrets(ctx, os.Stdout, listSecretsOptions); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to list secrets: %s", err), 4)
 			}
 
 			return nil
 		},
 	}
-	return command
+}
+
+func revokeSecret() *cli.Command {
+	return &cli.Command{
+		Name:  "revoke-secret",
+		Usage: "Revoke a secret issued by the wallet owner",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "wallet",
+				Value:       "",
+				Usage:       "path to the wallet",
+				Required:    true,
+				Destination: &walletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &accountAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "peer",
+				Value:       "",
+				Usage:       "address of neofs peer to connect to",
+				Required:    true,
+				Destination: &peerAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "access-key-id",
+				Usage:       "access key id of the secret to revoke",
+				Required:    true,
+				Destination: &accessKeyIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "registry-container-id",
+				Usage:       "container of the access key registry to resolve registered access key ids",
+				Required:    false,
+				Destination: &registryContainerIDFlag,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, log := prepare()
+
+			password := wallet.GetPassword(viper.GetViper(), envWalletPassphrase)
+			key, err := wallet.GetKeyFromPath(walletPathFlag, accountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load neofs private key: %s", err), 1)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			neoFS, err := createNeoFS(ctx, log, &key.PrivateKey, peerAddressFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to create NeoFS component: %s", err), 2)
+			}
+
+			agent := authmate.New(log, neoFS)
+
+			var tcancel context.CancelFunc
+			ctx, tcancel = context.WithTimeout(ctx, timeoutFlag)
+			defer tcancel()
+
+			secretAddress, err := resolveSecretAddress(ctx, neoFS, accessKeyIDFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to resolve access key id: %s", err), 4)
+			}
+
+			revokeSecretOptions := &authmate.RevokeSecretOptions{
+				SecretAddress: secretAddress,
+				NeoFSKey:      key,
+			}
+
+			if err = agent.RevokeSecret(ctx, revokeSecretOptions); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to revoke secret: %s", err), 3)
+			}
+
+			return nil
+		},
+	}
+}
+
+func updateSecret() *cli.Command {
+	return &cli.Command{
+		Name:  "update-secret",
+		Usage: "Update tokens of a secret keeping its access key id and secret access key",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "wallet",
+				Value:       "",
+				Usage:       "path to the wallet",
+				Required:    true,
+				Destination: &walletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &accountAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "peer",
+				Value:       "",
+				Usage:       "address of neofs peer to connect to",
+				Required:    true,
+				Destination: &peerAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "gate-wallet",
+				Value:       "",
+				Usage:       "path to the wallet of a gate the secret is issued for",
+				Required:    true,
+				Destination: &gateWalletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "gate-address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &gateAccountAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "access-key-id",
+				Usage:       "access key id of the secret to update",
+				Required:    true,
+				Destination: &accessKeyIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "registry-container-id",
+				Usage:       "container of the access key registry to resolve registered access key ids",
+				Required:    false,
+				Destination: &registryContainerIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "bearer-rules",
+				Usage:       "rules for bearer token (filepath or a plain json string are allowed)",
+				Required:    false,
+				Destination: &eaclRulesFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "gate-public-key",
+				Usage:       "public 256r1 key of a gate (use flags repeatedly for multiple gates)",
+				Required:    true,
+				Destination: &gatesPublicKeysFlag,
+			},
+			&cli.StringFlag{
+				Name:        "session-tokens",
+				Usage:       "create session tokens with rules, if the rules are set as 'none', no session tokens will be created",
+				Required:    false,
+				Destination: &sessionTokenFlag,
+				Value:       "",
+			},
+			&cli.DurationFlag{
+				Name: "lifetime",
+				Usage: `Lifetime of tokens. For example 50h30m (note: max time unit is an hour so to set a day you should use 24h). 
+It will be ceil rounded to the nearest amount of epoch.`,
+				Required:    false,
+				Destination: &lifetimeFlag,
+				Value:       defaultLifetime,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, log := prepare()
+
+			password := wallet.GetPassword(viper.GetViper(), envWalletPassphrase)
+			key, err := wallet.GetKeyFromPath(walletPathFlag, accountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load neofs private key: %s", err), 1)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			neoFS, err := createNeoFS(ctx, log, &key.PrivateKey, peerAddressFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to create NeoFS component: %s", err), 2)
+			}
+
+			agent := authmate.New(log, neoFS)
+
+			password = wallet.GetPassword(viper.GetViper(), envWalletGatePassphrase)
+			gateCreds, err := wallet.GetKeyFromPath(gateWalletPathFlag, gateAccountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to create owner's private key: %s", err), 3)
+			}
+
+			var gatesPublicKeys []*keys.PublicKey
+			for _, key := range gatesPublicKeysFlag.Value() {
+				gpk, err := keys.NewPublicKeyFromString(key)
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("failed to load gate's public key: %s", err), 4)
+				}
+				gatesPublicKeys = append(gatesPublicKeys, gpk)
+			}
+
+			if lifetimeFlag <= 0 {
+				return cli.Exit(fmt.Sprintf("lifetime must be greater 0, current value: %d", lifetimeFlag), 5)
+			}
+
+			bearerRules, err := getJSONRules(eaclRulesFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("couldn't parse 'bearer-rules' flag: %s", err.Error()), 6)
+			}
+
+			sessionRules, skipSessionRules, err := getSessionRules(sessionTokenFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("couldn't parse 'session-tokens' flag: %s", err.Error()), 7)
+			}
+
+			var tcancel context.CancelFunc
+			ctx, tcancel = context.WithTimeout(ctx, timeoutFlag)
+			defer tcancel()
+
+			secretAddress, err := resolveSecretAddress(ctx, neoFS, accessKeyIDFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to resolve access key id: %s", err), 8)
+			}
+
+			updateSecretOptions := &authmate.UpdateSecretOptions{
+				SecretAddress:     secretAddress,
+				AccessKeyID:       accessKeyIDFlag,
+				NeoFSKey:          key,
+				GatePrivateKey:    gateCreds,
+				GatesPublicKeys:   gatesPublicKeys,
+				EACLRules:         bearerRules,
+				SessionTokenRules: sessionRules,
+				SkipSessionRules:  skipSessionRules,
+				Lifetime:          lifetimeFlag,
+			}
+
+			if err = agent.UpdateSecret(ctx, os.Stdout, updateSecretOptions); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to update secret: %s", err), 9)
+			}
+
+			return nil
+		},
+	}
+}
+
+// resolveSecretAddress returns the access box address of the access key ID.
+// Registered access key IDs are resolved if the registry container is set.
+func resolveSecretAddress(ctx context.Context, neoFS authmate.NeoFS, accessKeyID string) (string, error) {
+	if registryContainerIDFlag == "" || !tokens.IsAccessKeyAlias(accessKeyID) {
+		return strings.Replace(accessKeyID, "0", "/", 1), nil
+	}
+
+	var registryContainerID cid.ID
+	if err := registryContainerID.DecodeString(registryContainerIDFlag); err != nil {
+		return "", fmt.Errorf("failed to parse registry container id: %w", err)
+	}
+
+	addr, err := tokens.
+		NewRegistry(neoFS, registryContainerID, cache.DefaultObjectsNameConfig(zap.NewNop())).
+		Resolve(ctx, accessKeyID)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.EncodeToString(), nil
 }
 
 func createNeoFS(ctx context.Context, log *zap.Logger, key *ecdsa.PrivateKey, peerAddress string) (authmate.NeoFS, error) {
@@ -2,26 +2,39 @@ package main
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/accesslog"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/api/events"
 	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"github.com/nspcc-dev/neofs-s3-gw/api/janitor"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+	"github.com/nspcc-dev/neofs-s3-gw/api/lifecycle"
 	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
+	"github.com/nspcc-dev/neofs-s3-gw/api/replication"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/wallet"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -36,6 +49,13 @@ type (
 		obj layer.Client
 		api api.Handler
 
+		lifecycle     *lifecycle.Worker
+		janitor       *janitor.Worker
+		replication   *replication.Worker
+		notifications *notifications.Controller
+		accessLog     *accesslog.Writer
+		usage         *layer.UsageTracker
+
 		metrics GateMetricsCollector
 
 		maxClients api.MaxClients
@@ -63,6 +83,13 @@ func newApp(ctx context.Context, l *zap.Logger, v *viper.Viper) *App {
 		ctr    auth.Center
 		obj    layer.Client
 		nc     *notifications.Controller
+		lw     *lifecycle.Worker
+		mj     *janitor.Worker
+		rw     *replication.Worker
+		rp     handler.Replicator
+		ls     handler.Listener
+		al     *accesslog.Writer
+		ut     *layer.UsageTracker
 
 		gateMetrics GateMetricsCollector
 
@@ -163,6 +190,15 @@ func newApp(ctx context.Context, l *zap.Logger, v *viper.Viper) *App {
 	}
 	l.Info("init tree service", zap.String("endpoint", treeServiceEndpoint))
 
+	// events of background processes are routed to notifications like events of requests
+	bus := events.NewBus()
+
+	if v.GetBool(cfgUsageEnabled) {
+		// usage of buckets without quotas is needed for metrics and the admin service only
+		trackAll := v.GetBool(cfgPrometheusEnabled) || v.GetBool(cfgUsageAdminEnabled)
+		ut = layer.NewUsageTracker(getLifetime(v, l, cfgUsageRefreshInterval, layer.DefaultUsageRefreshInterval), trackAll)
+	}
+
 	layerCfg := &layer.Config{
 		Caches: getCacheOptions(v, l),
 		AnonKey: layer.AnonymousKey{
@@ -170,13 +206,16 @@ func newApp(ctx context.Context, l *zap.Logger, v *viper.Viper) *App {
 		},
 		Resolver:    bucketResolver,
 		TreeService: treeService,
+		KeyRing:     getKeyRing(v, l, key),
+		Events:      bus,
+		Usage:       ut,
+		Read:        getReadOptions(v, l),
 	}
 
 	// prepare object layer
 	obj = layer.NewLayer(l, neofs.NewNeoFS(conns), layerCfg)
 
-	if v.GetBool(cfgEnableNATS) {
-		nopts := getNotificationsOptions(v, l)
+	if nopts := getNotificationsOptions(v, l); nopts.NATS != nil || len(nopts.Targets) != 0 {
 		nc, err = notifications.NewController(nopts, l)
 		if err != nil {
 			l.Fatal("failed to enable notifications", zap.Error(err))
@@ -188,15 +227,69 @@ func newApp(ctx context.Context, l *zap.Logger, v *viper.Viper) *App {
 	}
 
 	// prepare auth center
-	ctr = auth.New(neofs.NewAuthmateNeoFS(conns), key, getAccessBoxCacheConfig(v, l))
+	registry := getAccessKeyRegistry(v, l, conns)
+	ctr = auth.New(neofs.NewAuthmateNeoFS(conns), key, getAccessBoxCacheConfig(v, l), registry, v.GetBool(cfgSignatureV2Enabled))
+
+	if v.GetBool(cfgLifecycleEnabled) {
+		creds := tokens.New(neofs.NewAuthmateNeoFS(conns), key, getAccessBoxCacheConfig(v, l))
+		lopts := getLifecycleOptions(v, l)
+		lopts.Events = bus
+		lopts.Registry = registry
+		if lw, err = lifecycle.NewWorker(l, obj, creds, lopts); err != nil {
+			l.Fatal("could not initialize lifecycle worker", zap.Error(err))
+		}
+	}
+
+	if v.GetBool(cfgMultipartJanitorEnabled) {
+		creds := tokens.New(neofs.NewAuthmateNeoFS(conns), key, getAccessBoxCacheConfig(v, l))
+		mopts := getMultipartJanitorOptions(v, l)
+		mopts.Registry = registry
+		if mj, err = janitor.NewWorker(l, obj, creds, mopts); err != nil {
+			l.Fatal("could not initialize multipart janitor", zap.Error(err))
+		}
+	}
+
+	if v.GetBool(cfgAccessLoggingEnabled) {
+		creds := tokens.New(neofs.NewAuthmateNeoFS(conns), key, getAccessBoxCacheConfig(v, l))
+		aopts := getAccessLoggingOptions(v, l)
+		aopts.Registry = registry
+		if al, err = accesslog.NewWriter(l, obj, creds, aopts); err != nil {
+			l.Fatal("could not initialize access log writer", zap.Error(err))
+		}
+	}
+
+	if v.GetBool(cfgReplicationEnabled) {
+		ropts := getReplicationOptions(v, l)
+		ropts.Events = bus
+		if rw, err = replication.NewWorker(l, obj, ropts); err != nil {
+			l.Fatal("could not initialize replication worker", zap.Error(err))
+		}
+		rp = rw
+	}
+
+	if v.GetBool(cfgNotificationsListenEnabled) {
+		ls = notifications.NewBroadcaster(l, v.GetInt(cfgNotificationsListenBufferSize))
+	}
+
 	handlerOptions := getHandlerOptions(v, l)
+	handlerOptions.NotificatorEnabled = nc != nil
+	handlerOptions.Events = bus
 
-	if caller, err = handler.New(l, obj, nc, handlerOptions); err != nil {
+	if caller, err = handler.New(l, obj, nc, rp, ls, handlerOptions); err != nil {
 		l.Fatal("could not initialize API handler", zap.Error(err))
 	}
 
 	if v.GetBool(cfgPrometheusEnabled) {
 		gateMetrics = newGateMetrics(neofs.NewPoolStatistic(conns))
+		if nc != nil && nc.Outbox() != nil {
+			registerOutboxMetrics(nc.Outbox())
+		}
+		if ut != nil {
+			registerUsageMetrics(ut)
+		}
+		if mj != nil {
+			registerJanitorMetrics(mj)
+		}
 	}
 
 	return &App{
@@ -207,6 +300,13 @@ func newApp(ctx context.Context, l *zap.Logger, v *viper.Viper) *App {
 		tls: tls,
 		api: caller,
 
+		lifecycle:     lw,
+		janitor:       mj,
+		replication:   rw,
+		notifications: nc,
+		accessLog:     al,
+		usage:         ut,
+
 		metrics: gateMetrics,
 
 		webDone: make(chan struct{}, 1),
@@ -262,13 +362,20 @@ func (a *App) Server(ctx context.Context) {
 
 	pprof := NewPprofService(a.cfg, a.log)
 	prometheus := NewPrometheusService(a.cfg, a.log)
+	outboxAdmin := NewOutboxAdminService(a.cfg, a.log, a.notifications)
+	usageAdmin := NewUsageAdminService(a.cfg, a.log, a.usage)
 
 	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
 	// Attach S3 API:
 	domains := fetchDomains(a.cfg)
+	websiteDomains := a.cfg.GetStringSlice(cfgWebsiteDomains)
 	a.log.Info("fetch domains, prepare to use API",
-		zap.Strings("domains", domains))
-	api.Attach(router, domains, a.maxClients, a.api, a.ctr, a.log)
+		zap.Strings("domains", domains), zap.Strings("website_domains", websiteDomains))
+	var accessLogger api.AccessLogger
+	if a.accessLog != nil {
+		accessLogger = a.accessLog
+	}
+	api.Attach(router, domains, websiteDomains, fetchTrustedProxies(a.cfg, a.log), a.maxClients, a.api, a.ctr, accessLogger, a.log)
 
 	// Use mux.Router as http.Handler
 	srv.Handler = router
@@ -276,6 +383,37 @@ func (a *App) Server(ctx context.Context) {
 
 	go pprof.Start()
 	go prometheus.Start()
+	go outboxAdmin.Start()
+	go usageAdmin.Start()
+
+	if a.lifecycle != nil {
+		go a.lifecycle.Run(ctx)
+	}
+
+	if a.janitor != nil {
+		go a.janitor.Run(ctx)
+	}
+
+	if a.replication != nil {
+		go a.replication.Run(ctx)
+	}
+
+	if a.notifications != nil {
+		go a.notifications.Dispatch(ctx)
+	}
+
+	// access log writer is stopped after the server to keep records of the requests
+	// served during shutdown
+	accessLogCtx, stopAccessLog := context.WithCancel(context.Background())
+	accessLogDone := make(chan struct{})
+	if a.accessLog != nil {
+		go func() {
+			a.accessLog.Run(accessLogCtx)
+			close(accessLogDone)
+		}()
+	} else {
+		close(accessLogDone)
+	}
 
 	go func() {
 		a.log.Info("starting server",
@@ -308,24 +446,73 @@ func (a *App) Server(ctx context.Context) {
 		zap.Error(srv.Shutdown(ctx)))
 	pprof.ShutDown(ctx)
 	prometheus.ShutDown(ctx)
+	outboxAdmin.ShutDown(ctx)
+	usageAdmin.ShutDown(ctx)
+
+	stopAccessLog()
+	<-accessLogDone
 
 	close(a.webDone)
 }
 
 func getNotificationsOptions(v *viper.Viper, l *zap.Logger) *notifications.Options {
 	cfg := notifications.Options{}
-	cfg.URL = v.GetString(cfgNATSEndpoint)
-	cfg.Timeout = v.GetDuration(cfgNATSTimeout)
-	if cfg.Timeout <= 0 {
-		l.Error("invalid lifetime, using default value (in seconds)",
-			zap.String("parameter", cfgNATSTimeout),
-			zap.Duration("value in config", cfg.Timeout),
-			zap.Duration("default", notifications.DefaultTimeout))
-		cfg.Timeout = notifications.DefaultTimeout
-	}
-	cfg.TLSCertFilepath = v.GetString(cfgNATSTLSCertFile)
-	cfg.TLSAuthPrivateKeyFilePath = v.GetString(cfgNATSAuthPrivateKeyFile)
-	cfg.RootCAFiles = v.GetStringSlice(cfgNATSRootCAFiles)
+
+	if v.GetBool(cfgEnableNATS) {
+		cfg.NATS = &notifications.NATSOptions{
+			URL:                       v.GetString(cfgNATSEndpoint),
+			Timeout:                   v.GetDuration(cfgNATSTimeout),
+			TLSCertFilepath:           v.GetString(cfgNATSTLSCertFile),
+			TLSAuthPrivateKeyFilePath: v.GetString(cfgNATSAuthPrivateKeyFile),
+			RootCAFiles:               v.GetStringSlice(cfgNATSRootCAFiles),
+		}
+		if cfg.NATS.Timeout <= 0 {
+			l.Error("invalid lifetime, using default value (in seconds)",
+				zap.String("parameter", cfgNATSTimeout),
+				zap.Duration("value in config", cfg.NATS.Timeout),
+				zap.Duration("default", notifications.DefaultTimeout))
+			cfg.NATS.Timeout = notifications.DefaultTimeout
+		}
+	}
+
+	for i := 0; ; i++ {
+		prefix := cfgNotificationsTargets + "." + strconv.Itoa(i) + "."
+		name := v.GetString(prefix + "name")
+		if name == "" {
+			break
+		}
+
+		target := &notifications.TargetOptions{
+			Name:                      name,
+			Type:                      v.GetString(prefix + "type"),
+			Endpoint:                  v.GetString(prefix + "endpoint"),
+			Brokers:                   v.GetStringSlice(prefix + "brokers"),
+			Topic:                     v.GetString(prefix + "topic"),
+			Exchange:                  v.GetString(prefix + "exchange"),
+			Secret:                    v.GetString(prefix + "secret"),
+			AuthToken:                 v.GetString(prefix + "auth_token"),
+			Retries:                   v.GetInt(prefix + "retries"),
+			Timeout:                   v.GetDuration(prefix + "timeout"),
+			TLS:                       v.GetBool(prefix + "tls"),
+			User:                      v.GetString(prefix + "user"),
+			Password:                  v.GetString(prefix + "password"),
+			SASLMechanism:             v.GetString(prefix + "sasl_mechanism"),
+			TLSCertFilepath:           v.GetString(prefix + "cert_file"),
+			TLSAuthPrivateKeyFilePath: v.GetString(prefix + "key_file"),
+			RootCAFiles:               v.GetStringSlice(prefix + "root_ca"),
+		}
+		cfg.Targets = append(cfg.Targets, target)
+
+		l.Info("notification target", zap.String("arn", notifications.TargetARN(target.Name, target.Type)))
+	}
+
+	if v.GetBool(cfgNotificationsOutboxEnabled) {
+		cfg.Outbox = &notifications.OutboxOptions{
+			Path:       v.GetString(cfgNotificationsOutboxPath),
+			MinBackoff: getLifetime(v, l, cfgNotificationsOutboxMinBackoff, notifications.DefaultOutboxMinBackoff),
+			MaxBackoff: getLifetime(v, l, cfgNotificationsOutboxMaxBackoff, notifications.DefaultOutboxMaxBackoff),
+		}
+	}
 
 	return &cfg
 }
@@ -381,11 +568,152 @@ func getSize(v *viper.Viper, l *zap.Logger, cfgEntry string, defaultValue int) i
 	return defaultValue
 }
 
+func getLifecycleOptions(v *viper.Viper, l *zap.Logger) *lifecycle.Config {
+	return &lifecycle.Config{
+		Interval:   getLifetime(v, l, cfgLifecycleInterval, lifecycle.DefaultInterval),
+		AccessKeys: v.GetStringSlice(cfgLifecycleAccessKeys),
+	}
+}
+
+// getMultipartJanitorOptions loads multipart janitor parameters, TTL of uploads
+// is overridden for buckets by the indexed entries with bucket name and TTL.
+func getMultipartJanitorOptions(v *viper.Viper, l *zap.Logger) *janitor.Config {
+	bucketTTL := make(map[string]time.Duration)
+	for i := 0; ; i++ {
+		prefix := cfgMultipartJanitorBuckets + "." + strconv.Itoa(i) + "."
+		name := v.GetString(prefix + "name")
+		if name == "" {
+			break
+		}
+
+		ttl := v.GetDuration(prefix + "ttl")
+		if ttl < 0 {
+			l.Error("invalid multipart upload ttl of the bucket, uploads of the bucket aren't aborted",
+				zap.String("bucket", name), zap.Duration("value in config", ttl))
+			ttl = 0
+		}
+		bucketTTL[name] = ttl
+	}
+
+	return &janitor.Config{
+		Interval:   getLifetime(v, l, cfgMultipartJanitorInterval, janitor.DefaultInterval),
+		TTL:        getLifetime(v, l, cfgMultipartJanitorTTL, janitor.DefaultTTL),
+		BucketTTL:  bucketTTL,
+		AccessKeys: v.GetStringSlice(cfgMultipartJanitorAccessKeys),
+	}
+}
+
+func getReadOptions(v *viper.Viper, l *zap.Logger) *layer.ReadConfig {
+	return &layer.ReadConfig{
+		Concurrency: getSize(v, l, cfgReadConcurrency, 1),
+		ChunkSize:   uint64(getSize(v, l, cfgReadChunkSize, layer.DefaultReadChunkSize)),
+		BufferSize:  uint64(getSize(v, l, cfgReadBufferSize, layer.DefaultReadBufferSize)),
+	}
+}
+
+func getAccessLoggingOptions(v *viper.Viper, l *zap.Logger) *accesslog.Config {
+	return &accesslog.Config{
+		FlushInterval:   getLifetime(v, l, cfgAccessLoggingFlushInterval, accesslog.DefaultFlushInterval),
+		RefreshInterval: getLifetime(v, l, cfgAccessLoggingRefreshInterval, accesslog.DefaultRefreshInterval),
+		BufferSize:      getSize(v, l, cfgAccessLoggingBufferSize, accesslog.DefaultBufferSize),
+		MaxRecords:      getSize(v, l, cfgAccessLoggingMaxRecords, accesslog.DefaultMaxRecords),
+		AccessKeys:      v.GetStringSlice(cfgAccessLoggingAccessKeys),
+	}
+}
+
+// getReplicationOptions loads replication worker parameters, every remote target
+// is described by the indexed entry with its name, endpoint and credentials.
+func getReplicationOptions(v *viper.Viper, l *zap.Logger) *replication.Config {
+	targets := make(map[string]*replication.Target)
+	for i := 0; ; i++ {
+		prefix := cfgReplicationTargets + "." + strconv.Itoa(i) + "."
+		name := v.GetString(prefix + "name")
+		if name == "" {
+			break
+		}
+
+		targets[name] = &replication.Target{
+			Endpoint:        v.GetString(prefix + "endpoint"),
+			Region:          v.GetString(prefix + "region"),
+			AccessKeyID:     v.GetString(prefix + "access_key_id"),
+			SecretAccessKey: v.GetString(prefix + "secret_access_key"),
+		}
+	}
+
+	return &replication.Config{
+		Workers:   getSize(v, l, cfgReplicationWorkers, replication.DefaultWorkers),
+		QueueSize: getSize(v, l, cfgReplicationQueueSize, replication.DefaultQueueSize),
+		Targets:   targets,
+	}
+}
+
+// getKeyRing loads keys used for bucket default encryption. Every key is either
+// read from the file with hex encoded key or derived from the gateway wallet key.
+func getKeyRing(v *viper.Viper, l *zap.Logger, walletKey *keys.PrivateKey) *encryption.KeyRing {
+	ringKeys := make(map[string][]byte)
+	for i := 0; ; i++ {
+		prefix := cfgEncryptionKeys + "." + strconv.Itoa(i) + "."
+		id := v.GetString(prefix + "id")
+		if id == "" {
+			break
+		}
+
+		var key []byte
+		if file := v.GetString(prefix + "file"); file != "" {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				l.Fatal("could not read encryption key", zap.String("id", id), zap.Error(err))
+			}
+			if key, err = hex.DecodeString(strings.TrimSpace(string(content))); err != nil {
+				l.Fatal("invalid encryption key", zap.String("id", id), zap.Error(err))
+			}
+		} else if v.GetBool(prefix + "wallet") {
+			mac := hmac.New(sha256.New, walletKey.Bytes())
+			mac.Write([]byte("s3-gw-encryption-key:" + id))
+			key = mac.Sum(nil)
+		} else {
+			l.Fatal("encryption key source isn't set", zap.String("id", id))
+		}
+
+		ringKeys[id] = key
+	}
+
+	if len(ringKeys) == 0 {
+		return nil
+	}
+
+	keyRing, err := encryption.NewKeyRing(v.GetString(cfgEncryptionCurrentKey), ringKeys)
+	if err != nil {
+		l.Fatal("could not initialize encryption key ring", zap.Error(err))
+	}
+
+	l.Info("encryption key ring loaded",
+		zap.Int("keys", len(ringKeys)),
+		zap.String("current key", keyRing.CurrentKeyID()))
+
+	return keyRing
+}
+
+func getAccessKeyRegistry(v *viper.Viper, l *zap.Logger, conns *pool.Pool) *tokens.Registry {
+	containerID := v.GetString(cfgAccessKeyRegistryContainer)
+	if containerID == "" {
+		return nil
+	}
+
+	var cnrID cid.ID
+	if err := cnrID.DecodeString(containerID); err != nil {
+		l.Fatal("invalid access key registry container id", zap.String("container_id", containerID), zap.Error(err))
+	}
+
+	return tokens.NewRegistry(neofs.NewAuthmateNeoFS(conns), cnrID, cache.DefaultObjectsNameConfig(l))
+}
+
 func getAccessBoxCacheConfig(v *viper.Viper, l *zap.Logger) *cache.Config {
 	cacheCfg := cache.DefaultAccessBoxConfig(l)
 
 	cacheCfg.Lifetime = getLifetime(v, l, cfgAccessBoxCacheLifetime, cacheCfg.Lifetime)
 	cacheCfg.Size = getSize(v, l, cfgAccessBoxCacheSize, cacheCfg.Size)
+	cacheCfg.RevocationCheckInterval = getLifetime(v, l, cfgAccessBoxCacheRevocationCheckInterval, cacheCfg.RevocationCheckInterval)
 
 	return cacheCfg
 }
@@ -418,7 +746,14 @@ func getHandlerOptions(v *viper.Viper, l *zap.Logger) *handler.Config {
 	}
 
 	cfg.DefaultMaxAge = defaultMaxAge
-	cfg.NotificatorEnabled = v.GetBool(cfgEnableNATS)
+
+	for _, admin := range v.GetStringSlice(cfgUsageQuotaAdmins) {
+		var id user.ID
+		if err = id.DecodeString(admin); err != nil {
+			l.Fatal("invalid quota admin", zap.String("user", admin), zap.Error(err))
+		}
+		cfg.QuotaAdmins = append(cfg.QuotaAdmins, id)
+	}
 
 	return &cfg
 }
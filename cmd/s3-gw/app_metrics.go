@@ -3,6 +3,9 @@ package main
 import (
 	"net/http"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/janitor"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -211,6 +214,21 @@ func (m *poolMetricsCollector) updateRequestsDuration(node pool.NodeStatistic) {
 	m.requestDuration.WithLabelValues(node.Address(), methodCreateSession).Set(float64(node.AverageCreateSession().Milliseconds()))
 }
 
+// registerOutboxMetrics registers metrics of the notification outbox backlog.
+func registerOutboxMetrics(outbox *notifications.Outbox) {
+	prometheus.MustRegister(outbox)
+}
+
+// registerUsageMetrics registers metrics of the bucket usage.
+func registerUsageMetrics(usage *layer.UsageTracker) {
+	prometheus.MustRegister(usage)
+}
+
+// registerJanitorMetrics registers metrics of the multipart janitor.
+func registerJanitorMetrics(mj *janitor.Worker) {
+	prometheus.MustRegister(mj)
+}
+
 // NewPrometheusService creates a new service for gathering prometheus metrics.
 func NewPrometheusService(v *viper.Viper, log *zap.Logger) *Service {
 	if log == nil {
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewOutboxAdminService creates a new service to manage events in the notification outbox.
+// The service is disabled if the outbox isn't used.
+func NewOutboxAdminService(v *viper.Viper, l *zap.Logger, nc *notifications.Controller) *Service {
+	enabled := v.GetBool(cfgNotificationsOutboxAdminEnabled) && nc != nil && nc.Outbox() != nil
+
+	var handler http.Handler
+	if enabled {
+		handler = nc.OutboxHandler()
+	}
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgNotificationsOutboxAdminAddress),
+			Handler: handler,
+		},
+		enabled:     enabled,
+		serviceType: "Outbox",
+		log:         l.With(zap.String("service", "Outbox")),
+	}
+}
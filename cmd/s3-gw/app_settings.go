@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"runtime"
 	"sort"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
@@ -49,18 +51,25 @@ const ( // Settings.
 	cfgPoolErrorThreshold = "pool_error_threshold"
 
 	// Caching.
-	cfgObjectsCacheLifetime     = "cache.objects.lifetime"
-	cfgObjectsCacheSize         = "cache.objects.size"
-	cfgListObjectsCacheLifetime = "cache.list.lifetime"
-	cfgListObjectsCacheSize     = "cache.list.size"
-	cfgBucketsCacheLifetime     = "cache.buckets.lifetime"
-	cfgBucketsCacheSize         = "cache.buckets.size"
-	cfgNamesCacheLifetime       = "cache.names.lifetime"
-	cfgNamesCacheSize           = "cache.names.size"
-	cfgSystemLifetimeSize       = "cache.system.lifetime"
-	cfgSystemCacheSize          = "cache.system.size"
-	cfgAccessBoxCacheLifetime   = "cache.accessbox.lifetime"
-	cfgAccessBoxCacheSize       = "cache.accessbox.size"
+	cfgObjectsCacheLifetime                  = "cache.objects.lifetime"
+	cfgObjectsCacheSize                      = "cache.objects.size"
+	cfgListObjectsCacheLifetime              = "cache.list.lifetime"
+	cfgListObjectsCacheSize                  = "cache.list.size"
+	cfgBucketsCacheLifetime                  = "cache.buckets.lifetime"
+	cfgBucketsCacheSize                      = "cache.buckets.size"
+	cfgNamesCacheLifetime                    = "cache.names.lifetime"
+	cfgNamesCacheSize                        = "cache.names.size"
+	cfgSystemLifetimeSize                    = "cache.system.lifetime"
+	cfgSystemCacheSize                       = "cache.system.size"
+	cfgAccessBoxCacheLifetime                = "cache.accessbox.lifetime"
+	cfgAccessBoxCacheSize                    = "cache.accessbox.size"
+	cfgAccessBoxCacheRevocationCheckInterval = "cache.accessbox.revocation_check_interval"
+
+	// Access key registry.
+	cfgAccessKeyRegistryContainer = "access_key_registry.container_id"
+
+	// AWS Signature Version 2.
+	cfgSignatureV2Enabled = "signature_v2.enabled"
 
 	// NATS.
 	cfgEnableNATS             = "nats.enabled"
@@ -70,6 +79,59 @@ const ( // Settings.
 	cfgNATSAuthPrivateKeyFile = "nats.key_file"
 	cfgNATSRootCAFiles        = "nats.root_ca"
 
+	// Notifications.
+	cfgNotificationsTargets            = "notifications.targets"
+	cfgNotificationsOutboxEnabled      = "notifications.outbox.enabled"
+	cfgNotificationsOutboxPath         = "notifications.outbox.path"
+	cfgNotificationsOutboxMinBackoff   = "notifications.outbox.min_backoff"
+	cfgNotificationsOutboxMaxBackoff   = "notifications.outbox.max_backoff"
+	cfgNotificationsOutboxAdminEnabled = "notifications.outbox.admin.enabled"
+	cfgNotificationsOutboxAdminAddress = "notifications.outbox.admin.address"
+	cfgNotificationsListenEnabled      = "notifications.listen.enabled"
+	cfgNotificationsListenBufferSize   = "notifications.listen.buffer_size"
+
+	// Lifecycle.
+	cfgLifecycleEnabled    = "lifecycle.enabled"
+	cfgLifecycleInterval   = "lifecycle.interval"
+	cfgLifecycleAccessKeys = "lifecycle.access_keys"
+
+	// Multipart janitor.
+	cfgMultipartJanitorEnabled    = "multipart_janitor.enabled"
+	cfgMultipartJanitorInterval   = "multipart_janitor.interval"
+	cfgMultipartJanitorTTL        = "multipart_janitor.ttl"
+	cfgMultipartJanitorBuckets    = "multipart_janitor.buckets"
+	cfgMultipartJanitorAccessKeys = "multipart_janitor.access_keys"
+
+	// Parallel reads.
+	cfgReadConcurrency = "read.concurrency"
+	cfgReadChunkSize   = "read.chunk_size"
+	cfgReadBufferSize  = "read.buffer_size"
+
+	// Usage.
+	cfgUsageEnabled         = "usage.enabled"
+	cfgUsageRefreshInterval = "usage.refresh_interval"
+	cfgUsageQuotaAdmins     = "usage.quota_admins"
+	cfgUsageAdminEnabled    = "usage.admin.enabled"
+	cfgUsageAdminAddress    = "usage.admin.address"
+
+	// Access logging.
+	cfgAccessLoggingEnabled         = "access_logging.enabled"
+	cfgAccessLoggingFlushInterval   = "access_logging.flush_interval"
+	cfgAccessLoggingRefreshInterval = "access_logging.refresh_interval"
+	cfgAccessLoggingBufferSize      = "access_logging.buffer_size"
+	cfgAccessLoggingMaxRecords      = "access_logging.max_records"
+	cfgAccessLoggingAccessKeys      = "access_logging.access_keys"
+
+	// Replication.
+	cfgReplicationEnabled   = "replication.enabled"
+	cfgReplicationWorkers   = "replication.workers"
+	cfgReplicationQueueSize = "replication.queue_size"
+	cfgReplicationTargets   = "replication.targets"
+
+	// Encryption.
+	cfgEncryptionCurrentKey = "encryption.current_key"
+	cfgEncryptionKeys       = "encryption.keys"
+
 	// Policy.
 	cfgDefaultPolicy = "default_policy"
 
@@ -89,6 +151,12 @@ const ( // Settings.
 	cfgListenAddress = "listen_address"
 	cfgListenDomains = "listen_domains"
 
+	// Proxies which forwarding headers are trusted.
+	cfgTrustedProxies = "trusted_proxies"
+
+	// Website.
+	cfgWebsiteDomains = "website.domains"
+
 	// Peers.
 	cfgPeers = "peers"
 
@@ -167,6 +235,19 @@ func fetchDomains(v *viper.Viper) []string {
 	return res
 }
 
+func fetchTrustedProxies(v *viper.Viper, l *zap.Logger) []*net.IPNet {
+	var res []*net.IPNet
+	for _, cidr := range v.GetStringSlice(cfgTrustedProxies) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			l.Fatal("invalid trusted proxy network", zap.String("cidr", cidr), zap.Error(err))
+		}
+		res = append(res, network)
+	}
+
+	return res
+}
+
 func newSettings() *viper.Viper {
 	v := viper.New()
 
@@ -219,6 +300,12 @@ func newSettings() *viper.Viper {
 
 	v.SetDefault(cfgPProfAddress, "localhost:8085")
 	v.SetDefault(cfgPrometheusAddress, "localhost:8086")
+	v.SetDefault(cfgNotificationsOutboxAdminAddress, "localhost:8087")
+	v.SetDefault(cfgUsageAdminAddress, "localhost:8088")
+
+	// notifications:
+	v.SetDefault(cfgNotificationsListenEnabled, true)
+	v.SetDefault(cfgNotificationsListenBufferSize, notifications.DefaultListenerBufferSize)
 
 	// Binding flags
 	if err := v.BindPFlag(cfgPProfEnabled, flags.Lookup(cmdPProf)); err != nil {
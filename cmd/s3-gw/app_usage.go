@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewUsageAdminService creates a new service to show usage of the buckets.
+// The service is disabled if usage isn't tracked.
+func NewUsageAdminService(v *viper.Viper, l *zap.Logger, usage *layer.UsageTracker) *Service {
+	enabled := v.GetBool(cfgUsageAdminEnabled) && usage != nil
+
+	var handler http.Handler
+	if enabled {
+		handler = usage.Handler()
+	}
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgUsageAdminAddress),
+			Handler: handler,
+		},
+		enabled:     enabled,
+		serviceType: "Usage",
+		log:         l.With(zap.String("service", "Usage")),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"net"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
@@ -21,8 +22,9 @@ import (
 
 // Box represents friendly AccessBox.
 type Box struct {
-	Gate     *GateData
-	Policies []*ContainerPolicy
+	Gate         *GateData
+	Policies     []*ContainerPolicy
+	Restrictions *Restrictions
 }
 
 // ContainerPolicy represents friendly AccessBox_ContainerPolicy.
@@ -31,6 +33,16 @@ type ContainerPolicy struct {
 	Policy             netmap.PlacementPolicy
 }
 
+// Restrictions represents friendly AccessBox_Restrictions. Empty lists
+// and zero MaxObjectSize don't restrict requests.
+type Restrictions struct {
+	Buckets        []string
+	Prefixes       []string
+	Operations     []string
+	SourceNetworks []*net.IPNet
+	MaxObjectSize  uint64
+}
+
 // GateData represents gate tokens in AccessBox.
 type GateData struct {
 	AccessKey     string
@@ -96,6 +108,17 @@ func (x *AccessBox) Unmarshal(data []byte) error {
 // PackTokens adds bearer and session tokens to BearerTokens and SessionToken lists respectively.
 // Session token can be nil.
 func PackTokens(gatesData []*GateData) (*AccessBox, *Secrets, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate accessKey as hex: %w", err)
+	}
+
+	return PackTokensWithSecret(gatesData, secret)
+}
+
+// PackTokensWithSecret is the same as PackTokens but uses the given secret
+// instead of generating a new one. It allows to update tokens of the issued secret.
+func PackTokensWithSecret(gatesData []*GateData, secret []byte) (*AccessBox, *Secrets, error) {
 	box := &AccessBox{}
 	ephemeralKey, err := keys.NewPrivateKey()
 	if err != nil {
@@ -103,11 +126,6 @@ func PackTokens(gatesData []*GateData) (*AccessBox, *Secrets, error) {
 	}
 	box.OwnerPublicKey = ephemeralKey.PublicKey().Bytes()
 
-	secret, err := generateSecret()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate accessKey as hex: %w", err)
-	}
-
 	if err := box.addTokens(gatesData, ephemeralKey, secret); err != nil {
 		return nil, nil, fmt.Errorf("failed to add tokens to accessbox: %w", err)
 	}
@@ -154,6 +172,31 @@ func (x *AccessBox) GetPlacementPolicy() ([]*ContainerPolicy, error) {
 	return result, nil
 }
 
+// GetAccessRestrictions returns Restrictions from AccessBox, nil if AccessBox
+// doesn't restrict requests.
+func (x *AccessBox) GetAccessRestrictions() (*Restrictions, error) {
+	if x.Restrictions == nil {
+		return nil, nil
+	}
+
+	result := &Restrictions{
+		Buckets:       x.Restrictions.Buckets,
+		Prefixes:      x.Restrictions.Prefixes,
+		Operations:    x.Restrictions.Operations,
+		MaxObjectSize: x.Restrictions.MaxObjectSize,
+	}
+
+	for _, cidr := range x.Restrictions.SourceCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse source cidr: %w", err)
+		}
+		result.SourceNetworks = append(result.SourceNetworks, network)
+	}
+
+	return result, nil
+}
+
 // GetBox parses AccessBox to Box.
 func (x *AccessBox) GetBox(owner *keys.PrivateKey) (*Box, error) {
 	tokens, err := x.GetTokens(owner)
@@ -166,9 +209,15 @@ func (x *AccessBox) GetBox(owner *keys.PrivateKey) (*Box, error) {
 		return nil, fmt.Errorf("get policy: %w", err)
 	}
 
+	restrictions, err := x.GetAccessRestrictions()
+	if err != nil {
+		return nil, fmt.Errorf("get restrictions: %w", err)
+	}
+
 	return &Box{
-		Gate:     tokens,
-		Policies: policy,
+		Gate:         tokens,
+		Policies:     policy,
+		Restrictions: restrictions,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package accessbox
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/google/uuid"
@@ -170,3 +171,83 @@ func Test_unknown_key(t *testing.T) {
 	_, err = box.GetTokens(wrongCred)
 	require.Error(t, err)
 }
+
+func Test_pack_tokens_with_secret(t *testing.T) {
+	var tkn bearer.Token
+
+	sec, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	cred, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	tkn.SetEACLTable(*eacl.NewTable())
+	require.NoError(t, tkn.Sign(sec.PrivateKey))
+
+	box, secrets, err := PackTokens([]*GateData{NewGateData(cred.PublicKey(), &tkn)})
+	require.NoError(t, err)
+
+	tkns, err := box.GetTokens(cred)
+	require.NoError(t, err)
+	require.Equal(t, secrets.AccessKey, tkns.AccessKey)
+
+	secret, err := hex.DecodeString(secrets.AccessKey)
+	require.NoError(t, err)
+
+	box2, secrets2, err := PackTokensWithSecret([]*GateData{NewGateData(cred.PublicKey(), &tkn)}, secret)
+	require.NoError(t, err)
+	require.Equal(t, secrets.AccessKey, secrets2.AccessKey)
+	require.NotEqual(t, box.OwnerPublicKey, box2.OwnerPublicKey)
+
+	tkns, err = box2.GetTokens(cred)
+	require.NoError(t, err)
+	require.Equal(t, secrets.AccessKey, tkns.AccessKey)
+}
+
+func Test_restrictions_in_access_box(t *testing.T) {
+	var (
+		box2 AccessBox
+		tkn  bearer.Token
+	)
+
+	sec, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	cred, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	tkn.SetEACLTable(*eacl.NewTable())
+	require.NoError(t, tkn.Sign(sec.PrivateKey))
+
+	box, _, err := PackTokens([]*GateData{NewGateData(cred.PublicKey(), &tkn)})
+	require.NoError(t, err)
+
+	res, err := box.GetBox(cred)
+	require.NoError(t, err)
+	require.Nil(t, res.Restrictions)
+
+	box.Restrictions = &AccessBox_Restrictions{
+		Buckets:       []string{"bucket"},
+		Prefixes:      []string{"photos/"},
+		Operations:    []string{"GetObject"},
+		SourceCIDRs:   []string{"10.0.0.0/8"},
+		MaxObjectSize: 1024,
+	}
+
+	data, err := box.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, box2.Unmarshal(data))
+
+	res, err = box2.GetBox(cred)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bucket"}, res.Restrictions.Buckets)
+	require.Equal(t, []string{"photos/"}, res.Restrictions.Prefixes)
+	require.Equal(t, []string{"GetObject"}, res.Restrictions.Operations)
+	require.Len(t, res.Restrictions.SourceNetworks, 1)
+	require.Equal(t, "10.0.0.0/8", res.Restrictions.SourceNetworks[0].String())
+	require.EqualValues(t, 1024, res.Restrictions.MaxObjectSize)
+
+	box2.Restrictions.SourceCIDRs = []string{"10.0.0.0"}
+	_, err = box2.GetBox(cred)
+	require.Error(t, err)
+}
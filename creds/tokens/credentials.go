@@ -10,7 +10,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
 )
@@ -20,6 +22,7 @@ type (
 	Credentials interface {
 		GetBox(context.Context, oid.Address) (*accessbox.Box, error)
 		Put(context.Context, cid.ID, user.ID, *accessbox.AccessBox, uint64, ...*keys.PublicKey) (oid.Address, error)
+		Update(context.Context, oid.Address, user.ID, *accessbox.AccessBox, uint64, ...*keys.PublicKey) (oid.Address, error)
 	}
 
 	cred struct {
@@ -40,13 +43,38 @@ type PrmObjectCreate struct {
 	// File name.
 	Filename string
 
-	// Last NeoFS epoch of the object lifetime.
+	// Last NeoFS epoch of the object lifetime, zero means the object doesn't expire.
 	ExpirationEpoch uint64
 
+	// Additional object attributes.
+	Attributes [][2]string
+
 	// Object payload.
 	Payload []byte
 }
 
+// PrmObjectSearch groups parameters of objects searched by credential tool.
+type PrmObjectSearch struct {
+	// Container to search the objects in.
+	Container cid.ID
+
+	// NeoFS identifier of the objects owner (optional).
+	Owner *user.ID
+
+	// Attributes the objects must have (optional).
+	Attributes [][2]string
+}
+
+const (
+	// AttributeAccessBoxOrigin is an attribute of the updated access box versions,
+	// it contains ID of the access box object which address is used as access key ID.
+	AttributeAccessBoxOrigin = "S3-Access-Box-Origin"
+
+	// AttributeRevokedAccessBox is an attribute of the revocation marker,
+	// it contains ID of the removed access box object.
+	AttributeRevokedAccessBox = "S3-Revoked-Access-Box"
+)
+
 // NeoFS represents virtual connection to NeoFS network.
 type NeoFS interface {
 	// CreateObject creates and saves a parameterized object in the specified
@@ -63,6 +91,23 @@ type NeoFS interface {
 	// It returns exactly one non-nil value. It returns any error encountered which
 	// prevented the object payload from being read.
 	ReadObjectPayload(context.Context, oid.Address) ([]byte, error)
+
+	// ReadObjectHeader reads the header of the object from NeoFS network by address.
+	//
+	// It returns exactly one non-nil value. It returns any error encountered which
+	// prevented the object header from being read.
+	ReadObjectHeader(context.Context, oid.Address) (*object.Object, error)
+
+	// SearchObjects returns IDs of the root objects in the container matching
+	// the owner and the attributes.
+	//
+	// It returns any error encountered which prevented the objects from being found.
+	SearchObjects(context.Context, PrmObjectSearch) ([]oid.ID, error)
+
+	// ContainerOwner returns the owner of the container.
+	//
+	// It returns any error encountered which prevented the container from being read.
+	ContainerOwner(context.Context, cid.ID) (user.ID, error)
 }
 
 var (
@@ -70,6 +115,8 @@ var (
 	ErrEmptyPublicKeys = errors.New("HCS public keys could not be empty")
 	// ErrEmptyBearerToken is returned when no bearer token is provided.
 	ErrEmptyBearerToken = errors.New("Bearer token could not be empty")
+	// ErrAccessBoxRevoked is returned when the access box object is removed from NeoFS.
+	ErrAccessBoxRevoked = errors.New("access box is revoked")
 )
 
 var _ = New
@@ -80,13 +127,19 @@ func New(neoFS NeoFS, key *keys.PrivateKey, config *cache.Config) Credentials {
 }
 
 func (c *cred) GetBox(ctx context.Context, addr oid.Address) (*accessbox.Box, error) {
-	cachedBox := c.cache.Get(addr)
+	cachedBox, check := c.cache.GetWithCheck(addr)
 	if cachedBox != nil {
+		if check {
+			return c.checkRevocation(ctx, addr, cachedBox)
+		}
 		return cachedBox, nil
 	}
 
 	box, err := c.getAccessBox(ctx, addr)
 	if err != nil {
+		if errors.Is(err, ErrAccessBoxRevoked) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("get access box: %w", err)
 	}
 
@@ -102,9 +155,59 @@ func (c *cred) GetBox(ctx context.Context, addr oid.Address) (*accessbox.Box, er
 	return cachedBox, nil
 }
 
+// checkRevocation makes sure the access box object still exists and picks up
+// its latest version. If the check fails, e.g. NeoFS is unavailable, the cached
+// box is used for one more check interval only.
+func (c *cred) checkRevocation(ctx context.Context, addr oid.Address, cachedBox *accessbox.Box) (*accessbox.Box, error) {
+	box, err := c.getAccessBox(ctx, addr)
+	if err != nil {
+		if errors.Is(err, ErrAccessBoxRevoked) {
+			c.cache.Delete(addr)
+			return nil, err
+		}
+		if c.cache.CheckOverdue(addr) {
+			return nil, fmt.Errorf("check access box revocation: %w", err)
+		}
+		return cachedBox, nil
+	}
+
+	if updatedBox, err := box.GetBox(c.key); err == nil {
+		cachedBox = updatedBox
+	}
+
+	if err = c.cache.Put(addr, cachedBox); err != nil {
+		return nil, fmt.Errorf("put box into cache: %w", err)
+	}
+
+	return cachedBox, nil
+}
+
+func isRemoved(err error) bool {
+	return client.IsErrObjectAlreadyRemoved(err) || client.IsErrObjectNotFound(err)
+}
+
+// getAccessBox reads the latest version of the access box. ErrAccessBoxRevoked
+// is returned if the access box has the revocation marker or the read version
+// is removed. The access box isn't read if revocation markers or versions can't
+// be searched, since it can be revoked.
 func (c *cred) getAccessBox(ctx context.Context, addr oid.Address) (*accessbox.AccessBox, error) {
-	data, err := c.neoFS.ReadObjectPayload(ctx, addr)
+	latest, err := c.latestVersion(ctx, addr)
+	if err != nil {
+		if errors.Is(err, ErrAccessBoxRevoked) {
+			return nil, err
+		}
+		// the removed access box is revoked regardless of the search
+		if _, headErr := c.neoFS.ReadObjectHeader(ctx, addr); headErr != nil && isRemoved(headErr) {
+			return nil, fmt.Errorf("%w: %s", ErrAccessBoxRevoked, addr)
+		}
+		return nil, err
+	}
+
+	data, err := c.neoFS.ReadObjectPayload(ctx, latest)
 	if err != nil {
+		if isRemoved(err) {
+			return nil, fmt.Errorf("%w: %s", ErrAccessBoxRevoked, addr)
+		}
 		return nil, fmt.Errorf("read payload: %w", err)
 	}
 
@@ -117,7 +220,103 @@ func (c *cred) getAccessBox(ctx context.Context, addr oid.Address) (*accessbox.A
 	return &box, nil
 }
 
+// latestVersion returns the address of the most recent version of the access
+// box put by Update. The access box address is returned if it has no versions.
+// ErrAccessBoxRevoked is returned if the owner put the revocation marker.
+func (c *cred) latestVersion(ctx context.Context, addr oid.Address) (oid.Address, error) {
+	owner, err := c.boxOwner(ctx, addr)
+	if err != nil {
+		return addr, err
+	}
+
+	markers, err := c.neoFS.SearchObjects(ctx, PrmObjectSearch{
+		Container:  addr.Container(),
+		Owner:      &owner,
+		Attributes: [][2]string{{AttributeRevokedAccessBox, addr.Object().EncodeToString()}},
+	})
+	if err != nil {
+		return addr, fmt.Errorf("search revocation markers: %w", err)
+	} else if len(markers) != 0 {
+		return addr, fmt.Errorf("%w: %s", ErrAccessBoxRevoked, addr)
+	}
+
+	ids, err := c.neoFS.SearchObjects(ctx, PrmObjectSearch{
+		Container:  addr.Container(),
+		Owner:      &owner,
+		Attributes: [][2]string{{AttributeAccessBoxOrigin, addr.Object().EncodeToString()}},
+	})
+	if err != nil {
+		return addr, fmt.Errorf("search access box versions: %w", err)
+	}
+
+	var (
+		latest           = addr
+		latestTime int64 = -1
+	)
+
+	for _, id := range ids {
+		var versionAddr oid.Address
+		versionAddr.SetContainer(addr.Container())
+		versionAddr.SetObject(id)
+
+		header, err := c.neoFS.ReadObjectHeader(ctx, versionAddr)
+		if err != nil || header.OwnerID() == nil || !header.OwnerID().Equals(owner) {
+			continue
+		}
+
+		var created int64
+		for _, attr := range header.Attributes() {
+			if attr.Key() == object.AttributeTimestamp {
+				created, _ = strconv.ParseInt(attr.Value(), 10, 64)
+			}
+		}
+
+		// versions created at the same second are ordered by ID to get the same result on every gate
+		if created > latestTime || created == latestTime && id.EncodeToString() > latest.Object().EncodeToString() {
+			latest, latestTime = versionAddr, created
+		}
+	}
+
+	return latest, nil
+}
+
+// boxOwner returns the owner of the access box, only its versions are trusted,
+// otherwise anyone allowed to put objects into the container could replace the
+// access box. The owner of the container is trusted if the access box itself is
+// expired, the auth containers created by authmate accept objects only from it.
+func (c *cred) boxOwner(ctx context.Context, addr oid.Address) (user.ID, error) {
+	header, err := c.neoFS.ReadObjectHeader(ctx, addr)
+	if err != nil {
+		if !isRemoved(err) {
+			return user.ID{}, fmt.Errorf("read access box header: %w", err)
+		}
+
+		owner, err := c.neoFS.ContainerOwner(ctx, addr.Container())
+		if err != nil {
+			return user.ID{}, fmt.Errorf("get auth container owner: %w", err)
+		}
+		return owner, nil
+	}
+
+	if header.OwnerID() == nil {
+		return user.ID{}, errors.New("access box has no owner")
+	}
+
+	return *header.OwnerID(), nil
+}
+
 func (c *cred) Put(ctx context.Context, idCnr cid.ID, issuer user.ID, box *accessbox.AccessBox, expiration uint64, keys ...*keys.PublicKey) (oid.Address, error) {
+	return c.put(ctx, idCnr, issuer, box, expiration, nil, keys...)
+}
+
+// Update puts a new version of the access box. Gates resolve the access box
+// address to its latest version, so the access key ID and the secret stay the same.
+func (c *cred) Update(ctx context.Context, addr oid.Address, issuer user.ID, box *accessbox.AccessBox, expiration uint64, keys ...*keys.PublicKey) (oid.Address, error) {
+	return c.put(ctx, addr.Container(), issuer, box, expiration,
+		[][2]string{{AttributeAccessBoxOrigin, addr.Object().EncodeToString()}}, keys...)
+}
+
+func (c *cred) put(ctx context.Context, idCnr cid.ID, issuer user.ID, box *accessbox.AccessBox, expiration uint64, attributes [][2]string, keys ...*keys.PublicKey) (oid.Address, error) {
 	if len(keys) == 0 {
 		return oid.Address{}, ErrEmptyPublicKeys
 	} else if box == nil {
@@ -133,6 +332,7 @@ func (c *cred) Put(ctx context.Context, idCnr cid.ID, issuer user.ID, box *acces
 		Container:       idCnr,
 		Filename:        strconv.FormatInt(time.Now().Unix(), 10) + "_access.box",
 		ExpirationEpoch: expiration,
+		Attributes:      attributes,
 		Payload:         data,
 	})
 	if err != nil {
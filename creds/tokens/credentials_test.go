@@ -0,0 +1,297 @@
+package tokens
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	usertest "github.com/nspcc-dev/neofs-sdk-go/user/test"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type boxNeoFS struct {
+	objects map[oid.ID][]byte
+	headers map[oid.ID]*object.Object
+	removed map[oid.ID]bool
+	owner   user.ID
+	fail    bool
+	// failSearch makes only the search fail
+	failSearch bool
+	created    int64
+}
+
+func newBoxNeoFS() *boxNeoFS {
+	return &boxNeoFS{
+		objects: make(map[oid.ID][]byte),
+		headers: make(map[oid.ID]*object.Object),
+		removed: make(map[oid.ID]bool),
+	}
+}
+
+func (n *boxNeoFS) CreateObject(_ context.Context, prm PrmObjectCreate) (oid.ID, error) {
+	n.created++
+
+	header := object.New()
+	header.SetOwnerID(&prm.Creator)
+	for _, kv := range append([][2]string{{object.AttributeTimestamp, strconv.FormatInt(n.created, 10)}}, prm.Attributes...) {
+		attr := object.NewAttribute()
+		attr.SetKey(kv[0])
+		attr.SetValue(kv[1])
+		header.SetAttributes(append(header.Attributes(), *attr)...)
+	}
+
+	id := oidtest.ID()
+	n.objects[id] = prm.Payload
+	n.headers[id] = header
+	return id, nil
+}
+
+func (n *boxNeoFS) ReadObjectPayload(_ context.Context, addr oid.Address) ([]byte, error) {
+	if n.fail {
+		return nil, errors.New("network is unavailable")
+	}
+	if n.removed[addr.Object()] {
+		return nil, apistatus.ObjectAlreadyRemoved{}
+	}
+	payload, ok := n.objects[addr.Object()]
+	if !ok {
+		return nil, apistatus.ObjectNotFound{}
+	}
+	return payload, nil
+}
+
+func (n *boxNeoFS) ReadObjectHeader(_ context.Context, addr oid.Address) (*object.Object, error) {
+	if n.fail {
+		return nil, errors.New("network is unavailable")
+	}
+	header, ok := n.headers[addr.Object()]
+	if !ok || n.removed[addr.Object()] {
+		return nil, apistatus.ObjectNotFound{}
+	}
+	return header, nil
+}
+
+func (n *boxNeoFS) SearchObjects(_ context.Context, prm PrmObjectSearch) ([]oid.ID, error) {
+	if n.fail || n.failSearch {
+		return nil, errors.New("network is unavailable")
+	}
+
+	var ids []oid.ID
+	for id, header := range n.headers {
+		if prm.Owner != nil && !header.OwnerID().Equals(*prm.Owner) {
+			continue
+		}
+		if !n.removed[id] && hasAttributes(header, prm.Attributes) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (n *boxNeoFS) ContainerOwner(context.Context, cid.ID) (user.ID, error) {
+	if n.fail {
+		return user.ID{}, errors.New("network is unavailable")
+	}
+	return n.owner, nil
+}
+
+func hasAttributes(header *object.Object, attrs [][2]string) bool {
+	for _, kv := range attrs {
+		var found bool
+		for _, attr := range header.Attributes() {
+			if attr.Key() == kv[0] && attr.Value() == kv[1] {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func newBox(t *testing.T, issuerKey, gateKey *keys.PrivateKey, exp uint64, secret []byte) *accessbox.AccessBox {
+	var tkn bearer.Token
+	tkn.SetEACLTable(*eacl.NewTable())
+	tkn.SetExp(exp)
+	require.NoError(t, tkn.Sign(issuerKey.PrivateKey))
+
+	gatesData := []*accessbox.GateData{accessbox.NewGateData(gateKey.PublicKey(), &tkn)}
+	if secret == nil {
+		box, _, err := accessbox.PackTokens(gatesData)
+		require.NoError(t, err)
+		return box
+	}
+
+	box, _, err := accessbox.PackTokensWithSecret(gatesData, secret)
+	require.NoError(t, err)
+	return box
+}
+
+func TestGetBoxRevocation(t *testing.T) {
+	ctx := context.Background()
+
+	issuerKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	box := newBox(t, issuerKey, gateKey, 10, nil)
+
+	neoFS := newBoxNeoFS()
+	config := cache.DefaultAccessBoxConfig(zap.NewNop())
+	config.RevocationCheckInterval = 50 * time.Millisecond
+	creds := New(neoFS, gateKey, config)
+
+	addr, err := creds.Put(ctx, cidtest.ID(), *usertest.ID(), box, 10, gateKey.PublicKey())
+	require.NoError(t, err)
+
+	_, err = creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+
+	// the box is served from cache until the next check
+	neoFS.removed[addr.Object()] = true
+	_, err = creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+
+	// the cached box is used if NeoFS is unavailable for one more check interval only
+	time.Sleep(config.RevocationCheckInterval)
+	neoFS.fail = true
+	_, err = creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+
+	time.Sleep(config.RevocationCheckInterval)
+	_, err = creds.GetBox(ctx, addr)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrAccessBoxRevoked)
+
+	neoFS.fail = false
+	_, err = creds.GetBox(ctx, addr)
+	require.ErrorIs(t, err, ErrAccessBoxRevoked)
+
+	_, err = creds.GetBox(ctx, addr)
+	require.ErrorIs(t, err, ErrAccessBoxRevoked)
+}
+
+func TestGetBoxRevocationMarker(t *testing.T) {
+	ctx := context.Background()
+
+	issuerKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS := newBoxNeoFS()
+	config := cache.DefaultAccessBoxConfig(zap.NewNop())
+	owner := *usertest.ID()
+
+	addr, err := New(neoFS, gateKey, config).Put(ctx, cidtest.ID(), owner, newBox(t, issuerKey, gateKey, 10, nil), 10, gateKey.PublicKey())
+	require.NoError(t, err)
+
+	putMarker := func(creator user.ID) {
+		_, err := neoFS.CreateObject(ctx, PrmObjectCreate{
+			Creator:    creator,
+			Container:  addr.Container(),
+			Attributes: [][2]string{{AttributeRevokedAccessBox, addr.Object().EncodeToString()}},
+		})
+		require.NoError(t, err)
+	}
+
+	// the access box isn't accepted if revocation markers can't be searched
+	neoFS.failSearch = true
+	_, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrAccessBoxRevoked)
+	neoFS.failSearch = false
+
+	// markers put by other users are ignored
+	putMarker(*usertest.ID())
+	_, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.NoError(t, err)
+
+	// the access box is revoked even if it isn't removed yet
+	putMarker(owner)
+	_, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.ErrorIs(t, err, ErrAccessBoxRevoked)
+
+	// the removed access box is revoked even if the search fails
+	neoFS.removed[addr.Object()] = true
+	neoFS.failSearch = true
+	_, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.ErrorIs(t, err, ErrAccessBoxRevoked)
+}
+
+func TestGetBoxUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	issuerKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS := newBoxNeoFS()
+	neoFS.owner = *usertest.ID()
+	config := cache.DefaultAccessBoxConfig(zap.NewNop())
+	config.RevocationCheckInterval = 50 * time.Millisecond
+	creds := New(neoFS, gateKey, config)
+
+	addr, err := creds.Put(ctx, cidtest.ID(), neoFS.owner, newBox(t, issuerKey, gateKey, 10, nil), 10, gateKey.PublicKey())
+	require.NoError(t, err)
+
+	box, err := creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.True(t, box.Gate.BearerToken.InvalidAt(11))
+
+	secret, err := hex.DecodeString(box.Gate.AccessKey)
+	require.NoError(t, err)
+
+	for _, exp := range []uint64{20, 30} {
+		_, err = creds.Update(ctx, addr, neoFS.owner, newBox(t, issuerKey, gateKey, exp, secret), exp, gateKey.PublicKey())
+		require.NoError(t, err)
+	}
+
+	// versions put by other users are ignored
+	_, err = creds.Update(ctx, addr, *usertest.ID(), newBox(t, issuerKey, gateKey, 40, nil), 40, gateKey.PublicKey())
+	require.NoError(t, err)
+
+	// the cached box is used until the next check
+	box, err = creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.True(t, box.Gate.BearerToken.InvalidAt(11))
+
+	time.Sleep(config.RevocationCheckInterval)
+	box, err = creds.GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.False(t, box.Gate.BearerToken.InvalidAt(29))
+	require.True(t, box.Gate.BearerToken.InvalidAt(31))
+	require.Equal(t, hex.EncodeToString(secret), box.Gate.AccessKey)
+
+	// the latest version is used when the access box itself is expired
+	neoFS.removed[addr.Object()] = true
+	box, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.NoError(t, err)
+	require.False(t, box.Gate.BearerToken.InvalidAt(29))
+	require.True(t, box.Gate.BearerToken.InvalidAt(31))
+
+	for id := range neoFS.headers {
+		neoFS.removed[id] = true
+	}
+	_, err = New(neoFS, gateKey, config).GetBox(ctx, addr)
+	require.ErrorIs(t, err, ErrAccessBoxRevoked)
+}
@@ -0,0 +1,195 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+)
+
+// Attributes of the access key registry entries.
+const (
+	// AttributeAccessKeyID contains the access key ID chosen at issuance.
+	AttributeAccessKeyID = "S3-Access-Key-ID"
+	// AttributeAccessBox contains the address of the access box the access key ID refers to.
+	AttributeAccessBox = "S3-Access-Box"
+)
+
+// ErrAccessKeyNotFound is returned when the access key ID isn't registered.
+var ErrAccessKeyNotFound = errors.New("access key id is not registered")
+
+// accessKeyAliasRegexp matches access key IDs which can be registered, the
+// length and the characters are the same as AWS allows.
+var accessKeyAliasRegexp = regexp.MustCompile(`^\w{16,128}$`)
+
+// registryMissLifetime is a lifetime of cached lookups of unregistered access key IDs,
+// it's short since such access key IDs can be registered at any moment.
+const registryMissLifetime = 10 * time.Second
+
+// Registry resolves access key IDs chosen at issuance to access box addresses.
+// Every registry entry is an object in the registry container with
+// AttributeAccessKeyID and AttributeAccessBox attributes, entries are found
+// by these attributes with NeoFS object search. Only entries created by the
+// registry container owner are trusted, other users can't claim or shadow
+// access key IDs even if they are allowed to put objects into the container.
+type Registry struct {
+	neoFS     NeoFS
+	container cid.ID
+	cache     *cache.ObjectsNameCache
+	misses    gcache.Cache
+
+	mu    sync.Mutex
+	owner *user.ID
+}
+
+// NewRegistry creates a Registry of the access keys stored in the container.
+func NewRegistry(neoFS NeoFS, container cid.ID, config *cache.Config) *Registry {
+	return &Registry{
+		neoFS:     neoFS,
+		container: container,
+		cache:     cache.NewObjectsNameCache(config),
+		misses:    gcache.New(config.Size).LRU().Expiration(registryMissLifetime).Build(),
+	}
+}
+
+// IsAccessKeyAlias checks if the access key ID can be resolved through Registry.
+// Access key IDs in <cid>0<oid> format are access box addresses themselves.
+func IsAccessKeyAlias(accessKeyID string) bool {
+	var addr oid.Address
+	return accessKeyAliasRegexp.MatchString(accessKeyID) &&
+		addr.DecodeString(strings.ReplaceAll(accessKeyID, "0", "/")) != nil
+}
+
+// ParseAccessKeyID returns the access box address of the access key ID in <cid>0<oid> format.
+func ParseAccessKeyID(accessKeyID string) (oid.Address, error) {
+	var addr oid.Address
+	if err := addr.DecodeString(strings.ReplaceAll(accessKeyID, "0", "/")); err != nil {
+		return addr, fmt.Errorf("invalid access key id '%s': %w", accessKeyID, err)
+	}
+	return addr, nil
+}
+
+// ResolveAccessKey returns the address of the access box of the access key ID.
+// Aliases are resolved through the registry, they aren't accepted if the registry is nil.
+func ResolveAccessKey(ctx context.Context, registry *Registry, accessKeyID string) (oid.Address, error) {
+	if registry != nil && IsAccessKeyAlias(accessKeyID) {
+		return registry.Resolve(ctx, accessKeyID)
+	}
+	return ParseAccessKeyID(accessKeyID)
+}
+
+// CheckAccessKey checks if the access key ID is the access box address or
+// the alias which can be resolved through the registry.
+func CheckAccessKey(registry *Registry, accessKeyID string) error {
+	if registry != nil && IsAccessKeyAlias(accessKeyID) {
+		return nil
+	}
+	_, err := ParseAccessKeyID(accessKeyID)
+	return err
+}
+
+// Resolve returns the address of the access box registered for the access key ID.
+// ErrAccessKeyNotFound is returned if there is no such entry in the registry,
+// such results are cached for a short time.
+func (r *Registry) Resolve(ctx context.Context, accessKeyID string) (oid.Address, error) {
+	if addr := r.cache.Get(accessKeyID); addr != nil {
+		return *addr, nil
+	}
+	if r.misses.Has(accessKeyID) {
+		return oid.Address{}, fmt.Errorf("%w: %s", ErrAccessKeyNotFound, accessKeyID)
+	}
+
+	addr, err := r.resolve(ctx, accessKeyID)
+	if err != nil {
+		if errors.Is(err, ErrAccessKeyNotFound) {
+			_ = r.misses.Set(accessKeyID, struct{}{})
+		}
+		return oid.Address{}, err
+	}
+
+	if err = r.cache.Put(accessKeyID, addr); err != nil {
+		return oid.Address{}, fmt.Errorf("put access key into cache: %w", err)
+	}
+
+	return addr, nil
+}
+
+func (r *Registry) resolve(ctx context.Context, accessKeyID string) (oid.Address, error) {
+	owner, err := r.containerOwner(ctx)
+	if err != nil {
+		return oid.Address{}, err
+	}
+
+	ids, err := r.neoFS.SearchObjects(ctx, PrmObjectSearch{
+		Container:  r.container,
+		Owner:      &owner,
+		Attributes: [][2]string{{AttributeAccessKeyID, accessKeyID}},
+	})
+	if err != nil {
+		return oid.Address{}, fmt.Errorf("search registry entries: %w", err)
+	}
+
+	switch len(ids) {
+	case 0:
+		return oid.Address{}, fmt.Errorf("%w: %s", ErrAccessKeyNotFound, accessKeyID)
+	case 1:
+	default:
+		// no entry is preferred to not give access to the wrong access box
+		return oid.Address{}, fmt.Errorf("%w: %s has %d entries", ErrAccessKeyNotFound, accessKeyID, len(ids))
+	}
+
+	var entryAddr oid.Address
+	entryAddr.SetContainer(r.container)
+	entryAddr.SetObject(ids[0])
+
+	header, err := r.neoFS.ReadObjectHeader(ctx, entryAddr)
+	if err != nil {
+		return oid.Address{}, fmt.Errorf("read registry entry: %w", err)
+	}
+	if header.OwnerID() == nil || !header.OwnerID().Equals(owner) {
+		return oid.Address{}, fmt.Errorf("%w: %s entry isn't created by the registry owner", ErrAccessKeyNotFound, accessKeyID)
+	}
+
+	return RegisteredAccessBox(header)
+}
+
+// containerOwner returns the owner of the registry container, it never changes, so it's requested once.
+func (r *Registry) containerOwner(ctx context.Context) (user.ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.owner == nil {
+		owner, err := r.neoFS.ContainerOwner(ctx, r.container)
+		if err != nil {
+			return user.ID{}, fmt.Errorf("get registry container owner: %w", err)
+		}
+		r.owner = &owner
+	}
+
+	return *r.owner, nil
+}
+
+// RegisteredAccessBox returns the address of the access box from the header of the registry entry.
+func RegisteredAccessBox(header *object.Object) (oid.Address, error) {
+	var addr oid.Address
+	for _, attr := range header.Attributes() {
+		if attr.Key() == AttributeAccessBox {
+			if err := addr.DecodeString(attr.Value()); err != nil {
+				return addr, fmt.Errorf("invalid access box address of registry entry: %w", err)
+			}
+			return addr, nil
+		}
+	}
+
+	return addr, fmt.Errorf("registry entry has no '%s' attribute", AttributeAccessBox)
+}
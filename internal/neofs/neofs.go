@@ -533,6 +533,16 @@ func (x *AuthmateNeoFS) ContainerExists(ctx context.Context, idCnr cid.ID) error
 	return nil
 }
 
+// ContainerOwner implements authmate.NeoFS interface method.
+func (x *AuthmateNeoFS) ContainerOwner(ctx context.Context, idCnr cid.ID) (user.ID, error) {
+	cnr, err := x.neoFS.Container(ctx, idCnr)
+	if err != nil {
+		return user.ID{}, fmt.Errorf("get container via connection pool: %w", err)
+	}
+
+	return cnr.Owner(), nil
+}
+
 // TimeToEpoch implements authmate.NeoFS interface method.
 func (x *AuthmateNeoFS) TimeToEpoch(ctx context.Context, futureTime time.Time) (uint64, uint64, error) {
 	return x.neoFS.TimeToEpoch(ctx, futureTime)
@@ -541,8 +551,11 @@ func (x *AuthmateNeoFS) TimeToEpoch(ctx context.Context, futureTime time.Time) (
 // CreateContainer implements authmate.NeoFS interface method.
 func (x *AuthmateNeoFS) CreateContainer(ctx context.Context, prm authmate.PrmContainerCreate) (cid.ID, error) {
 	basicACL := acl.Private
-	// allow reading objects to OTHERS in order to provide read access to S3 gateways
+	// allow reading objects to OTHERS in order to provide read access to S3 gateways,
+	// searching is needed to find the latest versions of updated access boxes
 	basicACL.AllowOp(acl.OpObjectGet, acl.RoleOthers)
+	basicACL.AllowOp(acl.OpObjectHead, acl.RoleOthers)
+	basicACL.AllowOp(acl.OpObjectSearch, acl.RoleOthers)
 
 	return x.neoFS.CreateContainer(ctx, layer.PrmContainerCreate{
 		Creator:  prm.Owner,
@@ -570,13 +583,75 @@ func (x *AuthmateNeoFS) ReadObjectPayload(ctx context.Context, addr oid.Address)
 
 // CreateObject implements authmate.NeoFS interface method.
 func (x *AuthmateNeoFS) CreateObject(ctx context.Context, prm tokens.PrmObjectCreate) (oid.ID, error) {
+	attributes := prm.Attributes
+	if prm.ExpirationEpoch != 0 {
+		attributes = append([][2]string{
+			{"__NEOFS__EXPIRATION_EPOCH", strconv.FormatUint(prm.ExpirationEpoch, 10)}}, attributes...)
+	}
+
 	return x.neoFS.CreateObject(ctx, layer.PrmObjectCreate{
-		Creator:   prm.Creator,
-		Container: prm.Container,
-		Filename:  prm.Filename,
-		Attributes: [][2]string{
-			{"__NEOFS__EXPIRATION_EPOCH", strconv.FormatUint(prm.ExpirationEpoch, 10)}},
-		Payload: bytes.NewReader(prm.Payload),
+		Creator:    prm.Creator,
+		Container:  prm.Container,
+		Filename:   prm.Filename,
+		Attributes: attributes,
+		Payload:    bytes.NewReader(prm.Payload),
+	})
+}
+
+// SearchObjects implements authmate.NeoFS interface method.
+func (x *AuthmateNeoFS) SearchObjects(ctx context.Context, prm tokens.PrmObjectSearch) ([]oid.ID, error) {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	if prm.Owner != nil {
+		filters.AddObjectOwnerIDFilter(object.MatchStringEqual, *prm.Owner)
+	}
+	for _, attr := range prm.Attributes {
+		filters.AddFilter(attr[0], attr[1], object.MatchStringEqual)
+	}
+
+	var prmSearch pool.PrmObjectSearch
+	prmSearch.SetContainerID(prm.Container)
+	prmSearch.SetFilters(filters)
+
+	res, err := x.neoFS.pool.SearchObjects(ctx, prmSearch)
+	if err != nil {
+		return nil, fmt.Errorf("init object search via connection pool: %w", err)
+	}
+
+	defer res.Close()
+
+	var buf []oid.ID
+
+	err = res.Iterate(func(id oid.ID) bool {
+		buf = append(buf, id)
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read object list: %w", err)
+	}
+
+	return buf, nil
+}
+
+// ReadObjectHeader implements authmate.NeoFS interface method.
+func (x *AuthmateNeoFS) ReadObjectHeader(ctx context.Context, addr oid.Address) (*object.Object, error) {
+	res, err := x.neoFS.ReadObject(ctx, layer.PrmObjectRead{
+		Container:  addr.Container(),
+		Object:     addr.Object(),
+		WithHeader: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Head, nil
+}
+
+// DeleteObject implements authmate.NeoFS interface method.
+func (x *AuthmateNeoFS) DeleteObject(ctx context.Context, addr oid.Address) error {
+	return x.neoFS.DeleteObject(ctx, layer.PrmObjectDelete{
+		Container: addr.Container(),
+		Object:    addr.Object(),
 	})
 }
 
@@ -48,16 +48,23 @@ type (
 )
 
 const (
-	versioningKV        = "Versioning"
-	lockConfigurationKV = "LockConfiguration"
-	oidKV               = "OID"
-	fileNameKV          = "FileName"
-	isUnversionedKV     = "IsUnversioned"
-	isTagKV             = "IsTag"
-	uploadIDKV          = "UploadId"
-	partNumberKV        = "Number"
-	sizeKV              = "Size"
-	etagKV              = "ETag"
+	versioningKV              = "Versioning"
+	lockConfigurationKV       = "LockConfiguration"
+	encryptionConfigurationKV = "EncryptionConfiguration"
+	quotaHardLimitKV          = "QuotaHardLimit"
+	quotaSoftLimitKV          = "QuotaSoftLimit"
+	oidKV                     = "OID"
+	fileNameKV                = "FileName"
+	isUnversionedKV           = "IsUnversioned"
+	isTagKV                   = "IsTag"
+	uploadIDKV                = "UploadId"
+	partNumberKV              = "Number"
+	sizeKV                    = "Size"
+	etagKV                    = "ETag"
+	checksumKV                = "Checksum"
+	checksumAlgorithmKV       = "ChecksumAlgorithm"
+	replicationStatusKV       = "ReplicationStatus"
+	partsKV                   = "Parts"
 
 	// keys for lock.
 	isLockKV       = "IsLock"
@@ -71,11 +78,19 @@ const (
 	ownerKV          = "Owner"
 	createdKV        = "Created"
 
+	// keys for bucket policy.
+	policyKV = "Policy"
+
 	settingsFileName      = "bucket-settings"
 	notifConfFileName     = "bucket-notifications"
 	corsFilename          = "bucket-cors"
 	emptyFileName         = "<empty>" // to handle trailing and leading slash in name
 	bucketTaggingFilename = "bucket-tagging"
+	lifecycleFilename     = "bucket-lifecycle"
+	policyFilename        = "bucket-policy"
+	replicationFilename   = "bucket-replication"
+	websiteFilename       = "bucket-website"
+	loggingFilename       = "bucket-logging"
 
 	// versionTree -- ID of a tree with object versions.
 	versionTree = "version"
@@ -160,13 +175,14 @@ func newNodeVersion(filePath string, node NodeResponse) (*data.NodeVersion, erro
 		return nil, fmt.Errorf("invalid tree node: %w", err)
 	}
 
-	return newNodeVersionFromTreeNode(filePath, treeNode), nil
+	return newNodeVersionFromTreeNode(filePath, treeNode)
 }
 
-func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeVersion {
+func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) (*data.NodeVersion, error) {
 	_, isUnversioned := treeNode.Get(isUnversionedKV)
 	_, isDeleteMarker := treeNode.Get(isDeleteMarkerKV)
 	eTag, _ := treeNode.Get(etagKV)
+	replicationStatus, _ := treeNode.Get(replicationStatusKV)
 
 	version := &data.NodeVersion{
 		BaseNodeVersion: data.BaseNodeVersion{
@@ -174,10 +190,19 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 			OID:       treeNode.ObjID,
 			Timestamp: treeNode.TimeStamp,
 			ETag:      eTag,
+			Checksum:  newChecksum(treeNode.Meta),
 			Size:      treeNode.Size,
 			FilePath:  filePath,
 		},
-		IsUnversioned: isUnversioned,
+		IsUnversioned:     isUnversioned,
+		ReplicationStatus: replicationStatus,
+	}
+
+	if parts, ok := treeNode.Get(partsKV); ok {
+		var err error
+		if version.Parts, err = data.DecodeManifest(parts); err != nil {
+			return nil, fmt.Errorf("invalid parts of node version: %w", err)
+		}
 	}
 
 	if isDeleteMarker {
@@ -198,7 +223,7 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 			Owner:   owner,
 		}
 	}
-	return version
+	return version, nil
 }
 
 func newMultipartInfo(node NodeResponse) (*data.MultipartInfo, error) {
@@ -219,6 +244,8 @@ func newMultipartInfo(node NodeResponse) (*data.MultipartInfo, error) {
 			}
 		case ownerKV:
 			_ = multipartInfo.Owner.DecodeString(string(kv.GetValue()))
+		case checksumAlgorithmKV:
+			multipartInfo.ChecksumAlgorithm = string(kv.GetValue())
 		default:
 			multipartInfo.Meta[kv.GetKey()] = string(kv.GetValue())
 		}
@@ -234,6 +261,7 @@ func newMultipartInfo(node NodeResponse) (*data.MultipartInfo, error) {
 func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 	var err error
 	partInfo := &data.PartInfo{}
+	checksumMeta := make(map[string]string, 2)
 
 	for _, kv := range node.GetMeta() {
 		value := string(kv.GetValue())
@@ -248,6 +276,8 @@ func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 			}
 		case etagKV:
 			partInfo.ETag = value
+		case checksumKV, checksumAlgorithmKV:
+			checksumMeta[kv.GetKey()] = value
 		case sizeKV:
 			if partInfo.Size, err = strconv.ParseInt(value, 10, 64); err != nil {
 				return nil, fmt.Errorf("invalid part size: %w", err)
@@ -264,12 +294,33 @@ func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 	if partInfo.Number <= 0 {
 		return nil, fmt.Errorf("it's not a part node")
 	}
+	partInfo.Checksum = newChecksum(checksumMeta)
 
 	return partInfo, nil
 }
 
+// newChecksum forms additional checksum from node meta, returns nil if it is not set.
+func newChecksum(meta map[string]string) *data.Checksum {
+	algorithm, ok := meta[checksumAlgorithmKV]
+	if !ok {
+		return nil
+	}
+
+	return &data.Checksum{
+		Algorithm: algorithm,
+		Value:     meta[checksumKV],
+	}
+}
+
+func addChecksumMeta(meta map[string]string, checksum *data.Checksum) {
+	if checksum != nil {
+		meta[checksumAlgorithmKV] = checksum.Algorithm
+		meta[checksumKV] = checksum.Value
+	}
+}
+
 func (c *TreeClient) GetSettingsNode(ctx context.Context, cnrID cid.ID) (*data.BucketSettings, error) {
-	keysToReturn := []string{versioningKV, lockConfigurationKV}
+	keysToReturn := []string{versioningKV, lockConfigurationKV, encryptionConfigurationKV, quotaHardLimitKV, quotaSoftLimitKV}
 	node, err := c.getSystemNode(ctx, cnrID, []string{settingsFileName}, keysToReturn)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get node: %w", err)
@@ -286,6 +337,14 @@ func (c *TreeClient) GetSettingsNode(ctx context.Context, cnrID cid.ID) (*data.B
 		}
 	}
 
+	if encryptionAlgorithm, ok := node.Get(encryptionConfigurationKV); ok && len(encryptionAlgorithm) > 0 {
+		settings.EncryptionConfiguration = data.NewServerSideEncryptionConfiguration(encryptionAlgorithm)
+	}
+
+	if settings.Quota, err = parseQuota(node); err != nil {
+		return nil, fmt.Errorf("settings node: invalid quota: %w", err)
+	}
+
 	return settings, nil
 }
 
@@ -379,6 +438,190 @@ func (c *TreeClient) DeleteBucketCORS(ctx context.Context, cnrID cid.ID) (oid.ID
 	return oid.ID{}, layer.ErrNoNodeToRemove
 }
 
+func (c *TreeClient) GetBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{lifecycleFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{lifecycleFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = lifecycleFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, cnrID, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, cnrID, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketLifecycleConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{lifecycleFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, cnrID, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{replicationFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{replicationFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = replicationFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, cnrID, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, cnrID, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketReplicationConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{replicationFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, cnrID, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{websiteFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{websiteFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = websiteFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, cnrID, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, cnrID, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketWebsiteConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{websiteFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, cnrID, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{loggingFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{loggingFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = loggingFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, cnrID, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, cnrID, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketLoggingConfiguration(ctx context.Context, cnrID cid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{loggingFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, cnrID, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) PutReplicationStatus(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion, status string) error {
+	parentID, err := c.getParent(ctx, cnrID, versionTree, objVersion.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get parent node: %w", err)
+	}
+
+	version := *objVersion
+	version.ReplicationStatus = status
+
+	return c.moveNode(ctx, cnrID, versionTree, objVersion.ID, parentID, metaFromNodeVersion(&version))
+}
+
 func (c *TreeClient) GetObjectTagging(ctx context.Context, cnrID cid.ID, objVersion *data.NodeVersion) (map[string]string, error) {
 	tagNode, err := c.getTreeNode(ctx, cnrID, objVersion.ID, isTagKV)
 	if err != nil {
@@ -495,6 +738,41 @@ func (c *TreeClient) DeleteBucketTagging(ctx context.Context, cnrID cid.ID) erro
 	return nil
 }
 
+func (c *TreeClient) GetBucketPolicy(ctx context.Context, cnrID cid.ID) ([]byte, error) {
+	node, err := c.getSystemNode(ctx, cnrID, []string{policyFilename}, []string{policyKV})
+	if err != nil {
+		return nil, err
+	}
+
+	policy, ok := node.Get(policyKV)
+	if !ok {
+		return nil, layer.ErrNodeNotFound
+	}
+
+	return []byte(policy), nil
+}
+
+func (c *TreeClient) PutBucketPolicy(ctx context.Context, cnrID cid.ID, policy []byte) error {
+	node, err := c.getSystemNode(ctx, cnrID, []string{policyFilename}, []string{})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := map[string]string{
+		fileNameKV: policyFilename,
+		policyKV:   string(policy),
+	}
+
+	if isErrNotFound {
+		_, err = c.addNode(ctx, cnrID, systemTree, 0, meta)
+	} else {
+		err = c.moveNode(ctx, cnrID, systemTree, node.ID, 0, meta)
+	}
+
+	return err
+}
+
 func (c *TreeClient) getTreeNode(ctx context.Context, cnrID cid.ID, nodeID uint64, key string) (*TreeNode, error) {
 	nodes, err := c.getTreeNodes(ctx, cnrID, nodeID, key)
 	if err != nil {
@@ -537,7 +815,7 @@ func (c *TreeClient) GetVersions(ctx context.Context, cnrID cid.ID, filepath str
 }
 
 func (c *TreeClient) GetLatestVersion(ctx context.Context, cnrID cid.ID, objectName string) (*data.NodeVersion, error) {
-	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, checksumKV, checksumAlgorithmKV, replicationStatusKV, partsKV}
 	path := pathFromName(objectName)
 
 	p := &getNodesParams{
@@ -732,14 +1010,19 @@ func (c *TreeClient) getSubTreeVersions(ctx context.Context, cnrID cid.ID, nodeI
 			continue
 		}
 
+		version, err := newNodeVersionFromTreeNode(filepath, treeNode)
+		if err != nil {
+			continue
+		}
+
 		key := formLatestNodeKey(node.GetParentId(), fileName)
 		versionNodes, ok := versions[key]
 		if !ok {
-			versionNodes = []*data.NodeVersion{newNodeVersionFromTreeNode(filepath, treeNode)}
+			versionNodes = []*data.NodeVersion{version}
 		} else if !latestOnly {
-			versionNodes = append(versionNodes, newNodeVersionFromTreeNode(filepath, treeNode))
+			versionNodes = append(versionNodes, version)
 		} else if versionNodes[0].Timestamp <= treeNode.TimeStamp {
-			versionNodes[0] = newNodeVersionFromTreeNode(filepath, treeNode)
+			versionNodes[0] = version
 		}
 
 		versions[key] = versionNodes
@@ -920,6 +1203,7 @@ func (c *TreeClient) AddPart(ctx context.Context, cnrID cid.ID, multipartNodeID
 		createdKV:    strconv.FormatInt(info.Created.UTC().UnixMilli(), 10),
 		etagKV:       info.ETag,
 	}
+	addChecksumMeta(meta, info.Checksum)
 
 	var foundPartID uint64
 	for _, part := range parts {
@@ -1054,27 +1338,9 @@ func (c *TreeClient) Close() error {
 
 func (c *TreeClient) addVersion(ctx context.Context, cnrID cid.ID, treeID string, version *data.NodeVersion) error {
 	path := pathFromName(version.FilePath)
-	meta := map[string]string{
-		oidKV:      version.OID.EncodeToString(),
-		fileNameKV: path[len(path)-1],
-	}
-
-	if version.Size > 0 {
-		meta[sizeKV] = strconv.FormatInt(version.Size, 10)
-	}
-	if len(version.ETag) > 0 {
-		meta[etagKV] = version.ETag
-	}
-
-	if version.DeleteMarker != nil {
-		meta[isDeleteMarkerKV] = "true"
-		meta[ownerKV] = version.DeleteMarker.Owner.EncodeToString()
-		meta[createdKV] = strconv.FormatInt(version.DeleteMarker.Created.UTC().UnixMilli(), 10)
-	}
+	meta := metaFromNodeVersion(version)
 
 	if version.IsUnversioned {
-		meta[isUnversionedKV] = "true"
-
 		node, err := c.getUnversioned(ctx, cnrID, treeID, version.FilePath)
 		if err == nil {
 			parentID, err := c.getParent(ctx, cnrID, treeID, node.ID)
@@ -1094,7 +1360,7 @@ func (c *TreeClient) addVersion(ctx context.Context, cnrID cid.ID, treeID string
 }
 
 func (c *TreeClient) getVersions(ctx context.Context, cnrID cid.ID, treeID, filepath string, onlyUnversioned bool) ([]*data.NodeVersion, error) {
-	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, checksumKV, checksumAlgorithmKV, replicationStatusKV, partsKV}
 	path := pathFromName(filepath)
 	p := &getNodesParams{
 		CnrID:      cnrID,
@@ -1183,12 +1449,53 @@ func (c *TreeClient) getSubTree(ctx context.Context, cnrID cid.ID, treeID string
 	return subtree, nil
 }
 
+func metaFromNodeVersion(version *data.NodeVersion) map[string]string {
+	path := pathFromName(version.FilePath)
+	meta := map[string]string{
+		oidKV:      version.OID.EncodeToString(),
+		fileNameKV: path[len(path)-1],
+	}
+
+	if version.Size > 0 {
+		meta[sizeKV] = strconv.FormatInt(version.Size, 10)
+	}
+	if len(version.ETag) > 0 {
+		meta[etagKV] = version.ETag
+	}
+	addChecksumMeta(meta, version.Checksum)
+
+	if version.DeleteMarker != nil {
+		meta[isDeleteMarkerKV] = "true"
+		meta[ownerKV] = version.DeleteMarker.Owner.EncodeToString()
+		meta[createdKV] = strconv.FormatInt(version.DeleteMarker.Created.UTC().UnixMilli(), 10)
+	}
+
+	if version.IsUnversioned {
+		meta[isUnversionedKV] = "true"
+	}
+
+	if len(version.ReplicationStatus) > 0 {
+		meta[replicationStatusKV] = version.ReplicationStatus
+	}
+
+	if len(version.Parts) > 0 {
+		meta[partsKV] = data.EncodeManifest(version.Parts)
+	}
+
+	return meta
+}
+
 func metaFromSettings(settings *data.BucketSettings) map[string]string {
-	results := make(map[string]string, 3)
+	results := make(map[string]string, 6)
 
 	results[fileNameKV] = settingsFileName
 	results[versioningKV] = settings.Versioning
 	results[lockConfigurationKV] = encodeLockConfiguration(settings.LockConfiguration)
+	results[encryptionConfigurationKV] = settings.EncryptionConfiguration.Algorithm()
+	if settings.Quota != nil {
+		results[quotaHardLimitKV] = strconv.FormatUint(settings.Quota.HardLimit, 10)
+		results[quotaSoftLimitKV] = strconv.FormatUint(settings.Quota.SoftLimit, 10)
+	}
 
 	return results
 }
@@ -1198,6 +1505,9 @@ func metaFromMultipart(info *data.MultipartInfo) map[string]string {
 	info.Meta[uploadIDKV] = info.UploadID
 	info.Meta[ownerKV] = info.Owner.EncodeToString()
 	info.Meta[createdKV] = strconv.FormatInt(info.Created.UTC().UnixMilli(), 10)
+	if info.ChecksumAlgorithm != "" {
+		info.Meta[checksumAlgorithmKV] = info.ChecksumAlgorithm
+	}
 
 	return info.Meta
 }
@@ -1385,6 +1695,32 @@ func metaToKV(meta map[string]string) []*tree.KeyValue {
 	return result
 }
 
+func parseQuota(node *TreeNode) (*data.BucketQuota, error) {
+	hardLimit, hardOk := node.Get(quotaHardLimitKV)
+	softLimit, softOk := node.Get(quotaSoftLimitKV)
+	if !hardOk && !softOk {
+		return nil, nil
+	}
+
+	var (
+		quota = new(data.BucketQuota)
+		err   error
+	)
+
+	if hardOk {
+		if quota.HardLimit, err = strconv.ParseUint(hardLimit, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid hard limit: %w", err)
+		}
+	}
+	if softOk {
+		if quota.SoftLimit, err = strconv.ParseUint(softLimit, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid soft limit: %w", err)
+		}
+	}
+
+	return quota, nil
+}
+
 func parseLockConfiguration(value string) (*data.ObjectLockConfiguration, error) {
 	result := &data.ObjectLockConfiguration{}
 	if len(value) == 0 {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
 	"github.com/stretchr/testify/require"
 )
 
@@ -93,3 +94,43 @@ func TestLockConfigurationEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestQuotaEncoding(t *testing.T) {
+	settings := &data.BucketSettings{Quota: &data.BucketQuota{HardLimit: 1 << 30, SoftLimit: 1 << 20}}
+	node := &TreeNode{Meta: metaFromSettings(settings)}
+
+	quota, err := parseQuota(node)
+	require.NoError(t, err)
+	require.Equal(t, settings.Quota, quota)
+
+	quota, err = parseQuota(&TreeNode{Meta: metaFromSettings(&data.BucketSettings{})})
+	require.NoError(t, err)
+	require.Nil(t, quota)
+
+	_, err = parseQuota(&TreeNode{Meta: map[string]string{quotaHardLimitKV: "-1"}})
+	require.Error(t, err)
+}
+
+func TestManifestPartsEncoding(t *testing.T) {
+	version := &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{
+			OID:      oidtest.ID(),
+			FilePath: "dir/object",
+			Size:     15,
+			ETag:     "etag-2",
+		},
+		Parts: []data.ManifestPart{
+			{OID: oidtest.ID(), Size: 10, ETag: "etag1"},
+			{OID: oidtest.ID(), Size: 5, ETag: "etag2"},
+		},
+	}
+
+	node := &TreeNode{ObjID: version.OID, Size: version.Size, Meta: metaFromNodeVersion(version)}
+	decoded, err := newNodeVersionFromTreeNode(version.FilePath, node)
+	require.NoError(t, err)
+	require.Equal(t, version.Parts, decoded.Parts)
+
+	node.Meta[partsKV] = "invalid"
+	_, err = newNodeVersionFromTreeNode(version.FilePath, node)
+	require.Error(t, err)
+}